@@ -0,0 +1,27 @@
+// Command realtime-schema writes the JSON Schema of the realtime message envelope
+// and payloads, generated from the Go types in internal/dto.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/dto"
+)
+
+func main() {
+	out := flag.String("o", "web/static/schema/realtime-v1.json", "output file")
+	flag.Parse()
+
+	data, err := json.MarshalIndent(dto.RealtimeSchema(), "", "  ")
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to encode schema")
+	}
+
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		log.Fatal().Err(err).Str("file", *out).Msg("Failed to write schema")
+	}
+}
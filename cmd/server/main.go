@@ -104,6 +104,12 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// Hijacked WebSocket connections and open event streams are not closed by
+	// srv.Shutdown, so say goodbye to them first
+	if err := handlers.Hub.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Realtime hub did not shut down cleanly")
+	}
+
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal().Err(err).Msg("Server forced to shutdown")
 	}
@@ -0,0 +1,118 @@
+package audio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var mono16k = Format{AudioFormat: 1, Channels: 1, SampleRate: 16000, BitsPerSample: 16}
+
+// writeClip records a clip whose samples are all fill, so tests can tell clips apart
+func writeClip(t *testing.T, dir, name string, format Format, fill byte, frames int) {
+	path := filepath.Join(dir, filepath.FromSlash(name)+".wav")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+
+	data := make([]byte, frames*format.blockAlign())
+	for i := range data {
+		data[i] = fill
+	}
+	require.NoError(t, os.WriteFile(path, Encode(format, data), 0o644))
+}
+
+func TestDecode_RoundTrip(t *testing.T) {
+	raw := Encode(mono16k, []byte{1, 2, 3, 4})
+
+	clip, err := Decode(raw)
+	require.NoError(t, err)
+	assert.Equal(t, mono16k, clip.Format)
+	assert.Equal(t, []byte{1, 2, 3, 4}, clip.Data)
+}
+
+func TestDecode_SkipsExtraChunks(t *testing.T) {
+	raw := Encode(mono16k, []byte{1, 2})
+	// Insert an odd-sized LIST chunk, padded to even length, before the data chunk
+	list := []byte("LIST\x03\x00\x00\x00abc\x00")
+	withList := append(append(append([]byte{}, raw[:36]...), list...), raw[36:]...)
+
+	clip, err := Decode(withList)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2}, clip.Data)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	_, err := Decode([]byte("ID3 not a wav file"))
+	assert.ErrorIs(t, err, ErrNotWAV)
+
+	truncated := Encode(mono16k, make([]byte, 100))[:60]
+	_, err = Decode(truncated)
+	assert.ErrorIs(t, err, ErrNotWAV)
+}
+
+func TestConcat(t *testing.T) {
+	a := &Clip{Format: mono16k, Data: []byte{1, 1}}
+	b := &Clip{Format: mono16k, Data: []byte{2, 2}}
+
+	raw, err := Concat([]*Clip{a, b}, time.Millisecond)
+	require.NoError(t, err)
+
+	joined, err := Decode(raw)
+	require.NoError(t, err)
+	// 1ms at 16kHz is 16 frames of two bytes each
+	expected := append(append([]byte{1, 1}, make([]byte, 32)...), 2, 2)
+	assert.Equal(t, expected, joined.Data)
+}
+
+func TestConcat_FormatMismatch(t *testing.T) {
+	a := &Clip{Format: mono16k, Data: []byte{1, 1}}
+	b := &Clip{Format: Format{AudioFormat: 1, Channels: 2, SampleRate: 44100, BitsPerSample: 16}, Data: []byte{2, 2, 2, 2}}
+
+	_, err := Concat([]*Clip{a, b}, 0)
+	assert.ErrorIs(t, err, ErrFormatMismatch)
+}
+
+func TestSilence_EightBit(t *testing.T) {
+	format := Format{AudioFormat: 1, Channels: 1, SampleRate: 8000, BitsPerSample: 8}
+	assert.Equal(t, []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80}, Silence(format, time.Millisecond))
+}
+
+func TestLibrary_Script(t *testing.T) {
+	dir := t.TempDir()
+	writeClip(t, dir, "id/phrases/ticket", mono16k, 1, 1)
+	writeClip(t, dir, "en/chime", mono16k, 1, 1)
+	writeClip(t, dir, "en/counters/vip", mono16k, 1, 1)
+	library := NewLibrary(dir)
+
+	assert.Equal(t,
+		[]string{"phrases/ticket", "letters/a", "digits/0", "digits/1", "digits/2", "phrases/counter", "digits/3"},
+		library.Script("id", "A-012", "3"))
+	assert.Equal(t,
+		[]string{"chime", "phrases/ticket", "letters/b", "digits/7", "phrases/counter", "counters/vip"},
+		library.Script("en", "B7", "VIP"))
+}
+
+func TestLibrary_Clip(t *testing.T) {
+	dir := t.TempDir()
+	writeClip(t, dir, "id/digits/1", mono16k, 7, 2)
+	library := NewLibrary(dir)
+
+	assert.True(t, library.HasLanguage("id"))
+	assert.False(t, library.HasLanguage("en"))
+	assert.False(t, library.HasLanguage(".."))
+
+	clip, err := library.Clip("id", "digits/1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{7, 7, 7, 7}, clip.Data)
+
+	// Served from memory once loaded
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "id", "digits")))
+	_, err = library.Clip("id", "digits/1")
+	assert.NoError(t, err)
+
+	_, err = library.Clip("id", "digits/2")
+	assert.ErrorIs(t, err, ErrClipMissing)
+}
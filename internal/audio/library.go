@@ -0,0 +1,131 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ErrClipMissing is returned when a language pack has no recording for a clip
+var ErrClipMissing = errors.New("announcement clip not recorded")
+
+// Library loads clips from one directory per language and keeps them in memory:
+//
+//	<dir>/<lang>/chime.wav           optional, played first
+//	<dir>/<lang>/phrases/ticket.wav  "Nomor antrian" / "Ticket number"
+//	<dir>/<lang>/phrases/counter.wav "silakan menuju loket" / "please proceed to counter"
+//	<dir>/<lang>/letters/a.wav ... z.wav
+//	<dir>/<lang>/digits/0.wav ... 9.wav
+//	<dir>/<lang>/counters/<number>.wav  optional, a counter's spoken name
+type Library struct {
+	dir string
+
+	mu    sync.Mutex
+	clips map[string]*Clip
+}
+
+func NewLibrary(dir string) *Library {
+	return &Library{
+		dir:   dir,
+		clips: make(map[string]*Clip),
+	}
+}
+
+// HasLanguage reports whether a pack directory exists for lang
+func (l *Library) HasLanguage(lang string) bool {
+	if !validName(lang) {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(l.dir, lang))
+	return err == nil && info.IsDir()
+}
+
+// Clip returns the clip for key, e.g. "digits/4", from lang's pack
+func (l *Library) Clip(lang, key string) (*Clip, error) {
+	cacheKey := lang + "/" + key
+
+	l.mu.Lock()
+	clip, ok := l.clips[cacheKey]
+	l.mu.Unlock()
+	if ok {
+		return clip, nil
+	}
+
+	raw, err := os.ReadFile(filepath.Join(l.dir, lang, filepath.FromSlash(key)+".wav"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s/%s", ErrClipMissing, lang, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	clip, err = Decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s: %w", lang, key, err)
+	}
+
+	l.mu.Lock()
+	l.clips[cacheKey] = clip
+	l.mu.Unlock()
+	return clip, nil
+}
+
+// Has reports whether lang's pack has a recording for key
+func (l *Library) Has(lang, key string) bool {
+	_, err := l.Clip(lang, key)
+	return err == nil
+}
+
+// Script lists the clips announcing ticketNumber at counterNumber in lang:
+// chime, "ticket number", the ticket spelled out, "please go to counter" and the
+// counter's own recording or, failing that, its number spelled out
+func (l *Library) Script(lang, ticketNumber, counterNumber string) []string {
+	var script []string
+	if l.Has(lang, "chime") {
+		script = append(script, "chime")
+	}
+
+	script = append(script, "phrases/ticket")
+	script = append(script, spell(ticketNumber)...)
+	script = append(script, "phrases/counter")
+
+	counterKey := "counters/" + strings.ToLower(counterNumber)
+	if validName(strings.ToLower(counterNumber)) && l.Has(lang, counterKey) {
+		script = append(script, counterKey)
+	} else {
+		script = append(script, spell(counterNumber)...)
+	}
+	return script
+}
+
+// spell turns "A012" into letters/a, digits/0, digits/1, digits/2; anything else,
+// such as a dash, is skipped
+func spell(text string) []string {
+	var keys []string
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r >= '0' && r <= '9':
+			keys = append(keys, "digits/"+string(r))
+		case r >= 'a' && r <= 'z':
+			keys = append(keys, "letters/"+string(r))
+		}
+	}
+	return keys
+}
+
+// validName keeps request-supplied names from reaching outside the pack directory
+func validName(name string) bool {
+	if name == "" || len(name) > 32 {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
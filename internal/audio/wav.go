@@ -0,0 +1,142 @@
+// Package audio stitches pre-recorded WAV clips into call announcements.
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNotWAV is returned for data that is not a RIFF/WAVE file
+	ErrNotWAV = errors.New("not a WAV file")
+	// ErrFormatMismatch is returned when clips in one announcement differ in format
+	ErrFormatMismatch = errors.New("clips have different audio formats")
+)
+
+// Format is the part of a WAV fmt chunk that has to match between clips
+type Format struct {
+	AudioFormat   uint16
+	Channels      uint16
+	SampleRate    uint32
+	BitsPerSample uint16
+}
+
+func (f Format) blockAlign() int {
+	return int(f.Channels) * int(f.BitsPerSample) / 8
+}
+
+// Clip is a decoded WAV file
+type Clip struct {
+	Format Format
+	Data   []byte
+}
+
+// Decode reads the fmt and data chunks of a PCM WAV file and skips the rest
+func Decode(raw []byte) (*Clip, error) {
+	if len(raw) < 12 || string(raw[0:4]) != "RIFF" || string(raw[8:12]) != "WAVE" {
+		return nil, ErrNotWAV
+	}
+
+	var clip Clip
+	var haveFormat, haveData bool
+	for pos := 12; pos+8 <= len(raw); {
+		id := string(raw[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(raw[pos+4 : pos+8]))
+		body := raw[pos+8:]
+		if size > len(body) {
+			return nil, fmt.Errorf("%w: truncated %q chunk", ErrNotWAV, id)
+		}
+		body = body[:size]
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, fmt.Errorf("%w: short fmt chunk", ErrNotWAV)
+			}
+			clip.Format = Format{
+				AudioFormat:   binary.LittleEndian.Uint16(body[0:2]),
+				Channels:      binary.LittleEndian.Uint16(body[2:4]),
+				SampleRate:    binary.LittleEndian.Uint32(body[4:8]),
+				BitsPerSample: binary.LittleEndian.Uint16(body[14:16]),
+			}
+			haveFormat = true
+		case "data":
+			clip.Data = body
+			haveData = true
+		}
+
+		// Chunks are padded to an even length
+		pos += 8 + size + size%2
+	}
+
+	if !haveFormat || !haveData {
+		return nil, fmt.Errorf("%w: missing fmt or data chunk", ErrNotWAV)
+	}
+	if clip.Format.blockAlign() == 0 {
+		return nil, fmt.Errorf("%w: invalid format", ErrNotWAV)
+	}
+	return &clip, nil
+}
+
+// Encode writes a canonical 44-byte header WAV file
+func Encode(format Format, data []byte) []byte {
+	var buf bytes.Buffer
+	buf.Grow(44 + len(data))
+
+	blockAlign := format.blockAlign()
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+len(data)))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, format.AudioFormat)
+	binary.Write(&buf, binary.LittleEndian, format.Channels)
+	binary.Write(&buf, binary.LittleEndian, format.SampleRate)
+	binary.Write(&buf, binary.LittleEndian, uint32(int(format.SampleRate)*blockAlign))
+	binary.Write(&buf, binary.LittleEndian, uint16(blockAlign))
+	binary.Write(&buf, binary.LittleEndian, format.BitsPerSample)
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+// Concat joins clips into one WAV file with gap of silence between them. Every clip
+// must share the first clip's format; record a pack in one format to avoid resampling.
+func Concat(clips []*Clip, gap time.Duration) ([]byte, error) {
+	if len(clips) == 0 {
+		return nil, errors.New("no clips to join")
+	}
+
+	format := clips[0].Format
+	silence := Silence(format, gap)
+
+	var data bytes.Buffer
+	for i, clip := range clips {
+		if clip.Format != format {
+			return nil, ErrFormatMismatch
+		}
+		if i > 0 {
+			data.Write(silence)
+		}
+		data.Write(clip.Data)
+	}
+	return Encode(format, data.Bytes()), nil
+}
+
+// Silence returns d worth of silent samples in format
+func Silence(format Format, d time.Duration) []byte {
+	frames := int(d.Seconds() * float64(format.SampleRate))
+	silence := make([]byte, frames*format.blockAlign())
+
+	// 8-bit PCM is unsigned, so silence sits at the midpoint
+	if format.BitsPerSample == 8 {
+		for i := range silence {
+			silence[i] = 0x80
+		}
+	}
+	return silence
+}
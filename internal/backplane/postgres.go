@@ -0,0 +1,152 @@
+// Package backplane relays WebSocket hub broadcasts between app instances.
+package backplane
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/query"
+)
+
+// maxNotifyPayload keeps NOTIFY payloads under the server's 8000 byte limit
+const maxNotifyPayload = 7900
+
+// largePayloadPrefix marks a notification that carries a backplane_messages id
+const largePayloadPrefix = "@"
+
+// Postgres relays messages through LISTEN/NOTIFY on a single channel. Messages too
+// large for NOTIFY are stored in backplane_messages and announced by id.
+type Postgres struct {
+	pool *pgxpool.Pool
+	qry  *query.BackplaneQueries
+	cfg  *config.BackplaneConfig
+
+	mu        sync.Mutex
+	lastPurge time.Time
+}
+
+func NewPostgres(pool *pgxpool.Pool, cfg *config.BackplaneConfig) *Postgres {
+	return &Postgres{
+		pool: pool,
+		qry:  query.NewBackplaneQueries(),
+		cfg:  cfg,
+	}
+}
+
+// Publish notifies every listening instance
+func (p *Postgres) Publish(ctx context.Context, message []byte) error {
+	payload := string(message)
+
+	if len(payload) > maxNotifyPayload {
+		var id int64
+		if err := p.pool.QueryRow(ctx, p.qry.InsertMessage(ctx), payload).Scan(&id); err != nil {
+			return err
+		}
+		payload = largePayloadPrefix + strconv.FormatInt(id, 10)
+		p.purgeIfDue(ctx)
+	}
+
+	_, err := p.pool.Exec(ctx, p.qry.Notify(ctx), p.cfg.Channel, payload)
+	return err
+}
+
+// Listen holds a dedicated connection on the channel and reconnects with backoff
+// when it breaks, e.g. during a database failover
+func (p *Postgres) Listen(ctx context.Context, deliver func(message []byte)) error {
+	backoff := p.cfg.ReconnectMin
+
+	for {
+		listening, err := p.listen(ctx, deliver)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if listening {
+			backoff = p.cfg.ReconnectMin
+		}
+
+		log.Warn().Err(err).Str("channel", p.cfg.Channel).Dur("retry_in", backoff).Msg("Backplane connection lost, reconnecting")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > p.cfg.ReconnectMax {
+			backoff = p.cfg.ReconnectMax
+		}
+	}
+}
+
+// listen runs one LISTEN session and reports whether it got as far as listening
+func (p *Postgres) listen(ctx context.Context, deliver func(message []byte)) (bool, error) {
+	pooled, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// Take the connection out of the pool so the LISTEN never leaks to other callers
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{p.cfg.Channel}.Sanitize()); err != nil {
+		return false, err
+	}
+	log.Info().Str("channel", p.cfg.Channel).Msg("Backplane listening")
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+
+		payload := notification.Payload
+		if strings.HasPrefix(payload, largePayloadPrefix) {
+			payload, err = p.loadMessage(ctx, strings.TrimPrefix(payload, largePayloadPrefix))
+			if err != nil {
+				log.Error().Err(err).Str("channel", p.cfg.Channel).Msg("Failed to load large backplane message")
+				continue
+			}
+		}
+
+		deliver([]byte(payload))
+	}
+}
+
+func (p *Postgres) loadMessage(ctx context.Context, rawID string) (string, error) {
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return "", err
+	}
+
+	var payload string
+	err = p.pool.QueryRow(ctx, p.qry.GetMessage(ctx), id).Scan(&payload)
+	return payload, err
+}
+
+// purgeIfDue removes stored large messages every retention period
+func (p *Postgres) purgeIfDue(ctx context.Context) {
+	p.mu.Lock()
+	due := time.Since(p.lastPurge) > p.cfg.Retention
+	if due {
+		p.lastPurge = time.Now()
+	}
+	p.mu.Unlock()
+
+	if !due {
+		return
+	}
+
+	if _, err := p.pool.Exec(ctx, p.qry.PurgeMessages(ctx), int(p.cfg.Retention.Seconds())); err != nil {
+		log.Error().Err(err).Msg("Failed to purge backplane messages")
+	}
+}
@@ -0,0 +1,87 @@
+package backplane
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/websocket"
+)
+
+// TestPostgres_TwoHubs runs two hubs against one database, as two app instances
+// behind the load balancer would, and checks a broadcast on one reaches the other.
+// Set TEST_DATABASE_URL to a migrated database to run it.
+func TestPostgres_TwoHubs(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	cfg := &config.BackplaneConfig{
+		Channel:      "tenangantri_hub_test",
+		Retention:    time.Minute,
+		ReconnectMin: 100 * time.Millisecond,
+		ReconnectMax: time.Second,
+	}
+
+	dialA := startInstance(t, ctx, pool, cfg)
+	dialB := startInstance(t, ctx, pool, cfg)
+	connA, hubA := dialA()
+	connB, _ := dialB()
+
+	// Give both LISTEN sessions a moment to start
+	time.Sleep(300 * time.Millisecond)
+
+	hubA.Publish([]string{websocket.TopicDisplayAll}, "ticket_update", map[string]string{"ticket_number": "A001"})
+	assert.Contains(t, readMessage(t, connA), `"A001"`)
+	assert.Contains(t, readMessage(t, connB), `"A001"`)
+
+	// Larger than a NOTIFY payload allows, so it travels through backplane_messages
+	large := strings.Repeat("x", 10000)
+	hubA.Publish([]string{websocket.TopicDisplayAll}, "display_update", map[string]string{"blob": large})
+	assert.Contains(t, readMessage(t, connA), large)
+	assert.Contains(t, readMessage(t, connB), large)
+}
+
+func startInstance(t *testing.T, ctx context.Context, pool *pgxpool.Pool, cfg *config.BackplaneConfig) func() (*gorilla.Conn, *websocket.Hub) {
+	hub := websocket.NewHub(&config.WebSocketConfig{})
+	go hub.Run()
+	go hub.UseBackplane(ctx, NewPostgres(pool, cfg))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		websocket.ServeWs(hub, w, r, websocket.Identity{})
+	}))
+	t.Cleanup(server.Close)
+
+	return func() (*gorilla.Conn, *websocket.Hub) {
+		conn, _, err := gorilla.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?topics="+websocket.TopicDisplayAll, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		assert.Contains(t, readMessage(t, conn), `"hello"`)
+		return conn, hub
+	}
+}
+
+func readMessage(t *testing.T, conn *gorilla.Conn) string {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	return string(msg)
+}
@@ -1,15 +1,30 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	WebPush   WebPushConfig
+	Webhook   WebhookConfig
+	Backplane BackplaneConfig
+	WebSocket WebSocketConfig
+	Announce  AnnounceConfig
+	Calls     CallPolicyConfig
+	Shifts    ShiftConfig
+	SLA       SLAConfig
+	Alerts    AlertConfig
+	Signage   SignageConfig
+	Devices   DeviceConfig
+	Printer   PrinterConfig
+	Reports   ReportConfig
+	Mail      MailConfig
 }
 
 type ServerConfig struct {
@@ -34,6 +49,144 @@ type JWTConfig struct {
 	EnablePassword    bool
 }
 
+type WebPushConfig struct {
+	Subject       string
+	PrivateKey    string
+	KeyFile       string
+	NearlyUpAhead int
+	TTL           time.Duration
+}
+
+type WebhookConfig struct {
+	PollInterval    time.Duration
+	BatchSize       int
+	MaxAttempts     int
+	BackoffBase     time.Duration
+	BackoffMax      time.Duration
+	Timeout         time.Duration
+	OutboxRetention time.Duration
+}
+
+type WebSocketConfig struct {
+	AllowedOrigins []string
+	TokenTTL       time.Duration
+	ReplayBuffer   int
+	PingInterval   time.Duration
+	BroadcastQueue int
+	ClientBuffer   int
+	// Branch names this site in every realtime message
+	Branch string
+}
+
+type AnnounceConfig struct {
+	// ClipsDir holds one recorded clip pack per language
+	ClipsDir string
+	// Languages are announced in this order, each pack played once
+	Languages []string
+	Gap       time.Duration
+}
+
+// CallPolicyConfig decides when an unanswered call becomes a no-show
+type CallPolicyConfig struct {
+	// NoShowAfterRecalls is how many unanswered recalls make a ticket a no-show; zero turns this trigger off
+	NoShowAfterRecalls int
+	// NoShowTimeout is how long the last call may go unanswered before the ticket is a no-show;
+	// zero turns this trigger off
+	NoShowTimeout time.Duration
+}
+
+// ShiftConfig decides how staff activity is grouped into shifts
+type ShiftConfig struct {
+	// IdleGap ends a shift when its staff member has done nothing for this long
+	IdleGap time.Duration
+}
+
+// SLAConfig controls how tickets are checked against their category's SLA targets
+type SLAConfig struct {
+	// WarningPercent of a target flags a ticket as approaching it
+	WarningPercent int
+	// CheckInterval is how often live tickets are checked for breaches
+	CheckInterval time.Duration
+}
+
+// AlertConfig controls how often queue alert rules are checked
+type AlertConfig struct {
+	// CheckInterval is how often the rules are evaluated against the live queue
+	CheckInterval time.Duration
+}
+
+type SignageConfig struct {
+	// MediaDir stores uploaded signage images and videos
+	MediaDir      string
+	MaxUploadSize int64
+	// CacheMaxAge is how long displays may keep a media file; files never change once stored
+	CacheMaxAge time.Duration
+}
+
+// DeviceConfig controls device pairing, heartbeats and silent-device alerts
+type DeviceConfig struct {
+	// PairingTTL is how long a pairing code can be used
+	PairingTTL        time.Duration
+	HeartbeatInterval time.Duration
+	// SilentAfter is how long a paired device may go without a heartbeat before it counts as offline
+	SilentAfter time.Duration
+	// OpeningHours ("08:00-17:00") and OpeningDays (0 = Sunday) bound when silent devices raise alerts
+	OpeningHours string
+	OpeningDays  []string
+}
+
+// PrinterConfig controls tickets the server prints as ESC/POS for kiosk profiles with a printer
+type PrinterConfig struct {
+	// LogoPath is a PNG or JPEG printed at the top of every ticket; empty prints none
+	LogoPath string
+	// PaperWidth is the printable width in dots: 576 for 80 mm paper, 384 for 58 mm
+	PaperWidth int
+	// SpoolDir holds the spool files of profiles that print to a file
+	SpoolDir string
+	// Timeout bounds one print job; a kiosk shows the ticket on screen when it runs out
+	Timeout time.Duration
+	// PublicURL is the address the ticket QR code links to; empty uses the kiosk's own
+	PublicURL string
+}
+
+type ReportConfig struct {
+	// PDFMaxTickets caps the ticket appendix of a PDF report; the totals always cover every ticket
+	PDFMaxTickets int
+	// CSVDelimiter separates CSV export fields: comma, semicolon or tab
+	CSVDelimiter string
+	// CSVEncoding is utf-8, or utf-8-bom for spreadsheets that need the BOM to read UTF-8
+	CSVEncoding string
+	// ScheduleDir holds the files of scheduled report runs, kept for download from the run history
+	ScheduleDir string
+	// ScheduleInterval is how often due schedules and deliveries are checked
+	ScheduleInterval time.Duration
+	// ScheduleMaxAttempts is how often a run's delivery is tried before it is marked failed
+	ScheduleMaxAttempts int
+	ScheduleBackoffBase time.Duration
+	ScheduleBackoffMax  time.Duration
+	// PublicURL is the address of the app used for download links in report emails
+	PublicURL string
+}
+
+// MailConfig is the SMTP server outgoing email goes through; an empty Host disables email
+type MailConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	// From is the sender, either an address or "Name <address>"
+	From    string
+	Timeout time.Duration
+}
+
+type BackplaneConfig struct {
+	Driver       string
+	Channel      string
+	Retention    time.Duration
+	ReconnectMin time.Duration
+	ReconnectMax time.Duration
+}
+
 func Load() (*Config, error) {
 
 	viper.AddConfigPath(".")
@@ -54,6 +207,67 @@ func Load() (*Config, error) {
 	viper.SetDefault("JWT_SECRET", "your-secret-key-change-in-production")
 	viper.SetDefault("JWT_ACCESS_TOKEN_EXPIRY", "24h")
 	viper.SetDefault("ENABLE_PASSWORD", true)
+	viper.SetDefault("WEBPUSH_SUBJECT", "mailto:admin@tenangantri.local")
+	viper.SetDefault("WEBPUSH_VAPID_PRIVATE_KEY", "")
+	viper.SetDefault("WEBPUSH_KEY_FILE", "data/vapid_private_key")
+	viper.SetDefault("WEBPUSH_NEARLY_UP_AHEAD", 3)
+	viper.SetDefault("WEBPUSH_TTL", "30m")
+	viper.SetDefault("WEBHOOK_POLL_INTERVAL", "5s")
+	viper.SetDefault("WEBHOOK_BATCH_SIZE", 50)
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOK_BACKOFF_BASE", "30s")
+	viper.SetDefault("WEBHOOK_BACKOFF_MAX", "1h")
+	viper.SetDefault("WEBHOOK_TIMEOUT", "10s")
+	viper.SetDefault("WEBHOOK_OUTBOX_RETENTION", "168h")
+	viper.SetDefault("WS_ALLOWED_ORIGINS", "")
+	viper.SetDefault("WS_TOKEN_TTL", "60s")
+	viper.SetDefault("WS_REPLAY_BUFFER", 512)
+	viper.SetDefault("WS_PING_INTERVAL", "25s")
+	viper.SetDefault("WS_BROADCAST_QUEUE", 1024)
+	viper.SetDefault("WS_CLIENT_BUFFER", 256)
+	viper.SetDefault("BRANCH_ID", "main")
+	viper.SetDefault("ANNOUNCE_CLIPS_DIR", "web/audio")
+	viper.SetDefault("ANNOUNCE_LANGUAGES", "id,en")
+	viper.SetDefault("ANNOUNCE_GAP", "150ms")
+	viper.SetDefault("NO_SHOW_AFTER_RECALLS", 2)
+	viper.SetDefault("NO_SHOW_TIMEOUT", "0")
+	viper.SetDefault("SHIFT_IDLE_GAP", "2h")
+	viper.SetDefault("SLA_WARNING_PERCENT", 80)
+	viper.SetDefault("SLA_CHECK_INTERVAL", "15s")
+	viper.SetDefault("ALERT_CHECK_INTERVAL", "30s")
+	viper.SetDefault("SIGNAGE_MEDIA_DIR", "data/signage")
+	viper.SetDefault("SIGNAGE_MAX_UPLOAD_MB", 200)
+	viper.SetDefault("SIGNAGE_CACHE_MAX_AGE", "720h")
+	viper.SetDefault("DEVICE_PAIRING_TTL", "15m")
+	viper.SetDefault("DEVICE_HEARTBEAT_INTERVAL", "30s")
+	viper.SetDefault("DEVICE_SILENT_AFTER", "2m")
+	viper.SetDefault("DEVICE_OPENING_HOURS", "08:00-17:00")
+	viper.SetDefault("DEVICE_OPENING_DAYS", "1,2,3,4,5,6")
+	viper.SetDefault("PRINTER_LOGO", "")
+	viper.SetDefault("PRINTER_PAPER_WIDTH", 576)
+	viper.SetDefault("PRINTER_SPOOL_DIR", "data/spool")
+	viper.SetDefault("PRINTER_TIMEOUT", "5s")
+	viper.SetDefault("PRINTER_PUBLIC_URL", "")
+	viper.SetDefault("REPORT_PDF_MAX_TICKETS", 5000)
+	viper.SetDefault("REPORT_CSV_DELIMITER", "comma")
+	viper.SetDefault("REPORT_CSV_ENCODING", "utf-8-bom")
+	viper.SetDefault("REPORT_SCHEDULE_DIR", "data/reports")
+	viper.SetDefault("REPORT_SCHEDULE_INTERVAL", "30s")
+	viper.SetDefault("REPORT_SCHEDULE_MAX_ATTEMPTS", 5)
+	viper.SetDefault("REPORT_SCHEDULE_BACKOFF_BASE", "1m")
+	viper.SetDefault("REPORT_SCHEDULE_BACKOFF_MAX", "1h")
+	viper.SetDefault("REPORT_PUBLIC_URL", "http://localhost:8080")
+	viper.SetDefault("SMTP_HOST", "")
+	viper.SetDefault("SMTP_PORT", "587")
+	viper.SetDefault("SMTP_USERNAME", "")
+	viper.SetDefault("SMTP_PASSWORD", "")
+	viper.SetDefault("SMTP_FROM", "TenangAntri <noreply@tenangantri.local>")
+	viper.SetDefault("SMTP_TIMEOUT", "30s")
+	viper.SetDefault("BACKPLANE_DRIVER", "none")
+	viper.SetDefault("BACKPLANE_CHANNEL", "tenangantri_hub")
+	viper.SetDefault("BACKPLANE_RETENTION", "5m")
+	viper.SetDefault("BACKPLANE_RECONNECT_MIN", "1s")
+	viper.SetDefault("BACKPLANE_RECONNECT_MAX", "30s")
 
 	viper.AutomaticEnv()
 
@@ -77,9 +291,109 @@ func Load() (*Config, error) {
 			AccessTokenExpiry: viper.GetDuration("JWT_ACCESS_TOKEN_EXPIRY"),
 			EnablePassword:    viper.GetBool("ENABLE_PASSWORD"),
 		},
+		WebPush: WebPushConfig{
+			Subject:       viper.GetString("WEBPUSH_SUBJECT"),
+			PrivateKey:    viper.GetString("WEBPUSH_VAPID_PRIVATE_KEY"),
+			KeyFile:       viper.GetString("WEBPUSH_KEY_FILE"),
+			NearlyUpAhead: viper.GetInt("WEBPUSH_NEARLY_UP_AHEAD"),
+			TTL:           viper.GetDuration("WEBPUSH_TTL"),
+		},
+		Webhook: WebhookConfig{
+			PollInterval:    viper.GetDuration("WEBHOOK_POLL_INTERVAL"),
+			BatchSize:       viper.GetInt("WEBHOOK_BATCH_SIZE"),
+			MaxAttempts:     viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			BackoffBase:     viper.GetDuration("WEBHOOK_BACKOFF_BASE"),
+			BackoffMax:      viper.GetDuration("WEBHOOK_BACKOFF_MAX"),
+			Timeout:         viper.GetDuration("WEBHOOK_TIMEOUT"),
+			OutboxRetention: viper.GetDuration("WEBHOOK_OUTBOX_RETENTION"),
+		},
+		WebSocket: WebSocketConfig{
+			AllowedOrigins: splitList(viper.GetString("WS_ALLOWED_ORIGINS")),
+			TokenTTL:       viper.GetDuration("WS_TOKEN_TTL"),
+			ReplayBuffer:   viper.GetInt("WS_REPLAY_BUFFER"),
+			PingInterval:   viper.GetDuration("WS_PING_INTERVAL"),
+			BroadcastQueue: viper.GetInt("WS_BROADCAST_QUEUE"),
+			ClientBuffer:   viper.GetInt("WS_CLIENT_BUFFER"),
+			Branch:         viper.GetString("BRANCH_ID"),
+		},
+		Announce: AnnounceConfig{
+			ClipsDir:  viper.GetString("ANNOUNCE_CLIPS_DIR"),
+			Languages: splitList(viper.GetString("ANNOUNCE_LANGUAGES")),
+			Gap:       viper.GetDuration("ANNOUNCE_GAP"),
+		},
+		Calls: CallPolicyConfig{
+			NoShowAfterRecalls: viper.GetInt("NO_SHOW_AFTER_RECALLS"),
+			NoShowTimeout:      viper.GetDuration("NO_SHOW_TIMEOUT"),
+		},
+		Shifts: ShiftConfig{
+			IdleGap: viper.GetDuration("SHIFT_IDLE_GAP"),
+		},
+		SLA: SLAConfig{
+			WarningPercent: viper.GetInt("SLA_WARNING_PERCENT"),
+			CheckInterval:  viper.GetDuration("SLA_CHECK_INTERVAL"),
+		},
+		Alerts: AlertConfig{
+			CheckInterval: viper.GetDuration("ALERT_CHECK_INTERVAL"),
+		},
+		Signage: SignageConfig{
+			MediaDir:      viper.GetString("SIGNAGE_MEDIA_DIR"),
+			MaxUploadSize: viper.GetInt64("SIGNAGE_MAX_UPLOAD_MB") << 20,
+			CacheMaxAge:   viper.GetDuration("SIGNAGE_CACHE_MAX_AGE"),
+		},
+		Devices: DeviceConfig{
+			PairingTTL:        viper.GetDuration("DEVICE_PAIRING_TTL"),
+			HeartbeatInterval: viper.GetDuration("DEVICE_HEARTBEAT_INTERVAL"),
+			SilentAfter:       viper.GetDuration("DEVICE_SILENT_AFTER"),
+			OpeningHours:      viper.GetString("DEVICE_OPENING_HOURS"),
+			OpeningDays:       splitList(viper.GetString("DEVICE_OPENING_DAYS")),
+		},
+		Printer: PrinterConfig{
+			LogoPath:   viper.GetString("PRINTER_LOGO"),
+			PaperWidth: viper.GetInt("PRINTER_PAPER_WIDTH"),
+			SpoolDir:   viper.GetString("PRINTER_SPOOL_DIR"),
+			Timeout:    viper.GetDuration("PRINTER_TIMEOUT"),
+			PublicURL:  strings.TrimRight(viper.GetString("PRINTER_PUBLIC_URL"), "/"),
+		},
+		Reports: ReportConfig{
+			PDFMaxTickets:       viper.GetInt("REPORT_PDF_MAX_TICKETS"),
+			CSVDelimiter:        viper.GetString("REPORT_CSV_DELIMITER"),
+			CSVEncoding:         viper.GetString("REPORT_CSV_ENCODING"),
+			ScheduleDir:         viper.GetString("REPORT_SCHEDULE_DIR"),
+			ScheduleInterval:    viper.GetDuration("REPORT_SCHEDULE_INTERVAL"),
+			ScheduleMaxAttempts: viper.GetInt("REPORT_SCHEDULE_MAX_ATTEMPTS"),
+			ScheduleBackoffBase: viper.GetDuration("REPORT_SCHEDULE_BACKOFF_BASE"),
+			ScheduleBackoffMax:  viper.GetDuration("REPORT_SCHEDULE_BACKOFF_MAX"),
+			PublicURL:           strings.TrimRight(viper.GetString("REPORT_PUBLIC_URL"), "/"),
+		},
+		Mail: MailConfig{
+			Host:     viper.GetString("SMTP_HOST"),
+			Port:     viper.GetString("SMTP_PORT"),
+			Username: viper.GetString("SMTP_USERNAME"),
+			Password: viper.GetString("SMTP_PASSWORD"),
+			From:     viper.GetString("SMTP_FROM"),
+			Timeout:  viper.GetDuration("SMTP_TIMEOUT"),
+		},
+		Backplane: BackplaneConfig{
+			Driver:       viper.GetString("BACKPLANE_DRIVER"),
+			Channel:      viper.GetString("BACKPLANE_CHANNEL"),
+			Retention:    viper.GetDuration("BACKPLANE_RETENTION"),
+			ReconnectMin: viper.GetDuration("BACKPLANE_RECONNECT_MIN"),
+			ReconnectMax: viper.GetDuration("BACKPLANE_RECONNECT_MAX"),
+		},
 	}, nil
 }
 
+// splitList parses a comma separated env value, ignoring blanks
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 func (c *Config) GetDatabaseURL() string {
 	return "postgres://" + c.Database.User + ":" + c.Database.Password + "@" + c.Database.Host + ":" + c.Database.Port + "/" + c.Database.Name + "?sslmode=" + c.Database.SSLMode
 }
@@ -0,0 +1,57 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	// A Monday
+	from := time.Date(2026, 10, 19, 7, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 7 * * *", time.Date(2026, 10, 20, 7, 0, 0, 0, time.UTC)},
+		{"45 7 * * *", time.Date(2026, 10, 19, 7, 45, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2026, 10, 19, 7, 40, 0, 0, time.UTC)},
+		{"0 8 * * 1-5", time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)},
+		{"0 6 * * 6,7", time.Date(2026, 10, 24, 6, 0, 0, 0, time.UTC)},
+		{"0 6 * * 0", time.Date(2026, 10, 25, 6, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 10, 26, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Restricted day of month and day of week match either
+		{"0 9 1 * 3", time.Date(2026, 10, 21, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, s.Next(from), tt.expr)
+	}
+}
+
+func TestNext_IsStrictlyAfter(t *testing.T) {
+	s, err := Parse("0 7 * * *")
+	require.NoError(t, err)
+
+	at := time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC)
+	assert.Equal(t, at.AddDate(0, 0, 1), s.Next(at))
+}
+
+func TestNext_Never(t *testing.T) {
+	s, err := Parse("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, s.Next(time.Now()).IsZero())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
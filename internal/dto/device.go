@@ -0,0 +1,44 @@
+package dto
+
+// DeviceRequest creates or updates a kiosk or display. DisplayProfileID only applies to
+// displays and KioskProfileID to kiosks.
+type DeviceRequest struct {
+	Name             string `json:"name" binding:"required,max=100"`
+	Kind             string `json:"kind" binding:"required,oneof=kiosk display"`
+	DisplayProfileID *int   `json:"display_profile_id"`
+	KioskProfileID   *int   `json:"kiosk_profile_id"`
+}
+
+// DeviceCommandRequest is a remote action an admin sends to a device
+type DeviceCommandRequest struct {
+	Action string `json:"action" binding:"required,oneof=reload identify"`
+}
+
+// DevicePairRequest is sent by a device entering its pairing code
+type DevicePairRequest struct {
+	Code string `json:"code" binding:"required,max=16"`
+}
+
+// DevicePairResponse hands a newly paired device its token. The token is shown once.
+type DevicePairResponse struct {
+	Token  string     `json:"token"`
+	Device DeviceInfo `json:"device"`
+}
+
+// DeviceHeartbeatRequest is what a device reports about itself. Uptime is how long the
+// browser tab has been running and LastEventSeq the last realtime sequence it received.
+type DeviceHeartbeatRequest struct {
+	Version       string `json:"version" binding:"max=50"`
+	UptimeSeconds int64  `json:"uptime_seconds" binding:"min=0"`
+	LastEventSeq  int64  `json:"last_event_seq" binding:"min=0"`
+	URL           string `json:"url" binding:"max=255"`
+}
+
+// DeviceInfo tells a device who it is and what it should show
+type DeviceInfo struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	Kind              string `json:"kind"`
+	HomeURL           string `json:"home_url"`
+	HeartbeatInterval int    `json:"heartbeat_interval"`
+}
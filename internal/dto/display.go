@@ -0,0 +1,28 @@
+package dto
+
+import "tenangantri/internal/model"
+
+// DisplayProfileRequest creates or updates a display profile
+type DisplayProfileRequest struct {
+	Slug            string `json:"slug" binding:"required,max=50"`
+	Name            string `json:"name" binding:"required,max=100"`
+	Layout          string `json:"layout" binding:"required,oneof=landscape portrait"`
+	CategoryIDs     []int  `json:"category_ids"`
+	CounterIDs      []int  `json:"counter_ids"`
+	RecentCount     int    `json:"recent_count" binding:"required,min=1,max=50"`
+	ShowWaiting     bool   `json:"show_waiting"`
+	AccentColor     string `json:"accent_color" binding:"required"`
+	BackgroundColor string `json:"background_color" binding:"required"`
+	TickerText      string `json:"ticker_text"`
+	IsActive        *bool  `json:"is_active"`
+}
+
+// DisplayScreen is everything a profile's board renders on first load
+type DisplayScreen struct {
+	Profile  *model.DisplayProfile
+	Tickets  []DisplayTicket
+	Waiting  []CategoryQueueStats
+	Counters []model.Counter
+	// Topics are the hub topics the board subscribes to
+	Topics []string
+}
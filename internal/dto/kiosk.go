@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"slices"
+
+	"tenangantri/internal/model"
+)
+
+// KioskProfileRequest creates or updates a kiosk profile and replaces its categories
+type KioskProfileRequest struct {
+	Slug         string `json:"slug" binding:"required,max=50"`
+	Name         string `json:"name" binding:"required,max=100"`
+	Language     string `json:"language" binding:"required,oneof=id en"`
+	TicketOutput string `json:"ticket_output" binding:"required,oneof=print qr both"`
+	PrinterType  string `json:"printer_type" binding:"omitempty,oneof=browser network file"`
+	// PrinterTarget is host[:port] for a network printer or a file name in the spool directory
+	PrinterTarget string                        `json:"printer_target" binding:"max=255"`
+	IdleSeconds   int                           `json:"idle_seconds" binding:"min=0,max=3600"`
+	AttractTitle  string                        `json:"attract_title" binding:"max=100"`
+	AttractText   string                        `json:"attract_text" binding:"max=500"`
+	Categories    []KioskProfileCategoryRequest `json:"categories" binding:"dive"`
+	IsActive      *bool                         `json:"is_active"`
+}
+
+// KioskProfileCategoryRequest is one service button; empty label or icon use the category's own
+type KioskProfileCategoryRequest struct {
+	CategoryID int    `json:"category_id" binding:"required"`
+	Label      string `json:"label" binding:"max=100"`
+	Icon       string `json:"icon" binding:"max=50"`
+}
+
+// KioskScreen is what a kiosk offers. Profile is nil for the default kiosk at /kiosk.
+// Categories are in button order and carry the profile's labels and icons.
+type KioskScreen struct {
+	Profile    *model.KioskProfile
+	Categories []model.Category
+}
+
+// Offers reports whether the kiosk issues tickets for a category
+func (s *KioskScreen) Offers(categoryID int) bool {
+	return slices.ContainsFunc(s.Categories, func(category model.Category) bool {
+		return category.ID == categoryID
+	})
+}
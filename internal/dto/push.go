@@ -0,0 +1,33 @@
+package dto
+
+// PushSubscribeRequest carries a browser PushSubscription for a tracked ticket
+type PushSubscribeRequest struct {
+	TicketNumber string           `json:"ticket_number" binding:"required"`
+	Subscription PushSubscription `json:"subscription" binding:"required"`
+}
+
+// PushUnsubscribeRequest removes a browser subscription from a ticket
+type PushUnsubscribeRequest struct {
+	TicketNumber string `json:"ticket_number" binding:"required"`
+	Endpoint     string `json:"endpoint" binding:"required"`
+}
+
+// PushSubscription mirrors the JSON form of the browser PushSubscription object
+type PushSubscription struct {
+	Endpoint string               `json:"endpoint" binding:"required,url,startswith=https://"`
+	Keys     PushSubscriptionKeys `json:"keys" binding:"required"`
+}
+
+// PushSubscriptionKeys holds the client's encryption keys
+type PushSubscriptionKeys struct {
+	P256dh string `json:"p256dh" binding:"required"`
+	Auth   string `json:"auth" binding:"required"`
+}
+
+// PushMessage is the notification payload the service worker displays
+type PushMessage struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url"`
+	Tag   string `json:"tag"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+// AlertRuleRequest creates or updates a queue alert rule. Without a category the rule
+// watches every category; without recipients its alerts are not emailed.
+type AlertRuleRequest struct {
+	Name            string   `json:"name" binding:"required"`
+	Kind            string   `json:"kind" binding:"required,oneof=waiting_count oldest_wait no_open_counter"`
+	CategoryID      int      `json:"category_id"`
+	Threshold       int      `json:"threshold" binding:"min=0"`
+	CooldownMinutes *int     `json:"cooldown_minutes" binding:"omitempty,min=0"`
+	EmailRecipients []string `json:"email_recipients"`
+	NotifyWebhook   bool     `json:"notify_webhook"`
+	IsActive        *bool    `json:"is_active"`
+}
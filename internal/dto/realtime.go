@@ -0,0 +1,306 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"tenangantri/internal/model"
+)
+
+// RealtimeSchemaVersion is sent with every realtime message. It only changes when a
+// payload changes incompatibly; new optional fields keep the same version.
+const RealtimeSchemaVersion = 1
+
+// Realtime message types
+const (
+	RealtimeTicketUpdate    = "ticket_update"
+	RealtimeStatsUpdate     = "stats_update"
+	RealtimeCounterUpdate   = "counter_update"
+	RealtimeCounterCreated  = "counter_created"
+	RealtimeCounterUpdated  = "counter_updated"
+	RealtimeCounterDeleted  = "counter_deleted"
+	RealtimeCategoryCreated = "category_created"
+	RealtimeCategoryUpdated = "category_updated"
+	RealtimeCategoryDeleted = "category_deleted"
+	RealtimeTicketsReset    = "yesterday_tickets_reset"
+	RealtimeDisplayReload   = "display_reload"
+	RealtimeKioskReload     = "kiosk_reload"
+	RealtimeDeviceCommand   = "device_command"
+	RealtimeDeviceAlert     = "device_alert"
+	RealtimeSLABreach       = "sla_breach"
+	RealtimeQueueAlert      = "queue_alert"
+	RealtimeHello           = "hello"
+	RealtimeResync          = "resync"
+	RealtimeSubscribed      = "subscribed"
+	RealtimeError           = "error"
+)
+
+// RealtimeEnvelope wraps every message sent over /ws and /events
+type RealtimeEnvelope struct {
+	// ID is the same for a message on every instance; replies to one client have none
+	ID        string    `json:"id,omitempty"`
+	Type      string    `json:"type"`
+	Version   int       `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+	Branch    string    `json:"branch"`
+	// Seq is the stream position on the instance the client is connected to
+	Seq     uint64          `json:"seq,omitempty"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// TicketEvent is a ticket as realtime clients see it. Anonymous clients get the
+// Public copy, without the staff-only fields.
+type TicketEvent struct {
+	TicketNumber string `json:"ticket_number"`
+	Status       string `json:"status"`
+	CategoryID   int    `json:"category_id,omitempty"`
+	CounterID    int    `json:"counter_id,omitempty"`
+	// Announcement is the URL of the spoken call, set when a ticket is called,
+	// recalled or transferred to a counter
+	Announcement string `json:"announcement,omitempty"`
+
+	ID            int        `json:"id,omitempty"`
+	Priority      int        `json:"priority,omitempty"`
+	DailySequence int        `json:"daily_sequence,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	CalledAt      *time.Time `json:"called_at,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	WaitTime      *int64     `json:"wait_time,omitempty"`
+	ServiceTime   *int64     `json:"service_time,omitempty"`
+	Notes         string     `json:"notes,omitempty"`
+}
+
+// NewTicketEvent flattens a ticket's nullable columns into plain fields
+func NewTicketEvent(ticket *model.Ticket) TicketEvent {
+	e := TicketEvent{
+		TicketNumber:  ticket.TicketNumber,
+		Status:        ticket.Status,
+		ID:            ticket.ID,
+		Priority:      ticket.Priority,
+		DailySequence: ticket.DailySequence,
+		Notes:         ticket.Notes.String,
+	}
+	if ticket.CategoryID.Valid {
+		e.CategoryID = int(ticket.CategoryID.Int64)
+	}
+	if ticket.CounterID.Valid {
+		e.CounterID = int(ticket.CounterID.Int64)
+	}
+	if !ticket.CreatedAt.IsZero() {
+		e.CreatedAt = &ticket.CreatedAt
+	}
+	if ticket.CalledAt.Valid {
+		e.CalledAt = &ticket.CalledAt.Time
+	}
+	if ticket.CompletedAt.Valid {
+		e.CompletedAt = &ticket.CompletedAt.Time
+	}
+	if ticket.WaitTime.Valid {
+		e.WaitTime = &ticket.WaitTime.Int64
+	}
+	if ticket.ServiceTime.Valid {
+		e.ServiceTime = &ticket.ServiceTime.Int64
+	}
+	return e
+}
+
+// Public strips the ticket down to what display boards and trackers may see
+func (e TicketEvent) Public() TicketEvent {
+	return TicketEvent{
+		TicketNumber: e.TicketNumber,
+		Status:       e.Status,
+		CategoryID:   e.CategoryID,
+		CounterID:    e.CounterID,
+		Announcement: e.Announcement,
+	}
+}
+
+// CounterEvent is a counter as realtime clients see it. Deletions only carry the ID.
+type CounterEvent struct {
+	ID       int    `json:"id"`
+	Number   string `json:"number,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Location string `json:"location,omitempty"`
+	Status   string `json:"status,omitempty"`
+}
+
+func NewCounterEvent(counter *model.Counter) CounterEvent {
+	return CounterEvent{
+		ID:       counter.ID,
+		Number:   counter.Number,
+		Name:     counter.Name.String,
+		Location: counter.Location.String,
+		Status:   counter.Status,
+	}
+}
+
+// CategoryEvent is a category as realtime clients see it. Deletions only carry the ID.
+type CategoryEvent struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name,omitempty"`
+	Prefix    string `json:"prefix,omitempty"`
+	ColorCode string `json:"color_code,omitempty"`
+	Priority  int    `json:"priority,omitempty"`
+	IsActive  bool   `json:"is_active"`
+}
+
+func NewCategoryEvent(category *model.Category) CategoryEvent {
+	return CategoryEvent{
+		ID:        category.ID,
+		Name:      category.Name,
+		Prefix:    category.Prefix,
+		ColorCode: category.ColorCode,
+		Priority:  category.Priority,
+		IsActive:  category.IsActive,
+	}
+}
+
+// TicketsResetEvent reports how many of yesterday's open tickets were closed
+type TicketsResetEvent struct {
+	Count int `json:"count"`
+}
+
+// DisplayReloadEvent tells the boards of a display profile to reload. Slug is the
+// profile's current slug, so boards follow a rename; it is empty after a deletion.
+type DisplayReloadEvent struct {
+	Slug string `json:"slug"`
+}
+
+// KioskReloadEvent tells the kiosks of a kiosk profile to reload. Slug is the profile's
+// current slug, so kiosks follow a rename; it is empty after a deletion.
+type KioskReloadEvent struct {
+	Slug string `json:"slug"`
+}
+
+// DeviceCommandEvent is a remote action for one device: reload, identify or navigate to URL
+type DeviceCommandEvent struct {
+	Action string `json:"action"`
+	URL    string `json:"url,omitempty"`
+}
+
+// DeviceAlertEvent reports a device that went silent during opening hours or came back
+type DeviceAlertEvent struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Kind       string     `json:"kind"`
+	Status     string     `json:"status"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+}
+
+// NewDeviceAlertEvent builds the alert payload for a device in the given status
+func NewDeviceAlertEvent(device *model.Device, status string) DeviceAlertEvent {
+	e := DeviceAlertEvent{ID: device.ID, Name: device.Name, Kind: device.Kind, Status: status}
+	if device.LastSeenAt.Valid {
+		e.LastSeenAt = &device.LastSeenAt.Time
+	}
+	return e
+}
+
+// SLABreachEvent reports a ticket that waited or was served longer than its category's target
+type SLABreachEvent struct {
+	TicketID       int    `json:"ticket_id"`
+	TicketNumber   string `json:"ticket_number"`
+	Status         string `json:"status"`
+	CategoryID     int    `json:"category_id,omitempty"`
+	CounterID      int    `json:"counter_id,omitempty"`
+	Kind           string `json:"kind"`
+	TargetSeconds  int    `json:"target_seconds"`
+	ElapsedSeconds int    `json:"elapsed_seconds"`
+}
+
+// NewSLABreachEvent builds the breach payload for a ticket
+func NewSLABreachEvent(ticket *model.Ticket, kind string, targetSeconds, elapsedSeconds int) SLABreachEvent {
+	return SLABreachEvent{
+		TicketID:       ticket.ID,
+		TicketNumber:   ticket.TicketNumber,
+		Status:         ticket.Status,
+		CategoryID:     int(ticket.CategoryID.Int64),
+		CounterID:      int(ticket.CounterID.Int64),
+		Kind:           kind,
+		TargetSeconds:  targetSeconds,
+		ElapsedSeconds: elapsedSeconds,
+	}
+}
+
+// QueueAlertEvent reports a queue alert rule that fired for a category. Value and
+// threshold are ticket counts, or minutes for kind oldest_wait.
+type QueueAlertEvent struct {
+	ID           int       `json:"id"`
+	RuleID       int       `json:"rule_id,omitempty"`
+	RuleName     string    `json:"rule_name"`
+	Kind         string    `json:"kind"`
+	CategoryID   int       `json:"category_id,omitempty"`
+	CategoryName string    `json:"category_name"`
+	Value        int       `json:"value"`
+	Threshold    int       `json:"threshold"`
+	Message      string    `json:"message"`
+	TriggeredAt  time.Time `json:"triggered_at"`
+}
+
+// NewQueueAlertEvent builds the payload for a recorded alert
+func NewQueueAlertEvent(alert *model.QueueAlert) QueueAlertEvent {
+	return QueueAlertEvent{
+		ID:           alert.ID,
+		RuleID:       int(alert.RuleID.Int64),
+		RuleName:     alert.RuleName,
+		Kind:         alert.Kind,
+		CategoryID:   int(alert.CategoryID.Int64),
+		CategoryName: alert.CategoryName,
+		Value:        alert.Value,
+		Threshold:    alert.Threshold,
+		Message:      alert.Message,
+		TriggeredAt:  alert.TriggeredAt,
+	}
+}
+
+// HelloEvent is sent on connect, after any replay, with the current stream position
+type HelloEvent struct {
+	Instance string `json:"instance"`
+	Seq      uint64 `json:"seq"`
+}
+
+// ResyncEvent tells a resuming client its gap can no longer be replayed
+type ResyncEvent struct {
+	Seq uint64 `json:"seq"`
+}
+
+// SubscribedEvent lists the client's topics after a subscribe or unsubscribe
+type SubscribedEvent struct {
+	Topics []string `json:"topics"`
+}
+
+// ErrorEvent reports a rejected client request
+type ErrorEvent struct {
+	Message string `json:"message"`
+}
+
+// RealtimeEventSpec documents one message type for the generated schema
+type RealtimeEventSpec struct {
+	Type        string
+	Description string
+	Payload     interface{}
+}
+
+// RealtimeEvents lists every message type clients can receive
+var RealtimeEvents = []RealtimeEventSpec{
+	{RealtimeTicketUpdate, "A ticket was issued, called, transferred or closed. Calls carry an announcement audio URL. Anonymous clients only get ticket_number, status, category_id, counter_id and announcement.", TicketEvent{}},
+	{RealtimeStatsUpdate, "Fresh dashboard numbers, sent after every ticket change.", DashboardStats{}},
+	{RealtimeCounterUpdate, "A counter changed status.", CounterEvent{}},
+	{RealtimeCounterCreated, "A counter was created.", CounterEvent{}},
+	{RealtimeCounterUpdated, "A counter was edited.", CounterEvent{}},
+	{RealtimeCounterDeleted, "A counter was deleted; only id is set.", CounterEvent{}},
+	{RealtimeCategoryCreated, "A category was created.", CategoryEvent{}},
+	{RealtimeCategoryUpdated, "A category was edited or switched on or off.", CategoryEvent{}},
+	{RealtimeCategoryDeleted, "A category was deleted; only id is set.", CategoryEvent{}},
+	{RealtimeTicketsReset, "Yesterday's open tickets were closed.", TicketsResetEvent{}},
+	{RealtimeDisplayReload, "A display profile changed; boards on its screen topic reload, following a new slug when set.", DisplayReloadEvent{}},
+	{RealtimeKioskReload, "A kiosk profile changed; kiosks on its kiosk topic reload, following a new slug when set.", KioskReloadEvent{}},
+	{RealtimeDeviceCommand, "A remote action for the device on a device topic: reload, identify, or navigate to url.", DeviceCommandEvent{}},
+	{RealtimeDeviceAlert, "A device went offline during opening hours or came back online; sent to admins.", DeviceAlertEvent{}},
+	{RealtimeSLABreach, "A ticket waited (kind wait) or was served (kind service) longer than its category's target; sent to staff of that category or counter and to admins.", SLABreachEvent{}},
+	{RealtimeQueueAlert, "A queue alert rule fired for a category: too many waiting tickets (kind waiting_count), a ticket waiting too long (oldest_wait) or no open counter (no_open_counter); sent on the alerts topic to supervisors and to admins.", QueueAlertEvent{}},
+	{RealtimeHello, "Sent on connect, after any replayed messages.", HelloEvent{}},
+	{RealtimeResync, "The missed messages are no longer available; reload current state.", ResyncEvent{}},
+	{RealtimeSubscribed, "The client's topics after a subscribe or unsubscribe request.", SubscribedEvent{}},
+	{RealtimeError, "A client request was rejected.", ErrorEvent{}},
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"fmt"
+
+	"tenangantri/internal/jsonschema"
+)
+
+//go:generate go run ../../cmd/realtime-schema -o ../../web/static/schema/realtime-v1.json
+
+// RealtimeSchema describes the envelope and the payload of every realtime message
+// type, derived from the Go types above so the published contract cannot drift
+func RealtimeSchema() jsonschema.Schema {
+	reflector := jsonschema.NewReflector()
+	envelope := reflector.Reflect(RealtimeEnvelope{})
+
+	types := make([]string, 0, len(RealtimeEvents))
+	payloads := make([]jsonschema.Schema, 0, len(RealtimeEvents))
+	for _, spec := range RealtimeEvents {
+		types = append(types, spec.Type)
+		payloads = append(payloads, jsonschema.Schema{
+			"description": spec.Description,
+			"if": jsonschema.Schema{
+				"properties": jsonschema.Schema{"type": jsonschema.Schema{"const": spec.Type}},
+			},
+			"then": jsonschema.Schema{
+				"properties": jsonschema.Schema{"payload": reflector.Reflect(spec.Payload)},
+			},
+		})
+	}
+
+	envelopeDef := reflector.Defs["RealtimeEnvelope"]
+	envelopeDef["properties"].(jsonschema.Schema)["type"] = jsonschema.Schema{"enum": types}
+	envelopeDef["properties"].(jsonschema.Schema)["version"] = jsonschema.Schema{"const": RealtimeSchemaVersion}
+
+	return jsonschema.Schema{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"$id":         fmt.Sprintf("/static/schema/realtime-v%d.json", RealtimeSchemaVersion),
+		"title":       "TenangAntri realtime message",
+		"description": "One message from /ws or /events. WebSocket frames may hold several messages separated by newlines.",
+		"allOf":       append([]jsonschema.Schema{envelope}, payloads...),
+		"$defs":       reflector.Defs,
+	}
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/model"
+)
+
+func TestNewTicketEvent(t *testing.T) {
+	called := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	ticket := &model.Ticket{
+		ID:           10,
+		TicketNumber: "A010",
+		Status:       "serving",
+		CategoryID:   sql.NullInt64{Int64: 2, Valid: true},
+		CounterID:    sql.NullInt64{Int64: 3, Valid: true},
+		CalledAt:     sql.NullTime{Time: called, Valid: true},
+		WaitTime:     sql.NullInt64{Int64: 120, Valid: true},
+		Notes:        sql.NullString{String: "wheelchair", Valid: true},
+	}
+
+	data, err := json.Marshal(NewTicketEvent(ticket))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id": 10,
+		"ticket_number": "A010",
+		"status": "serving",
+		"category_id": 2,
+		"counter_id": 3,
+		"called_at": "2026-01-05T09:00:00Z",
+		"wait_time": 120,
+		"notes": "wheelchair"
+	}`, string(data))
+
+	data, err = json.Marshal(NewTicketEvent(ticket).Public())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ticket_number":"A010","status":"serving","category_id":2,"counter_id":3}`, string(data))
+}
+
+func TestNewCounterEvent(t *testing.T) {
+	data, err := json.Marshal(NewCounterEvent(&model.Counter{ID: 1, Number: "1", Name: sql.NullString{String: "Loket 1", Valid: true}, Status: "active"}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":1,"number":"1","name":"Loket 1","status":"active"}`, string(data))
+}
+
+// TestRealtimeSchema_UpToDate fails when the DTOs change without regenerating the
+// published schema: run go generate ./internal/dto/
+func TestRealtimeSchema_UpToDate(t *testing.T) {
+	want, err := json.MarshalIndent(RealtimeSchema(), "", "  ")
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("../../web/static/schema/realtime-v1.json")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(want)+"\n", string(got))
+}
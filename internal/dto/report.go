@@ -0,0 +1,27 @@
+package dto
+
+// ReportRequest holds the report filters of the reports page and the export links.
+// Dates are YYYY-MM-DD; the range defaults to the last seven days.
+type ReportRequest struct {
+	DateFrom   string `form:"date_from"`
+	DateTo     string `form:"date_to"`
+	CategoryID int    `form:"category_id"`
+	CounterID  int    `form:"counter_id"`
+	Status     string `form:"status" binding:"omitempty,oneof=waiting serving completed no_show cancelled"`
+}
+
+// CSVExportRequest holds the filters and format of a CSV export. An empty delimiter or
+// encoding falls back to the configured one.
+type CSVExportRequest struct {
+	ReportRequest
+	Delimiter string `form:"delimiter" binding:"omitempty,oneof=comma semicolon tab"`
+	Encoding  string `form:"encoding" binding:"omitempty,oneof=utf-8 utf-8-bom"`
+}
+
+// StaffPerformanceRequest holds the filters of the staff performance report. Dates are
+// YYYY-MM-DD; the range defaults to the last seven days.
+type StaffPerformanceRequest struct {
+	DateFrom string `form:"date_from"`
+	DateTo   string `form:"date_to"`
+	UserID   int    `form:"user_id"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+// ReportScheduleRequest creates or updates a report schedule
+type ReportScheduleRequest struct {
+	Name       string   `json:"name" binding:"required"`
+	ReportType string   `json:"report_type" binding:"required,oneof=daily weekly monthly"`
+	Format     string   `json:"format" binding:"required,oneof=pdf xlsx csv"`
+	CategoryID int      `json:"category_id"`
+	CounterID  int      `json:"counter_id"`
+	Status     string   `json:"status" binding:"omitempty,oneof=waiting serving completed no_show cancelled"`
+	Recipients []string `json:"recipients" binding:"required,min=1"`
+	Cron       string   `json:"cron" binding:"required"`
+	IsActive   *bool    `json:"is_active"`
+}
@@ -44,6 +44,9 @@ type CreateCategoryRequest struct {
 	ColorCode   string      `json:"color_code" form:"color_code"`
 	Description string      `json:"description" form:"description"`
 	Icon        string      `json:"icon" form:"icon"`
+	// SLA targets in minutes, 0 for none
+	SLAWaitMinutes    int `json:"sla_wait_minutes" form:"sla_wait_minutes" binding:"min=0"`
+	SLAServiceMinutes int `json:"sla_service_minutes" form:"sla_service_minutes" binding:"min=0"`
 }
 
 // UnmarshalJSON for CreateCategoryRequest to handle string priority
@@ -56,6 +59,8 @@ func (r *CreateCategoryRequest) UnmarshalJSON(data []byte) error {
 		ColorCode   string      `json:"color_code"`
 		Description string      `json:"description"`
 		Icon        string      `json:"icon"`
+		SLAWait     int         `json:"sla_wait_minutes"`
+		SLAService  int         `json:"sla_service_minutes"`
 	}{}
 
 	if err := json.Unmarshal(data, &aux); err != nil {
@@ -67,6 +72,8 @@ func (r *CreateCategoryRequest) UnmarshalJSON(data []byte) error {
 	r.ColorCode = aux.ColorCode
 	r.Description = aux.Description
 	r.Icon = aux.Icon
+	r.SLAWaitMinutes = aux.SLAWait
+	r.SLAServiceMinutes = aux.SLAService
 
 	// Handle priority conversion
 	switch v := aux.Priority.(type) {
@@ -116,7 +123,7 @@ type CreateUserRequest struct {
 	FullName  string `json:"full_name" form:"full_name" validate:"required"`
 	Email     string `json:"email" form:"email" validate:"email"`
 	Phone     string `json:"phone" form:"phone"`
-	Role      string `json:"role" form:"role" validate:"required,oneof=admin staff"`
+	Role      string `json:"role" form:"role" validate:"required,oneof=admin staff supervisor"`
 	CounterID *int   `json:"counter_id" form:"counter_id"`
 }
 
@@ -138,6 +145,6 @@ type UpdateUserRequest struct {
 	FullName  string `json:"full_name" form:"full_name"`
 	Email     string `json:"email" form:"email"`
 	Phone     string `json:"phone" form:"phone"`
-	Role      string `json:"role" form:"role" validate:"required,oneof=admin staff"`
+	Role      string `json:"role" form:"role" validate:"required,oneof=admin staff supervisor"`
 	CounterID *int   `json:"counter_id" form:"counter_id"`
 }
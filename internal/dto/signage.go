@@ -0,0 +1,40 @@
+package dto
+
+// SignagePlaylistRequest creates or updates a playlist and replaces its items
+type SignagePlaylistRequest struct {
+	Name  string                       `json:"name" binding:"required,max=100"`
+	Items []SignagePlaylistItemRequest `json:"items" binding:"dive"`
+}
+
+// SignagePlaylistItemRequest is one playlist entry; a zero duration plays a video to its end
+type SignagePlaylistItemRequest struct {
+	MediaID         int `json:"media_id" binding:"required"`
+	DurationSeconds int `json:"duration_seconds" binding:"min=0,max=3600"`
+}
+
+// SignageScheduleRequest creates or updates when a profile plays a playlist
+type SignageScheduleRequest struct {
+	DisplayProfileID int    `json:"display_profile_id" binding:"required"`
+	PlaylistID       int    `json:"playlist_id" binding:"required"`
+	DaysOfWeek       []int  `json:"days_of_week"`
+	StartTime        string `json:"start_time" binding:"required"`
+	EndTime          string `json:"end_time" binding:"required"`
+	Priority         int    `json:"priority"`
+	IsActive         *bool  `json:"is_active"`
+}
+
+// SignagePlayback is the playlist a display should loop right now. Items is empty when
+// no schedule applies.
+type SignagePlayback struct {
+	PlaylistID int                   `json:"playlist_id,omitempty"`
+	Name       string                `json:"name,omitempty"`
+	Items      []SignagePlaybackItem `json:"items"`
+}
+
+// SignagePlaybackItem is one media file to show. Duration is in seconds; zero plays a
+// video to its end.
+type SignagePlaybackItem struct {
+	URL      string `json:"url"`
+	Type     string `json:"type"`
+	Duration int    `json:"duration"`
+}
@@ -4,13 +4,15 @@ import "tenangantri/internal/model"
 
 // StaffDashboardResponse represents the staff dashboard data
 type StaffDashboardResponse struct {
-	User             *model.User          `json:"user"`
-	Counter          *model.Counter       `json:"counter"`
-	CurrentTicket    *model.Ticket        `json:"current_ticket"`
-	WaitingTickets   []model.Ticket       `json:"waiting_tickets"`
-	QueueStats       []CategoryQueueStats `json:"queue_stats"`
-	CompletedTickets []model.Ticket       `json:"completed_tickets"`
-	CategoryIDs      []int                `json:"category_ids"`
+	User             *model.User              `json:"user"`
+	Counter          *model.Counter           `json:"counter"`
+	CurrentTicket    *model.Ticket            `json:"current_ticket"`
+	CurrentCalls     *model.TicketCallSummary `json:"current_calls,omitempty"`
+	WaitingTickets   []model.Ticket           `json:"waiting_tickets"`
+	QueueStats       []CategoryQueueStats     `json:"queue_stats"`
+	CompletedTickets []model.Ticket           `json:"completed_tickets"`
+	CategoryIDs      []int                    `json:"category_ids"`
+	MyStats          *model.StaffPerformance  `json:"my_stats"`
 }
 
 // StaffQueueStatusResponse represents the queue status for staff
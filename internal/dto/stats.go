@@ -57,6 +57,7 @@ type DisplayTicket struct {
 	Status         string    `json:"status"`
 	DailySequence  int       `json:"daily_sequence"`
 	QueueDate      time.Time `json:"queue_date"`
+	CategoryID     int       `json:"category_id"`
 }
 
 // WebSocketMessage represents a WebSocket message
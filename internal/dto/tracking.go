@@ -5,6 +5,7 @@ import "time"
 // TrackingInfo contains comprehensive tracking information for a ticket
 type TrackingInfo struct {
 	TicketNumber                string    `json:"ticket_number"`
+	CategoryID                  int       `json:"category_id,omitempty"`
 	CategoryName                string    `json:"category_name"`
 	CategoryColor               string    `json:"category_color"`
 	Status                      string    `json:"status"`
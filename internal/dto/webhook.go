@@ -0,0 +1,18 @@
+package dto
+
+// WebhookSubscriptionRequest creates or updates a webhook subscription
+type WebhookSubscriptionRequest struct {
+	Name       string   `json:"name" binding:"required"`
+	URL        string   `json:"url" binding:"required,url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+	IsActive   *bool    `json:"is_active"`
+}
+
+// WebhookEvent is the JSON body posted to subscribers
+type WebhookEvent struct {
+	ID         int64       `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt string      `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
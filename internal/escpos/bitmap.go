@@ -0,0 +1,67 @@
+package escpos
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+)
+
+// Bitmap is a one-bit image packed for raster printing: rows of RowBytes bytes,
+// most significant bit leftmost, a set bit printed black
+type Bitmap struct {
+	Width  int
+	Height int
+	Data   []byte
+}
+
+// RowBytes is the number of bytes holding one row of dots
+func (b *Bitmap) RowBytes() int {
+	return (b.Width + 7) / 8
+}
+
+// NewBitmap converts img to black and white, scaled down to at most maxWidth dots.
+// Transparent pixels stay white.
+func NewBitmap(img image.Image, maxWidth int) *Bitmap {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if maxWidth > 0 && width > maxWidth {
+		height = height * maxWidth / width
+		width = maxWidth
+	}
+
+	bitmap := &Bitmap{Width: width, Height: height}
+	rowBytes := bitmap.RowBytes()
+	bitmap.Data = make([]byte, rowBytes*height)
+	for y := 0; y < height; y++ {
+		srcY := bounds.Min.Y + y*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			srcX := bounds.Min.X + x*bounds.Dx()/width
+			r, g, b, a := img.At(srcX, srcY).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// Rec. 601 luma on 16-bit channels
+			if (299*r+587*g+114*b)/1000 < 0x8000 {
+				bitmap.Data[y*rowBytes+x/8] |= 0x80 >> (x % 8)
+			}
+		}
+	}
+	return bitmap
+}
+
+// LoadBitmap reads a PNG or JPEG file as a bitmap at most maxWidth dots wide
+func LoadBitmap(path string, maxWidth int) (*Bitmap, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return NewBitmap(img, maxWidth), nil
+}
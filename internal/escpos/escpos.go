@@ -0,0 +1,132 @@
+package escpos
+
+import "bytes"
+
+// ESC/POS control bytes
+const (
+	esc = 0x1b
+	gs  = 0x1d
+	lf  = 0x0a
+)
+
+// Alignment of the lines that follow
+type Alignment byte
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+// QR code error correction levels
+const (
+	QRCorrectionL byte = 48 + iota
+	QRCorrectionM
+	QRCorrectionQ
+	QRCorrectionH
+)
+
+// Builder writes an ESC/POS job. Text is sent in the printer's default code page,
+// so characters outside printable ASCII print as '?'.
+type Builder struct {
+	buf bytes.Buffer
+}
+
+// NewBuilder starts a job with the printer reset to its defaults
+func NewBuilder() *Builder {
+	b := &Builder{}
+	b.buf.Write([]byte{esc, '@'})
+	return b
+}
+
+// Align sets the alignment of the following lines
+func (b *Builder) Align(alignment Alignment) *Builder {
+	b.buf.Write([]byte{esc, 'a', byte(alignment)})
+	return b
+}
+
+// Bold turns emphasized text on or off
+func (b *Builder) Bold(on bool) *Builder {
+	var n byte
+	if on {
+		n = 1
+	}
+	b.buf.Write([]byte{esc, 'E', n})
+	return b
+}
+
+// Size sets the character magnification, 1 to 8 times in each direction
+func (b *Builder) Size(width, height int) *Builder {
+	width = min(max(width, 1), 8)
+	height = min(max(height, 1), 8)
+	b.buf.Write([]byte{gs, '!', byte(width-1)<<4 | byte(height-1)})
+	return b
+}
+
+// Line prints text followed by a line feed
+func (b *Builder) Line(text string) *Builder {
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			b.buf.WriteByte(lf)
+		case r < 0x20 || r > 0x7e:
+			b.buf.WriteByte('?')
+		default:
+			b.buf.WriteByte(byte(r))
+		}
+	}
+	b.buf.WriteByte(lf)
+	return b
+}
+
+// Feed advances the paper by n lines
+func (b *Builder) Feed(lines int) *Builder {
+	b.buf.Write([]byte{esc, 'd', byte(min(max(lines, 0), 255))})
+	return b
+}
+
+// Image prints a bitmap as a raster image
+func (b *Builder) Image(bitmap *Bitmap) *Builder {
+	if bitmap == nil || bitmap.Width == 0 || bitmap.Height == 0 {
+		return b
+	}
+	rowBytes := bitmap.RowBytes()
+	b.buf.Write([]byte{gs, 'v', '0', 0,
+		byte(rowBytes), byte(rowBytes >> 8),
+		byte(bitmap.Height), byte(bitmap.Height >> 8)})
+	b.buf.Write(bitmap.Data)
+	b.buf.WriteByte(lf)
+	return b
+}
+
+// QRCode has the printer draw a model 2 QR code for data. moduleSize is the width
+// of one module in dots, 1 to 16.
+func (b *Builder) QRCode(data string, moduleSize int, correction byte) *Builder {
+	if data == "" {
+		return b
+	}
+	b.qrFunction('A', '2', 0)
+	b.qrFunction('C', byte(min(max(moduleSize, 1), 16)))
+	b.qrFunction('E', correction)
+	b.qrFunction('P', append([]byte{'0'}, data...)...)
+	b.qrFunction('Q', '0')
+	return b
+}
+
+// qrFunction writes one GS ( k function of the QR code symbol
+func (b *Builder) qrFunction(fn byte, params ...byte) {
+	size := len(params) + 2
+	b.buf.Write([]byte{gs, '(', 'k', byte(size), byte(size >> 8), '1', fn})
+	b.buf.Write(params)
+}
+
+// Cut feeds the paper past the cutter and cuts it
+func (b *Builder) Cut() *Builder {
+	b.buf.Write([]byte{gs, 'V', 'A', 3})
+	return b
+}
+
+// Bytes returns the job
+func (b *Builder) Bytes() []byte {
+	return b.buf.Bytes()
+}
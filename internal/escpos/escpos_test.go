@@ -0,0 +1,249 @@
+package escpos
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// printedLine is one line of text as the printer would lay it out
+type printedLine struct {
+	Text   string
+	Width  int
+	Height int
+	Bold   bool
+}
+
+// printout is what a printer makes of a job
+type printout struct {
+	Lines  []printedLine
+	Images []Bitmap
+	QRCode string
+	Cut    bool
+}
+
+// decode interprets the subset of ESC/POS that Builder writes
+func decode(job []byte) (*printout, error) {
+	out := &printout{}
+	width, height, bold := 1, 1, false
+	var text []byte
+	var qrData string
+
+	need := func(i, n int) error {
+		if i+n > len(job) {
+			return fmt.Errorf("truncated command at %d", i)
+		}
+		return nil
+	}
+
+	for i := 0; i < len(job); {
+		switch job[i] {
+		case lf:
+			out.Lines = append(out.Lines, printedLine{Text: string(text), Width: width, Height: height, Bold: bold})
+			text = nil
+			i++
+		case esc:
+			if err := need(i, 2); err != nil {
+				return nil, err
+			}
+			switch job[i+1] {
+			case '@':
+				width, height, bold = 1, 1, false
+				i += 2
+			case 'a', 'd':
+				i += 3
+			case 'E':
+				if err := need(i, 3); err != nil {
+					return nil, err
+				}
+				bold = job[i+2] == 1
+				i += 3
+			default:
+				return nil, fmt.Errorf("unknown ESC %q at %d", job[i+1], i)
+			}
+		case gs:
+			if err := need(i, 3); err != nil {
+				return nil, err
+			}
+			switch job[i+1] {
+			case '!':
+				width, height = int(job[i+2]>>4)+1, int(job[i+2]&0x0f)+1
+				i += 3
+			case 'V':
+				out.Cut = true
+				i += 4
+			case 'v':
+				if err := need(i, 8); err != nil {
+					return nil, err
+				}
+				rowBytes := int(job[i+4]) | int(job[i+5])<<8
+				rows := int(job[i+6]) | int(job[i+7])<<8
+				if err := need(i+8, rowBytes*rows); err != nil {
+					return nil, err
+				}
+				out.Images = append(out.Images, Bitmap{Width: rowBytes * 8, Height: rows, Data: job[i+8 : i+8+rowBytes*rows]})
+				i += 8 + rowBytes*rows
+			case '(':
+				if err := need(i, 7); err != nil {
+					return nil, err
+				}
+				size := int(job[i+3]) | int(job[i+4])<<8
+				if err := need(i+5, size); err != nil {
+					return nil, err
+				}
+				params := job[i+7 : i+5+size]
+				switch job[i+6] {
+				case 'P':
+					qrData = string(params[1:])
+				case 'Q':
+					out.QRCode = qrData
+				}
+				i += 5 + size
+			default:
+				return nil, fmt.Errorf("unknown GS %q at %d", job[i+1], i)
+			}
+		default:
+			text = append(text, job[i])
+			i++
+		}
+	}
+	return out, nil
+}
+
+func (p *printout) line(text string) (printedLine, bool) {
+	i := slices.IndexFunc(p.Lines, func(line printedLine) bool { return line.Text == text })
+	if i < 0 {
+		return printedLine{}, false
+	}
+	return p.Lines[i], true
+}
+
+// listen accepts one job on a local TCP port, as a network printer would
+func listen(t *testing.T) (string, <-chan []byte) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	jobs := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		job, _ := io.ReadAll(conn)
+		jobs <- job
+	}()
+	return listener.Addr().String(), jobs
+}
+
+func TestTicket_PrintsOverNetwork(t *testing.T) {
+	logo := image.NewRGBA(image.Rect(0, 0, 16, 4))
+	for x := 0; x < 16; x++ {
+		logo.Set(x, 1, color.Black)
+	}
+
+	ticket := &Ticket{
+		Logo:     NewBitmap(logo, 576),
+		Title:    "Nomor Antrian",
+		Number:   "A001",
+		Category: "Poli Umum",
+		Details:  []string{"19/10/2026 08:15", "Orang di depan Anda: 3"},
+		QRCode:   "https://antri.example/track?ticket=A001",
+		QRLabel:  "Pindai untuk melacak antrian Anda",
+	}
+
+	addr, jobs := listen(t)
+	printer := &NetworkPrinter{Addr: addr, Timeout: time.Second}
+	require.NoError(t, printer.Print(context.Background(), ticket.Encode()))
+
+	var job []byte
+	select {
+	case job = <-jobs:
+	case <-time.After(2 * time.Second):
+		t.Fatal("printer received nothing")
+	}
+	out, err := decode(job)
+	require.NoError(t, err)
+
+	number, ok := out.line("A001")
+	require.True(t, ok, "ticket number not printed")
+	assert.Equal(t, printedLine{Text: "A001", Width: 4, Height: 4, Bold: true}, number)
+	category, ok := out.line("Poli Umum")
+	require.True(t, ok, "category not printed")
+	assert.Equal(t, 2, category.Width)
+	for _, text := range ticket.Details {
+		line, ok := out.line(text)
+		assert.True(t, ok, "detail %q not printed", text)
+		assert.Equal(t, printedLine{Text: text, Width: 1, Height: 1}, line)
+	}
+	_, ok = out.line(ticket.QRLabel)
+	assert.True(t, ok, "QR label not printed")
+	assert.Equal(t, ticket.QRCode, out.QRCode)
+
+	require.Len(t, out.Images, 1)
+	assert.Equal(t, 4, out.Images[0].Height)
+	assert.Equal(t, []byte{0xff, 0xff}, out.Images[0].Data[2:4], "second logo row should be black")
+	assert.True(t, out.Cut, "ticket was not cut")
+}
+
+func TestNetworkPrinter_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	printer := &NetworkPrinter{Addr: addr, Timeout: time.Second}
+	assert.Error(t, printer.Print(context.Background(), NewBuilder().Line("x").Bytes()))
+}
+
+func TestFilePrinter_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kiosk.prn")
+	printer := &FilePrinter{Path: path}
+	for _, number := range []string{"A001", "A002"} {
+		job := (&Ticket{Number: number, Category: "Poli Umum"}).Encode()
+		require.NoError(t, printer.Print(context.Background(), job))
+	}
+
+	spool, err := os.ReadFile(path)
+	require.NoError(t, err)
+	out, err := decode(spool)
+	require.NoError(t, err)
+	for _, number := range []string{"A001", "A002"} {
+		_, ok := out.line(number)
+		assert.True(t, ok, "spool is missing ticket %s", number)
+	}
+}
+
+func TestBuilder_ReplacesNonASCII(t *testing.T) {
+	out, err := decode(NewBuilder().Line("Café").Bytes())
+	require.NoError(t, err)
+	require.Len(t, out.Lines, 1)
+	assert.Equal(t, "Caf?", out.Lines[0].Text)
+}
+
+func TestNewBitmap_ScalesToWidth(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 100, 50))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	img.Set(0, 0, color.Black)
+
+	bitmap := NewBitmap(img, 40)
+	assert.Equal(t, 40, bitmap.Width)
+	assert.Equal(t, 20, bitmap.Height)
+	require.Len(t, bitmap.Data, 5*20)
+	assert.Equal(t, []byte{0x80, 0}, bitmap.Data[:2], "only the first dot should be black")
+}
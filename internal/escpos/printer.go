@@ -0,0 +1,64 @@
+package escpos
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// DefaultPort is the raw printing port of network receipt printers
+const DefaultPort = "9100"
+
+// Printer accepts ESC/POS jobs
+type Printer interface {
+	Print(ctx context.Context, job []byte) error
+}
+
+// NetworkPrinter sends jobs over a raw TCP connection
+type NetworkPrinter struct {
+	// Addr is host:port; a bare host uses DefaultPort
+	Addr string
+	// Timeout bounds connecting and writing one job; zero waits for ctx only
+	Timeout time.Duration
+}
+
+func (p *NetworkPrinter) Print(ctx context.Context, job []byte) error {
+	addr := p.Addr
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, DefaultPort)
+	}
+
+	dialer := net.Dialer{Timeout: p.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connect to printer %s: %w", addr, err)
+	}
+	defer conn.Close()
+
+	if p.Timeout > 0 {
+		conn.SetWriteDeadline(time.Now().Add(p.Timeout))
+	}
+	if _, err := conn.Write(job); err != nil {
+		return fmt.Errorf("send job to printer %s: %w", addr, err)
+	}
+	return conn.Close()
+}
+
+// FilePrinter appends jobs to a spool file or a local printer device
+type FilePrinter struct {
+	Path string
+}
+
+func (p *FilePrinter) Print(ctx context.Context, job []byte) error {
+	file, err := os.OpenFile(p.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("open printer spool: %w", err)
+	}
+	if _, err := file.Write(job); err != nil {
+		file.Close()
+		return fmt.Errorf("write printer spool %s: %w", p.Path, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,42 @@
+package escpos
+
+// Ticket is the content of a printed queue ticket, already in the kiosk's language
+type Ticket struct {
+	// Logo is printed at the top when set
+	Logo *Bitmap
+	// Title is the line above the number, e.g. "Nomor Antrian"
+	Title    string
+	Number   string
+	Category string
+	// Details are small lines under the category: date and time, people ahead
+	Details []string
+	// QRCode is encoded below the details when set, with QRLabel under it
+	QRCode  string
+	QRLabel string
+}
+
+// Encode renders the ticket as a complete job, ending with a cut
+func (t *Ticket) Encode() []byte {
+	b := NewBuilder().Align(AlignCenter)
+	if t.Logo != nil {
+		b.Image(t.Logo)
+	}
+	if t.Title != "" {
+		b.Line(t.Title)
+	}
+
+	b.Bold(true).Size(4, 4).Line(t.Number)
+	b.Size(2, 2).Line(t.Category)
+	b.Bold(false).Size(1, 1).Feed(1)
+	for _, detail := range t.Details {
+		b.Line(detail)
+	}
+
+	if t.QRCode != "" {
+		b.Feed(1).QRCode(t.QRCode, 6, QRCorrectionM)
+		if t.QRLabel != "" {
+			b.Line(t.QRLabel)
+		}
+	}
+	return b.Feed(3).Cut().Bytes()
+}
@@ -0,0 +1,17 @@
+package event
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor records the user performing the current request so subscribers such as
+// the audit log can attribute events without every service method taking a user ID
+func WithActor(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// ActorFromContext returns the acting user ID, or 0 when the action had no signed-in user
+func ActorFromContext(ctx context.Context) int {
+	userID, _ := ctx.Value(actorKey{}).(int)
+	return userID
+}
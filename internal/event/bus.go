@@ -0,0 +1,87 @@
+package event
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Publisher is what services depend on to emit events
+type Publisher interface {
+	Publish(ctx context.Context, e Event)
+}
+
+// Handler receives published events
+type Handler func(ctx context.Context, e Event)
+
+// Bus dispatches events synchronously, in subscription order, to every matching handler.
+// Handlers must be quick; anything slow (network calls) should hand off to a goroutine.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	all      []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Subscribe registers h for events with the given names
+func (b *Bus) Subscribe(h Handler, names ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, name := range names {
+		b.handlers[name] = append(b.handlers[name], h)
+	}
+}
+
+// SubscribeAll registers h for every event
+func (b *Bus) SubscribeAll(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.all = append(b.all, h)
+}
+
+// On registers a typed handler for one event type
+func On[T Event](b *Bus, h func(ctx context.Context, e T)) {
+	var zero T
+	b.Subscribe(func(ctx context.Context, e Event) {
+		if typed, ok := e.(T); ok {
+			h(ctx, typed)
+		}
+	}, zero.Name())
+}
+
+// Publish delivers e to its subscribers. The context is detached from cancellation
+// so handlers keep working after the HTTP request that caused the event has finished.
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers[e.Name()])+len(b.all))
+	handlers = append(handlers, b.handlers[e.Name()]...)
+	handlers = append(handlers, b.all...)
+	b.mu.RUnlock()
+
+	ctx = context.WithoutCancel(ctx)
+	for _, h := range handlers {
+		b.dispatch(ctx, h, e)
+	}
+}
+
+func (b *Bus) dispatch(ctx context.Context, h Handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().Interface("panic", r).Str("event", e.Name()).Msg("Event handler panicked")
+		}
+	}()
+	h(ctx, e)
+}
+
+// Nop discards every event; useful where a Publisher is required but nothing listens
+type Nop struct{}
+
+func (Nop) Publish(context.Context, Event) {}
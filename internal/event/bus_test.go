@@ -0,0 +1,61 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"tenangantri/internal/model"
+)
+
+func TestBus_PublishOrderAndFilter(t *testing.T) {
+	bus := NewBus()
+	var got []string
+
+	bus.Subscribe(func(ctx context.Context, e Event) { got = append(got, "first:"+e.Name()) }, NameTicketCalled)
+	On(bus, func(ctx context.Context, e TicketCalled) { got = append(got, "typed:"+e.Ticket.TicketNumber) })
+	bus.SubscribeAll(func(ctx context.Context, e Event) { got = append(got, "all:"+e.Name()) })
+
+	bus.Publish(context.Background(), TicketCalled{Ticket: &model.Ticket{TicketNumber: "A001"}})
+	bus.Publish(context.Background(), TicketsReset{Count: 2})
+
+	assert.Equal(t, []string{
+		"first:ticket.called",
+		"typed:A001",
+		"all:ticket.called",
+		"all:tickets.reset",
+	}, got)
+}
+
+func TestBus_HandlerPanicDoesNotStopOthers(t *testing.T) {
+	bus := NewBus()
+	called := false
+
+	bus.Subscribe(func(ctx context.Context, e Event) { panic("boom") }, NameTicketIssued)
+	bus.Subscribe(func(ctx context.Context, e Event) { called = true }, NameTicketIssued)
+
+	bus.Publish(context.Background(), TicketIssued{})
+
+	assert.True(t, called)
+}
+
+func TestBus_ContextOutlivesRequest(t *testing.T) {
+	bus := NewBus()
+	var handlerErr error
+
+	bus.SubscribeAll(func(ctx context.Context, e Event) { handlerErr = ctx.Err() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bus.Publish(ctx, TicketIssued{})
+
+	assert.NoError(t, handlerErr)
+}
+
+func TestTicketOf(t *testing.T) {
+	ticket := &model.Ticket{ID: 1}
+
+	assert.Equal(t, ticket, TicketOf(TicketCompleted{Ticket: ticket}))
+	assert.Nil(t, TicketOf(CounterStatusChanged{CounterID: 1}))
+}
@@ -0,0 +1,212 @@
+// Package event is an in-process bus for domain events published by the service layer.
+//
+// Services publish what happened; consumers such as the WebSocket hub, the stats
+// cache, web push, webhooks and the audit log subscribe to it. Handlers never
+// broadcast directly, so every state change reaches every consumer the same way.
+package event
+
+import (
+	"tenangantri/internal/model"
+)
+
+// Event is implemented by every domain event
+type Event interface {
+	Name() string
+}
+
+// Event names
+const (
+	NameTicketIssued          = "ticket.issued"
+	NameTicketCalled          = "ticket.called"
+	NameTicketRecalled        = "ticket.recalled"
+	NameTicketTransferred     = "ticket.transferred"
+	NameTicketCompleted       = "ticket.completed"
+	NameTicketNoShow          = "ticket.no_show"
+	NameTicketCancelled       = "ticket.cancelled"
+	NameTicketRequeued        = "ticket.requeued"
+	NameTicketSLABreached     = "ticket.sla_breached"
+	NameTicketsReset          = "tickets.reset"
+	NameCounterStatusChanged  = "counter.status_changed"
+	NameCounterChanged        = "counter.changed"
+	NameCategoryChanged       = "category.changed"
+	NameDisplayProfileChanged = "display_profile.changed"
+	NameKioskProfileChanged   = "kiosk_profile.changed"
+	NameDeviceCommand         = "device.command"
+	NameDeviceSilent          = "device.silent"
+	NameDeviceRecovered       = "device.recovered"
+	NameQueueAlertRaised      = "queue.alert_raised"
+)
+
+// Actions for CounterChanged, CategoryChanged, DisplayProfileChanged and KioskProfileChanged
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionDeleted = "deleted"
+)
+
+// TicketIssued is published when a ticket is created at the kiosk or by an admin
+type TicketIssued struct {
+	Ticket        *model.Ticket
+	QueuePosition int
+}
+
+// TicketCalled is published when a ticket is assigned to a counter and called
+type TicketCalled struct {
+	Ticket    *model.Ticket
+	CounterID int
+}
+
+// TicketRecalled is published when staff call the current ticket again
+type TicketRecalled struct {
+	Ticket    *model.Ticket
+	CounterID int
+}
+
+// TicketTransferred is published when a ticket is moved to another counter
+type TicketTransferred struct {
+	Ticket    *model.Ticket
+	CounterID int
+}
+
+// TicketCompleted is published when service for a ticket is finished
+type TicketCompleted struct {
+	Ticket    *model.Ticket
+	CounterID int
+}
+
+// TicketNoShow is published when a called customer did not show up
+type TicketNoShow struct {
+	Ticket    *model.Ticket
+	CounterID int
+}
+
+// TicketCancelled is published when a ticket is cancelled
+type TicketCancelled struct {
+	Ticket *model.Ticket
+}
+
+// TicketRequeued is published when an admin puts a ticket back to waiting
+type TicketRequeued struct {
+	Ticket *model.Ticket
+}
+
+// TicketSLABreached is published once when a ticket waits or is served longer than the
+// target of its category. Kind is model.SLAKindWait or model.SLAKindService.
+type TicketSLABreached struct {
+	Ticket         *model.Ticket
+	Kind           string
+	TargetSeconds  int
+	ElapsedSeconds int
+}
+
+// TicketsReset is published after yesterday's waiting tickets were cancelled in bulk
+type TicketsReset struct {
+	Count int
+}
+
+// CounterStatusChanged is published when a counter goes idle, serving, paused or offline
+type CounterStatusChanged struct {
+	CounterID int
+	Status    string
+}
+
+// CounterChanged is published when a counter is created, updated or deleted
+type CounterChanged struct {
+	Action    string
+	CounterID int
+	Counter   *model.Counter
+}
+
+// CategoryChanged is published when a category is created, updated or deleted
+type CategoryChanged struct {
+	Action     string
+	CategoryID int
+	Category   *model.Category
+}
+
+// DisplayProfileChanged is published when a display profile is created, updated or deleted.
+// PreviousSlug is set when an update renamed the profile.
+type DisplayProfileChanged struct {
+	Action       string
+	ProfileID    int
+	Slug         string
+	PreviousSlug string
+}
+
+// KioskProfileChanged is published when a kiosk profile is created, updated or deleted.
+// PreviousSlug is set when an update renamed the profile.
+type KioskProfileChanged struct {
+	Action       string
+	ProfileID    int
+	Slug         string
+	PreviousSlug string
+}
+
+// DeviceCommand is published when an admin sends a remote action to a device.
+// URL is set for navigate.
+type DeviceCommand struct {
+	DeviceID int
+	Action   string
+	URL      string
+}
+
+// DeviceSilent is published once when a paired device stops sending heartbeats during opening hours
+type DeviceSilent struct {
+	Device *model.Device
+}
+
+// DeviceRecovered is published when a device that was reported silent sends a heartbeat again
+type DeviceRecovered struct {
+	Device *model.Device
+}
+
+// QueueAlertRaised is published when a queue alert rule fires for a category, at most once
+// per cooldown
+type QueueAlertRaised struct {
+	Alert *model.QueueAlert
+}
+
+func (TicketIssued) Name() string          { return NameTicketIssued }
+func (TicketCalled) Name() string          { return NameTicketCalled }
+func (TicketRecalled) Name() string        { return NameTicketRecalled }
+func (TicketTransferred) Name() string     { return NameTicketTransferred }
+func (TicketCompleted) Name() string       { return NameTicketCompleted }
+func (TicketNoShow) Name() string          { return NameTicketNoShow }
+func (TicketCancelled) Name() string       { return NameTicketCancelled }
+func (TicketRequeued) Name() string        { return NameTicketRequeued }
+func (TicketSLABreached) Name() string     { return NameTicketSLABreached }
+func (TicketsReset) Name() string          { return NameTicketsReset }
+func (CounterStatusChanged) Name() string  { return NameCounterStatusChanged }
+func (CounterChanged) Name() string        { return NameCounterChanged }
+func (CategoryChanged) Name() string       { return NameCategoryChanged }
+func (DisplayProfileChanged) Name() string { return NameDisplayProfileChanged }
+func (KioskProfileChanged) Name() string   { return NameKioskProfileChanged }
+func (DeviceCommand) Name() string         { return NameDeviceCommand }
+func (DeviceSilent) Name() string          { return NameDeviceSilent }
+func (DeviceRecovered) Name() string       { return NameDeviceRecovered }
+func (QueueAlertRaised) Name() string      { return NameQueueAlertRaised }
+
+// TicketOf returns the ticket carried by a ticket event, or nil for other events
+func TicketOf(e Event) *model.Ticket {
+	switch ev := e.(type) {
+	case TicketIssued:
+		return ev.Ticket
+	case TicketCalled:
+		return ev.Ticket
+	case TicketRecalled:
+		return ev.Ticket
+	case TicketTransferred:
+		return ev.Ticket
+	case TicketCompleted:
+		return ev.Ticket
+	case TicketNoShow:
+		return ev.Ticket
+	case TicketCancelled:
+		return ev.Ticket
+	case TicketRequeued:
+		return ev.Ticket
+	case TicketSLABreached:
+		return ev.Ticket
+	}
+	return nil
+}
@@ -1,9 +1,9 @@
 package handler
 
 import (
+	"bytes"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,19 +13,18 @@ import (
 	"tenangantri/internal/dto"
 	"tenangantri/internal/model"
 	"tenangantri/internal/service"
-	"tenangantri/internal/websocket"
 )
 
 // AdminHandler handles admin-related requests
 type AdminHandler struct {
-	adminService *service.AdminService
-	hub          *websocket.Hub
+	adminService  *service.AdminService
+	reportService *service.ReportService
 }
 
-func NewAdminHandler(adminService *service.AdminService, hub *websocket.Hub) *AdminHandler {
+func NewAdminHandler(adminService *service.AdminService, reportService *service.ReportService) *AdminHandler {
 	return &AdminHandler{
-		adminService: adminService,
-		hub:          hub,
+		adminService:  adminService,
+		reportService: reportService,
 	}
 }
 
@@ -223,7 +222,6 @@ func (h *AdminHandler) CreateCategory(c *gin.Context) {
 		return
 	}
 
-	h.hub.Broadcast("category_created", category)
 	c.JSON(http.StatusCreated, category)
 }
 
@@ -248,7 +246,6 @@ func (h *AdminHandler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	h.hub.Broadcast("category_updated", category)
 	c.JSON(http.StatusOK, category)
 }
 
@@ -273,7 +270,6 @@ func (h *AdminHandler) UpdateCategoryStatus(c *gin.Context) {
 		return
 	}
 
-	h.hub.Broadcast("category_updated", category)
 	c.JSON(http.StatusOK, category)
 }
 
@@ -291,7 +287,6 @@ func (h *AdminHandler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	h.hub.Broadcast("category_deleted", id)
 	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
 }
 
@@ -352,7 +347,6 @@ func (h *AdminHandler) CreateCounter(c *gin.Context) {
 		return
 	}
 
-	h.hub.Broadcast("counter_created", counter)
 	c.JSON(http.StatusCreated, counter)
 }
 
@@ -376,7 +370,6 @@ func (h *AdminHandler) UpdateCounter(c *gin.Context) {
 		return
 	}
 
-	h.hub.Broadcast("counter_updated", counter)
 	c.JSON(http.StatusOK, counter)
 }
 
@@ -394,7 +387,6 @@ func (h *AdminHandler) DeleteCounter(c *gin.Context) {
 		return
 	}
 
-	h.hub.Broadcast("counter_deleted", id)
 	c.JSON(http.StatusOK, gin.H{"message": "Counter deleted successfully"})
 }
 
@@ -477,7 +469,6 @@ func (h *AdminHandler) UpdateCounterStatus(c *gin.Context) {
 		return
 	}
 
-	h.hub.Broadcast("counter_updated", counter)
 	c.JSON(http.StatusOK, counter)
 }
 
@@ -612,11 +603,6 @@ func (h *AdminHandler) CreateTicket(c *gin.Context) {
 		return
 	}
 
-	// Broadcast update
-	stats, _ := h.adminService.GetStats(c.Request.Context())
-	h.hub.BroadcastStatsUpdate(stats)
-	h.hub.BroadcastTicketUpdate(ticket)
-
 	c.JSON(http.StatusCreated, ticket)
 }
 
@@ -640,11 +626,6 @@ func (h *AdminHandler) UpdateTicketStatus(c *gin.Context) {
 		return
 	}
 
-	// Broadcast updates
-	stats, _ := h.adminService.GetStats(c.Request.Context())
-	h.hub.BroadcastStatsUpdate(stats)
-	h.hub.BroadcastTicketUpdate(ticket)
-
 	c.JSON(http.StatusOK, ticket)
 }
 
@@ -656,17 +637,12 @@ func (h *AdminHandler) CancelTicket(c *gin.Context) {
 		return
 	}
 
-	ticket, err := h.adminService.CancelTicket(c.Request.Context(), id)
+	_, err = h.adminService.CancelTicket(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel ticket"})
 		return
 	}
 
-	// Broadcast updates
-	stats, _ := h.adminService.GetStats(c.Request.Context())
-	h.hub.BroadcastStatsUpdate(stats)
-	h.hub.BroadcastTicketUpdate(ticket)
-
 	c.JSON(http.StatusOK, gin.H{"message": "Ticket cancelled successfully"})
 }
 
@@ -684,252 +660,126 @@ func (h *AdminHandler) Reports(c *gin.Context) {
 	})
 }
 
-// ExportTickets exports tickets to CSV/Excel
+// ExportTickets streams the tickets of a date range and filters as CSV
 func (h *AdminHandler) ExportTickets(c *gin.Context) {
-	filename := "tickets_" + time.Now().Format("2006-01-02") + ".csv"
-
-	// Set headers for CSV download
-	c.Header("Content-Type", "text/csv")
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-
-	// Get filtered tickets
-	tickets, err := h.adminService.ListTickets(c.Request.Context(), map[string]interface{}{
-		"date_from":   c.Query("date_from"),
-		"date_to":     c.Query("date_to"),
-		"status":      c.Query("status"),
-		"category_id": c.Query("category_id"),
-		"counter_id":  c.Query("counter_id"),
-		"search":      c.Query("search"),
-	})
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export tickets"})
+	var req dto.CSVExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	// Generate CSV content
-	var csvContent strings.Builder
-	csvContent.WriteString("Ticket Number,Category,Status,Created At,Priority,Wait Time,Service Time,Notes\n")
-
-	for _, ticket := range tickets {
-		waitTime := "0m 0s"
-		if ticket.WaitTime.Valid {
-			minutes := ticket.WaitTime.Int64 / 60
-			seconds := ticket.WaitTime.Int64 % 60
-			waitTime = fmt.Sprintf("%dm %ds", minutes, seconds)
-		}
-
-		serviceTime := "0m"
-		if ticket.ServiceTime.Valid {
-			minutes := ticket.ServiceTime.Int64 / 60
-			serviceTime = fmt.Sprintf("%dm", minutes)
-		}
-
-		csvContent.WriteString(fmt.Sprintf("%s,%s,%s,%s,%s,%s,%s,%s\n",
-			ticket.TicketNumber,
-			fmt.Sprintf("%d", ticket.CategoryID.Int64),
-			ticket.Status,
-			ticket.CreatedAt.Format("2006-01-02 15:04:05"),
-			fmt.Sprintf("%d", ticket.Priority),
-			waitTime,
-			serviceTime,
-			ticket.Notes.String,
-		))
-	}
-
-	// Send CSV response
-	c.String(http.StatusOK, csvContent.String())
-}
-
-// ExportPDF exports tickets to PDF
-func (h *AdminHandler) ExportPDF(c *gin.Context) {
-	filename := "tickets_" + time.Now().Format("2006-01-02") + ".pdf"
-
-	// For now, just return a placeholder
-	c.Header("Content-Type", "application/pdf")
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-	c.JSON(http.StatusOK, gin.H{"message": "PDF export functionality - TODO"})
-}
-
-// GetReportData gets data for reports page
-func (h *AdminHandler) GetReportData(c *gin.Context) {
-	// Get date parameters from query
-	dateFrom := c.Query("date_from")
-	dateTo := c.Query("date_to")
-	reportType := c.Query("type")
-
-	// Get tickets within date range
-	filters := map[string]interface{}{
-		"date_from": dateFrom,
-		"date_to":   dateTo,
-	}
-
-	tickets, err := h.adminService.ListTickets(c.Request.Context(), filters)
+	filter, err := h.reportService.Filter(&req.ReportRequest)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get report data"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	// Process data based on report type
-	switch reportType {
-	case "summary":
-		response := gin.H{
-			"total_tickets": len(tickets),
-			"pending":       countTicketsByStatus(tickets, "pending"),
-			"serving":       countTicketsByStatus(tickets, "serving"),
-			"completed":     countTicketsByStatus(tickets, "completed"),
-			"cancelled":     countTicketsByStatus(tickets, "cancelled"),
-			"no_show":       countTicketsByStatus(tickets, "no_show"),
-		}
-		c.JSON(http.StatusOK, response)
-	case "detailed":
-		c.JSON(http.StatusOK, tickets)
-	case "performance":
-		response := gin.H{
-			"avg_wait_time":    calculateAverageWaitTime(tickets),
-			"avg_service_time": calculateAverageServiceTime(tickets),
-			"completion_rate":  calculateCompletionRate(tickets),
-		}
-		c.JSON(http.StatusOK, response)
-	case "categories":
-		response := gin.H{
-			"categories": getCategoryBreakdown(tickets),
-		}
-		c.JSON(http.StatusOK, response)
-	case "counters":
-		response := gin.H{
-			"counters": getCounterBreakdown(tickets),
-		}
-		c.JSON(http.StatusOK, response)
-	case "hourly":
-		response := gin.H{
-			"hourly_stats": getHourlyBreakdown(tickets),
-		}
-		c.JSON(http.StatusOK, response)
-	default:
-		// Return all data by default
-		c.JSON(http.StatusOK, tickets)
+	format, err := h.reportService.CSVFormat(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-}
 
-// Helper functions for report data processing
-func countTicketsByStatus(tickets []model.Ticket, status string) int {
-	count := 0
-	for _, ticket := range tickets {
-		if ticket.Status == status {
-			count++
+	filename := fmt.Sprintf("tiket_%s_%s.csv", filter.DateFrom.Format("2006-01-02"), filter.DateTo.Format("2006-01-02"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	if err := h.reportService.WriteCSV(c.Request.Context(), c.Writer, filter, format); err != nil {
+		log.Error().Err(err).Str("layer", "handler").Msg("Failed to export tickets")
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export tickets"})
+			return
 		}
+		// The file is already partly sent, so only a broken connection tells the client it is incomplete
+		abortStream(c)
 	}
-	return count
 }
 
-func calculateAverageWaitTime(tickets []model.Ticket) float64 {
-	if len(tickets) == 0 {
-		return 0
-	}
-
-	total := int64(0)
-	count := 0
-	for _, ticket := range tickets {
-		if ticket.WaitTime.Valid {
-			total += ticket.WaitTime.Int64
-			count++
-		}
-	}
-
-	if count == 0 {
-		return 0
+// abortStream cuts the connection of a response that is already partly sent, so that the
+// client reports a failed download instead of saving a truncated file as complete
+func abortStream(c *gin.Context) {
+	c.Abort()
+	conn, buf, err := c.Writer.Hijack()
+	if err != nil {
+		// Not hijackable (HTTP/2); net/http resets the stream on this panic
+		panic(http.ErrAbortHandler)
 	}
-	return float64(total) / float64(count)
+	buf.Flush()
+	conn.Close()
 }
 
-func calculateAverageServiceTime(tickets []model.Ticket) float64 {
-	if len(tickets) == 0 {
-		return 0
-	}
-
-	total := int64(0)
-	count := 0
-	for _, ticket := range tickets {
-		if ticket.ServiceTime.Valid {
-			total += ticket.ServiceTime.Int64
-			count++
-		}
+// ExportPDF exports the report of a date range and filters as a PDF
+func (h *AdminHandler) ExportPDF(c *gin.Context) {
+	var req dto.ReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-
-	if count == 0 {
-		return 0
+	filter, err := h.reportService.Filter(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	return float64(total) / float64(count)
-}
 
-func calculateCompletionRate(tickets []model.Ticket) float64 {
-	if len(tickets) == 0 {
-		return 0
+	// Rendered into memory first so that a failed query is still a clean error response
+	var buf bytes.Buffer
+	if err := h.reportService.WritePDF(c.Request.Context(), &buf, filter); err != nil {
+		log.Error().Err(err).Str("layer", "handler").Msg("Failed to generate PDF report")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate report"})
+		return
 	}
 
-	completed := countTicketsByStatus(tickets, "completed")
-	return float64(completed) / float64(len(tickets)) * 100
+	filename := fmt.Sprintf("laporan_%s_%s.pdf", filter.DateFrom.Format("2006-01-02"), filter.DateTo.Format("2006-01-02"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
 }
 
-func getCategoryBreakdown(tickets []model.Ticket) []gin.H {
-	categoryMap := make(map[string]int)
-
-	for _, ticket := range tickets {
-		if ticket.CategoryID.Valid {
-			key := fmt.Sprintf("%d", ticket.CategoryID.Int64)
-			categoryMap[key]++
-		}
+// ExportXLSX streams the tickets and breakdowns of a date range and filters as an Excel workbook
+func (h *AdminHandler) ExportXLSX(c *gin.Context) {
+	var req dto.ReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-
-	var result []gin.H
-	for category, count := range categoryMap {
-		result = append(result, gin.H{
-			"category": category,
-			"count":    count,
-		})
+	filter, err := h.reportService.Filter(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	return result
-}
-
-func getCounterBreakdown(tickets []model.Ticket) []gin.H {
-	counterMap := make(map[string]int)
-
-	for _, ticket := range tickets {
-		if ticket.CounterID.Valid {
-			key := fmt.Sprintf("%d", ticket.CounterID.Int64)
-			counterMap[key]++
+	filename := fmt.Sprintf("laporan_%s_%s.xlsx", filter.DateFrom.Format("2006-01-02"), filter.DateTo.Format("2006-01-02"))
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	if err := h.reportService.WriteXLSX(c.Request.Context(), c.Writer, filter); err != nil {
+		log.Error().Err(err).Str("layer", "handler").Msg("Failed to export XLSX report")
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export report"})
+			return
 		}
+		// The workbook is already partly sent, so only a broken connection tells the client it is incomplete
+		abortStream(c)
 	}
-
-	var result []gin.H
-	for counter, count := range counterMap {
-		result = append(result, gin.H{
-			"counter": counter,
-			"count":   count,
-		})
-	}
-
-	return result
 }
 
-func getHourlyBreakdown(tickets []model.Ticket) []gin.H {
-	hourMap := make(map[int]int)
-
-	for _, ticket := range tickets {
-		hour := ticket.CreatedAt.Hour()
-		hourMap[hour]++
+// GetReportData aggregates the tickets of a date range and filters for the reports page
+func (h *AdminHandler) GetReportData(c *gin.Context) {
+	var req dto.ReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter, err := h.reportService.Filter(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	var result []gin.H
-	for hour := 0; hour < 24; hour++ {
-		count := hourMap[hour]
-		result = append(result, gin.H{
-			"hour":  hour,
-			"count": count,
-		})
+	report, err := h.reportService.GetReport(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get report data"})
+		return
 	}
 
-	return result
+	c.JSON(http.StatusOK, report)
 }
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+	"tenangantri/internal/service"
+)
+
+// failingReportRepository streams a number of tickets and then fails, like a query
+// that breaks off midway
+type failingReportRepository struct {
+	repository.ReportRepository
+	tickets int
+}
+
+func (r *failingReportRepository) EachTicket(ctx context.Context, filter model.ReportFilter, fn func(*model.ReportTicket) error) error {
+	for i := 0; i < r.tickets; i++ {
+		if err := fn(&model.ReportTicket{
+			TicketNumber: fmt.Sprintf("A%03d", i),
+			CategoryName: "Umum",
+			Status:       "completed",
+			CreatedAt:    time.Now(),
+		}); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("connection reset")
+}
+
+func newExportServer(t *testing.T, tickets int) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	reportService := service.NewReportService(&failingReportRepository{tickets: tickets}, nil, nil,
+		&config.ReportConfig{CSVDelimiter: "comma", CSVEncoding: "utf-8"})
+	h := NewAdminHandler(nil, reportService)
+
+	r := gin.New()
+	r.GET("/tickets.csv", h.ExportTickets)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAdminHandler_ExportTicketsFailsMidStream(t *testing.T) {
+	// Enough rows that the first part of the file is sent before the query fails
+	server := newExportServer(t, 1000)
+
+	resp, err := http.Get(server.URL + "/tickets.csv")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Contains(t, string(body), "Ticket Number")
+}
+
+func TestAdminHandler_ExportTicketsFailsBeforeStream(t *testing.T) {
+	server := newExportServer(t, 1)
+
+	resp, err := http.Get(server.URL + "/tickets.csv")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"error":"Failed to export tickets"}`, string(body))
+}
@@ -15,14 +15,16 @@ import (
 
 // AuthHandler handles authentication requests
 type AuthHandler struct {
-	userService *service.UserService
-	config      *config.JWTConfig
+	userService        *service.UserService
+	performanceService *service.StaffPerformanceService
+	config             *config.JWTConfig
 }
 
-func NewAuthHandler(userService *service.UserService, cfg *config.JWTConfig) *AuthHandler {
+func NewAuthHandler(userService *service.UserService, performanceService *service.StaffPerformanceService, cfg *config.JWTConfig) *AuthHandler {
 	return &AuthHandler{
-		userService: userService,
-		config:      cfg,
+		userService:        userService,
+		performanceService: performanceService,
+		config:             cfg,
 	}
 }
 
@@ -86,6 +88,13 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 // Logout handles user logout
 func (h *AuthHandler) Logout(c *gin.Context) {
+	// Signing out ends the staff member's shift
+	if token, err := c.Cookie("auth_token"); err == nil && token != "" {
+		if claims, err := middleware.ParseToken(token); err == nil && (claims.Role == "staff" || claims.Role == "supervisor") {
+			h.performanceService.EndShift(c.Request.Context(), int(claims.UserID))
+		}
+	}
+
 	c.SetCookie("auth_token", "", -1, "/", "", false, true)
 	c.Redirect(http.StatusFound, "/login")
 }
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+	"tenangantri/internal/service"
+)
+
+// DeviceHandler serves device pairing and heartbeats and the admin device registry
+type DeviceHandler struct {
+	deviceService *service.DeviceService
+}
+
+func NewDeviceHandler(deviceService *service.DeviceService) *DeviceHandler {
+	return &DeviceHandler{
+		deviceService: deviceService,
+	}
+}
+
+// ShowPair shows the page where a kiosk or display enters its pairing code
+func (h *DeviceHandler) ShowPair(c *gin.Context) {
+	c.HTML(http.StatusOK, "pages/device/pair.html", gin.H{})
+}
+
+// Pair exchanges a pairing code for a device token
+func (h *DeviceHandler) Pair(c *gin.Context) {
+	var req dto.DevicePairRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.deviceService.Pair(c.Request.Context(), req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Heartbeat records a device's report. The device token is sent as a bearer token.
+func (h *DeviceHandler) Heartbeat(c *gin.Context) {
+	var req dto.DeviceHeartbeatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	info, err := h.deviceService.Heartbeat(c.Request.Context(), token, c.Request.UserAgent(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record heartbeat"})
+		return
+	}
+	if info == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unknown device"})
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+// ListDevices shows the devices admin page
+func (h *DeviceHandler) ListDevices(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	devices, err := h.deviceService.ListDevices(ctx, time.Now())
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListDevices").Msg("Failed to load devices")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load devices"})
+		return
+	}
+
+	profiles, err := h.deviceService.ListProfiles(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListDevices").Msg("Failed to load display profiles")
+		profiles = []model.DisplayProfile{}
+	}
+
+	kioskProfiles, err := h.deviceService.ListKioskProfiles(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListDevices").Msg("Failed to load kiosk profiles")
+		kioskProfiles = []model.KioskProfile{}
+	}
+
+	c.HTML(http.StatusOK, "pages/admin/devices.html", gin.H{
+		"Devices":       devices,
+		"Profiles":      profiles,
+		"KioskProfiles": kioskProfiles,
+		"ActiveTab":     "devices",
+	})
+}
+
+// GetDevice returns a device as JSON
+func (h *DeviceHandler) GetDevice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	device, err := h.deviceService.GetDevice(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+// CreateDevice registers a device and returns it with its pairing code
+func (h *DeviceHandler) CreateDevice(c *gin.Context) {
+	var req dto.DeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	device, err := h.deviceService.CreateDevice(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, device)
+}
+
+// UpdateDevice updates a device
+func (h *DeviceHandler) UpdateDevice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	var req dto.DeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	device, err := h.deviceService.UpdateDevice(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+// DeleteDevice deletes a device
+func (h *DeviceHandler) DeleteDevice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	if err := h.deviceService.DeleteDevice(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete device"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Device deleted successfully"})
+}
+
+// NewPairingCode issues a new pairing code, unpairing the device
+func (h *DeviceHandler) NewPairingCode(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	device, err := h.deviceService.NewPairingCode(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+// SendCommand sends a remote action to a device
+func (h *DeviceHandler) SendCommand(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	var req dto.DeviceCommandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.deviceService.SendCommand(c.Request.Context(), id, req.Action); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Command sent"})
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
@@ -14,12 +15,14 @@ import (
 
 // DisplayHandler handles display-related requests
 type DisplayHandler struct {
-	displayService *service.DisplayService
+	displayService      *service.DisplayService
+	announcementService *service.AnnouncementService
 }
 
-func NewDisplayHandler(displayService *service.DisplayService) *DisplayHandler {
+func NewDisplayHandler(displayService *service.DisplayService, announcementService *service.AnnouncementService) *DisplayHandler {
 	return &DisplayHandler{
-		displayService: displayService,
+		displayService:      displayService,
+		announcementService: announcementService,
 	}
 }
 
@@ -93,8 +96,32 @@ func (h *DisplayHandler) ShowCategoryDisplay(c *gin.Context) {
 		return
 	}
 
-	c.HTML(http.StatusOK, "display/category.html", gin.H{
+	if category == nil {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{"Error": "Category not found"})
+		return
+	}
+
+	c.HTML(http.StatusOK, "pages/display/category.html", gin.H{
 		"Category": category,
 		"Tickets":  tickets,
 	})
 }
+
+// GetAnnouncement streams the spoken call announcement for a ticket at a counter.
+// ?lang=id,en picks the packs; by default every configured pack is played.
+func (h *DisplayHandler) GetAnnouncement(c *gin.Context) {
+	var langs []string
+	if lang := c.Query("lang"); lang != "" {
+		langs = strings.Split(lang, ",")
+	}
+
+	wav, err := h.announcementService.Build(c.Param("ticket"), c.Param("counter"), langs)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The audio for a ticket and counter never changes, so displays may keep it for the day
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "audio/wav", wav)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+	"tenangantri/internal/service"
+	"tenangantri/internal/websocket"
+)
+
+// DisplayProfileHandler serves profile-driven display boards and their admin management
+type DisplayProfileHandler struct {
+	displayProfileService *service.DisplayProfileService
+}
+
+func NewDisplayProfileHandler(displayProfileService *service.DisplayProfileService) *DisplayProfileHandler {
+	return &DisplayProfileHandler{
+		displayProfileService: displayProfileService,
+	}
+}
+
+// ShowScreen shows the display board configured by a profile
+func (h *DisplayProfileHandler) ShowScreen(c *gin.Context) {
+	screen, err := h.displayProfileService.GetScreen(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ShowScreen").Msg("Failed to load display profile")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load display data"})
+		return
+	}
+	if screen == nil {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{"Error": "Display profile not found"})
+		return
+	}
+
+	c.HTML(http.StatusOK, "pages/display/profile.html", gin.H{
+		"Profile":  screen.Profile,
+		"Tickets":  screen.Tickets,
+		"Waiting":  screen.Waiting,
+		"Counters": screen.Counters,
+		"Topics":   screenTopics(screen.Profile),
+	})
+}
+
+// screenTopics subscribes a board to its reload topic and to the categories and
+// counters it shows, or to every public update when it shows everything
+func screenTopics(profile *model.DisplayProfile) []string {
+	topics := []string{websocket.ScreenTopic(profile.Slug)}
+	if len(profile.CategoryIDs) == 0 && len(profile.CounterIDs) == 0 {
+		return append(topics, websocket.TopicDisplayAll)
+	}
+	for _, id := range profile.CategoryIDs {
+		topics = append(topics, websocket.CategoryTopic(id))
+	}
+	for _, id := range profile.CounterIDs {
+		topics = append(topics, websocket.CounterTopic(id))
+	}
+	return topics
+}
+
+// ListProfiles shows the display profiles admin page
+func (h *DisplayProfileHandler) ListProfiles(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	profiles, err := h.displayProfileService.ListProfiles(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListProfiles").Msg("Failed to load display profiles")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load display profiles"})
+		return
+	}
+
+	categories, counters, err := h.displayProfileService.ListChoices(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListProfiles").Msg("Failed to load categories and counters")
+		categories = []model.Category{}
+		counters = []model.Counter{}
+	}
+
+	c.HTML(http.StatusOK, "pages/admin/display_profiles.html", gin.H{
+		"Profiles":   profiles,
+		"Categories": categories,
+		"Counters":   counters,
+		"ActiveTab":  "display_profiles",
+	})
+}
+
+// GetProfile returns a display profile as JSON
+func (h *DisplayProfileHandler) GetProfile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid display profile ID"})
+		return
+	}
+
+	profile, err := h.displayProfileService.GetProfile(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Display profile not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// CreateProfile creates a display profile
+func (h *DisplayProfileHandler) CreateProfile(c *gin.Context) {
+	var req dto.DisplayProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.displayProfileService.CreateProfile(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, profile)
+}
+
+// UpdateProfile updates a display profile
+func (h *DisplayProfileHandler) UpdateProfile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid display profile ID"})
+		return
+	}
+
+	var req dto.DisplayProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.displayProfileService.UpdateProfile(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// DeleteProfile deletes a display profile
+func (h *DisplayProfileHandler) DeleteProfile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid display profile ID"})
+		return
+	}
+
+	if err := h.displayProfileService.DeleteProfile(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete display profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Display profile deleted successfully"})
+}
@@ -1,9 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"sort"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
@@ -11,24 +11,34 @@ import (
 	"tenangantri/internal/dto"
 	"tenangantri/internal/model"
 	"tenangantri/internal/service"
-	"tenangantri/internal/websocket"
 )
 
 // KioskHandler handles kiosk-related requests
 type KioskHandler struct {
 	kioskService *service.KioskService
-	hub          *websocket.Hub
+	printService *service.TicketPrintService
 }
 
-func NewKioskHandler(kioskService *service.KioskService, hub *websocket.Hub) *KioskHandler {
+func NewKioskHandler(kioskService *service.KioskService, printService *service.TicketPrintService) *KioskHandler {
 	return &KioskHandler{
 		kioskService: kioskService,
-		hub:          hub,
+		printService: printService,
 	}
 }
 
-// ShowKiosk shows kiosk main page
+// ShowKiosk shows kiosk main page, while no kiosk profile is active
 func (h *KioskHandler) ShowKiosk(c *gin.Context) {
+	open, err := h.kioskService.DefaultKioskOpen(c.Request.Context())
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ShowKiosk").Msg("Failed to load kiosk profiles")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load kiosk"})
+		return
+	}
+	if !open {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{"Error": "Kiosk profiles are in use; open a kiosk at /kiosk/p/<slug>"})
+		return
+	}
+
 	categories, err := h.kioskService.GetCategories(c.Request.Context())
 	log.Info().Interface("categories", categories).Msg("Categories")
 	if err != nil {
@@ -36,6 +46,26 @@ func (h *KioskHandler) ShowKiosk(c *gin.Context) {
 		categories = []model.Category{}
 	}
 
+	h.renderKiosk(c, &dto.KioskScreen{Categories: categories})
+}
+
+// ShowProfileKiosk shows the kiosk configured by a kiosk profile
+func (h *KioskHandler) ShowProfileKiosk(c *gin.Context) {
+	screen, err := h.kioskService.GetKiosk(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ShowProfileKiosk").Msg("Failed to load kiosk profile")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load kiosk"})
+		return
+	}
+	if screen == nil {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{"Error": "Kiosk profile not found"})
+		return
+	}
+
+	h.renderKiosk(c, screen)
+}
+
+func (h *KioskHandler) renderKiosk(c *gin.Context, screen *dto.KioskScreen) {
 	type CategoryWithQueue struct {
 		model.Category
 		WaitingCount int `json:"waiting_count"`
@@ -53,77 +83,162 @@ func (h *KioskHandler) ShowKiosk(c *gin.Context) {
 		categoryQueueMap[cat.CategoryID] = cat.WaitingCount
 	}
 
-	categoriesWithQueue := make([]CategoryWithQueue, 0, len(categories))
-	for _, cat := range categories {
+	categoriesWithQueue := make([]CategoryWithQueue, 0, len(screen.Categories))
+	for _, cat := range screen.Categories {
 		categoriesWithQueue = append(categoriesWithQueue, CategoryWithQueue{
 			Category:     cat,
 			WaitingCount: categoryQueueMap[cat.ID],
 		})
 	}
 
-	sort.Slice(categoriesWithQueue, func(i, j int) bool {
-		if categoriesWithQueue[i].Priority == categoriesWithQueue[j].Priority {
-			return categoriesWithQueue[i].Name < categoriesWithQueue[j].Name
-		}
-		return categoriesWithQueue[i].Priority > categoriesWithQueue[j].Priority
-	})
+	// A profile that lists its categories keeps its own button order
+	if screen.Profile == nil || len(screen.Profile.Categories) == 0 {
+		sort.Slice(categoriesWithQueue, func(i, j int) bool {
+			if categoriesWithQueue[i].Priority == categoriesWithQueue[j].Priority {
+				return categoriesWithQueue[i].Name < categoriesWithQueue[j].Name
+			}
+			return categoriesWithQueue[i].Priority > categoriesWithQueue[j].Priority
+		})
+	}
+
+	ticketURL := "/kiosk/ticket"
+	if screen.Profile != nil {
+		ticketURL = "/kiosk/p/" + screen.Profile.Slug + "/ticket"
+	}
 
 	c.HTML(http.StatusOK, "pages/kiosk/index.html", gin.H{
 		"Categories":     categoriesWithQueue,
 		"ActiveCounters": stats.ActiveCounters,
+		"Profile":        screen.Profile,
+		"TicketURL":      ticketURL,
+		"Text":           kioskText(screen.Profile),
 	})
 }
 
 // GenerateTicket generates a new ticket from kiosk
 func (h *KioskHandler) GenerateTicket(c *gin.Context) {
+	h.generateTicket(c, nil)
+}
+
+// GenerateProfileTicket generates a ticket at a profile's kiosk, for the categories it offers only
+func (h *KioskHandler) GenerateProfileTicket(c *gin.Context) {
+	screen, err := h.kioskService.GetKiosk(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "GenerateProfileTicket").Msg("Failed to load kiosk profile")
+		h.ticketError(c, http.StatusInternalServerError, kioskText(nil), "generate_failed", "Failed to generate ticket")
+		return
+	}
+	if screen == nil {
+		h.ticketError(c, http.StatusNotFound, kioskText(nil), "not_offered", "Kiosk profile not found")
+		return
+	}
+
+	h.generateTicket(c, screen)
+}
+
+func (h *KioskHandler) generateTicket(c *gin.Context, screen *dto.KioskScreen) {
+	var profile *model.KioskProfile
+	if screen != nil {
+		profile = screen.Profile
+	}
+	text := kioskText(profile)
+
 	var req dto.CreateTicketRequest
 	if err := c.ShouldBind(&req); err != nil {
-		if c.GetHeader("HX-Request") != "" {
-			c.HTML(http.StatusBadRequest, "pages/kiosk/ticket_error.html", gin.H{
-				"Error": "Please select a service category",
-			})
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		}
+		h.ticketError(c, http.StatusBadRequest, text, "select_service", err.Error())
+		return
+	}
+	if screen != nil && !screen.Offers(req.CategoryID) {
+		h.ticketError(c, http.StatusForbidden, text, "not_offered", "Category is not offered at this kiosk")
 		return
 	}
 
-	ticket, queuePosition, estimatedWaitTime, err := h.kioskService.GenerateTicket(c.Request.Context(), &req)
+	ticket, queuePosition, estimatedWaitTime, err := h.kioskService.GenerateTicket(c.Request.Context(), &req, screen)
+	if errors.Is(err, service.ErrKioskProfilesInUse) {
+		h.ticketError(c, http.StatusForbidden, text, "not_offered", "Kiosk profiles are in use; use a profile's kiosk")
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to generate ticket")
-		if c.GetHeader("HX-Request") != "" {
-			c.HTML(http.StatusInternalServerError, "pages/kiosk/ticket_error.html", gin.H{
-				"Error": "Failed to generate ticket. Please try again.",
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate ticket"})
-		}
+		h.ticketError(c, http.StatusInternalServerError, text, "generate_failed", "Failed to generate ticket")
 		return
 	}
 
-	// Broadcast updates
-	stats, _, _ := h.kioskService.GetQueueInfo(c.Request.Context())
-	if stats != nil {
-		h.hub.BroadcastStatsUpdate(stats)
+	category := h.ticketCategory(c, screen, req.CategoryID)
+
+	// Profiles with a receipt printer print on the server; when that fails the kiosk
+	// shows the ticket on screen instead of opening the browser's print dialog
+	printsOnServer := profile != nil && profile.PrintsOnServer()
+	printed, printFailed := false, false
+	if printsOnServer {
+		if err := h.printService.PrintTicket(c.Request.Context(), profile, ticket, category.Name, requestOrigin(c)); err != nil {
+			log.Error().Err(err).Str("layer", "handler").Str("func", "generateTicket").Msg("Failed to print ticket, showing it on screen")
+			printFailed = true
+		} else {
+			printed = true
+		}
 	}
-	h.hub.BroadcastTicketUpdate(ticket)
 
 	// Check if HTMX request
 	if c.GetHeader("HX-Request") != "" {
 		c.HTML(http.StatusOK, "pages/kiosk/ticket_preview.html", gin.H{
 			"Ticket":            ticket,
+			"Category":          category,
 			"QueuePosition":     queuePosition,
 			"EstimatedWaitTime": estimatedWaitTime,
+			"Prints":            (profile == nil || profile.Prints()) && !printsOnServer,
+			"Printed":           printed,
+			"PrintFailed":       printFailed,
+			"ShowsQR":           (profile != nil && profile.ShowsQR()) || printFailed,
+			"Text":              text,
 		})
 	} else {
 		c.JSON(http.StatusCreated, gin.H{
 			"ticket":              ticket,
 			"queue_position":      queuePosition,
 			"estimated_wait_time": estimatedWaitTime,
+			"printed":             printed,
 		})
 	}
 }
 
+// requestOrigin returns the scheme and host the kiosk reached the server on
+func requestOrigin(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// ticketCategory returns the category a ticket was issued for, as the kiosk labels it
+func (h *KioskHandler) ticketCategory(c *gin.Context, screen *dto.KioskScreen, categoryID int) model.Category {
+	if screen != nil {
+		for _, category := range screen.Categories {
+			if category.ID == categoryID {
+				return category
+			}
+		}
+	}
+	category, err := h.kioskService.GetCategory(c.Request.Context(), categoryID)
+	if err != nil || category == nil {
+		return model.Category{ID: categoryID}
+	}
+	return *category
+}
+
+// ticketError answers a failed ticket request with the kiosk's error card, or JSON for API clients
+func (h *KioskHandler) ticketError(c *gin.Context, status int, text map[string]string, key, apiError string) {
+	if c.GetHeader("HX-Request") != "" {
+		c.HTML(status, "pages/kiosk/ticket_error.html", gin.H{
+			"Error": text[key],
+			"Text":  text,
+		})
+	} else {
+		c.JSON(status, gin.H{"error": apiError})
+	}
+}
+
 // GetTicketStatus gets ticket status
 func (h *KioskHandler) GetTicketStatus(c *gin.Context) {
 	ticketNumber := c.Param("number")
@@ -138,18 +253,6 @@ func (h *KioskHandler) GetTicketStatus(c *gin.Context) {
 	})
 }
 
-// PrintTicket shows printable ticket view
-func (h *KioskHandler) PrintTicket(c *gin.Context) {
-	ticketNumber := c.Param("number")
-
-	// In a real implementation, this would trigger a print job
-	// For now, we just return a printable view
-	c.HTML(http.StatusOK, "pages/kiosk/print_ticket.html", gin.H{
-		"TicketNumber": ticketNumber,
-		"Date":         time.Now().Format("2006-01-02 15:04:05"),
-	})
-}
-
 // GetQueueInfo gets queue information for kiosk
 func (h *KioskHandler) GetQueueInfo(c *gin.Context) {
 	stats, categories, err := h.kioskService.GetQueueInfo(c.Request.Context())
@@ -164,3 +267,69 @@ func (h *KioskHandler) GetQueueInfo(c *gin.Context) {
 		"categories":      categories,
 	})
 }
+
+// kioskTexts holds the kiosk's wording per profile language
+var kioskTexts = map[string]map[string]string{
+	model.KioskLanguageIndonesian: {
+		"locale":          "id-ID",
+		"subtitle":        "Pilih layanan untuk mendapatkan tiket",
+		"track":           "Lacak",
+		"loading":         "Memuat...",
+		"waiting":         "antrian",
+		"thanks":          "Terima kasih atas kesabaran Anda. kami akan melayani Anda segera.",
+		"touch_to_start":  "Sentuh layar untuk mengambil tiket",
+		"your_ticket":     "Tiket Anda",
+		"keep_number":     "Mohon simpan nomor ini",
+		"ticket_number":   "Nomor Tiket",
+		"queue_position":  "Posisi Antrian",
+		"estimated_wait":  "Estimasi Waktu Tunggu",
+		"wait_notice":     "Mohon tunggu nomor Anda dipanggil. Perhatikan papan tampilan untuk pembaruan.",
+		"scan_to_track":   "Pindai untuk melacak antrian Anda",
+		"printed":         "Tiket Anda sedang dicetak. Silakan ambil di printer.",
+		"print_failed":    "Printer sedang tidak tersedia. Silakan foto atau catat nomor tiket Anda.",
+		"print":           "Print",
+		"done":            "Selesai",
+		"issued_at":       "Issued at",
+		"oops":            "Ups!",
+		"try_again":       "Coba Lagi",
+		"select_service":  "Silakan pilih layanan",
+		"not_offered":     "Layanan ini tidak tersedia di kiosk ini",
+		"generate_failed": "Gagal membuat tiket. Silakan coba lagi.",
+	},
+	model.KioskLanguageEnglish: {
+		"locale":          "en-GB",
+		"subtitle":        "Choose a service to get a ticket",
+		"track":           "Track",
+		"loading":         "Loading...",
+		"waiting":         "waiting",
+		"thanks":          "Thank you for your patience. We will serve you shortly.",
+		"touch_to_start":  "Touch the screen to take a ticket",
+		"your_ticket":     "Your Ticket",
+		"keep_number":     "Please keep this number",
+		"ticket_number":   "Ticket Number",
+		"queue_position":  "Queue Position",
+		"estimated_wait":  "Estimated Wait",
+		"wait_notice":     "Please wait for your number to be called. Watch the display board for updates.",
+		"scan_to_track":   "Scan to track your ticket",
+		"printed":         "Your ticket is printing. Please take it from the printer.",
+		"print_failed":    "The printer is unavailable. Please take a photo or note down your ticket number.",
+		"print":           "Print",
+		"done":            "Done",
+		"issued_at":       "Issued at",
+		"oops":            "Oops!",
+		"try_again":       "Try Again",
+		"select_service":  "Please select a service",
+		"not_offered":     "This service is not available at this kiosk",
+		"generate_failed": "Failed to generate ticket. Please try again.",
+	},
+}
+
+// kioskText returns the wording for a profile's language; the default kiosk is Indonesian
+func kioskText(profile *model.KioskProfile) map[string]string {
+	if profile != nil {
+		if text, ok := kioskTexts[profile.Language]; ok {
+			return text
+		}
+	}
+	return kioskTexts[model.KioskLanguageIndonesian]
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+	"tenangantri/internal/service"
+)
+
+// KioskProfileHandler serves the kiosk profiles admin management
+type KioskProfileHandler struct {
+	kioskProfileService *service.KioskProfileService
+}
+
+func NewKioskProfileHandler(kioskProfileService *service.KioskProfileService) *KioskProfileHandler {
+	return &KioskProfileHandler{
+		kioskProfileService: kioskProfileService,
+	}
+}
+
+// ListProfiles shows the kiosk profiles admin page
+func (h *KioskProfileHandler) ListProfiles(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	profiles, err := h.kioskProfileService.ListProfiles(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListProfiles").Msg("Failed to load kiosk profiles")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load kiosk profiles"})
+		return
+	}
+
+	categories, err := h.kioskProfileService.ListCategories(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListProfiles").Msg("Failed to load categories")
+		categories = []model.Category{}
+	}
+
+	c.HTML(http.StatusOK, "pages/admin/kiosk_profiles.html", gin.H{
+		"Profiles":   profiles,
+		"Categories": categories,
+		"ActiveTab":  "kiosk_profiles",
+	})
+}
+
+// GetProfile returns a kiosk profile with its categories as JSON
+func (h *KioskProfileHandler) GetProfile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid kiosk profile ID"})
+		return
+	}
+
+	profile, err := h.kioskProfileService.GetProfile(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kiosk profile not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// CreateProfile creates a kiosk profile
+func (h *KioskProfileHandler) CreateProfile(c *gin.Context) {
+	var req dto.KioskProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.kioskProfileService.CreateProfile(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, profile)
+}
+
+// UpdateProfile updates a kiosk profile
+func (h *KioskProfileHandler) UpdateProfile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid kiosk profile ID"})
+		return
+	}
+
+	var req dto.KioskProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.kioskProfileService.UpdateProfile(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// DeleteProfile deletes a kiosk profile
+func (h *KioskProfileHandler) DeleteProfile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid kiosk profile ID"})
+		return
+	}
+
+	if err := h.kioskProfileService.DeleteProfile(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete kiosk profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Kiosk profile deleted successfully"})
+}
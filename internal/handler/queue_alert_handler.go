@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+	"tenangantri/internal/service"
+)
+
+// QueueAlertHandler handles admin management of queue alert rules and their history
+type QueueAlertHandler struct {
+	queueAlertService *service.QueueAlertService
+}
+
+func NewQueueAlertHandler(queueAlertService *service.QueueAlertService) *QueueAlertHandler {
+	return &QueueAlertHandler{
+		queueAlertService: queueAlertService,
+	}
+}
+
+// ListRules shows alert rules and the alerts they raised
+func (h *QueueAlertHandler) ListRules(c *gin.Context) {
+	ctx := c.Request.Context()
+	ruleID, _ := strconv.Atoi(c.Query("rule_id"))
+
+	rules, err := h.queueAlertService.ListRules(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListRules").Msg("Failed to load alert rules")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load alert rules"})
+		return
+	}
+
+	alerts, err := h.queueAlertService.ListAlerts(ctx, ruleID, 100)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListRules").Msg("Failed to load queue alerts")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load queue alerts"})
+		return
+	}
+
+	categories, err := h.queueAlertService.ListCategories(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListRules").Msg("Failed to load categories")
+		categories = []model.Category{}
+	}
+
+	c.HTML(http.StatusOK, "pages/admin/alerts.html", gin.H{
+		"Rules":      rules,
+		"Alerts":     alerts,
+		"Categories": categories,
+		"RuleID":     ruleID,
+		"ActiveTab":  "alerts",
+	})
+}
+
+// GetRule returns an alert rule as JSON
+func (h *QueueAlertHandler) GetRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert rule ID"})
+		return
+	}
+
+	rule, err := h.queueAlertService.GetRule(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Alert rule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateRule creates an alert rule
+func (h *QueueAlertHandler) CreateRule(c *gin.Context) {
+	var req dto.AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.queueAlertService.CreateRule(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRule updates an alert rule
+func (h *QueueAlertHandler) UpdateRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert rule ID"})
+		return
+	}
+
+	var req dto.AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.queueAlertService.UpdateRule(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule deletes an alert rule
+func (h *QueueAlertHandler) DeleteRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert rule ID"})
+		return
+	}
+
+	if err := h.queueAlertService.DeleteRule(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete alert rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Alert rule deleted successfully"})
+}
+
+// ListAlerts returns the latest alerts as JSON, of one rule when rule_id is set
+func (h *QueueAlertHandler) ListAlerts(c *gin.Context) {
+	ruleID, _ := strconv.Atoi(c.Query("rule_id"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+
+	alerts, err := h.queueAlertService.ListAlerts(c.Request.Context(), ruleID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load queue alerts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, alerts)
+}
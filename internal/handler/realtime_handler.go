@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/middleware"
+	"tenangantri/internal/service"
+	"tenangantri/internal/websocket"
+)
+
+// RealtimeHandler serves the live event stream
+type RealtimeHandler struct {
+	hub           *websocket.Hub
+	deviceService *service.DeviceService
+	cfg           *config.WebSocketConfig
+}
+
+func NewRealtimeHandler(hub *websocket.Hub, deviceService *service.DeviceService, cfg *config.WebSocketConfig) *RealtimeHandler {
+	return &RealtimeHandler{
+		hub:           hub,
+		deviceService: deviceService,
+		cfg:           cfg,
+	}
+}
+
+// ServeWs upgrades the connection for the caller's identity; anonymous callers
+// only get public topics and redacted payloads
+func (h *RealtimeHandler) ServeWs(c *gin.Context) {
+	identity, ok := h.identify(c)
+	if !ok {
+		return
+	}
+
+	websocket.ServeWs(h.hub, c.Writer, c.Request, identity)
+}
+
+// ServeSSE streams the same events as ServeWs over Server-Sent Events, with the
+// same identity rules
+func (h *RealtimeHandler) ServeSSE(c *gin.Context) {
+	identity, ok := h.identify(c)
+	if !ok {
+		return
+	}
+
+	websocket.ServeSSE(h.hub, c.Writer, c.Request, identity)
+}
+
+// IssueStreamToken returns a short-lived token for opening the stream where the
+// auth_token cookie is not sent, e.g. from another allowed origin
+func (h *RealtimeHandler) IssueStreamToken(c *gin.Context) {
+	token, err := middleware.GenerateStreamToken(middleware.GetCurrentUserID(c), c.GetString("username"), middleware.GetCurrentUserRole(c), h.cfg.TokenTTL)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "IssueStreamToken").Msg("Failed to generate stream token")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":      token,
+		"expires_in": int(h.cfg.TokenTTL.Seconds()),
+	})
+}
+
+// Metrics reports the hub's connection and delivery counters
+func (h *RealtimeHandler) Metrics(c *gin.Context) {
+	c.JSON(http.StatusOK, h.hub.Metrics())
+}
+
+// identify reads the caller's login and, for a paired kiosk or display, the device token
+// it passes as ?device_token=, which is the only way to join that device's topic
+func (h *RealtimeHandler) identify(c *gin.Context) (websocket.Identity, bool) {
+	claims, err := middleware.StreamClaims(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		return websocket.Identity{}, false
+	}
+
+	var identity websocket.Identity
+	if claims != nil {
+		identity = websocket.Identity{UserID: int(claims.UserID), Role: claims.Role}
+	}
+
+	if token := c.Query("device_token"); token != "" {
+		device, err := h.deviceService.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			log.Error().Err(err).Str("layer", "handler").Str("func", "identify").Msg("Failed to load device")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load device"})
+			return websocket.Identity{}, false
+		}
+		if device == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unknown device token"})
+			return websocket.Identity{}, false
+		}
+		identity.DeviceID = device.ID
+	}
+	return identity, true
+}
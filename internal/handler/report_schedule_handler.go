@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+	"tenangantri/internal/service"
+)
+
+// ReportScheduleHandler handles admin management of scheduled report emails
+type ReportScheduleHandler struct {
+	reportScheduleService *service.ReportScheduleService
+}
+
+func NewReportScheduleHandler(reportScheduleService *service.ReportScheduleService) *ReportScheduleHandler {
+	return &ReportScheduleHandler{
+		reportScheduleService: reportScheduleService,
+	}
+}
+
+// ListSchedules shows report schedules and their run history
+func (h *ReportScheduleHandler) ListSchedules(c *gin.Context) {
+	ctx := c.Request.Context()
+	scheduleID, _ := strconv.Atoi(c.Query("schedule_id"))
+
+	schedules, err := h.reportScheduleService.ListSchedules(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListSchedules").Msg("Failed to load report schedules")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load report schedules"})
+		return
+	}
+
+	runs, err := h.reportScheduleService.ListRuns(ctx, scheduleID, 100)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListSchedules").Msg("Failed to load report runs")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load report runs"})
+		return
+	}
+
+	categories, counters, err := h.reportScheduleService.ListChoices(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListSchedules").Msg("Failed to load categories and counters")
+		categories = []model.Category{}
+		counters = []model.Counter{}
+	}
+
+	c.HTML(http.StatusOK, "pages/admin/report_schedules.html", gin.H{
+		"Schedules":   schedules,
+		"Runs":        runs,
+		"Categories":  categories,
+		"Counters":    counters,
+		"ReportTypes": model.ReportTypes,
+		"Formats":     model.ReportFormats,
+		"ScheduleID":  scheduleID,
+		"ActiveTab":   "report_schedules",
+	})
+}
+
+// GetSchedule returns a report schedule as JSON
+func (h *ReportScheduleHandler) GetSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report schedule ID"})
+		return
+	}
+
+	schedule, err := h.reportScheduleService.GetSchedule(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report schedule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// CreateSchedule creates a report schedule
+func (h *ReportScheduleHandler) CreateSchedule(c *gin.Context) {
+	var req dto.ReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.reportScheduleService.CreateSchedule(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// UpdateSchedule updates a report schedule
+func (h *ReportScheduleHandler) UpdateSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report schedule ID"})
+		return
+	}
+
+	var req dto.ReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.reportScheduleService.UpdateSchedule(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule deletes a report schedule
+func (h *ReportScheduleHandler) DeleteSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report schedule ID"})
+		return
+	}
+
+	if err := h.reportScheduleService.DeleteSchedule(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete report schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Report schedule deleted successfully"})
+}
+
+// RunSchedule queues a run of a schedule right away
+func (h *ReportScheduleHandler) RunSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report schedule ID"})
+		return
+	}
+
+	runID, err := h.reportScheduleService.RunNow(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report schedule not found"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Laporan sedang dikirim", "run_id": runID})
+}
+
+// ListRuns returns the run history as JSON
+func (h *ReportScheduleHandler) ListRuns(c *gin.Context) {
+	scheduleID, _ := strconv.Atoi(c.Query("schedule_id"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+
+	runs, err := h.reportScheduleService.ListRuns(c.Request.Context(), scheduleID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load report runs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+// DownloadRun sends the report a run rendered
+func (h *ReportScheduleHandler) DownloadRun(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report run ID"})
+		return
+	}
+
+	run, err := h.reportScheduleService.GetRun(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report run not found"})
+		return
+	}
+	path := h.reportScheduleService.RunFile(run)
+	if run.FileName == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report has not been rendered yet"})
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report file is no longer available"})
+		return
+	}
+
+	// The run ID prefix only keeps stored names unique
+	filename := run.FileName[strings.IndexByte(run.FileName, '_')+1:]
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.File(path)
+}
+
+// RetryRun queues a run to be rendered and sent again
+func (h *ReportScheduleHandler) RetryRun(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report run ID"})
+		return
+	}
+
+	if err := h.reportScheduleService.RetryRun(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report run not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pengiriman dijadwalkan ulang"})
+}
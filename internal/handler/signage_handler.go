@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+	"tenangantri/internal/service"
+)
+
+// SignageHandler serves signage media and playlists to displays and their admin management
+type SignageHandler struct {
+	signageService *service.SignageService
+	maxUploadSize  int64
+}
+
+func NewSignageHandler(signageService *service.SignageService, maxUploadSize int64) *SignageHandler {
+	return &SignageHandler{
+		signageService: signageService,
+		maxUploadSize:  maxUploadSize,
+	}
+}
+
+// GetPlayback returns the playlist a display profile should loop now
+func (h *SignageHandler) GetPlayback(c *gin.Context) {
+	playback, err := h.signageService.CurrentPlayback(c.Request.Context(), c.Param("slug"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.JSON(http.StatusOK, playback)
+}
+
+// ServeMedia streams a stored media file. Files are named by content hash and never
+// change, so displays may cache them; Range requests let videos seek.
+func (h *SignageHandler) ServeMedia(c *gin.Context) {
+	path, err := h.signageService.MediaPath(c.Param("file"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	maxAge := int(h.signageService.MediaCacheMaxAge().Seconds())
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, immutable", maxAge))
+	c.File(path)
+}
+
+// ShowSignage shows the signage admin page
+func (h *SignageHandler) ShowSignage(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	media, err := h.signageService.ListMedia(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ShowSignage").Msg("Failed to load signage media")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load signage"})
+		return
+	}
+
+	playlists, err := h.signageService.ListPlaylists(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ShowSignage").Msg("Failed to load signage playlists")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load signage"})
+		return
+	}
+
+	schedules, err := h.signageService.ListSchedules(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ShowSignage").Msg("Failed to load signage schedules")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load signage"})
+		return
+	}
+
+	profiles, err := h.signageService.ListProfiles(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ShowSignage").Msg("Failed to load display profiles")
+		profiles = []model.DisplayProfile{}
+	}
+
+	c.HTML(http.StatusOK, "pages/admin/signage.html", gin.H{
+		"Media":         media,
+		"Playlists":     playlists,
+		"Schedules":     schedules,
+		"Profiles":      profiles,
+		"MaxUploadSize": h.maxUploadSize >> 20,
+		"ActiveTab":     "signage",
+	})
+}
+
+// UploadMedia stores an uploaded image or MP4
+func (h *SignageHandler) UploadMedia(c *gin.Context) {
+	// Leave a megabyte for the multipart headers; the service enforces the exact limit
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadSize+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required and must be at most " + strconv.FormatInt(h.maxUploadSize>>20, 10) + " MB"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read upload"})
+		return
+	}
+	defer file.Close()
+
+	name := c.PostForm("name")
+	if name == "" {
+		name = fileHeader.Filename
+	}
+
+	media, err := h.signageService.UploadMedia(c.Request.Context(), name, file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// DeleteMedia deletes a media file and removes it from playlists
+func (h *SignageHandler) DeleteMedia(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return
+	}
+
+	if err := h.signageService.DeleteMedia(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Media deleted successfully"})
+}
+
+// GetPlaylist returns a playlist with its items
+func (h *SignageHandler) GetPlaylist(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playlist ID"})
+		return
+	}
+
+	playlist, err := h.signageService.GetPlaylist(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, playlist)
+}
+
+// CreatePlaylist creates a playlist
+func (h *SignageHandler) CreatePlaylist(c *gin.Context) {
+	var req dto.SignagePlaylistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	playlist, err := h.signageService.CreatePlaylist(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, playlist)
+}
+
+// UpdatePlaylist renames a playlist and replaces its items
+func (h *SignageHandler) UpdatePlaylist(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playlist ID"})
+		return
+	}
+
+	var req dto.SignagePlaylistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	playlist, err := h.signageService.UpdatePlaylist(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, playlist)
+}
+
+// DeletePlaylist deletes a playlist
+func (h *SignageHandler) DeletePlaylist(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playlist ID"})
+		return
+	}
+
+	if err := h.signageService.DeletePlaylist(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete playlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Playlist deleted successfully"})
+}
+
+// GetSchedule returns a schedule
+func (h *SignageHandler) GetSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID"})
+		return
+	}
+
+	schedule, err := h.signageService.GetSchedule(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// CreateSchedule creates a schedule
+func (h *SignageHandler) CreateSchedule(c *gin.Context) {
+	var req dto.SignageScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.signageService.CreateSchedule(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// UpdateSchedule updates a schedule
+func (h *SignageHandler) UpdateSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID"})
+		return
+	}
+
+	var req dto.SignageScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.signageService.UpdateSchedule(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule deletes a schedule
+func (h *SignageHandler) DeleteSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID"})
+		return
+	}
+
+	if err := h.signageService.DeleteSchedule(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Schedule deleted successfully"})
+}
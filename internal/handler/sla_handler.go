@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/middleware"
+	"tenangantri/internal/service"
+)
+
+// SLAHandler lists the tickets that are near or past their category's SLA target
+type SLAHandler struct {
+	slaService *service.SLAService
+}
+
+func NewSLAHandler(slaService *service.SLAService) *SLAHandler {
+	return &SLAHandler{
+		slaService: slaService,
+	}
+}
+
+// Live returns every flagged ticket for the admin dashboard
+func (h *SLAHandler) Live(c *gin.Context) {
+	tickets, err := h.slaService.Live(c.Request.Context())
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "Live").Msg("Failed to load SLA tickets")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load SLA status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tickets": tickets})
+}
+
+// StaffLive returns the flagged tickets of the categories served at the staff member's counter
+func (h *SLAHandler) StaffLive(c *gin.Context) {
+	tickets, err := h.slaService.LiveForStaff(c.Request.Context(), middleware.GetCurrentUserID(c))
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "StaffLive").Msg("Failed to load SLA tickets")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load SLA status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tickets": tickets})
+}
@@ -9,19 +9,16 @@ import (
 
 	"tenangantri/internal/middleware"
 	"tenangantri/internal/service"
-	"tenangantri/internal/websocket"
 )
 
 // StaffHandler handles staff-related requests
 type StaffHandler struct {
 	staffService *service.StaffService
-	hub          *websocket.Hub
 }
 
-func NewStaffHandler(staffService *service.StaffService, hub *websocket.Hub) *StaffHandler {
+func NewStaffHandler(staffService *service.StaffService) *StaffHandler {
 	return &StaffHandler{
 		staffService: staffService,
-		hub:          hub,
 	}
 }
 
@@ -44,10 +41,12 @@ func (h *StaffHandler) Dashboard(c *gin.Context) {
 		"User":             data.User,
 		"Counter":          data.Counter,
 		"CurrentTicket":    data.CurrentTicket,
+		"CurrentCalls":     data.CurrentCalls,
 		"WaitingTickets":   data.WaitingTickets,
 		"QueueStats":       data.QueueStats,
 		"CompletedTickets": data.CompletedTickets,
 		"CategoryIDs":      data.CategoryIDs,
+		"MyStats":          data.MyStats,
 	})
 }
 
@@ -66,8 +65,6 @@ func (h *StaffHandler) CallNext(c *gin.Context) {
 		return
 	}
 
-	h.hub.BroadcastTicketUpdate(ticket)
-
 	c.JSON(http.StatusOK, ticket)
 }
 
@@ -86,8 +83,6 @@ func (h *StaffHandler) CallAgain(c *gin.Context) {
 		return
 	}
 
-	h.hub.BroadcastTicketUpdate(ticket)
-
 	c.JSON(http.StatusOK, ticket)
 }
 
@@ -101,9 +96,6 @@ func (h *StaffHandler) CompleteTicket(c *gin.Context) {
 		return
 	}
 
-	// Broadcast updates
-	h.hub.Broadcast("ticket_completed", gin.H{"message": "Ticket completed successfully"})
-
 	c.JSON(http.StatusOK, gin.H{"message": "Ticket completed successfully"})
 }
 
@@ -117,12 +109,27 @@ func (h *StaffHandler) MarkNoShow(c *gin.Context) {
 		return
 	}
 
-	// Broadcast updates
-	h.hub.Broadcast("ticket_no_show", gin.H{"message": "Ticket marked as no-show"})
-
 	c.JSON(http.StatusOK, gin.H{"message": "Ticket marked as no-show"})
 }
 
+// MarkArrived records that the called customer came to the counter
+func (h *StaffHandler) MarkArrived(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	ticket, err := h.staffService.MarkArrived(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark customer as arrived"})
+		return
+	}
+
+	if ticket == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Tidak ada ticket yang sedang dilayani"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Customer marked as arrived"})
+}
+
 // PauseCounter pauses the counter
 func (h *StaffHandler) PauseCounter(c *gin.Context) {
 	userID := middleware.GetCurrentUserID(c)
@@ -212,9 +219,6 @@ func (h *StaffHandler) TransferTicket(c *gin.Context) {
 		return
 	}
 
-	// Broadcast updates
-	h.hub.BroadcastTicketUpdate(ticket)
-
 	c.JSON(http.StatusOK, ticket)
 }
 
@@ -240,6 +244,23 @@ func (h *StaffHandler) GetTicketDetail(c *gin.Context) {
 	c.JSON(http.StatusOK, ticket)
 }
 
+// GetTicketCalls lists every call, recall and transfer of a ticket
+func (h *StaffHandler) GetTicketCalls(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	calls, err := h.staffService.GetTicketCalls(c.Request.Context(), ticketID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get ticket calls"})
+		return
+	}
+
+	c.JSON(http.StatusOK, calls)
+}
+
 // TicketsPage shows the tickets management page for staff
 func (h *StaffHandler) TicketsPage(c *gin.Context) {
 	userID := middleware.GetCurrentUserID(c)
@@ -322,8 +343,6 @@ func (h *StaffHandler) CancelTicket(c *gin.Context) {
 		return
 	}
 
-	h.hub.Broadcast("ticket_cancelled", gin.H{"message": "Ticket cancelled successfully"})
-
 	c.JSON(http.StatusOK, gin.H{"message": "Ticket cancelled successfully"})
 }
 
@@ -336,7 +355,6 @@ func (h *StaffHandler) ResetYesterdayTickets(c *gin.Context) {
 	}
 
 	message := fmt.Sprintf("%d tiket kemarin berhasil dibatalkan", count)
-	h.hub.Broadcast("yesterday_tickets_reset", gin.H{"message": message})
 
 	c.JSON(http.StatusOK, gin.H{"message": message})
 }
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/middleware"
+	"tenangantri/internal/model"
+	"tenangantri/internal/service"
+)
+
+// StaffPerformanceHandler serves the staff performance report and staff members' own stats
+type StaffPerformanceHandler struct {
+	performanceService *service.StaffPerformanceService
+}
+
+func NewStaffPerformanceHandler(performanceService *service.StaffPerformanceService) *StaffPerformanceHandler {
+	return &StaffPerformanceHandler{
+		performanceService: performanceService,
+	}
+}
+
+// ShowReport shows the staff performance page
+func (h *StaffPerformanceHandler) ShowReport(c *gin.Context) {
+	staff, err := h.performanceService.ListStaff(c.Request.Context())
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ShowReport").Msg("Failed to load staff")
+		staff = []model.User{}
+	}
+
+	c.HTML(http.StatusOK, "pages/admin/staff_performance.html", gin.H{
+		"Staff":     staff,
+		"ActiveTab": "staff_performance",
+	})
+}
+
+// GetReport returns the staff performance of a date range as JSON
+func (h *StaffPerformanceHandler) GetReport(c *gin.Context) {
+	var req dto.StaffPerformanceRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter, err := h.performanceService.Filter(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.performanceService.Report(c.Request.Context(), filter)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "GetReport").Msg("Failed to build staff performance report")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load staff performance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// MyStats returns the signed-in staff member's performance today
+func (h *StaffPerformanceHandler) MyStats(c *gin.Context) {
+	stats, err := h.performanceService.Today(c.Request.Context(), middleware.GetCurrentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
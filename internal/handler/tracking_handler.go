@@ -7,17 +7,20 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 
+	"tenangantri/internal/dto"
 	"tenangantri/internal/service"
 )
 
 // TrackingHandler handles ticket tracking requests
 type TrackingHandler struct {
 	trackingService *service.TrackingService
+	pushService     *service.PushService
 }
 
-func NewTrackingHandler(trackingService *service.TrackingService) *TrackingHandler {
+func NewTrackingHandler(trackingService *service.TrackingService, pushService *service.PushService) *TrackingHandler {
 	return &TrackingHandler{
 		trackingService: trackingService,
+		pushService:     pushService,
 	}
 }
 
@@ -50,3 +53,43 @@ func (h *TrackingHandler) GetTrackingInfo(c *gin.Context) {
 		"TrackingInfo": trackingInfo,
 	})
 }
+
+// GetPushPublicKey returns the VAPID public key used to subscribe to Web Push
+func (h *TrackingHandler) GetPushPublicKey(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"public_key": h.pushService.PublicKey()})
+}
+
+// SubscribePush stores a Web Push subscription for a ticket
+func (h *TrackingHandler) SubscribePush(c *gin.Context) {
+	var req dto.PushSubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.TicketNumber = strings.ToUpper(strings.TrimSpace(req.TicketNumber))
+
+	if err := h.pushService.Subscribe(c.Request.Context(), &req); err != nil {
+		log.Error().Err(err).Str("ticket_number", req.TicketNumber).Msg("Failed to subscribe to push notifications")
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Notifikasi diaktifkan"})
+}
+
+// UnsubscribePush removes a Web Push subscription from a ticket
+func (h *TrackingHandler) UnsubscribePush(c *gin.Context) {
+	var req dto.PushUnsubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.TicketNumber = strings.ToUpper(strings.TrimSpace(req.TicketNumber))
+
+	if err := h.pushService.Unsubscribe(c.Request.Context(), req.TicketNumber, req.Endpoint); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notifikasi dinonaktifkan"})
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+	"tenangantri/internal/service"
+)
+
+// WebhookHandler handles admin management of outbound webhooks
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// ListWebhooks shows webhook subscriptions and the delivery log
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	ctx := c.Request.Context()
+	status := c.Query("status")
+	subscriptionID, _ := strconv.Atoi(c.Query("subscription_id"))
+
+	subscriptions, err := h.webhookService.ListSubscriptions(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListWebhooks").Msg("Failed to load webhook subscriptions")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load webhooks"})
+		return
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(ctx, status, subscriptionID, 100)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListWebhooks").Msg("Failed to load webhook deliveries")
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Error": "Failed to load webhook deliveries"})
+		return
+	}
+
+	counts, err := h.webhookService.DeliveryCounts(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "handler").Str("func", "ListWebhooks").Msg("Failed to count webhook deliveries")
+		counts = map[string]int{}
+	}
+
+	c.HTML(http.StatusOK, "pages/admin/webhooks.html", gin.H{
+		"Subscriptions":  subscriptions,
+		"Deliveries":     deliveries,
+		"Counts":         counts,
+		"EventTypes":     model.WebhookEventTypes,
+		"StatusFilter":   status,
+		"SubscriptionID": subscriptionID,
+		"ActiveTab":      "webhooks",
+	})
+}
+
+// GetWebhook returns a webhook subscription as JSON
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	sub, err := h.webhookService.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// CreateWebhook creates a webhook subscription
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req dto.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub, err := h.webhookService.CreateSubscription(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+// UpdateWebhook updates a webhook subscription
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	var req dto.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub, err := h.webhookService.UpdateSubscription(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// DeleteWebhook deletes a webhook subscription
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	if err := h.webhookService.DeleteSubscription(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// ListDeliveries returns the delivery log as JSON
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	subscriptionID, _ := strconv.Atoi(c.Query("subscription_id"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+
+	deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), c.Query("status"), subscriptionID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load webhook deliveries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// RedeliverDelivery queues a delivery to be sent again
+func (h *WebhookHandler) RedeliverDelivery(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+
+	if err := h.webhookService.Redeliver(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pengiriman dijadwalkan ulang"})
+}
@@ -0,0 +1,126 @@
+// Package jsonschema derives JSON Schema (draft 2020-12) documents from Go types
+// using the same field names and omitempty rules as encoding/json.
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON Schema node. Maps marshal with sorted keys, so the output of
+// a given set of types is always byte-for-byte the same.
+type Schema map[string]interface{}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Reflector builds schemas and collects every named struct it meets under Defs
+type Reflector struct {
+	Defs map[string]Schema
+}
+
+func NewReflector() *Reflector {
+	return &Reflector{Defs: make(map[string]Schema)}
+}
+
+// Reflect returns the schema of v's type. Named structs become references into Defs.
+func (r *Reflector) Reflect(v interface{}) Schema {
+	return r.reflect(reflect.TypeOf(v))
+}
+
+func (r *Reflector) reflect(t reflect.Type) Schema {
+	switch t {
+	case timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(r.reflect(t.Elem()))
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Schema{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": r.reflect(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": r.reflect(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.object(t)
+		}
+		if _, ok := r.Defs[t.Name()]; !ok {
+			// Reserve the name first so recursive types terminate
+			r.Defs[t.Name()] = Schema{}
+			r.Defs[t.Name()] = r.object(t)
+		}
+		return Schema{"$ref": "#/$defs/" + t.Name()}
+	default:
+		return Schema{}
+	}
+}
+
+// object lists a struct's JSON fields. Fields without omitempty are required, and
+// nil slices and maps among them may be null, as encoding/json writes them.
+func (r *Reflector) object(t reflect.Type) Schema {
+	properties := Schema{}
+	required := []string{}
+	r.fields(t, properties, &required)
+
+	schema := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (r *Reflector) fields(t reflect.Type, properties Schema, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			r.fields(field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		omitEmpty := strings.Contains(options, "omitempty")
+		schema := r.reflect(field.Type)
+		if !omitEmpty {
+			*required = append(*required, name)
+			if k := field.Type.Kind(); (k == reflect.Slice || k == reflect.Map) && field.Type != rawMessageType {
+				schema = nullable(schema)
+			}
+		} else if field.Type.Kind() == reflect.Ptr {
+			// A nil pointer is left out rather than written as null
+			schema = r.reflect(field.Type.Elem())
+		}
+		properties[name] = schema
+	}
+}
+
+func nullable(schema Schema) Schema {
+	return Schema{"anyOf": []Schema{schema, {"type": "null"}}}
+}
@@ -0,0 +1,58 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type inner struct {
+	Name string `json:"name"`
+}
+
+type sample struct {
+	ID       int             `json:"id"`
+	Label    string          `json:"label,omitempty"`
+	At       time.Time       `json:"at"`
+	Done     *time.Time      `json:"done,omitempty"`
+	Tags     []string        `json:"tags"`
+	Counts   map[string]int  `json:"counts,omitempty"`
+	Inner    inner           `json:"inner"`
+	Raw      json.RawMessage `json:"raw"`
+	Skipped  string          `json:"-"`
+	internal string
+	Nested   map[string]*inner `json:"nested,omitempty"`
+}
+
+func TestReflector_Reflect(t *testing.T) {
+	r := NewReflector()
+	assert.Equal(t, Schema{"$ref": "#/$defs/sample"}, r.Reflect(sample{}))
+
+	got, err := json.Marshal(r.Defs)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"inner": {
+			"type": "object",
+			"properties": {"name": {"type": "string"}},
+			"required": ["name"]
+		},
+		"sample": {
+			"type": "object",
+			"properties": {
+				"id": {"type": "integer"},
+				"label": {"type": "string"},
+				"at": {"type": "string", "format": "date-time"},
+				"done": {"type": "string", "format": "date-time"},
+				"tags": {"anyOf": [{"type": "array", "items": {"type": "string"}}, {"type": "null"}]},
+				"counts": {"type": "object", "additionalProperties": {"type": "integer"}},
+				"inner": {"$ref": "#/$defs/inner"},
+				"raw": {},
+				"nested": {"type": "object", "additionalProperties": {"anyOf": [{"$ref": "#/$defs/inner"}, {"type": "null"}]}}
+			},
+			"required": ["id", "at", "tags", "inner", "raw"]
+		}
+	}`, string(got))
+}
@@ -0,0 +1,223 @@
+// Package mail sends email with attachments over SMTP.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"tenangantri/internal/config"
+)
+
+// Attachment is a file sent along with a message; Content is read once while sending
+type Attachment struct {
+	Name        string
+	ContentType string
+	Content     io.Reader
+}
+
+// Message is a plain text email with optional attachments
+type Message struct {
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// Sender delivers messages through one SMTP server
+type Sender struct {
+	cfg *config.MailConfig
+	now func() time.Time
+}
+
+func NewSender(cfg *config.MailConfig) *Sender {
+	return &Sender{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// Configured reports whether an SMTP server is set
+func (s *Sender) Configured() bool {
+	return s.cfg.Host != ""
+}
+
+// Send delivers msg to all its recipients. The connection is upgraded with STARTTLS when the
+// server offers it, and authenticated when a username is configured.
+func (s *Sender) Send(ctx context.Context, msg *Message) error {
+	if !s.Configured() {
+		return fmt.Errorf("SMTP is not configured")
+	}
+	if len(msg.To) == 0 {
+		return fmt.Errorf("message has no recipients")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(s.cfg.Host, s.cfg.Port)
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(envelopeAddress(s.cfg.From)); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("recipient %s: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if err := s.write(w, msg); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// write writes msg as a MIME message
+func (s *Sender) write(w io.Writer, msg *Message) error {
+	mw := multipart.NewWriter(w)
+
+	var header bytes.Buffer
+	fmt.Fprintf(&header, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&header, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&header, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&header, "Date: %s\r\n", s.now().Format(time.RFC1123Z))
+	fmt.Fprintf(&header, "Message-ID: %s\r\n", messageID(s.cfg.From))
+	header.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&header, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mw.Boundary())
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return err
+	}
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+	if err := writeBase64(part, strings.NewReader(msg.Body)); err != nil {
+		return err
+	}
+
+	for _, attachment := range msg.Attachments {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(attachment.ContentType, map[string]string{"name": attachment.Name})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return err
+		}
+		if err := writeBase64(part, attachment.Content); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+// writeBase64 copies r to w as base64 in lines of 76 characters
+func writeBase64(w io.Writer, r io.Reader) error {
+	lines := &lineWriter{w: w}
+	enc := base64.NewEncoder(base64.StdEncoding, lines)
+	if _, err := io.Copy(enc, r); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if lines.n > 0 {
+		_, err := io.WriteString(w, "\r\n")
+		return err
+	}
+	return nil
+}
+
+// lineWriter breaks what it writes into lines of 76 bytes
+type lineWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := min(76-l.n, len(p))
+		if _, err := l.w.Write(p[:chunk]); err != nil {
+			return written, err
+		}
+		written += chunk
+		l.n += chunk
+		p = p[chunk:]
+		if l.n == 76 {
+			if _, err := io.WriteString(l.w, "\r\n"); err != nil {
+				return written, err
+			}
+			l.n = 0
+		}
+	}
+	return written, nil
+}
+
+// envelopeAddress is the bare address of "Name <address>"
+func envelopeAddress(from string) string {
+	if i := strings.LastIndexByte(from, '<'); i >= 0 {
+		return strings.TrimSuffix(from[i+1:], ">")
+	}
+	return from
+}
+
+func messageID(from string) string {
+	buf := make([]byte, 12)
+	rand.Read(buf)
+	domain := "localhost"
+	if _, d, ok := strings.Cut(envelopeAddress(from), "@"); ok {
+		domain = d
+	}
+	return "<" + hex.EncodeToString(buf) + "@" + domain + ">"
+}
@@ -0,0 +1,187 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+)
+
+// sink is a local SMTP server that accepts one message per connection
+type sink struct {
+	ln       net.Listener
+	rejectTo string
+	messages chan sinkMessage
+}
+
+type sinkMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+func newSink(t *testing.T) *sink {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &sink{ln: ln, messages: make(chan sinkMessage, 4)}
+	t.Cleanup(func() { ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *sink) config() *config.MailConfig {
+	host, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	return &config.MailConfig{Host: host, Port: port, From: "TenangAntri <laporan@tenangantri.test>", Timeout: 5 * time.Second}
+}
+
+func (s *sink) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *sink) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	var msg sinkMessage
+	reply("220 sink ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimSpace(line)
+		upper := strings.ToUpper(cmd)
+		switch {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 sink")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			msg.from = strings.Trim(cmd[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			to := strings.Trim(cmd[len("RCPT TO:"):], "<>")
+			if to == s.rejectTo {
+				reply("550 no such user")
+				continue
+			}
+			msg.to = append(msg.to, to)
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			msg.data = data.String()
+			s.messages <- msg
+			reply("250 queued")
+		case upper == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSender_Send(t *testing.T) {
+	sink := newSink(t)
+	sender := NewSender(sink.config())
+
+	attachment := strings.Repeat("laporan antrian ", 20)
+	err := sender.Send(context.Background(), &Message{
+		To:      []string{"manajer@example.com", "supervisor@example.com"},
+		Subject: "Laporan harian — 18 Oktober",
+		Body:    "Terlampir laporan antrian kemarin.",
+		Attachments: []Attachment{
+			{Name: "laporan.csv", ContentType: "text/csv", Content: strings.NewReader(attachment)},
+		},
+	})
+	require.NoError(t, err)
+
+	got := <-sink.messages
+	assert.Equal(t, "laporan@tenangantri.test", got.from)
+	assert.Equal(t, []string{"manajer@example.com", "supervisor@example.com"}, got.to)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(got.data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Laporan harian — 18 Oktober", subject)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/mixed", mediaType)
+
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	var parts []string
+	var filenames []string
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+		require.NoError(t, err)
+		parts = append(parts, string(content))
+		filenames = append(filenames, part.FileName())
+	}
+	require.Len(t, parts, 2)
+	assert.Equal(t, "Terlampir laporan antrian kemarin.", parts[0])
+	assert.Equal(t, attachment, parts[1])
+	assert.Equal(t, "laporan.csv", filenames[1])
+}
+
+func TestSender_SendRejectedRecipient(t *testing.T) {
+	sink := newSink(t)
+	sink.rejectTo = "hilang@example.com"
+	sender := NewSender(sink.config())
+
+	err := sender.Send(context.Background(), &Message{To: []string{"hilang@example.com"}, Subject: "x", Body: "x"})
+	assert.ErrorContains(t, err, "hilang@example.com")
+}
+
+func TestSender_NotConfigured(t *testing.T) {
+	sender := NewSender(&config.MailConfig{})
+	assert.False(t, sender.Configured())
+	assert.Error(t, sender.Send(context.Background(), &Message{To: []string{"a@example.com"}}))
+}
+
+func TestWriteBase64_WrapsLines(t *testing.T) {
+	var out strings.Builder
+	require.NoError(t, writeBase64(&out, strings.NewReader(strings.Repeat("x", 200))))
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\r\n"), "\r\n")
+	for _, line := range lines[:len(lines)-1] {
+		assert.Len(t, line, 76)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.Join(lines, ""))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("x", 200), string(decoded))
+}
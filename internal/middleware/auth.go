@@ -3,11 +3,13 @@ package middleware
 import (
 	"encoding/json"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"tenangantri/internal/config"
+	"tenangantri/internal/event"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -69,6 +71,55 @@ func GenerateToken(userID int, username, role string, expiry time.Duration) (str
 	return token.SignedString(jwtSecret)
 }
 
+// streamAudience marks short-lived tokens that can only open realtime connections
+const streamAudience = "stream"
+
+// GenerateStreamToken issues a token for clients that cannot send the auth_token
+// cookie when opening /ws or /events, e.g. a page served from another origin
+func GenerateStreamToken(userID int, username, role string, expiry time.Duration) (string, error) {
+	claims := Claims{
+		UserID:   FlexibleInt(userID),
+		Username: username,
+		Role:     role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{streamAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtSecret)
+}
+
+// StreamClaims identifies a realtime connection from the token query parameter or
+// the auth_token cookie. Anonymous clients get nil claims and no error; an invalid
+// explicit token is an error, while a stale cookie just falls back to anonymous.
+func StreamClaims(c *gin.Context) (*Claims, error) {
+	if token := c.Query("token"); token != "" {
+		claims, err := ParseToken(token)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(claims.Audience, streamAudience) {
+			return nil, jwt.ErrTokenInvalidAudience
+		}
+		return claims, nil
+	}
+
+	tokenCookie, err := c.Cookie("auth_token")
+	if err != nil || tokenCookie == "" {
+		return nil, nil
+	}
+
+	claims, err := ParseToken(tokenCookie)
+	if err != nil {
+		log.Debug().Err(err).Msg("Ignoring invalid auth_token cookie on realtime connection")
+		return nil, nil
+	}
+	return claims, nil
+}
+
 func ParseToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		// Verify signing method
@@ -116,9 +167,16 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Stream tokens only open realtime connections
+		if slices.Contains(claims.Audience, streamAudience) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+
 		c.Set("userID", int(claims.UserID))
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Request = c.Request.WithContext(event.WithActor(c.Request.Context(), int(claims.UserID)))
 		c.Next()
 	}
 }
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"net/url"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -21,7 +22,7 @@ func LoggerMiddleware() gin.HandlerFunc {
 		statusCode := c.Writer.Status()
 
 		if raw != "" {
-			path = path + "?" + raw
+			path = path + "?" + redactQuery(raw)
 		}
 
 		log.Info().
@@ -33,3 +34,20 @@ func LoggerMiddleware() gin.HandlerFunc {
 			Msg("Request")
 	}
 }
+
+// redactQuery masks the credentials realtime clients pass in the query string
+func redactQuery(raw string) string {
+	// A malformed pair is dropped, the rest are still parsed and checked
+	values, _ := url.ParseQuery(raw)
+	redacted := false
+	for _, key := range []string{"token", "device_token"} {
+		if values.Has(key) {
+			values.Set(key, "*")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return raw
+	}
+	return values.Encode()
+}
@@ -15,6 +15,9 @@ type Category struct {
 	Description sql.NullString `json:"description" db:"description"`
 	Icon        sql.NullString `json:"icon" db:"icon"`
 	IsActive    bool           `json:"is_active" db:"is_active"`
-	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+	// SLA targets in minutes, 0 when the category has none
+	SLAWaitMinutes    int       `json:"sla_wait_minutes" db:"sla_wait_minutes"`
+	SLAServiceMinutes int       `json:"sla_service_minutes" db:"sla_service_minutes"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Device kinds
+const (
+	DeviceKindKiosk   = "kiosk"
+	DeviceKindDisplay = "display"
+)
+
+// Device statuses shown to admins
+const (
+	DeviceStatusUnpaired = "unpaired"
+	DeviceStatusOnline   = "online"
+	DeviceStatusOffline  = "offline"
+)
+
+// Remote actions an admin can send to a device
+const (
+	DeviceActionReload   = "reload"
+	DeviceActionIdentify = "identify"
+	DeviceActionNavigate = "navigate"
+)
+
+// Device is a kiosk or display screen that reports in with heartbeats
+type Device struct {
+	ID               int            `json:"id" db:"id"`
+	Name             string         `json:"name" db:"name"`
+	Kind             string         `json:"kind" db:"kind"`
+	DisplayProfileID sql.NullInt64  `json:"display_profile_id" db:"display_profile_id"`
+	ProfileSlug      sql.NullString `json:"profile_slug" db:"profile_slug"`
+	KioskProfileID   sql.NullInt64  `json:"kiosk_profile_id" db:"kiosk_profile_id"`
+	KioskProfileSlug sql.NullString `json:"kiosk_profile_slug" db:"kiosk_profile_slug"`
+	PairingCode      sql.NullString `json:"pairing_code" db:"pairing_code"`
+	PairingExpiresAt sql.NullTime   `json:"pairing_expires_at" db:"pairing_expires_at"`
+	TokenHash        sql.NullString `json:"-" db:"token_hash"`
+	PairedAt         sql.NullTime   `json:"paired_at" db:"paired_at"`
+	LastSeenAt       sql.NullTime   `json:"last_seen_at" db:"last_seen_at"`
+	AppVersion       string         `json:"app_version" db:"app_version"`
+	UserAgent        string         `json:"user_agent" db:"user_agent"`
+	CurrentURL       string         `json:"current_url" db:"current_url"`
+	UptimeSeconds    int64          `json:"uptime_seconds" db:"uptime_seconds"`
+	LastEventSeq     int64          `json:"last_event_seq" db:"last_event_seq"`
+	SilentSince      sql.NullTime   `json:"silent_since" db:"silent_since"`
+	Status           string         `json:"status" db:"-"`
+	CreatedAt        time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// IsPaired reports whether the device holds a token
+func (d *Device) IsPaired() bool {
+	return d.TokenHash.Valid
+}
+
+// HomeURL is the page the device should show
+func (d *Device) HomeURL() string {
+	if d.Kind == DeviceKindKiosk {
+		if d.KioskProfileSlug.Valid {
+			return "/kiosk/p/" + d.KioskProfileSlug.String
+		}
+		return "/kiosk"
+	}
+	if d.ProfileSlug.Valid {
+		return "/display/p/" + d.ProfileSlug.String
+	}
+	return "/display"
+}
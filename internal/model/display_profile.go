@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Display profile layouts
+const (
+	DisplayLayoutLandscape = "landscape"
+	DisplayLayoutPortrait  = "portrait"
+)
+
+// DisplayProfile configures what one group of display screens shows.
+// Empty CategoryIDs or CounterIDs means every category or counter.
+type DisplayProfile struct {
+	ID              int       `json:"id" db:"id"`
+	Slug            string    `json:"slug" db:"slug"`
+	Name            string    `json:"name" db:"name"`
+	Layout          string    `json:"layout" db:"layout"`
+	CategoryIDs     []int     `json:"category_ids" db:"category_ids"`
+	CounterIDs      []int     `json:"counter_ids" db:"counter_ids"`
+	RecentCount     int       `json:"recent_count" db:"recent_count"`
+	ShowWaiting     bool      `json:"show_waiting" db:"show_waiting"`
+	AccentColor     string    `json:"accent_color" db:"accent_color"`
+	BackgroundColor string    `json:"background_color" db:"background_color"`
+	TickerText      string    `json:"ticker_text" db:"ticker_text"`
+	IsActive        bool      `json:"is_active" db:"is_active"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+// Kiosk profile languages
+const (
+	KioskLanguageIndonesian = "id"
+	KioskLanguageEnglish    = "en"
+)
+
+// How a kiosk hands out a ticket
+const (
+	KioskOutputPrint = "print"
+	KioskOutputQR    = "qr"
+	KioskOutputBoth  = "both"
+)
+
+// Where a kiosk's tickets are printed
+const (
+	KioskPrinterBrowser = "browser"
+	KioskPrinterNetwork = "network"
+	KioskPrinterFile    = "file"
+)
+
+// KioskProfile configures what one group of kiosks offers and how they look.
+// A profile without categories offers every active category.
+type KioskProfile struct {
+	ID            int                    `json:"id" db:"id"`
+	Slug          string                 `json:"slug" db:"slug"`
+	Name          string                 `json:"name" db:"name"`
+	Language      string                 `json:"language" db:"language"`
+	TicketOutput  string                 `json:"ticket_output" db:"ticket_output"`
+	PrinterType   string                 `json:"printer_type" db:"printer_type"`
+	PrinterTarget string                 `json:"printer_target" db:"printer_target"`
+	IdleSeconds   int                    `json:"idle_seconds" db:"idle_seconds"`
+	AttractTitle  string                 `json:"attract_title" db:"attract_title"`
+	AttractText   string                 `json:"attract_text" db:"attract_text"`
+	IsActive      bool                   `json:"is_active" db:"is_active"`
+	CategoryCount int                    `json:"category_count" db:"category_count"`
+	Categories    []KioskProfileCategory `json:"categories,omitempty" db:"-"`
+	CreatedAt     time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at" db:"updated_at"`
+}
+
+// Prints reports whether tickets are printed
+func (p *KioskProfile) Prints() bool {
+	return p.TicketOutput == KioskOutputPrint || p.TicketOutput == KioskOutputBoth
+}
+
+// PrintsOnServer reports whether the server prints tickets as ESC/POS instead of the kiosk browser
+func (p *KioskProfile) PrintsOnServer() bool {
+	return p.Prints() && (p.PrinterType == KioskPrinterNetwork || p.PrinterType == KioskPrinterFile)
+}
+
+// ShowsQR reports whether tickets show a QR code for the tracking page
+func (p *KioskProfile) ShowsQR() bool {
+	return p.TicketOutput == KioskOutputQR || p.TicketOutput == KioskOutputBoth
+}
+
+// KioskProfileCategory is one service button on a profile's kiosks.
+// Empty Label or Icon use the category's own.
+type KioskProfileCategory struct {
+	ID             int    `json:"id" db:"id"`
+	KioskProfileID int    `json:"kiosk_profile_id" db:"kiosk_profile_id"`
+	CategoryID     int    `json:"category_id" db:"category_id"`
+	Position       int    `json:"position" db:"position"`
+	Label          string `json:"label" db:"label"`
+	Icon           string `json:"icon" db:"icon"`
+	CategoryName   string `json:"category_name" db:"category_name"`
+}
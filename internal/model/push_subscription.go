@@ -0,0 +1,20 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PushSubscription is a browser Web Push subscription attached to a tracked ticket
+type PushSubscription struct {
+	ID                 int          `json:"id" db:"id"`
+	TicketID           int          `json:"ticket_id" db:"ticket_id"`
+	Endpoint           string       `json:"endpoint" db:"endpoint"`
+	P256dh             string       `json:"p256dh" db:"p256dh"`
+	Auth               string       `json:"auth" db:"auth"`
+	NearlyUpNotifiedAt sql.NullTime `json:"nearly_up_notified_at,omitempty" db:"nearly_up_notified_at"`
+	CalledNotifiedAt   sql.NullTime `json:"called_notified_at,omitempty" db:"called_notified_at"`
+	ExpiresAt          time.Time    `json:"expires_at" db:"expires_at"`
+	CreatedAt          time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time    `json:"updated_at" db:"updated_at"`
+}
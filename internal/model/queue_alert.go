@@ -0,0 +1,63 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Alert rule kinds
+const (
+	// AlertKindWaitingCount fires when more than Threshold tickets are waiting
+	AlertKindWaitingCount = "waiting_count"
+	// AlertKindOldestWait fires when the oldest waiting ticket has waited more than Threshold minutes
+	AlertKindOldestWait = "oldest_wait"
+	// AlertKindNoOpenCounter fires when tickets are waiting and no counter serving the category is open
+	AlertKindNoOpenCounter = "no_open_counter"
+)
+
+// AlertKinds lists every kind a rule can use
+var AlertKinds = []string{AlertKindWaitingCount, AlertKindOldestWait, AlertKindNoOpenCounter}
+
+// AlertRule is a threshold on the live queue of one category, or of every category when
+// CategoryID is null. After firing for a category it stays quiet for CooldownMinutes.
+type AlertRule struct {
+	ID              int            `json:"id" db:"id"`
+	Name            string         `json:"name" db:"name"`
+	Kind            string         `json:"kind" db:"kind"`
+	CategoryID      sql.NullInt64  `json:"category_id" db:"category_id"`
+	CategoryName    sql.NullString `json:"category_name" db:"category_name"`
+	Threshold       int            `json:"threshold" db:"threshold"`
+	CooldownMinutes int            `json:"cooldown_minutes" db:"cooldown_minutes"`
+	EmailRecipients []string       `json:"email_recipients" db:"email_recipients"`
+	NotifyWebhook   bool           `json:"notify_webhook" db:"notify_webhook"`
+	IsActive        bool           `json:"is_active" db:"is_active"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// QueueStat is the live queue of one active category
+type QueueStat struct {
+	CategoryID        int    `json:"category_id" db:"category_id"`
+	CategoryName      string `json:"category_name" db:"category_name"`
+	Waiting           int    `json:"waiting" db:"waiting"`
+	OldestWaitSeconds int    `json:"oldest_wait_seconds" db:"oldest_wait_seconds"`
+	OpenCounters      int    `json:"open_counters" db:"open_counters"`
+}
+
+// QueueAlert is one firing of a rule for a category. Value is what was measured, in the
+// unit of the rule's threshold.
+type QueueAlert struct {
+	ID            int            `json:"id" db:"id"`
+	RuleID        sql.NullInt64  `json:"rule_id" db:"rule_id"`
+	RuleName      string         `json:"rule_name" db:"rule_name"`
+	Kind          string         `json:"kind" db:"kind"`
+	CategoryID    sql.NullInt64  `json:"category_id" db:"category_id"`
+	CategoryName  string         `json:"category_name" db:"category_name"`
+	Value         int            `json:"value" db:"value"`
+	Threshold     int            `json:"threshold" db:"threshold"`
+	Message       string         `json:"message" db:"message"`
+	NotifyWebhook bool           `json:"notify_webhook" db:"notify_webhook"`
+	TriggeredAt   time.Time      `json:"triggered_at" db:"triggered_at"`
+	EmailedAt     sql.NullTime   `json:"emailed_at" db:"emailed_at"`
+	EmailError    sql.NullString `json:"email_error" db:"email_error"`
+}
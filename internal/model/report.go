@@ -0,0 +1,157 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ReportFilter selects the tickets a report covers: those taken from the start of
+// DateFrom to the end of DateTo, optionally narrowed down. Zero values do not filter.
+type ReportFilter struct {
+	DateFrom   time.Time `json:"date_from"`
+	DateTo     time.Time `json:"date_to"`
+	CategoryID int       `json:"category_id,omitempty"`
+	CounterID  int       `json:"counter_id,omitempty"`
+	Status     string    `json:"status,omitempty"`
+}
+
+// ReportSLA is how many tickets of a group were measured against their category's SLA
+// targets and met them, and the share that met them as a percentage. Tickets of categories
+// without a target are not measured; waits count once a ticket is called or breached its
+// target, service once a ticket is completed.
+type ReportSLA struct {
+	SLAWaitTickets       int     `json:"sla_wait_tickets" db:"sla_wait_tickets"`
+	SLAWaitMet           int     `json:"sla_wait_met" db:"sla_wait_met"`
+	SLAWaitCompliance    float64 `json:"sla_wait_compliance" db:"sla_wait_compliance"`
+	SLAServiceTickets    int     `json:"sla_service_tickets" db:"sla_service_tickets"`
+	SLAServiceMet        int     `json:"sla_service_met" db:"sla_service_met"`
+	SLAServiceCompliance float64 `json:"sla_service_compliance" db:"sla_service_compliance"`
+}
+
+// ReportSummary holds the totals of a report. Rates are percentages of all tickets and
+// times are in seconds, over the tickets that have them.
+type ReportSummary struct {
+	TotalTickets         int     `json:"total_tickets" db:"total_tickets"`
+	Waiting              int     `json:"waiting" db:"waiting"`
+	Serving              int     `json:"serving" db:"serving"`
+	Completed            int     `json:"completed" db:"completed"`
+	NoShow               int     `json:"no_show" db:"no_show"`
+	Cancelled            int     `json:"cancelled" db:"cancelled"`
+	CompletionRate       float64 `json:"completion_rate" db:"completion_rate"`
+	NoShowRate           float64 `json:"no_show_rate" db:"no_show_rate"`
+	AvgWaitSeconds       float64 `json:"avg_wait_seconds" db:"avg_wait_seconds"`
+	MedianWaitSeconds    float64 `json:"median_wait_seconds" db:"median_wait_seconds"`
+	P90WaitSeconds       float64 `json:"p90_wait_seconds" db:"p90_wait_seconds"`
+	AvgServiceSeconds    float64 `json:"avg_service_seconds" db:"avg_service_seconds"`
+	MedianServiceSeconds float64 `json:"median_service_seconds" db:"median_service_seconds"`
+	P90ServiceSeconds    float64 `json:"p90_service_seconds" db:"p90_service_seconds"`
+	ReportSLA
+}
+
+// ReportCategory is one category's line of a report. Tickets whose category was
+// deleted are grouped under CategoryID 0.
+type ReportCategory struct {
+	CategoryID           int     `json:"category_id" db:"category_id"`
+	Name                 string  `json:"name" db:"name"`
+	Prefix               string  `json:"prefix" db:"prefix"`
+	ColorCode            string  `json:"color_code" db:"color_code"`
+	Total                int     `json:"total" db:"total"`
+	Completed            int     `json:"completed" db:"completed"`
+	NoShow               int     `json:"no_show" db:"no_show"`
+	CompletionRate       float64 `json:"completion_rate" db:"completion_rate"`
+	NoShowRate           float64 `json:"no_show_rate" db:"no_show_rate"`
+	AvgWaitSeconds       float64 `json:"avg_wait_seconds" db:"avg_wait_seconds"`
+	MedianWaitSeconds    float64 `json:"median_wait_seconds" db:"median_wait_seconds"`
+	P90WaitSeconds       float64 `json:"p90_wait_seconds" db:"p90_wait_seconds"`
+	AvgServiceSeconds    float64 `json:"avg_service_seconds" db:"avg_service_seconds"`
+	MedianServiceSeconds float64 `json:"median_service_seconds" db:"median_service_seconds"`
+	P90ServiceSeconds    float64 `json:"p90_service_seconds" db:"p90_service_seconds"`
+	ReportSLA
+}
+
+// ReportCounter is one counter's line of a report, over the tickets it called
+type ReportCounter struct {
+	CounterID            int     `json:"counter_id" db:"counter_id"`
+	Number               string  `json:"number" db:"number"`
+	Name                 string  `json:"name" db:"name"`
+	Total                int     `json:"total" db:"total"`
+	Completed            int     `json:"completed" db:"completed"`
+	NoShow               int     `json:"no_show" db:"no_show"`
+	CompletionRate       float64 `json:"completion_rate" db:"completion_rate"`
+	NoShowRate           float64 `json:"no_show_rate" db:"no_show_rate"`
+	AvgWaitSeconds       float64 `json:"avg_wait_seconds" db:"avg_wait_seconds"`
+	MedianWaitSeconds    float64 `json:"median_wait_seconds" db:"median_wait_seconds"`
+	P90WaitSeconds       float64 `json:"p90_wait_seconds" db:"p90_wait_seconds"`
+	AvgServiceSeconds    float64 `json:"avg_service_seconds" db:"avg_service_seconds"`
+	MedianServiceSeconds float64 `json:"median_service_seconds" db:"median_service_seconds"`
+	P90ServiceSeconds    float64 `json:"p90_service_seconds" db:"p90_service_seconds"`
+}
+
+// ReportDay is one day's line of a report
+type ReportDay struct {
+	Day               time.Time `json:"day" db:"day"`
+	Total             int       `json:"total" db:"total"`
+	Completed         int       `json:"completed" db:"completed"`
+	NoShow            int       `json:"no_show" db:"no_show"`
+	Cancelled         int       `json:"cancelled" db:"cancelled"`
+	AvgWaitSeconds    float64   `json:"avg_wait_seconds" db:"avg_wait_seconds"`
+	AvgServiceSeconds float64   `json:"avg_service_seconds" db:"avg_service_seconds"`
+	ReportSLA
+}
+
+// ReportStaff is one staff member's line of a report, over the tickets they last called.
+// Tickets called by a since deleted user are grouped under UserID 0.
+type ReportStaff struct {
+	UserID            int     `json:"user_id" db:"user_id"`
+	FullName          string  `json:"full_name" db:"full_name"`
+	Username          string  `json:"username" db:"username"`
+	Total             int     `json:"total" db:"total"`
+	Completed         int     `json:"completed" db:"completed"`
+	NoShow            int     `json:"no_show" db:"no_show"`
+	AvgServiceSeconds float64 `json:"avg_service_seconds" db:"avg_service_seconds"`
+}
+
+// ReportHour is the tickets taken in one hour of the day, over all days of a report
+type ReportHour struct {
+	Hour           int     `json:"hour" db:"hour"`
+	Tickets        int     `json:"tickets" db:"tickets"`
+	AvgWaitSeconds float64 `json:"avg_wait_seconds" db:"avg_wait_seconds"`
+}
+
+// ReportWeekday is the tickets taken on one ISO day of the week (1 is Monday), over all weeks of a report
+type ReportWeekday struct {
+	Weekday        int     `json:"weekday" db:"weekday"`
+	Tickets        int     `json:"tickets" db:"tickets"`
+	AvgWaitSeconds float64 `json:"avg_wait_seconds" db:"avg_wait_seconds"`
+}
+
+// ReportTicket is a ticket as reports list it, with names instead of IDs. The staff
+// member is whoever finished the ticket or, when it was finished without one, whoever
+// called it.
+type ReportTicket struct {
+	TicketNumber  string         `json:"ticket_number" db:"ticket_number"`
+	CategoryName  string         `json:"category_name" db:"category_name"`
+	CounterNumber string         `json:"counter_number" db:"counter_number"`
+	StaffName     string         `json:"staff_name" db:"staff_name"`
+	Status        string         `json:"status" db:"status"`
+	Priority      int            `json:"priority" db:"priority"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	CalledAt      sql.NullTime   `json:"called_at" db:"called_at"`
+	CompletedAt   sql.NullTime   `json:"completed_at" db:"completed_at"`
+	WaitTime      sql.NullInt64  `json:"wait_time" db:"wait_time"`
+	ServiceTime   sql.NullInt64  `json:"service_time" db:"service_time"`
+	Notes         sql.NullString `json:"notes" db:"notes"`
+}
+
+// Report is the aggregate view of the tickets a filter selects
+type Report struct {
+	Filter     ReportFilter     `json:"filter"`
+	Summary    ReportSummary    `json:"summary"`
+	Categories []ReportCategory `json:"categories"`
+	Counters   []ReportCounter  `json:"counters"`
+	Days       []ReportDay      `json:"days"`
+	// Hourly has all 24 hours, including those without tickets
+	Hourly []ReportHour `json:"hourly"`
+	// Weekdays has all seven days from Monday, including those without tickets
+	Weekdays []ReportWeekday `json:"weekdays"`
+}
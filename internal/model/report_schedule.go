@@ -0,0 +1,77 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Report types of a schedule, named after the period each run covers
+const (
+	ReportTypeDaily   = "daily"
+	ReportTypeWeekly  = "weekly"
+	ReportTypeMonthly = "monthly"
+)
+
+// ReportTypes lists every report type a schedule can use
+var ReportTypes = []string{ReportTypeDaily, ReportTypeWeekly, ReportTypeMonthly}
+
+// Report file formats
+const (
+	ReportFormatPDF  = "pdf"
+	ReportFormatXLSX = "xlsx"
+	ReportFormatCSV  = "csv"
+)
+
+// ReportFormats lists every format a schedule can send
+var ReportFormats = []string{ReportFormatPDF, ReportFormatXLSX, ReportFormatCSV}
+
+// Report run statuses
+const (
+	ReportRunPending = "pending"
+	ReportRunSent    = "sent"
+	ReportRunFailed  = "failed"
+)
+
+// ReportSchedule emails a report to its recipients whenever its cron expression fires
+type ReportSchedule struct {
+	ID         int           `json:"id" db:"id"`
+	Name       string        `json:"name" db:"name"`
+	ReportType string        `json:"report_type" db:"report_type"`
+	Format     string        `json:"format" db:"format"`
+	CategoryID sql.NullInt64 `json:"category_id" db:"category_id"`
+	CounterID  sql.NullInt64 `json:"counter_id" db:"counter_id"`
+	Status     string        `json:"status" db:"status"`
+	Recipients []string      `json:"recipients" db:"recipients"`
+	Cron       string        `json:"cron" db:"cron"`
+	IsActive   bool          `json:"is_active" db:"is_active"`
+	NextRunAt  sql.NullTime  `json:"next_run_at" db:"next_run_at"`
+	LastRunAt  sql.NullTime  `json:"last_run_at" db:"last_run_at"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// ReportRun is one rendering and delivery of a schedule's report
+type ReportRun struct {
+	ID            int64          `json:"id" db:"id"`
+	ScheduleID    int            `json:"schedule_id" db:"schedule_id"`
+	ScheduledFor  time.Time      `json:"scheduled_for" db:"scheduled_for"`
+	PeriodFrom    time.Time      `json:"period_from" db:"period_from"`
+	PeriodTo      time.Time      `json:"period_to" db:"period_to"`
+	Format        string         `json:"format" db:"format"`
+	Recipients    []string       `json:"recipients" db:"recipients"`
+	Status        string         `json:"status" db:"status"`
+	Attempts      int            `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time      `json:"next_attempt_at" db:"next_attempt_at"`
+	FileName      string         `json:"file_name" db:"file_name"`
+	FileSize      int64          `json:"file_size" db:"file_size"`
+	LastError     sql.NullString `json:"last_error,omitempty" db:"last_error"`
+	SentAt        sql.NullTime   `json:"sent_at,omitempty" db:"sent_at"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+
+	// Joined fields
+	ScheduleName string        `json:"schedule_name" db:"schedule_name"`
+	ReportType   string        `json:"report_type" db:"report_type"`
+	CategoryID   sql.NullInt64 `json:"category_id" db:"category_id"`
+	CounterID    sql.NullInt64 `json:"counter_id" db:"counter_id"`
+	StatusFilter string        `json:"status_filter" db:"status_filter"`
+}
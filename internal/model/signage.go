@@ -0,0 +1,66 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// SignageMedia is an uploaded image or video stored under its content hash
+type SignageMedia struct {
+	ID          int       `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Filename    string    `json:"filename" db:"filename"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	SizeBytes   int64     `json:"size_bytes" db:"size_bytes"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// IsVideo reports whether the media plays as a video rather than an image
+func (m *SignageMedia) IsVideo() bool {
+	return strings.HasPrefix(m.ContentType, "video/")
+}
+
+// SizeKB returns the file size in kilobytes, rounded up
+func (m *SignageMedia) SizeKB() int64 {
+	return (m.SizeBytes + 1023) / 1024
+}
+
+// SignagePlaylist is an ordered loop of media shown in a display's media zone
+type SignagePlaylist struct {
+	ID        int                   `json:"id" db:"id"`
+	Name      string                `json:"name" db:"name"`
+	ItemCount int                   `json:"item_count" db:"item_count"`
+	Items     []SignagePlaylistItem `json:"items,omitempty" db:"-"`
+	CreatedAt time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// SignagePlaylistItem is one media entry of a playlist. A zero duration plays a video to its end.
+type SignagePlaylistItem struct {
+	ID              int    `json:"id" db:"id"`
+	PlaylistID      int    `json:"playlist_id" db:"playlist_id"`
+	MediaID         int    `json:"media_id" db:"media_id"`
+	Position        int    `json:"position" db:"position"`
+	DurationSeconds int    `json:"duration_seconds" db:"duration_seconds"`
+	MediaName       string `json:"media_name" db:"media_name"`
+	Filename        string `json:"filename" db:"filename"`
+	ContentType     string `json:"content_type" db:"content_type"`
+}
+
+// SignageSchedule plays a playlist on a display profile during a weekly time window.
+// Times are "HH:MM" in server local time; an end before the start runs past midnight.
+type SignageSchedule struct {
+	ID               int       `json:"id" db:"id"`
+	DisplayProfileID int       `json:"display_profile_id" db:"display_profile_id"`
+	PlaylistID       int       `json:"playlist_id" db:"playlist_id"`
+	DaysOfWeek       []int     `json:"days_of_week" db:"days_of_week"`
+	StartTime        string    `json:"start_time" db:"start_time"`
+	EndTime          string    `json:"end_time" db:"end_time"`
+	Priority         int       `json:"priority" db:"priority"`
+	IsActive         bool      `json:"is_active" db:"is_active"`
+	ProfileName      string    `json:"profile_name" db:"profile_name"`
+	PlaylistName     string    `json:"playlist_name" db:"playlist_name"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// SLA kinds: how long a ticket waits to be called, and how long its service takes
+const (
+	SLAKindWait    = "wait"
+	SLAKindService = "service"
+)
+
+// SLA levels of a live ticket
+const (
+	SLALevelWarning  = "warning"
+	SLALevelBreached = "breached"
+)
+
+// SLATicket is a waiting or serving ticket measured against its category's target.
+// Waiting tickets have no counter.
+type SLATicket struct {
+	TicketID       int    `json:"ticket_id" db:"ticket_id"`
+	TicketNumber   string `json:"ticket_number" db:"ticket_number"`
+	Status         string `json:"status" db:"status"`
+	CategoryID     int    `json:"category_id" db:"category_id"`
+	CategoryName   string `json:"category_name" db:"category_name"`
+	CounterID      int    `json:"counter_id" db:"counter_id"`
+	CounterNumber  string `json:"counter_number" db:"counter_number"`
+	Kind           string `json:"kind" db:"kind"`
+	TargetSeconds  int    `json:"target_seconds" db:"target_seconds"`
+	ElapsedSeconds int    `json:"elapsed_seconds" db:"elapsed_seconds"`
+	Level          string `json:"level" db:"-"`
+}
+
+// SLABreach records a ticket going past one of its targets, once per ticket and kind
+type SLABreach struct {
+	TicketID       int       `json:"ticket_id" db:"ticket_id"`
+	Kind           string    `json:"kind" db:"kind"`
+	TargetSeconds  int       `json:"target_seconds" db:"target_seconds"`
+	ElapsedSeconds int       `json:"elapsed_seconds" db:"elapsed_seconds"`
+	BreachedAt     time.Time `json:"breached_at" db:"breached_at"`
+}
@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// StaffPerformanceFilter selects the shifts that started within a range of days, of one
+// staff member when UserID is not 0
+type StaffPerformanceFilter struct {
+	DateFrom time.Time `json:"date_from"`
+	DateTo   time.Time `json:"date_to"`
+	UserID   int       `json:"user_id,omitempty"`
+}
+
+// StaffShiftStats is the raw activity of one shift, as recorded by the database
+type StaffShiftStats struct {
+	ShiftID       int       `json:"shift_id" db:"shift_id"`
+	UserID        int       `json:"user_id" db:"user_id"`
+	FullName      string    `json:"full_name" db:"full_name"`
+	Username      string    `json:"username" db:"username"`
+	CounterNumber string    `json:"counter_number" db:"counter_number"`
+	StartedAt     time.Time `json:"started_at" db:"started_at"`
+	EndedAt       time.Time `json:"ended_at" db:"ended_at"`
+	// Open shifts are still going on; their end is the current time
+	Open      bool `json:"open" db:"open"`
+	Completed int  `json:"completed" db:"completed"`
+	NoShow    int  `json:"no_show" db:"no_show"`
+	// ServiceSeconds is the total service time of the completed tickets
+	ServiceSeconds float64 `json:"service_seconds" db:"service_seconds"`
+	BusySeconds    float64 `json:"busy_seconds" db:"busy_seconds"`
+	PauseSeconds   float64 `json:"pause_seconds" db:"pause_seconds"`
+}
+
+// StaffPerformanceStats are the performance figures of one shift or of several together.
+// Busy time runs from calling a ticket until it is finished; idle time is what is left
+// of the shift after busy and pause time, and utilisation is busy time as a percentage
+// of the shift less its pauses.
+type StaffPerformanceStats struct {
+	TicketsServed     int     `json:"tickets_served"`
+	NoShow            int     `json:"no_show"`
+	NoShowRate        float64 `json:"no_show_rate"`
+	AvgServiceSeconds float64 `json:"avg_service_seconds"`
+	ShiftSeconds      float64 `json:"shift_seconds"`
+	BusySeconds       float64 `json:"busy_seconds"`
+	PauseSeconds      float64 `json:"pause_seconds"`
+	IdleSeconds       float64 `json:"idle_seconds"`
+	Utilisation       float64 `json:"utilisation"`
+}
+
+// StaffShiftPerformance is one shift of a staff performance report
+type StaffShiftPerformance struct {
+	ShiftID       int       `json:"shift_id"`
+	CounterNumber string    `json:"counter_number"`
+	StartedAt     time.Time `json:"started_at"`
+	EndedAt       time.Time `json:"ended_at"`
+	Open          bool      `json:"open"`
+	StaffPerformanceStats
+}
+
+// StaffPerformance is a staff member's performance over a report's range, with each shift
+type StaffPerformance struct {
+	UserID   int    `json:"user_id"`
+	FullName string `json:"full_name"`
+	Username string `json:"username"`
+	StaffPerformanceStats
+	Shifts []StaffShiftPerformance `json:"shifts"`
+}
+
+// StaffPerformanceReport is the staff performance of a filter, one entry per staff member
+type StaffPerformanceReport struct {
+	Filter StaffPerformanceFilter `json:"filter"`
+	Staff  []StaffPerformance     `json:"staff"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// TicketCall kinds
+const (
+	TicketCallKindCall     = "call"
+	TicketCallKindRecall   = "recall"
+	TicketCallKindTransfer = "transfer"
+)
+
+// TicketCall is one entry in a ticket's call log
+type TicketCall struct {
+	ID        int           `json:"id" db:"id"`
+	TicketID  int           `json:"ticket_id" db:"ticket_id"`
+	CounterID sql.NullInt64 `json:"counter_id" db:"counter_id"`
+	UserID    sql.NullInt64 `json:"user_id" db:"user_id"`
+	Kind      string        `json:"kind" db:"kind"`
+	CalledAt  time.Time     `json:"called_at" db:"called_at"`
+	// Joined for display
+	CounterNumber sql.NullString `json:"counter_number" db:"counter_number"`
+	StaffName     sql.NullString `json:"staff_name" db:"staff_name"`
+}
+
+// TicketCallSummary is where a serving ticket stands since it was last called or transferred
+type TicketCallSummary struct {
+	TicketID     int          `json:"ticket_id" db:"ticket_id"`
+	RecallCount  int          `json:"recall_count" db:"recall_count"`
+	LastCalledAt sql.NullTime `json:"last_called_at" db:"last_called_at"`
+	AnsweredAt   sql.NullTime `json:"answered_at" db:"answered_at"`
+}
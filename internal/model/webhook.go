@@ -0,0 +1,73 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Webhook event types emitted from the ticket outbox
+const (
+	WebhookEventTicketIssued    = "ticket.issued"
+	WebhookEventTicketCalled    = "ticket.called"
+	WebhookEventTicketCompleted = "ticket.completed"
+	WebhookEventTicketCancelled = "ticket.cancelled"
+	WebhookEventTicketNoShow    = "ticket.no_show"
+	// Written by the SLA monitor rather than the ticket trigger
+	WebhookEventTicketSLABreached = "ticket.sla_breached"
+	// Written for queue alert rules that notify webhooks
+	WebhookEventQueueAlert = "queue.alert"
+)
+
+// WebhookEventTypes lists every event a subscription can select
+var WebhookEventTypes = []string{
+	WebhookEventTicketIssued,
+	WebhookEventTicketCalled,
+	WebhookEventTicketCompleted,
+	WebhookEventTicketCancelled,
+	WebhookEventTicketNoShow,
+	WebhookEventTicketSLABreached,
+	WebhookEventQueueAlert,
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookSubscription is an external endpoint that receives queue events
+type WebhookSubscription struct {
+	ID         int       `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	URL        string    `json:"url" db:"url"`
+	Secret     string    `json:"secret" db:"secret"`
+	EventTypes []string  `json:"event_types" db:"event_types"`
+	IsActive   bool      `json:"is_active" db:"is_active"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookDelivery tracks one event sent to one subscription
+type WebhookDelivery struct {
+	ID             int64           `json:"id" db:"id"`
+	SubscriptionID int             `json:"subscription_id" db:"subscription_id"`
+	EventID        int64           `json:"event_id" db:"event_id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	OccurredAt     time.Time       `json:"occurred_at" db:"occurred_at"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	LastAttemptAt  sql.NullTime    `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	LastStatusCode sql.NullInt64   `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      sql.NullString  `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    sql.NullTime    `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+
+	// Joined fields
+	SubscriptionName string `json:"subscription_name,omitempty" db:"subscription_name"`
+	URL              string `json:"url,omitempty" db:"url"`
+	Secret           string `json:"-" db:"secret"`
+}
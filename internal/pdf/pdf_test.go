@@ -0,0 +1,88 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var streamPattern = regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`)
+
+// contents inflates every content stream of a written document
+func contents(t *testing.T, data []byte) []string {
+	t.Helper()
+	var pages []string
+	for _, match := range streamPattern.FindAllSubmatch(data, -1) {
+		zr, err := zlib.NewReader(bytes.NewReader(match[1]))
+		require.NoError(t, err)
+		content, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		pages = append(pages, string(content))
+	}
+	return pages
+}
+
+func TestDocument_WriteTo(t *testing.T) {
+	doc := New(A4Width, A4Height)
+	doc.Title = "Laporan (mingguan)"
+	doc.CreatedAt = time.Date(2026, 10, 19, 8, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+	first := doc.AddPage()
+	first.Text(40, 60, HelveticaBold, 18, Black, "Halo (dunia) – 100%")
+	first.Rect(40, 100, 200, 20, RGB(59, 130, 246))
+	doc.AddPage().Line(40, 800, 555, 800, 0.5, Black)
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	require.NoError(t, err)
+	data := buf.Bytes()
+	assert.Equal(t, int64(len(data)), n)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(data, []byte("%%EOF\n")))
+	assert.Contains(t, string(data), "/Count 2")
+	assert.Contains(t, string(data), "/Title (Laporan \\(mingguan\\))")
+	assert.Contains(t, string(data), "/CreationDate (D:20261019080000+07'00')")
+
+	// Every xref entry points at the start of its object
+	xref := bytes.LastIndex(data, []byte("startxref\n"))
+	start, err := strconv.Atoi(string(bytes.Fields(data[xref+len("startxref\n"):])[0]))
+	require.NoError(t, err)
+	entries := bytes.Split(data[start:], []byte("\n"))[3:]
+	for i := 1; i <= 9; i++ {
+		offset, err := strconv.Atoi(string(entries[i-1][:10]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(data[offset:], []byte(fmt.Sprintf("%d 0 obj", i))), "object %d", i)
+	}
+
+	pages := contents(t, data)
+	require.Len(t, pages, 2)
+	assert.Contains(t, pages[0], "/F2 18 Tf 0 0 0 rg 40 781.89 Td (Halo \\(dunia\\) \x96 100%) Tj")
+	assert.Contains(t, pages[0], "0.23 0.51 0.96 rg 40 721.89 200 20 re f")
+	assert.Contains(t, pages[1], "40 41.89 m 555 41.89 l S")
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.InDelta(t, 22.78, TextWidth(Helvetica, 10, "Hello"), 0.001)
+	assert.Greater(t, TextWidth(HelveticaBold, 10, "Hello"), TextWidth(Helvetica, 10, "Hello"))
+
+	short := Truncate(Helvetica, 10, "Pelayanan Administrasi Kependudukan", 60)
+	assert.LessOrEqual(t, TextWidth(Helvetica, 10, short), 60.0)
+	assert.Regexp(t, `^Pelayanan.*\.\.\.$`, short)
+	assert.Equal(t, "Umum", Truncate(Helvetica, 10, "Umum", 60))
+}
+
+func TestHexColor(t *testing.T) {
+	color, ok := HexColor("#FF8000")
+	require.True(t, ok)
+	assert.Equal(t, RGB(255, 128, 0), color)
+
+	_, ok = HexColor("blue")
+	assert.False(t, ok)
+}
@@ -0,0 +1,27 @@
+package query
+
+import (
+	"context"
+)
+
+type BackplaneQueries struct{}
+
+func NewBackplaneQueries() *BackplaneQueries {
+	return &BackplaneQueries{}
+}
+
+func (q *BackplaneQueries) Notify(ctx context.Context) string {
+	return `SELECT pg_notify($1, $2)`
+}
+
+func (q *BackplaneQueries) InsertMessage(ctx context.Context) string {
+	return `INSERT INTO backplane_messages (payload) VALUES ($1) RETURNING id`
+}
+
+func (q *BackplaneQueries) GetMessage(ctx context.Context) string {
+	return `SELECT payload FROM backplane_messages WHERE id = $1`
+}
+
+func (q *BackplaneQueries) PurgeMessages(ctx context.Context) string {
+	return `DELETE FROM backplane_messages WHERE created_at < NOW() - $1::int * INTERVAL '1 second'`
+}
@@ -11,20 +11,21 @@ func NewCategoryQueries() *CategoryQueries {
 }
 
 func (q *CategoryQueries) CreateCategory(ctx context.Context) string {
-	return `INSERT INTO categories (name, prefix, priority, color_code, description, icon, is_active) 
-	VALUES ($1, $2, $3, $4, $5, $6, $7) 
+	return `INSERT INTO categories (name, prefix, priority, color_code, description, icon, is_active, sla_wait_minutes, sla_service_minutes) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
 	RETURNING id, created_at, updated_at`
 }
 
 func (q *CategoryQueries) GetCategoryByID(ctx context.Context) string {
-	return `SELECT id, name, prefix, priority, color_code, description, icon, is_active, created_at, updated_at 
+	return `SELECT id, name, prefix, priority, color_code, description, icon, is_active, sla_wait_minutes, sla_service_minutes, created_at, updated_at 
 	FROM categories WHERE id = $1`
 }
 
 func (q *CategoryQueries) UpdateCategory(ctx context.Context) string {
 	return `UPDATE categories 
-	SET name = $1, prefix = $2, priority = $3, color_code = $4, description = $5, icon = $6, is_active = $7, updated_at = NOW() 
-	WHERE id = $8`
+	SET name = $1, prefix = $2, priority = $3, color_code = $4, description = $5, icon = $6, is_active = $7,
+		sla_wait_minutes = $8, sla_service_minutes = $9, updated_at = NOW() 
+	WHERE id = $10`
 }
 
 func (q *CategoryQueries) DeleteCategory(ctx context.Context) string {
@@ -32,7 +33,7 @@ func (q *CategoryQueries) DeleteCategory(ctx context.Context) string {
 }
 
 func (q *CategoryQueries) ListCategories(ctx context.Context, activeOnly bool, withCountersOnly bool) string {
-	query := `SELECT DISTINCT categories.id, categories.name, categories.prefix, categories.priority, categories.color_code, categories.description, categories.icon, categories.is_active, categories.created_at, categories.updated_at FROM categories`
+	query := `SELECT DISTINCT categories.id, categories.name, categories.prefix, categories.priority, categories.color_code, categories.description, categories.icon, categories.is_active, categories.sla_wait_minutes, categories.sla_service_minutes, categories.created_at, categories.updated_at FROM categories`
 
 	if withCountersOnly {
 		query += ` INNER JOIN counters ON counters.category_id = categories.id AND counters.current_staff_id IS NOT NULL`
@@ -0,0 +1,84 @@
+package query
+
+import "context"
+
+type DeviceQueries struct{}
+
+func NewDeviceQueries() *DeviceQueries {
+	return &DeviceQueries{}
+}
+
+const deviceColumns = `d.id, d.name, d.kind, d.display_profile_id, dp.slug AS profile_slug,
+	d.kiosk_profile_id, kp.slug AS kiosk_profile_slug, d.pairing_code, d.pairing_expires_at,
+	d.token_hash, d.paired_at, d.last_seen_at, d.app_version, d.user_agent, d.current_url, d.uptime_seconds,
+	d.last_event_seq, d.silent_since, d.created_at, d.updated_at`
+
+const deviceFrom = ` FROM devices d
+	LEFT JOIN display_profiles dp ON dp.id = d.display_profile_id
+	LEFT JOIN kiosk_profiles kp ON kp.id = d.kiosk_profile_id`
+
+func (q *DeviceQueries) List(ctx context.Context) string {
+	return `SELECT ` + deviceColumns + deviceFrom + ` ORDER BY d.name, d.id`
+}
+
+func (q *DeviceQueries) GetByID(ctx context.Context) string {
+	return `SELECT ` + deviceColumns + deviceFrom + ` WHERE d.id = $1`
+}
+
+// GetByPairingCode only finds codes that have not expired
+func (q *DeviceQueries) GetByPairingCode(ctx context.Context) string {
+	return `SELECT ` + deviceColumns + deviceFrom + ` WHERE d.pairing_code = $1 AND d.pairing_expires_at > NOW()`
+}
+
+func (q *DeviceQueries) GetByTokenHash(ctx context.Context) string {
+	return `SELECT ` + deviceColumns + deviceFrom + ` WHERE d.token_hash = $1`
+}
+
+func (q *DeviceQueries) Create(ctx context.Context) string {
+	return `INSERT INTO devices (name, kind, display_profile_id, kiosk_profile_id, pairing_code, pairing_expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`
+}
+
+func (q *DeviceQueries) Update(ctx context.Context) string {
+	return `UPDATE devices SET name = $2, kind = $3, display_profile_id = $4, kiosk_profile_id = $5 WHERE id = $1`
+}
+
+func (q *DeviceQueries) Delete(ctx context.Context) string {
+	return `DELETE FROM devices WHERE id = $1`
+}
+
+// SetPairingCode issues a new code and revokes the current token, so the device has to pair again
+func (q *DeviceQueries) SetPairingCode(ctx context.Context) string {
+	return `UPDATE devices SET pairing_code = $2, pairing_expires_at = $3, token_hash = NULL, paired_at = NULL, silent_since = NULL
+	WHERE id = $1`
+}
+
+// CompletePairing stores the token of a device that used a valid code. The code is
+// checked again so two devices racing for the same code cannot both pair.
+func (q *DeviceQueries) CompletePairing(ctx context.Context) string {
+	return `UPDATE devices SET token_hash = $3, paired_at = NOW(), last_seen_at = NOW(), pairing_code = NULL, pairing_expires_at = NULL
+	WHERE id = $1 AND pairing_code = $2 AND pairing_expires_at > NOW()`
+}
+
+func (q *DeviceQueries) RecordHeartbeat(ctx context.Context) string {
+	return `UPDATE devices SET last_seen_at = NOW(), app_version = $2, user_agent = $3, current_url = $4,
+		uptime_seconds = $5, last_event_seq = $6, silent_since = NULL
+	WHERE id = $1`
+}
+
+// ListNewlySilent finds paired devices without a heartbeat for $1 seconds that have
+// not been alerted on yet
+func (q *DeviceQueries) ListNewlySilent(ctx context.Context) string {
+	return `SELECT ` + deviceColumns + deviceFrom + `
+	WHERE d.token_hash IS NOT NULL AND d.silent_since IS NULL
+		AND COALESCE(d.last_seen_at, d.paired_at) < NOW() - make_interval(secs => $1)
+	ORDER BY d.id`
+}
+
+// MarkSilent records that the alert for a device fired; it returns no row when a
+// heartbeat arrived in the meantime
+func (q *DeviceQueries) MarkSilent(ctx context.Context) string {
+	return `UPDATE devices SET silent_since = NOW()
+	WHERE id = $1 AND silent_since IS NULL AND COALESCE(last_seen_at, paired_at) < NOW() - make_interval(secs => $2)
+	RETURNING silent_since`
+}
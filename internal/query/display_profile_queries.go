@@ -0,0 +1,40 @@
+package query
+
+import "context"
+
+type DisplayProfileQueries struct{}
+
+func NewDisplayProfileQueries() *DisplayProfileQueries {
+	return &DisplayProfileQueries{}
+}
+
+const displayProfileColumns = `id, slug, name, layout, category_ids, counter_ids, recent_count, show_waiting,
+	accent_color, background_color, ticker_text, is_active, created_at, updated_at`
+
+func (q *DisplayProfileQueries) List(ctx context.Context) string {
+	return `SELECT ` + displayProfileColumns + ` FROM display_profiles ORDER BY name`
+}
+
+func (q *DisplayProfileQueries) GetByID(ctx context.Context) string {
+	return `SELECT ` + displayProfileColumns + ` FROM display_profiles WHERE id = $1`
+}
+
+func (q *DisplayProfileQueries) GetBySlug(ctx context.Context) string {
+	return `SELECT ` + displayProfileColumns + ` FROM display_profiles WHERE slug = $1`
+}
+
+func (q *DisplayProfileQueries) Create(ctx context.Context) string {
+	return `INSERT INTO display_profiles (slug, name, layout, category_ids, counter_ids, recent_count, show_waiting,
+		accent_color, background_color, ticker_text, is_active)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at`
+}
+
+func (q *DisplayProfileQueries) Update(ctx context.Context) string {
+	return `UPDATE display_profiles SET slug = $2, name = $3, layout = $4, category_ids = $5, counter_ids = $6,
+		recent_count = $7, show_waiting = $8, accent_color = $9, background_color = $10, ticker_text = $11, is_active = $12
+	WHERE id = $1 RETURNING updated_at`
+}
+
+func (q *DisplayProfileQueries) Delete(ctx context.Context) string {
+	return `DELETE FROM display_profiles WHERE id = $1`
+}
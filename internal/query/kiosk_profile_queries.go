@@ -0,0 +1,62 @@
+package query
+
+import "context"
+
+type KioskProfileQueries struct{}
+
+func NewKioskProfileQueries() *KioskProfileQueries {
+	return &KioskProfileQueries{}
+}
+
+const kioskProfileColumns = `kp.id, kp.slug, kp.name, kp.language, kp.ticket_output, kp.printer_type, kp.printer_target,
+	kp.idle_seconds, kp.attract_title, kp.attract_text, kp.is_active,
+	(SELECT COUNT(*) FROM kiosk_profile_categories c WHERE c.kiosk_profile_id = kp.id) AS category_count,
+	kp.created_at, kp.updated_at`
+
+func (q *KioskProfileQueries) List(ctx context.Context) string {
+	return `SELECT ` + kioskProfileColumns + ` FROM kiosk_profiles kp ORDER BY kp.name`
+}
+
+func (q *KioskProfileQueries) GetByID(ctx context.Context) string {
+	return `SELECT ` + kioskProfileColumns + ` FROM kiosk_profiles kp WHERE kp.id = $1`
+}
+
+func (q *KioskProfileQueries) GetBySlug(ctx context.Context) string {
+	return `SELECT ` + kioskProfileColumns + ` FROM kiosk_profiles kp WHERE kp.slug = $1`
+}
+
+func (q *KioskProfileQueries) Create(ctx context.Context) string {
+	return `INSERT INTO kiosk_profiles (slug, name, language, ticket_output, printer_type, printer_target, idle_seconds,
+		attract_title, attract_text, is_active)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, updated_at`
+}
+
+func (q *KioskProfileQueries) Update(ctx context.Context) string {
+	return `UPDATE kiosk_profiles SET slug = $2, name = $3, language = $4, ticket_output = $5, printer_type = $6,
+		printer_target = $7, idle_seconds = $8, attract_title = $9, attract_text = $10, is_active = $11
+	WHERE id = $1 RETURNING updated_at`
+}
+
+func (q *KioskProfileQueries) Delete(ctx context.Context) string {
+	return `DELETE FROM kiosk_profiles WHERE id = $1`
+}
+
+func (q *KioskProfileQueries) ListCategories(ctx context.Context) string {
+	return `SELECT c.id, c.kiosk_profile_id, c.category_id, c.position, c.label, c.icon, cat.name AS category_name
+	FROM kiosk_profile_categories c
+	JOIN categories cat ON cat.id = c.category_id
+	WHERE c.kiosk_profile_id = $1
+	ORDER BY c.position`
+}
+
+// ReplaceCategories swaps a profile's categories for the category IDs in $2 with the labels
+// in $3 and icons in $4, positioned in array order. Both happen in one statement, so a failure
+// keeps the old list.
+func (q *KioskProfileQueries) ReplaceCategories(ctx context.Context) string {
+	return `WITH removed AS (
+		DELETE FROM kiosk_profile_categories WHERE kiosk_profile_id = $1
+	)
+	INSERT INTO kiosk_profile_categories (kiosk_profile_id, category_id, position, label, icon)
+	SELECT $1, item.category_id, item.position, item.label, item.icon
+	FROM unnest($2::int[], $3::text[], $4::text[]) WITH ORDINALITY AS item(category_id, label, icon, position)`
+}
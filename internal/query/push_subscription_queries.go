@@ -0,0 +1,52 @@
+package query
+
+import (
+	"context"
+)
+
+type PushSubscriptionQueries struct{}
+
+func NewPushSubscriptionQueries() *PushSubscriptionQueries {
+	return &PushSubscriptionQueries{}
+}
+
+func (q *PushSubscriptionQueries) Upsert(ctx context.Context) string {
+	return `INSERT INTO push_subscriptions (ticket_id, endpoint, p256dh, auth, expires_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (ticket_id, endpoint) DO UPDATE SET p256dh = EXCLUDED.p256dh, auth = EXCLUDED.auth, expires_at = EXCLUDED.expires_at
+	RETURNING id, created_at, updated_at`
+}
+
+func (q *PushSubscriptionQueries) ListByTicketID(ctx context.Context) string {
+	return `SELECT id, ticket_id, endpoint, p256dh, auth, nearly_up_notified_at, called_notified_at, expires_at, created_at, updated_at
+	FROM push_subscriptions WHERE ticket_id = $1 AND expires_at > NOW()`
+}
+
+func (q *PushSubscriptionQueries) ListNearlyUpPending(ctx context.Context) string {
+	return `SELECT id, ticket_id, endpoint, p256dh, auth, nearly_up_notified_at, called_notified_at, expires_at, created_at, updated_at
+	FROM push_subscriptions WHERE ticket_id = ANY($1) AND nearly_up_notified_at IS NULL AND expires_at > NOW()`
+}
+
+func (q *PushSubscriptionQueries) MarkNearlyUpNotified(ctx context.Context) string {
+	return `UPDATE push_subscriptions SET nearly_up_notified_at = NOW() WHERE id = $1`
+}
+
+func (q *PushSubscriptionQueries) MarkCalledNotified(ctx context.Context) string {
+	return `UPDATE push_subscriptions SET called_notified_at = NOW() WHERE id = $1`
+}
+
+func (q *PushSubscriptionQueries) DeleteByID(ctx context.Context) string {
+	return `DELETE FROM push_subscriptions WHERE id = $1`
+}
+
+func (q *PushSubscriptionQueries) DeleteByTicketAndEndpoint(ctx context.Context) string {
+	return `DELETE FROM push_subscriptions WHERE ticket_id = $1 AND endpoint = $2`
+}
+
+// DeleteExpired removes subscriptions past their expiry or whose ticket is no longer in the queue
+func (q *PushSubscriptionQueries) DeleteExpired(ctx context.Context) string {
+	return `DELETE FROM push_subscriptions ps
+	USING tickets t
+	WHERE ps.ticket_id = t.id
+		AND (ps.expires_at <= NOW() OR t.status IN ('completed', 'no_show', 'cancelled') OR t.queue_date < CURRENT_DATE)`
+}
@@ -0,0 +1,95 @@
+package query
+
+import (
+	"context"
+)
+
+type QueueAlertQueries struct{}
+
+func NewQueueAlertQueries() *QueueAlertQueries {
+	return &QueueAlertQueries{}
+}
+
+const alertRuleColumns = `r.id, r.name, r.kind, r.category_id, c.name AS category_name, r.threshold, r.cooldown_minutes,
+	r.email_recipients, r.notify_webhook, r.is_active, r.created_at, r.updated_at`
+
+const queueAlertColumns = `id, rule_id, rule_name, kind, category_id, category_name, value, threshold, message,
+	notify_webhook, triggered_at, emailed_at, email_error`
+
+func (q *QueueAlertQueries) ListRules(ctx context.Context) string {
+	return `SELECT ` + alertRuleColumns + `
+	FROM alert_rules r LEFT JOIN categories c ON c.id = r.category_id
+	ORDER BY r.name, r.id`
+}
+
+func (q *QueueAlertQueries) ListActiveRules(ctx context.Context) string {
+	return `SELECT ` + alertRuleColumns + `
+	FROM alert_rules r LEFT JOIN categories c ON c.id = r.category_id
+	WHERE r.is_active
+	ORDER BY r.id`
+}
+
+func (q *QueueAlertQueries) GetRuleByID(ctx context.Context) string {
+	return `SELECT ` + alertRuleColumns + `
+	FROM alert_rules r LEFT JOIN categories c ON c.id = r.category_id
+	WHERE r.id = $1`
+}
+
+func (q *QueueAlertQueries) CreateRule(ctx context.Context) string {
+	return `INSERT INTO alert_rules (name, kind, category_id, threshold, cooldown_minutes, email_recipients, notify_webhook, is_active)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, updated_at`
+}
+
+func (q *QueueAlertQueries) UpdateRule(ctx context.Context) string {
+	return `UPDATE alert_rules SET name = $2, kind = $3, category_id = $4, threshold = $5, cooldown_minutes = $6,
+		email_recipients = $7, notify_webhook = $8, is_active = $9
+	WHERE id = $1`
+}
+
+func (q *QueueAlertQueries) DeleteRule(ctx context.Context) string {
+	return `DELETE FROM alert_rules WHERE id = $1`
+}
+
+// QueueStats reads the live queue of every active category: today's waiting tickets, how
+// long the oldest of them has waited and how many active counters serving it are open
+func (q *QueueAlertQueries) QueueStats(ctx context.Context) string {
+	return `SELECT c.id AS category_id, c.name AS category_name,
+		COUNT(t.id)::int AS waiting,
+		COALESCE(EXTRACT(EPOCH FROM (NOW() - MIN(t.created_at))), 0)::int AS oldest_wait_seconds,
+		(SELECT COUNT(*) FROM counter_category cc
+			JOIN counters co ON co.id = cc.counter_id
+			WHERE cc.category_id = c.id AND co.is_active AND co.status IN ('idle', 'serving'))::int AS open_counters
+	FROM categories c
+	LEFT JOIN tickets t ON t.category_id = c.id AND t.status = 'waiting' AND t.queue_date = CURRENT_DATE
+	WHERE c.is_active
+	GROUP BY c.id, c.name
+	ORDER BY c.id`
+}
+
+// RecordAlert starts the cooldown of rule $1 for category $2 and records the alert, unless
+// the rule fired for that category less than $3 minutes ago. Nothing is returned then.
+func (q *QueueAlertQueries) RecordAlert(ctx context.Context) string {
+	return `WITH claimed AS (
+		INSERT INTO alert_rule_states (rule_id, category_id, last_triggered_at)
+		VALUES ($1::int, $2::int, NOW())
+		ON CONFLICT (rule_id, category_id) DO UPDATE SET last_triggered_at = EXCLUDED.last_triggered_at
+		WHERE alert_rule_states.last_triggered_at <= NOW() - $3::int * INTERVAL '1 minute'
+		RETURNING rule_id, category_id
+	)
+	INSERT INTO queue_alerts (rule_id, rule_name, kind, category_id, category_name, value, threshold, message, notify_webhook)
+	SELECT rule_id, $4::text, $5::text, category_id, $6::text, $7::int, $8::int, $9::text, $10::bool FROM claimed
+	RETURNING ` + queueAlertColumns
+}
+
+// MarkAlertEmailed records that alert $1 was emailed, or the error $2 when sending failed
+func (q *QueueAlertQueries) MarkAlertEmailed(ctx context.Context) string {
+	return `UPDATE queue_alerts SET emailed_at = CASE WHEN $2::text IS NULL THEN NOW() END, email_error = $2
+	WHERE id = $1`
+}
+
+// ListAlerts lists the latest alerts, of one rule when $1 is not 0, newest first
+func (q *QueueAlertQueries) ListAlerts(ctx context.Context) string {
+	return `SELECT ` + queueAlertColumns + ` FROM queue_alerts
+	WHERE $1 = 0 OR rule_id = $1
+	ORDER BY id DESC LIMIT $2`
+}
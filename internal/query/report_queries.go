@@ -0,0 +1,223 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"tenangantri/internal/model"
+)
+
+type ReportQueries struct{}
+
+func NewReportQueries() *ReportQueries {
+	return &ReportQueries{}
+}
+
+// ReportQuery is a report statement with the arguments of its filter
+type ReportQuery struct {
+	Query string
+	Args  []any
+}
+
+// staffUser joins the user who finished each ticket of t, or is serving it, as u
+const staffUser = `users u ON u.id = COALESCE(t.completed_by, t.served_by)`
+
+// timeStats are the wait and service time statistics of the tickets t of a group, in seconds;
+// percentiles skip tickets without a time, as the averages do
+const timeStats = `COALESCE(AVG(t.wait_time), 0)::float8 AS avg_wait_seconds,
+		COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY t.wait_time), 0)::float8 AS median_wait_seconds,
+		COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY t.wait_time), 0)::float8 AS p90_wait_seconds,
+		COALESCE(AVG(t.service_time), 0)::float8 AS avg_service_seconds,
+		COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY t.service_time), 0)::float8 AS median_service_seconds,
+		COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY t.service_time), 0)::float8 AS p90_service_seconds`
+
+// rates are the percentages of the tickets t of a group that were completed or did not show up
+const rates = `COALESCE(COUNT(*) FILTER (WHERE t.status = 'completed') * 100.0 / NULLIF(COUNT(*), 0), 0)::float8 AS completion_rate,
+		COALESCE(COUNT(*) FILTER (WHERE t.status = 'no_show') * 100.0 / NULLIF(COUNT(*), 0), 0)::float8 AS no_show_rate`
+
+// slaJoins join the category c of each ticket t and its recorded wait breach wb, for slaStats
+const slaJoins = `LEFT JOIN categories c ON c.id = t.category_id
+	LEFT JOIN sla_breaches wb ON wb.ticket_id = t.id AND wb.kind = 'wait'`
+
+// slaStats are the SLA compliance counts and percentages of the tickets t of a group,
+// against the current targets of their category c. A ticket still waiting counts only
+// once it breached its wait target.
+const slaStats = `COUNT(*) FILTER (WHERE c.sla_wait_minutes > 0 AND (t.called_at IS NOT NULL OR wb.ticket_id IS NOT NULL)) AS sla_wait_tickets,
+		COUNT(*) FILTER (WHERE c.sla_wait_minutes > 0 AND t.called_at - t.created_at <= c.sla_wait_minutes * INTERVAL '1 minute') AS sla_wait_met,
+		COALESCE(COUNT(*) FILTER (WHERE c.sla_wait_minutes > 0 AND t.called_at - t.created_at <= c.sla_wait_minutes * INTERVAL '1 minute') * 100.0 /
+			NULLIF(COUNT(*) FILTER (WHERE c.sla_wait_minutes > 0 AND (t.called_at IS NOT NULL OR wb.ticket_id IS NOT NULL)), 0), 0)::float8 AS sla_wait_compliance,
+		COUNT(*) FILTER (WHERE c.sla_service_minutes > 0 AND t.status = 'completed' AND t.service_time IS NOT NULL) AS sla_service_tickets,
+		COUNT(*) FILTER (WHERE c.sla_service_minutes > 0 AND t.status = 'completed' AND t.service_time <= c.sla_service_minutes * 60) AS sla_service_met,
+		COALESCE(COUNT(*) FILTER (WHERE c.sla_service_minutes > 0 AND t.status = 'completed' AND t.service_time <= c.sla_service_minutes * 60) * 100.0 /
+			NULLIF(COUNT(*) FILTER (WHERE c.sla_service_minutes > 0 AND t.status = 'completed' AND t.service_time IS NOT NULL), 0), 0)::float8 AS sla_service_compliance`
+
+// ReportTicketsCursor is the cursor EachTicket reads tickets through
+const ReportTicketsCursor = "report_tickets"
+
+// where selects the tickets of a filter; it is written against tickets as t
+func (q *ReportQueries) where(filter model.ReportFilter) (string, []any) {
+	where := `t.created_at >= $1 AND t.created_at < $2`
+	args := []any{filter.DateFrom, filter.DateTo.AddDate(0, 0, 1)}
+
+	if filter.CategoryID != 0 {
+		args = append(args, filter.CategoryID)
+		where += fmt.Sprintf(" AND t.category_id = $%d", len(args))
+	}
+	if filter.CounterID != 0 {
+		args = append(args, filter.CounterID)
+		where += fmt.Sprintf(" AND t.counter_id = $%d", len(args))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where += fmt.Sprintf(" AND t.status = $%d", len(args))
+	}
+	return where, args
+}
+
+func (q *ReportQueries) Summary(ctx context.Context, filter model.ReportFilter) ReportQuery {
+	where, args := q.where(filter)
+	return ReportQuery{
+		Query: `SELECT COUNT(*) AS total_tickets,
+		COUNT(*) FILTER (WHERE t.status = 'waiting') AS waiting,
+		COUNT(*) FILTER (WHERE t.status = 'serving') AS serving,
+		COUNT(*) FILTER (WHERE t.status = 'completed') AS completed,
+		COUNT(*) FILTER (WHERE t.status = 'no_show') AS no_show,
+		COUNT(*) FILTER (WHERE t.status = 'cancelled') AS cancelled,
+		` + rates + `,
+		` + timeStats + `,
+		` + slaStats + `
+	FROM tickets t
+	` + slaJoins + `
+	WHERE ` + where,
+		Args: args,
+	}
+}
+
+func (q *ReportQueries) ByCategory(ctx context.Context, filter model.ReportFilter) ReportQuery {
+	where, args := q.where(filter)
+	return ReportQuery{
+		Query: `SELECT COALESCE(c.id, 0) AS category_id, COALESCE(c.name, '') AS name, COALESCE(c.prefix, '') AS prefix,
+		COALESCE(c.color_code, '') AS color_code,
+		COUNT(*) AS total,
+		COUNT(*) FILTER (WHERE t.status = 'completed') AS completed,
+		COUNT(*) FILTER (WHERE t.status = 'no_show') AS no_show,
+		` + rates + `,
+		` + timeStats + `,
+		` + slaStats + `
+	FROM tickets t
+	` + slaJoins + `
+	WHERE ` + where + `
+	GROUP BY c.id, c.name, c.prefix, c.color_code
+	ORDER BY total DESC, name`,
+		Args: args,
+	}
+}
+
+func (q *ReportQueries) ByCounter(ctx context.Context, filter model.ReportFilter) ReportQuery {
+	where, args := q.where(filter)
+	return ReportQuery{
+		Query: `SELECT co.id AS counter_id, co.number, co.name,
+		COUNT(*) AS total,
+		COUNT(*) FILTER (WHERE t.status = 'completed') AS completed,
+		COUNT(*) FILTER (WHERE t.status = 'no_show') AS no_show,
+		` + rates + `,
+		` + timeStats + `
+	FROM tickets t
+	JOIN counters co ON co.id = t.counter_id
+	WHERE ` + where + `
+	GROUP BY co.id, co.number, co.name
+	ORDER BY co.number`,
+		Args: args,
+	}
+}
+
+func (q *ReportQueries) Daily(ctx context.Context, filter model.ReportFilter) ReportQuery {
+	where, args := q.where(filter)
+	return ReportQuery{
+		Query: `SELECT t.created_at::date AS day, COUNT(*) AS total,
+		COUNT(*) FILTER (WHERE t.status = 'completed') AS completed,
+		COUNT(*) FILTER (WHERE t.status = 'no_show') AS no_show,
+		COUNT(*) FILTER (WHERE t.status = 'cancelled') AS cancelled,
+		COALESCE(AVG(t.wait_time), 0)::float8 AS avg_wait_seconds,
+		COALESCE(AVG(t.service_time), 0)::float8 AS avg_service_seconds,
+		` + slaStats + `
+	FROM tickets t
+	` + slaJoins + `
+	WHERE ` + where + `
+	GROUP BY 1 ORDER BY 1`,
+		Args: args,
+	}
+}
+
+func (q *ReportQueries) ByStaff(ctx context.Context, filter model.ReportFilter) ReportQuery {
+	where, args := q.where(filter)
+	return ReportQuery{
+		Query: `SELECT COALESCE(u.id, 0) AS user_id, COALESCE(u.full_name, '') AS full_name, COALESCE(u.username, '') AS username,
+		COUNT(*) AS total,
+		COUNT(*) FILTER (WHERE t.status = 'completed') AS completed,
+		COUNT(*) FILTER (WHERE t.status = 'no_show') AS no_show,
+		COALESCE(AVG(t.service_time), 0)::float8 AS avg_service_seconds
+	FROM tickets t
+	LEFT JOIN ` + staffUser + `
+	WHERE ` + where + ` AND t.called_at IS NOT NULL
+	GROUP BY u.id, u.full_name, u.username
+	ORDER BY total DESC, full_name`,
+		Args: args,
+	}
+}
+
+func (q *ReportQueries) Hourly(ctx context.Context, filter model.ReportFilter) ReportQuery {
+	where, args := q.where(filter)
+	return ReportQuery{
+		Query: `SELECT EXTRACT(HOUR FROM t.created_at)::int AS hour, COUNT(*) AS tickets,
+		COALESCE(AVG(t.wait_time), 0)::float8 AS avg_wait_seconds
+	FROM tickets t WHERE ` + where + `
+	GROUP BY 1 ORDER BY 1`,
+		Args: args,
+	}
+}
+
+// Weekdays groups a filter's tickets by ISO day of the week, 1 being Monday
+func (q *ReportQueries) Weekdays(ctx context.Context, filter model.ReportFilter) ReportQuery {
+	where, args := q.where(filter)
+	return ReportQuery{
+		Query: `SELECT EXTRACT(ISODOW FROM t.created_at)::int AS weekday, COUNT(*) AS tickets,
+		COALESCE(AVG(t.wait_time), 0)::float8 AS avg_wait_seconds
+	FROM tickets t WHERE ` + where + `
+	GROUP BY 1 ORDER BY 1`,
+		Args: args,
+	}
+}
+
+// ListTickets lists a filter's tickets oldest first, at most limit of them or all if limit is 0
+func (q *ReportQueries) ListTickets(ctx context.Context, filter model.ReportFilter, limit int) ReportQuery {
+	where, args := q.where(filter)
+	query := `SELECT t.ticket_number, COALESCE(c.name, '') AS category_name, COALESCE(co.number, '') AS counter_number,
+		COALESCE(u.full_name, '') AS staff_name, t.status, t.priority,
+		t.created_at, t.called_at, t.completed_at, t.wait_time, t.service_time, t.notes
+	FROM tickets t
+	LEFT JOIN categories c ON c.id = t.category_id
+	LEFT JOIN counters co ON co.id = t.counter_id
+	LEFT JOIN ` + staffUser + `
+	WHERE ` + where + `
+	ORDER BY t.created_at, t.id`
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	return ReportQuery{Query: query, Args: args}
+}
+
+// DeclareTicketsCursor opens a cursor over a filter's tickets, oldest first
+func (q *ReportQueries) DeclareTicketsCursor(ctx context.Context, filter model.ReportFilter) ReportQuery {
+	list := q.ListTickets(ctx, filter, 0)
+	return ReportQuery{
+		Query: "DECLARE " + ReportTicketsCursor + " NO SCROLL CURSOR FOR " + list.Query,
+		Args:  list.Args,
+	}
+}
+
+// FetchTickets reads the next n tickets of the cursor
+func (q *ReportQueries) FetchTickets(ctx context.Context, n int) string {
+	return fmt.Sprintf("FETCH FORWARD %d FROM %s", n, ReportTicketsCursor)
+}
@@ -0,0 +1,43 @@
+package query
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"tenangantri/internal/model"
+)
+
+func TestReportQueries_FilterArgs(t *testing.T) {
+	q := NewReportQueries()
+	ctx := context.Background()
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	r := q.ByCategory(ctx, model.ReportFilter{DateFrom: day, DateTo: day, CounterID: 2, Status: "completed"})
+
+	if len(r.Args) != 4 {
+		t.Fatalf("Expected 4 args, got %d", len(r.Args))
+	}
+	if !r.Args[1].(time.Time).Equal(day.AddDate(0, 0, 1)) {
+		t.Errorf("Expected the range to end at the start of the next day, got %v", r.Args[1])
+	}
+	for _, cond := range []string{"t.counter_id = $3", "t.status = $4"} {
+		if !strings.Contains(r.Query, cond) {
+			t.Errorf("Expected SQL to contain '%s', got: %s", cond, r.Query)
+		}
+	}
+}
+
+func TestReportQueries_Summary(t *testing.T) {
+	q := NewReportQueries()
+	r := q.Summary(context.Background(), model.ReportFilter{})
+
+	expectedColumns := []string{"completion_rate", "no_show_rate", "median_wait_seconds", "p90_wait_seconds",
+		"median_service_seconds", "p90_service_seconds", "sla_wait_compliance", "sla_service_compliance"}
+	for _, col := range expectedColumns {
+		if !strings.Contains(r.Query, col) {
+			t.Errorf("Expected SQL to contain column '%s', got: %s", col, r.Query)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package query
+
+import (
+	"context"
+)
+
+type ReportScheduleQueries struct{}
+
+func NewReportScheduleQueries() *ReportScheduleQueries {
+	return &ReportScheduleQueries{}
+}
+
+const reportScheduleColumns = `id, name, report_type, format, category_id, counter_id, status, recipients, cron,
+	is_active, next_run_at, last_run_at, created_at, updated_at`
+
+const reportRunColumns = `r.id, r.schedule_id, r.scheduled_for, r.period_from, r.period_to, r.format, r.recipients,
+	r.status, r.attempts, r.next_attempt_at, r.file_name, r.file_size, r.last_error, r.sent_at, r.created_at,
+	s.name AS schedule_name, s.report_type, s.category_id, s.counter_id, s.status AS status_filter`
+
+func (q *ReportScheduleQueries) ListSchedules(ctx context.Context) string {
+	return `SELECT ` + reportScheduleColumns + ` FROM report_schedules ORDER BY name`
+}
+
+func (q *ReportScheduleQueries) GetScheduleByID(ctx context.Context) string {
+	return `SELECT ` + reportScheduleColumns + ` FROM report_schedules WHERE id = $1`
+}
+
+func (q *ReportScheduleQueries) CreateSchedule(ctx context.Context) string {
+	return `INSERT INTO report_schedules (name, report_type, format, category_id, counter_id, status, recipients, cron, is_active, next_run_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, updated_at`
+}
+
+func (q *ReportScheduleQueries) UpdateSchedule(ctx context.Context) string {
+	return `UPDATE report_schedules SET name = $2, report_type = $3, format = $4, category_id = $5, counter_id = $6,
+		status = $7, recipients = $8, cron = $9, is_active = $10, next_run_at = $11
+	WHERE id = $1`
+}
+
+func (q *ReportScheduleQueries) DeleteSchedule(ctx context.Context) string {
+	return `DELETE FROM report_schedules WHERE id = $1`
+}
+
+// ListDueSchedules lists the active schedules whose next run is at or before $1
+func (q *ReportScheduleQueries) ListDueSchedules(ctx context.Context) string {
+	return `SELECT ` + reportScheduleColumns + ` FROM report_schedules
+	WHERE is_active AND next_run_at <= $1 ORDER BY next_run_at`
+}
+
+// EnqueueDueRun moves schedule $1 from its run at $2 on to $3 and queues the run for the period $4 to $5.
+// The schedule only moves on if its next run is still $2, so of several instances only one queues the run.
+func (q *ReportScheduleQueries) EnqueueDueRun(ctx context.Context) string {
+	return `WITH s AS (
+		UPDATE report_schedules SET next_run_at = $3, last_run_at = $2
+		WHERE id = $1 AND next_run_at = $2
+		RETURNING id, format, recipients
+	)
+	INSERT INTO report_runs (schedule_id, scheduled_for, period_from, period_to, format, recipients, next_attempt_at)
+	SELECT id, $2, $4, $5, format, recipients, $2 FROM s
+	ON CONFLICT (schedule_id, scheduled_for) DO NOTHING
+	RETURNING id`
+}
+
+// EnqueueRun queues a run of schedule $1 at $2 for the period $3 to $4, outside its cron timing
+func (q *ReportScheduleQueries) EnqueueRun(ctx context.Context) string {
+	return `INSERT INTO report_runs (schedule_id, scheduled_for, period_from, period_to, format, recipients, next_attempt_at)
+	SELECT id, $2, $3, $4, format, recipients, $2 FROM report_schedules WHERE id = $1
+	RETURNING id`
+}
+
+// ClaimDueRuns locks a batch of runs due at $1 for this worker for $3 seconds and counts the attempt
+func (q *ReportScheduleQueries) ClaimDueRuns(ctx context.Context) string {
+	return `WITH due AS (
+		SELECT id FROM report_runs
+		WHERE status = 'pending' AND next_attempt_at <= $1
+			AND (locked_until IS NULL OR locked_until < $1)
+		ORDER BY next_attempt_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	), r AS (
+		UPDATE report_runs rr
+		SET attempts = rr.attempts + 1, locked_until = $1 + $3::int * INTERVAL '1 second'
+		FROM due WHERE rr.id = due.id
+		RETURNING rr.*
+	)
+	SELECT ` + reportRunColumns + `
+	FROM r JOIN report_schedules s ON s.id = r.schedule_id`
+}
+
+// SetRunFile records the rendered file of a run
+func (q *ReportScheduleQueries) SetRunFile(ctx context.Context) string {
+	return `UPDATE report_runs SET file_name = $2, file_size = $3 WHERE id = $1`
+}
+
+func (q *ReportScheduleQueries) MarkRunSent(ctx context.Context) string {
+	return `UPDATE report_runs
+	SET status = 'sent', sent_at = CURRENT_TIMESTAMP, last_error = NULL, locked_until = NULL
+	WHERE id = $1`
+}
+
+// MarkRunFailed records a failed attempt; $3 is the next attempt time and $4 the resulting status
+func (q *ReportScheduleQueries) MarkRunFailed(ctx context.Context) string {
+	return `UPDATE report_runs
+	SET status = $4, last_error = $2, next_attempt_at = $3, locked_until = NULL
+	WHERE id = $1`
+}
+
+// RetryRun puts a run back in the queue with a fresh attempt budget
+func (q *ReportScheduleQueries) RetryRun(ctx context.Context) string {
+	return `UPDATE report_runs
+	SET status = 'pending', attempts = 0, next_attempt_at = $2, locked_until = NULL, sent_at = NULL
+	WHERE id = $1`
+}
+
+func (q *ReportScheduleQueries) GetRunByID(ctx context.Context) string {
+	return `SELECT ` + reportRunColumns + `
+	FROM report_runs r JOIN report_schedules s ON s.id = r.schedule_id
+	WHERE r.id = $1`
+}
+
+// ListRuns lists the latest runs, of one schedule when $1 is not 0, newest first
+func (q *ReportScheduleQueries) ListRuns(ctx context.Context) string {
+	return `SELECT ` + reportRunColumns + `
+	FROM report_runs r JOIN report_schedules s ON s.id = r.schedule_id
+	WHERE $1 = 0 OR r.schedule_id = $1
+	ORDER BY r.id DESC LIMIT $2`
+}
@@ -0,0 +1,125 @@
+package query
+
+import "context"
+
+type SignageQueries struct{}
+
+func NewSignageQueries() *SignageQueries {
+	return &SignageQueries{}
+}
+
+const signageScheduleColumns = `s.id, s.display_profile_id, s.playlist_id, s.days_of_week,
+	TO_CHAR(s.start_time, 'HH24:MI') AS start_time, TO_CHAR(s.end_time, 'HH24:MI') AS end_time,
+	s.priority, s.is_active, dp.name AS profile_name, p.name AS playlist_name, s.created_at, s.updated_at`
+
+func (q *SignageQueries) ListMedia(ctx context.Context) string {
+	return `SELECT id, name, filename, content_type, size_bytes, created_at, updated_at
+	FROM signage_media ORDER BY created_at DESC`
+}
+
+func (q *SignageQueries) GetMediaByID(ctx context.Context) string {
+	return `SELECT id, name, filename, content_type, size_bytes, created_at, updated_at
+	FROM signage_media WHERE id = $1`
+}
+
+func (q *SignageQueries) GetMediaByFilename(ctx context.Context) string {
+	return `SELECT id, name, filename, content_type, size_bytes, created_at, updated_at
+	FROM signage_media WHERE filename = $1`
+}
+
+func (q *SignageQueries) CreateMedia(ctx context.Context) string {
+	return `INSERT INTO signage_media (name, filename, content_type, size_bytes)
+	VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at`
+}
+
+func (q *SignageQueries) DeleteMedia(ctx context.Context) string {
+	return `DELETE FROM signage_media WHERE id = $1`
+}
+
+func (q *SignageQueries) ListPlaylists(ctx context.Context) string {
+	return `SELECT p.id, p.name, COUNT(i.id)::INT AS item_count, p.created_at, p.updated_at
+	FROM signage_playlists p
+	LEFT JOIN signage_playlist_items i ON i.playlist_id = p.id
+	GROUP BY p.id
+	ORDER BY p.name`
+}
+
+func (q *SignageQueries) GetPlaylistByID(ctx context.Context) string {
+	return `SELECT p.id, p.name, COUNT(i.id)::INT AS item_count, p.created_at, p.updated_at
+	FROM signage_playlists p
+	LEFT JOIN signage_playlist_items i ON i.playlist_id = p.id
+	WHERE p.id = $1
+	GROUP BY p.id`
+}
+
+func (q *SignageQueries) CreatePlaylist(ctx context.Context) string {
+	return `INSERT INTO signage_playlists (name) VALUES ($1) RETURNING id, created_at, updated_at`
+}
+
+func (q *SignageQueries) UpdatePlaylist(ctx context.Context) string {
+	return `UPDATE signage_playlists SET name = $2 WHERE id = $1`
+}
+
+func (q *SignageQueries) DeletePlaylist(ctx context.Context) string {
+	return `DELETE FROM signage_playlists WHERE id = $1`
+}
+
+func (q *SignageQueries) ListPlaylistItems(ctx context.Context) string {
+	return `SELECT i.id, i.playlist_id, i.media_id, i.position, i.duration_seconds,
+		m.name AS media_name, m.filename, m.content_type
+	FROM signage_playlist_items i
+	JOIN signage_media m ON m.id = i.media_id
+	WHERE i.playlist_id = $1
+	ORDER BY i.position`
+}
+
+// ReplacePlaylistItems swaps a playlist's items for the media IDs in $2 with the durations
+// in $3, positioned in array order. Both happen in one statement, so a failure keeps the old list.
+func (q *SignageQueries) ReplacePlaylistItems(ctx context.Context) string {
+	return `WITH removed AS (
+		DELETE FROM signage_playlist_items WHERE playlist_id = $1
+	)
+	INSERT INTO signage_playlist_items (playlist_id, media_id, position, duration_seconds)
+	SELECT $1, item.media_id, item.position, item.duration_seconds
+	FROM unnest($2::int[], $3::int[]) WITH ORDINALITY AS item(media_id, duration_seconds, position)`
+}
+
+func (q *SignageQueries) ListSchedules(ctx context.Context) string {
+	return `SELECT ` + signageScheduleColumns + `
+	FROM signage_schedules s
+	JOIN display_profiles dp ON dp.id = s.display_profile_id
+	JOIN signage_playlists p ON p.id = s.playlist_id
+	ORDER BY dp.name, s.priority DESC, s.start_time`
+}
+
+func (q *SignageQueries) ListSchedulesByProfile(ctx context.Context) string {
+	return `SELECT ` + signageScheduleColumns + `
+	FROM signage_schedules s
+	JOIN display_profiles dp ON dp.id = s.display_profile_id
+	JOIN signage_playlists p ON p.id = s.playlist_id
+	WHERE s.display_profile_id = $1 AND s.is_active
+	ORDER BY s.priority DESC, s.id DESC`
+}
+
+func (q *SignageQueries) GetScheduleByID(ctx context.Context) string {
+	return `SELECT ` + signageScheduleColumns + `
+	FROM signage_schedules s
+	JOIN display_profiles dp ON dp.id = s.display_profile_id
+	JOIN signage_playlists p ON p.id = s.playlist_id
+	WHERE s.id = $1`
+}
+
+func (q *SignageQueries) CreateSchedule(ctx context.Context) string {
+	return `INSERT INTO signage_schedules (display_profile_id, playlist_id, days_of_week, start_time, end_time, priority, is_active)
+	VALUES ($1, $2, $3, $4::time, $5::time, $6, $7) RETURNING id, created_at, updated_at`
+}
+
+func (q *SignageQueries) UpdateSchedule(ctx context.Context) string {
+	return `UPDATE signage_schedules SET display_profile_id = $2, playlist_id = $3, days_of_week = $4,
+		start_time = $5::time, end_time = $6::time, priority = $7, is_active = $8
+	WHERE id = $1`
+}
+
+func (q *SignageQueries) DeleteSchedule(ctx context.Context) string {
+	return `DELETE FROM signage_schedules WHERE id = $1`
+}
@@ -0,0 +1,53 @@
+package query
+
+import (
+	"context"
+)
+
+type SLAQueries struct{}
+
+func NewSLAQueries() *SLAQueries {
+	return &SLAQueries{}
+}
+
+// slaTickets selects today's waiting tickets that were never called and the tickets being
+// served, with the target of their category and how long they have waited or been in
+// service so far as s.started_at. Tickets without a target are left out.
+const slaTickets = `SELECT t.id AS ticket_id, t.ticket_number, t.status, c.id AS category_id, c.name AS category_name,
+		COALESCE(co.id, 0) AS counter_id, COALESCE(co.number, '') AS counter_number, s.kind, s.target_seconds,
+		EXTRACT(EPOCH FROM (NOW() - s.started_at))::int AS elapsed_seconds
+	FROM tickets t
+	JOIN categories c ON c.id = t.category_id
+	LEFT JOIN counters co ON co.id = t.counter_id
+	CROSS JOIN LATERAL (SELECT
+		CASE WHEN t.status = 'waiting' THEN 'wait' ELSE 'service' END AS kind,
+		CASE WHEN t.status = 'waiting' THEN c.sla_wait_minutes ELSE c.sla_service_minutes END * 60 AS target_seconds,
+		CASE WHEN t.status = 'waiting' THEN t.created_at ELSE COALESCE(t.answered_at, t.last_called_at, t.called_at) END AS started_at) s
+	WHERE t.queue_date = CURRENT_DATE
+		AND ((t.status = 'waiting' AND t.called_at IS NULL) OR t.status = 'serving')
+		AND s.target_seconds > 0`
+
+// LiveTickets lists the tickets that have used up at least the fraction $1 of their target,
+// closest to breaching first. With categories, $2 limits them to those category IDs.
+func (q *SLAQueries) LiveTickets(ctx context.Context, withCategories bool) string {
+	query := slaTickets + `
+		AND NOW() - s.started_at >= s.target_seconds * $1::float8 * INTERVAL '1 second'`
+	if withCategories {
+		query += `
+		AND t.category_id = ANY($2)`
+	}
+	return query + `
+	ORDER BY EXTRACT(EPOCH FROM (NOW() - s.started_at)) / s.target_seconds DESC, t.id`
+}
+
+// RecordBreaches records every ticket that is past a target and returns the breaches that
+// were not recorded before
+func (q *SLAQueries) RecordBreaches(ctx context.Context) string {
+	return `WITH due AS (` + slaTickets + `
+		AND NOW() - s.started_at > s.target_seconds * INTERVAL '1 second'
+	)
+	INSERT INTO sla_breaches (ticket_id, category_id, kind, target_seconds, elapsed_seconds)
+	SELECT ticket_id, category_id, kind, target_seconds, elapsed_seconds FROM due
+	ON CONFLICT (ticket_id, kind) DO NOTHING
+	RETURNING ticket_id, kind, target_seconds, elapsed_seconds, breached_at`
+}
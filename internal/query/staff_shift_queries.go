@@ -0,0 +1,100 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tenangantri/internal/model"
+)
+
+type StaffShiftQueries struct{}
+
+func NewStaffShiftQueries() *StaffShiftQueries {
+	return &StaffShiftQueries{}
+}
+
+// CloseStaleShift ends the open shift of user $1 at its last activity when that was before $2,
+// or when the user has moved to another counter than $3, along with any pause left open
+func (q *StaffShiftQueries) CloseStaleShift(ctx context.Context) string {
+	return `WITH closed AS (
+		UPDATE staff_shifts SET ended_at = last_active_at
+		WHERE user_id = $1 AND ended_at IS NULL AND (last_active_at < $2 OR counter_id IS DISTINCT FROM $3::int)
+		RETURNING id, ended_at
+	)
+	UPDATE counter_pauses cp SET resumed_at = GREATEST(cp.paused_at, closed.ended_at)
+	FROM closed WHERE cp.shift_id = closed.id AND cp.resumed_at IS NULL`
+}
+
+// TouchShift records activity of user $1 at counter $2 at $3, opening a shift if none is open
+func (q *StaffShiftQueries) TouchShift(ctx context.Context) string {
+	return `INSERT INTO staff_shifts (user_id, counter_id, started_at, last_active_at) VALUES ($1, $2, $3, $3)
+	ON CONFLICT (user_id) WHERE ended_at IS NULL
+	DO UPDATE SET last_active_at = GREATEST(staff_shifts.last_active_at, EXCLUDED.last_active_at)`
+}
+
+// EndShift ends the open shift of user $1 at $2, or at its last activity when that was
+// before $3, along with any pause left open
+func (q *StaffShiftQueries) EndShift(ctx context.Context) string {
+	return `WITH ended AS (
+		UPDATE staff_shifts SET ended_at = CASE WHEN last_active_at < $3 THEN last_active_at ELSE GREATEST(last_active_at, $2) END
+		WHERE user_id = $1 AND ended_at IS NULL
+		RETURNING id, ended_at
+	)
+	UPDATE counter_pauses cp SET resumed_at = GREATEST(cp.paused_at, ended.ended_at)
+	FROM ended WHERE cp.shift_id = ended.id AND cp.resumed_at IS NULL`
+}
+
+// StartPause starts a pause of user $1's open shift at $2, unless one is already going on
+func (q *StaffShiftQueries) StartPause(ctx context.Context) string {
+	return `INSERT INTO counter_pauses (shift_id, user_id, counter_id, paused_at)
+	SELECT id, user_id, counter_id, $2 FROM staff_shifts WHERE user_id = $1 AND ended_at IS NULL
+	ON CONFLICT (user_id) WHERE resumed_at IS NULL DO NOTHING`
+}
+
+// EndPause ends the pause of user $1 at $2
+func (q *StaffShiftQueries) EndPause(ctx context.Context) string {
+	return `UPDATE counter_pauses SET resumed_at = GREATEST(paused_at, $2) WHERE user_id = $1 AND resumed_at IS NULL`
+}
+
+// Stats sums up the shifts that started within a filter's days, ordered by staff member then
+// time. A shift still open ends at now, or at its last activity if that was before staleBefore.
+// Tickets count towards the shift of whoever finished them; a ticket still being served
+// counts as busy time of the open shift serving it.
+func (q *StaffShiftQueries) Stats(ctx context.Context, filter model.StaffPerformanceFilter, now, staleBefore time.Time) ReportQuery {
+	args := []any{filter.DateFrom, filter.DateTo.AddDate(0, 0, 1), now, staleBefore}
+	where := `s.started_at >= $1 AND s.started_at < $2`
+	if filter.UserID != 0 {
+		args = append(args, filter.UserID)
+		where += fmt.Sprintf(" AND s.user_id = $%d", len(args))
+	}
+
+	return ReportQuery{
+		Query: `SELECT s.id AS shift_id, s.user_id, u.full_name, u.username, COALESCE(co.number, '') AS counter_number,
+		s.started_at, se.ended_at, se.open,
+		tk.completed, tk.no_show, tk.service_seconds, tk.busy_seconds, p.pause_seconds
+	FROM staff_shifts s
+	JOIN users u ON u.id = s.user_id
+	LEFT JOIN counters co ON co.id = s.counter_id
+	CROSS JOIN LATERAL (
+		SELECT COALESCE(s.ended_at, CASE WHEN s.last_active_at >= $4 THEN $3 ELSE s.last_active_at END) AS ended_at,
+			s.ended_at IS NULL AND s.last_active_at >= $4 AS open
+	) se
+	CROSS JOIN LATERAL (
+		SELECT COUNT(*) FILTER (WHERE t.status = 'completed') AS completed,
+			COUNT(*) FILTER (WHERE t.status = 'no_show') AS no_show,
+			COALESCE(SUM(t.service_time) FILTER (WHERE t.status = 'completed'), 0)::float8 AS service_seconds,
+			COALESCE(SUM(GREATEST(EXTRACT(EPOCH FROM (COALESCE(t.completed_at, se.ended_at) - GREATEST(t.served_at, s.started_at))), 0)), 0)::float8 AS busy_seconds
+		FROM tickets t
+		WHERE (t.completed_by = s.user_id AND t.completed_at >= s.started_at AND t.completed_at <= se.ended_at)
+			OR (se.open AND t.status = 'serving' AND t.served_by = s.user_id)
+	) tk
+	CROSS JOIN LATERAL (
+		SELECT COALESCE(SUM(GREATEST(EXTRACT(EPOCH FROM (LEAST(COALESCE(cp.resumed_at, se.ended_at), se.ended_at) - cp.paused_at)), 0)), 0)::float8 AS pause_seconds
+		FROM counter_pauses cp WHERE cp.shift_id = s.id
+	) p
+	WHERE ` + where + `
+	ORDER BY u.full_name, s.user_id, s.started_at`,
+		Args: args,
+	}
+}
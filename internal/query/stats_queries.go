@@ -28,7 +28,7 @@ func (q *StatsQueries) GetQueueLengthByCategory(ctx context.Context) string {
 		FROM tickets t2 
 		WHERE t2.category_id = c.id AND t2.status IN ('serving', 'completed') 
 			AND t2.queue_date = CURRENT_DATE 
-		ORDER BY t2.called_at DESC 
+		ORDER BY t2.last_called_at DESC 
 		LIMIT 1
 	), '') as last_ticket_number,
 	COALESCE((
@@ -63,7 +63,7 @@ func (q *StatsQueries) GetQueueLengthByCategories(ctx context.Context, categoryI
 		FROM tickets t2 
 		WHERE t2.category_id = c.id AND t2.status IN ('serving', 'completed') 
 			AND t2.queue_date = CURRENT_DATE 
-		ORDER BY t2.called_at DESC 
+		ORDER BY t2.last_called_at DESC 
 		LIMIT 1
 	), '') as last_ticket_number,
 	COALESCE((
@@ -87,7 +87,21 @@ func (q *StatsQueries) GetHourlyDistribution(ctx context.Context) string {
 }
 
 func (q *StatsQueries) GetCurrentlyServingTickets(ctx context.Context) string {
-	return `SELECT t.ticket_number, c.number, cat.prefix, cat.color_code, t.status, t.daily_sequence, t.queue_date FROM tickets t JOIN counters c ON t.counter_id = c.id JOIN categories cat ON t.category_id = cat.id WHERE t.status = 'serving' ORDER BY t.called_at DESC LIMIT 10`
+	return `SELECT t.ticket_number, c.number, cat.prefix, cat.color_code, t.status, t.daily_sequence, t.queue_date, t.category_id FROM tickets t JOIN counters c ON t.counter_id = c.id JOIN categories cat ON t.category_id = cat.id WHERE t.status = 'serving' ORDER BY t.last_called_at DESC LIMIT 10`
+}
+
+// GetRecentlyCalledTickets lists today's most recently called tickets, newest first.
+// $1 and $2 filter by category and counter IDs; an empty array matches everything. $3 is the limit.
+func (q *StatsQueries) GetRecentlyCalledTickets(ctx context.Context) string {
+	return `SELECT t.ticket_number, c.number, cat.prefix, cat.color_code, t.status, t.daily_sequence, t.queue_date, t.category_id
+	FROM tickets t
+	JOIN counters c ON t.counter_id = c.id
+	JOIN categories cat ON t.category_id = cat.id
+	WHERE t.queue_date = CURRENT_DATE AND t.status IN ('serving', 'completed') AND t.last_called_at IS NOT NULL
+		AND (cardinality($1::int[]) = 0 OR t.category_id = ANY($1::int[]))
+		AND (cardinality($2::int[]) = 0 OR t.counter_id = ANY($2::int[]))
+	ORDER BY t.last_called_at DESC
+	LIMIT $3`
 }
 
 func (q *StatsQueries) GetTotalTicketsToday(ctx context.Context) string {
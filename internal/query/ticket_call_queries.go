@@ -0,0 +1,45 @@
+package query
+
+import (
+	"context"
+)
+
+type TicketCallQueries struct{}
+
+func NewTicketCallQueries() *TicketCallQueries {
+	return &TicketCallQueries{}
+}
+
+func (q *TicketCallQueries) Create(ctx context.Context) string {
+	return `INSERT INTO ticket_calls (ticket_id, counter_id, user_id, kind) VALUES ($1, $2, $3, $4) RETURNING id, called_at`
+}
+
+func (q *TicketCallQueries) ListByTicketID(ctx context.Context) string {
+	return `SELECT tc.id, tc.ticket_id, tc.counter_id, tc.user_id, tc.kind, tc.called_at, c.number AS counter_number, u.full_name AS staff_name
+	FROM ticket_calls tc
+	LEFT JOIN counters c ON c.id = tc.counter_id
+	LEFT JOIN users u ON u.id = tc.user_id
+	WHERE tc.ticket_id = $1
+	ORDER BY tc.called_at, tc.id`
+}
+
+// recallsSinceLastCall counts a ticket's recalls since it was last called or transferred
+const recallsSinceLastCall = `(SELECT COUNT(*) FROM ticket_calls r
+		WHERE r.ticket_id = t.id AND r.kind = 'recall'
+		AND r.called_at >= COALESCE((SELECT MAX(p.called_at) FROM ticket_calls p WHERE p.ticket_id = t.id AND p.kind <> 'recall'), '-infinity'))`
+
+func (q *TicketCallQueries) GetSummary(ctx context.Context) string {
+	return `SELECT t.id AS ticket_id, ` + recallsSinceLastCall + ` AS recall_count, t.last_called_at, t.answered_at
+	FROM tickets t WHERE t.id = $1`
+}
+
+// ListUnanswered finds serving tickets nobody has come for: recalled at least $1 times
+// since the last call, or silent for $2 seconds since the last call or recall. Either
+// trigger is off when its argument is 0.
+func (q *TicketCallQueries) ListUnanswered(ctx context.Context) string {
+	return `SELECT t.id FROM tickets t
+	WHERE t.status = 'serving' AND t.answered_at IS NULL
+		AND (($1::INT > 0 AND ` + recallsSinceLastCall + ` >= $1::INT)
+			OR ($2::FLOAT8 > 0 AND t.last_called_at < NOW() - make_interval(secs => $2::FLOAT8)))
+	ORDER BY t.last_called_at`
+}
@@ -25,22 +25,46 @@ func (q *TicketQueries) GetTicketWithDetails(ctx context.Context) string {
 }
 
 func (q *TicketQueries) GetTicketByNumber(ctx context.Context) string {
-	return `SELECT t.id, t.ticket_number, t.category_id, t.counter_id, t.status, t.priority, t.created_at, t.called_at, t.completed_at, t.wait_time, t.service_time, t.daily_sequence, t.queue_date, t.notes FROM tickets t WHERE t.ticket_number = $1`
+	return `SELECT t.id, t.ticket_number, t.category_id, t.counter_id, t.status, t.priority, t.created_at, t.called_at, t.completed_at, t.wait_time, t.service_time, t.daily_sequence, t.queue_date, t.notes FROM tickets t WHERE t.ticket_number = $1 ORDER BY t.queue_date DESC, t.id DESC LIMIT 1`
 }
 
 func (q *TicketQueries) UpdateTicketStatus(ctx context.Context, status string) string {
 	switch status {
 	case "serving":
-		return `UPDATE tickets SET status = $1, called_at = NOW() WHERE id = $2`
+		return `UPDATE tickets SET status = $1, called_at = COALESCE(called_at, NOW()), last_called_at = NOW() WHERE id = $2`
 	case "completed", "no_show":
-		return `UPDATE tickets SET status = $1, completed_at = NOW(), wait_time = EXTRACT(EPOCH FROM (called_at - created_at))::INT, service_time = EXTRACT(EPOCH FROM (NOW() - called_at))::INT WHERE id = $2`
+		// Waiting ends at the first call; service starts when the customer arrives, or at the last call
+		return `UPDATE tickets SET status = $1, completed_at = NOW(), wait_time = EXTRACT(EPOCH FROM (called_at - created_at))::INT, service_time = EXTRACT(EPOCH FROM (NOW() - COALESCE(answered_at, last_called_at, called_at)))::INT WHERE id = $2`
 	default:
 		return `UPDATE tickets SET status = $1 WHERE id = $2`
 	}
 }
 
+// AssignTicketToCounter starts serving a ticket at counter $1 by user $3; a transfer passes
+// no user and keeps the ticket with the staff member who called it until someone finishes
+// it at the new counter
 func (q *TicketQueries) AssignTicketToCounter(ctx context.Context) string {
-	return `UPDATE tickets SET counter_id = $1, status = 'serving', called_at = NOW() WHERE id = $2`
+	return `UPDATE tickets SET counter_id = $1, status = 'serving', called_at = COALESCE(called_at, NOW()), last_called_at = NOW(), answered_at = NULL,
+		served_by = COALESCE(NULLIF($3, 0), served_by), served_at = NOW() WHERE id = $2`
+}
+
+// FinishTicket completes ticket $2 or marks it a no-show as status $1, stamped with user $3
+// or, when finished without one, with the user serving it. A ticket no longer being served
+// is left as it is, so a finish racing another one changes no row.
+func (q *TicketQueries) FinishTicket(ctx context.Context) string {
+	return `UPDATE tickets SET status = $1, completed_at = NOW(), wait_time = EXTRACT(EPOCH FROM (called_at - created_at))::INT,
+		service_time = EXTRACT(EPOCH FROM (NOW() - COALESCE(answered_at, last_called_at, called_at)))::INT,
+		served_by = COALESCE(served_by, NULLIF($3, 0)), completed_by = COALESCE(NULLIF($3, 0), served_by)
+	WHERE id = $2 AND status = 'serving'`
+}
+
+// RecallTicket moves the last call time; called_at keeps the first call
+func (q *TicketQueries) RecallTicket(ctx context.Context) string {
+	return `UPDATE tickets SET last_called_at = NOW() WHERE id = $1`
+}
+
+func (q *TicketQueries) MarkTicketAnswered(ctx context.Context) string {
+	return `UPDATE tickets SET answered_at = COALESCE(answered_at, NOW()) WHERE id = $1 AND status = 'serving'`
 }
 
 func (q *TicketQueries) GetNextTicket(ctx context.Context, categoryIDs []int) string {
@@ -114,6 +138,12 @@ func (q *TicketQueries) GetTodayTicketCountByCategory(ctx context.Context) strin
 	return `SELECT COUNT(*) FROM tickets WHERE category_id = $1 AND queue_date = CURRENT_DATE`
 }
 
+// GetWaitingAheadCount counts the waiting tickets of ticket $1's category that were taken before it
+func (q *TicketQueries) GetWaitingAheadCount(ctx context.Context) string {
+	return `SELECT COUNT(*) FROM tickets t JOIN tickets mine ON mine.id = $1
+	WHERE t.category_id = mine.category_id AND t.status = 'waiting' AND t.created_at < mine.created_at`
+}
+
 func (q *TicketQueries) GenerateTicketNumber(ctx context.Context) string {
 	return `SELECT COALESCE(MAX(daily_sequence), 0) + 1 FROM tickets WHERE category_id = $1 AND queue_date = CURRENT_DATE`
 }
@@ -148,7 +178,7 @@ func (q *TicketQueries) GetTodayCompletedTicketsByCategories(ctx context.Context
 }
 
 func (q *TicketQueries) GetLastCalledTicketByCategory(ctx context.Context) string {
-	return `SELECT ticket_number FROM tickets WHERE category_id = $1 AND status IN ('serving', 'completed') ORDER BY called_at DESC LIMIT 1`
+	return `SELECT ticket_number FROM tickets WHERE category_id = $1 AND status IN ('serving', 'completed') ORDER BY last_called_at DESC LIMIT 1`
 }
 
 func (q *TicketQueries) GetTodayTicketsByCategories(ctx context.Context, categoryIDs []int) string {
@@ -4,6 +4,9 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
+
+	"tenangantri/internal/model"
 )
 
 func TestTicketQueries_GenerateTicketNumber(t *testing.T) {
@@ -60,6 +63,27 @@ func TestStatsQueries_GetCurrentlyServingTickets(t *testing.T) {
 	if !strings.Contains(sql, "t.queue_date") {
 		t.Errorf("Expected SQL to contain 't.queue_date', got: %s", sql)
 	}
+	if !strings.Contains(sql, "t.category_id") {
+		t.Errorf("Expected SQL to contain 't.category_id', got: %s", sql)
+	}
+}
+
+func TestStatsQueries_GetRecentlyCalledTickets(t *testing.T) {
+	q := NewStatsQueries()
+	ctx := context.Background()
+
+	sql := q.GetRecentlyCalledTickets(ctx)
+
+	// An empty filter must match every category and counter
+	if !strings.Contains(sql, "cardinality($1::int[]) = 0 OR t.category_id = ANY($1::int[])") {
+		t.Errorf("Expected SQL to treat an empty category filter as all, got: %s", sql)
+	}
+	if !strings.Contains(sql, "cardinality($2::int[]) = 0 OR t.counter_id = ANY($2::int[])") {
+		t.Errorf("Expected SQL to treat an empty counter filter as all, got: %s", sql)
+	}
+	if !strings.Contains(sql, "ORDER BY t.last_called_at DESC") {
+		t.Errorf("Expected SQL to order by last call, got: %s", sql)
+	}
 }
 
 func TestTicketQueries_ListTickets(t *testing.T) {
@@ -75,3 +99,158 @@ func TestTicketQueries_ListTickets(t *testing.T) {
 	// Test filters - though ListTickets doesn't use queue_date for filtering yet based on my previous edits
 	// (it used created_at for date_from/date_to). Let's check if I should update that too.
 }
+
+func TestWebhookQueries_FanOutOutbox(t *testing.T) {
+	q := NewWebhookQueries()
+	ctx := context.Background()
+
+	sql := q.FanOutOutbox(ctx)
+
+	if !strings.Contains(sql, "FOR UPDATE SKIP LOCKED") {
+		t.Errorf("Expected SQL to lock outbox rows with SKIP LOCKED, got: %s", sql)
+	}
+	if !strings.Contains(sql, "processed_at = CURRENT_TIMESTAMP") {
+		t.Errorf("Expected SQL to mark events processed, got: %s", sql)
+	}
+}
+
+func TestWebhookQueries_ListDeliveries(t *testing.T) {
+	q := NewWebhookQueries()
+	ctx := context.Background()
+
+	result := q.ListDeliveries(ctx, "dead", 4, 0)
+
+	if !strings.Contains(result.Query, "d.status = $1") || !strings.Contains(result.Query, "d.subscription_id = $2") {
+		t.Errorf("Expected status and subscription filters, got: %s", result.Query)
+	}
+	if !strings.HasSuffix(result.Query, "LIMIT 100") {
+		t.Errorf("Expected default LIMIT 100, got: %s", result.Query)
+	}
+	if len(result.Args) != 2 {
+		t.Errorf("Expected 2 args, got %d", len(result.Args))
+	}
+}
+
+func TestTicketQueries_CallTimes(t *testing.T) {
+	q := NewTicketQueries()
+	ctx := context.Background()
+
+	// Wait time is measured to the first call, so later calls must not move called_at
+	for name, sql := range map[string]string{
+		"AssignTicketToCounter": q.AssignTicketToCounter(ctx),
+		"UpdateTicketStatus":    q.UpdateTicketStatus(ctx, "serving"),
+	} {
+		if !strings.Contains(sql, "called_at = COALESCE(called_at, NOW())") {
+			t.Errorf("%s: expected called_at to keep the first call, got: %s", name, sql)
+		}
+		if !strings.Contains(sql, "last_called_at = NOW()") {
+			t.Errorf("%s: expected last_called_at to be updated, got: %s", name, sql)
+		}
+	}
+
+	if sql := q.RecallTicket(ctx); !strings.Contains(sql, "SET last_called_at = NOW() WHERE") {
+		t.Errorf("Expected recall to only move last_called_at, got: %s", sql)
+	}
+
+	// A transfer has no user of its own, so the ticket stays with the staff member who called it
+	if sql := q.AssignTicketToCounter(ctx); !strings.Contains(sql, "served_by = COALESCE(NULLIF($3, 0), served_by)") {
+		t.Errorf("Expected AssignTicketToCounter to keep the serving staff member on transfer, got: %s", sql)
+	}
+
+	// Tickets finished by the system or an admin stay with the staff member serving them
+	if sql := q.FinishTicket(ctx); !strings.Contains(sql, "completed_by = COALESCE(NULLIF($3, 0), served_by)") {
+		t.Errorf("Expected FinishTicket to fall back to the serving staff member, got: %s", sql)
+	}
+}
+
+func TestTicketCallQueries_ListUnanswered(t *testing.T) {
+	sql := NewTicketCallQueries().ListUnanswered(context.Background())
+
+	// Each trigger fires on its own and is off at 0
+	for _, want := range []string{
+		"($1::INT > 0 AND ",
+		"OR ($2::FLOAT8 > 0 AND t.last_called_at < NOW() - make_interval(secs => $2::FLOAT8))",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("Expected ListUnanswered to contain %q, got: %s", want, sql)
+		}
+	}
+}
+
+func TestSignageQueries_ReplacePlaylistItems(t *testing.T) {
+	q := NewSignageQueries()
+	sql := q.ReplacePlaylistItems(context.Background())
+
+	// Delete and insert run as one statement so a failed insert keeps the old items
+	if !strings.HasPrefix(sql, "WITH removed AS (") || !strings.Contains(sql, "DELETE FROM signage_playlist_items WHERE playlist_id = $1") {
+		t.Errorf("Expected the old items to be deleted in the same statement, got: %s", sql)
+	}
+	if !strings.Contains(sql, "unnest($2::int[], $3::int[]) WITH ORDINALITY") {
+		t.Errorf("Expected positions to follow array order, got: %s", sql)
+	}
+}
+
+func TestDeviceQueries_Pairing(t *testing.T) {
+	q := NewDeviceQueries()
+	ctx := context.Background()
+
+	// The code is checked again when pairing completes, so it can only be used once
+	if sql := q.CompletePairing(ctx); !strings.Contains(sql, "WHERE id = $1 AND pairing_code = $2 AND pairing_expires_at > NOW()") {
+		t.Errorf("Expected pairing to re-check the code, got: %s", sql)
+	}
+	if sql := q.SetPairingCode(ctx); !strings.Contains(sql, "token_hash = NULL") {
+		t.Errorf("Expected a new pairing code to revoke the token, got: %s", sql)
+	}
+	if sql := q.ListNewlySilent(ctx); !strings.Contains(sql, "d.silent_since IS NULL") {
+		t.Errorf("Expected devices to be reported once, got: %s", sql)
+	}
+}
+
+func TestKioskProfileQueries_ReplaceCategories(t *testing.T) {
+	q := NewKioskProfileQueries()
+	sql := q.ReplaceCategories(context.Background())
+
+	// The old list is removed in the same statement that writes the new one
+	if !strings.Contains(sql, "DELETE FROM kiosk_profile_categories WHERE kiosk_profile_id = $1") {
+		t.Errorf("Expected ReplaceCategories to remove the old categories, got: %s", sql)
+	}
+	if !strings.Contains(sql, "WITH ORDINALITY AS item(category_id, label, icon, position)") {
+		t.Errorf("Expected ReplaceCategories to position categories in array order, got: %s", sql)
+	}
+}
+
+func TestTicketQueries_GetWaitingAheadCount(t *testing.T) {
+	sql := NewTicketQueries().GetWaitingAheadCount(context.Background())
+
+	// Only waiting tickets of the same category taken earlier are ahead
+	if !strings.Contains(sql, "t.category_id = mine.category_id AND t.status = 'waiting' AND t.created_at < mine.created_at") {
+		t.Errorf("Expected GetWaitingAheadCount to count earlier waiting tickets of the category, got: %s", sql)
+	}
+}
+
+func TestReportQueries_Filter(t *testing.T) {
+	q := NewReportQueries()
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+
+	rq := q.Summary(context.Background(), model.ReportFilter{DateFrom: day, DateTo: day})
+	// The range includes all of its last day
+	if len(rq.Args) != 2 || rq.Args[1] != day.AddDate(0, 0, 1) {
+		t.Errorf("Expected the range to end the day after DateTo, got: %v", rq.Args)
+	}
+
+	rq = q.ListTickets(context.Background(), model.ReportFilter{DateFrom: day, DateTo: day, CounterID: 3, Status: "completed"}, 100)
+	if !strings.Contains(rq.Query, "t.counter_id = $3 AND t.status = $4") {
+		t.Errorf("Expected optional filters to be numbered after the range, got: %s", rq.Query)
+	}
+	if !strings.Contains(rq.Query, "LIMIT $5") || rq.Args[4] != 100 {
+		t.Errorf("Expected ListTickets to limit the tickets listed, got: %s %v", rq.Query, rq.Args)
+	}
+	if rq = q.ListTickets(context.Background(), model.ReportFilter{DateFrom: day, DateTo: day}, 0); strings.Contains(rq.Query, "LIMIT $") {
+		t.Errorf("Expected a limit of 0 to list every ticket, got: %s", rq.Query)
+	}
+
+	// Staff are credited with the tickets they finished, or are still serving
+	if rq = q.ByStaff(context.Background(), model.ReportFilter{DateFrom: day, DateTo: day}); !strings.Contains(rq.Query, "COALESCE(t.completed_by, t.served_by)") {
+		t.Errorf("Expected ByStaff to credit the finishing staff member, got: %s", rq.Query)
+	}
+}
@@ -0,0 +1,142 @@
+package query
+
+import (
+	"context"
+	"fmt"
+)
+
+type WebhookQueries struct{}
+
+func NewWebhookQueries() *WebhookQueries {
+	return &WebhookQueries{}
+}
+
+const webhookDeliveryColumns = `d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.occurred_at, d.status, d.attempts,
+	d.next_attempt_at, d.last_attempt_at, d.last_status_code, d.last_error, d.delivered_at, d.created_at,
+	s.name AS subscription_name, s.url, s.secret`
+
+func (q *WebhookQueries) ListSubscriptions(ctx context.Context) string {
+	return `SELECT id, name, url, secret, event_types, is_active, created_at, updated_at
+	FROM webhook_subscriptions ORDER BY name`
+}
+
+func (q *WebhookQueries) GetSubscriptionByID(ctx context.Context) string {
+	return `SELECT id, name, url, secret, event_types, is_active, created_at, updated_at
+	FROM webhook_subscriptions WHERE id = $1`
+}
+
+func (q *WebhookQueries) CreateSubscription(ctx context.Context) string {
+	return `INSERT INTO webhook_subscriptions (name, url, secret, event_types, is_active)
+	VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at`
+}
+
+func (q *WebhookQueries) UpdateSubscription(ctx context.Context) string {
+	return `UPDATE webhook_subscriptions SET name = $2, url = $3, secret = $4, event_types = $5, is_active = $6
+	WHERE id = $1`
+}
+
+func (q *WebhookQueries) DeleteSubscription(ctx context.Context) string {
+	return `DELETE FROM webhook_subscriptions WHERE id = $1`
+}
+
+// FanOutOutbox moves a batch of unprocessed outbox events into per-subscription deliveries.
+// Everything happens in one statement, so an event is either fully fanned out or left for the next run.
+func (q *WebhookQueries) FanOutOutbox(ctx context.Context) string {
+	return `WITH events AS (
+		SELECT id, event_type, payload, created_at FROM outbox_events
+		WHERE processed_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	), fanned AS (
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, occurred_at)
+		SELECT s.id, e.id, e.event_type, e.payload, e.created_at
+		FROM events e
+		JOIN webhook_subscriptions s ON s.is_active AND e.event_type = ANY(s.event_types)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	)
+	UPDATE outbox_events SET processed_at = CURRENT_TIMESTAMP WHERE id IN (SELECT id FROM events)`
+}
+
+// ClaimDueDeliveries locks a batch of due deliveries for this worker and counts the attempt
+func (q *WebhookQueries) ClaimDueDeliveries(ctx context.Context) string {
+	return `WITH due AS (
+		SELECT id FROM webhook_deliveries
+		WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+			AND (locked_until IS NULL OR locked_until < CURRENT_TIMESTAMP)
+		ORDER BY next_attempt_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	), d AS (
+		UPDATE webhook_deliveries wd
+		SET attempts = wd.attempts + 1, last_attempt_at = CURRENT_TIMESTAMP, locked_until = CURRENT_TIMESTAMP + $2::int * INTERVAL '1 second'
+		FROM due WHERE wd.id = due.id
+		RETURNING wd.*
+	)
+	SELECT ` + webhookDeliveryColumns + `
+	FROM d JOIN webhook_subscriptions s ON s.id = d.subscription_id`
+}
+
+func (q *WebhookQueries) MarkDelivered(ctx context.Context) string {
+	return `UPDATE webhook_deliveries
+	SET status = 'delivered', delivered_at = CURRENT_TIMESTAMP, last_status_code = $2, last_error = NULL, locked_until = NULL
+	WHERE id = $1`
+}
+
+// MarkFailed records a failed attempt; $4 is the next attempt time and $5 the resulting status
+func (q *WebhookQueries) MarkFailed(ctx context.Context) string {
+	return `UPDATE webhook_deliveries
+	SET status = $5, last_status_code = $2, last_error = $3, next_attempt_at = $4, locked_until = NULL
+	WHERE id = $1`
+}
+
+// Redeliver puts a delivery back in the queue with a fresh attempt budget
+func (q *WebhookQueries) Redeliver(ctx context.Context) string {
+	return `UPDATE webhook_deliveries
+	SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, locked_until = NULL, delivered_at = NULL
+	WHERE id = $1`
+}
+
+type ListDeliveriesResult struct {
+	Query string
+	Args  []any
+}
+
+// ListDeliveries builds the delivery log query with optional status and subscription filters
+func (q *WebhookQueries) ListDeliveries(ctx context.Context, status string, subscriptionID, limit int) ListDeliveriesResult {
+	query := `SELECT ` + webhookDeliveryColumns + `
+	FROM webhook_deliveries d JOIN webhook_subscriptions s ON s.id = d.subscription_id
+	WHERE 1=1`
+	args := make([]any, 0)
+	argCount := 1
+
+	if status != "" {
+		query += fmt.Sprintf(" AND d.status = $%d", argCount)
+		args = append(args, status)
+		argCount++
+	}
+	if subscriptionID > 0 {
+		query += fmt.Sprintf(" AND d.subscription_id = $%d", argCount)
+		args = append(args, subscriptionID)
+		argCount++
+	}
+
+	query += ` ORDER BY d.id DESC`
+
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	} else {
+		query += " LIMIT 100"
+	}
+
+	return ListDeliveriesResult{Query: query, Args: args}
+}
+
+func (q *WebhookQueries) CountDeliveriesByStatus(ctx context.Context) string {
+	return `SELECT status, COUNT(*) FROM webhook_deliveries GROUP BY status`
+}
+
+// PurgeProcessedOutbox removes fanned-out outbox events older than the retention window
+func (q *WebhookQueries) PurgeProcessedOutbox(ctx context.Context) string {
+	return `DELETE FROM outbox_events WHERE processed_at IS NOT NULL AND processed_at < CURRENT_TIMESTAMP - $1::int * INTERVAL '1 second'`
+}
@@ -39,6 +39,7 @@ func (r *categoryRepository) GetByID(ctx context.Context, id int) (*model.Catego
 	err := row.Scan(
 		&cat.ID, &cat.Name, &cat.Prefix, &cat.Priority,
 		&cat.ColorCode, &cat.Description, &cat.Icon, &cat.IsActive,
+		&cat.SLAWaitMinutes, &cat.SLAServiceMinutes,
 		&cat.CreatedAt, &cat.UpdatedAt,
 	)
 	if err != nil {
@@ -55,7 +56,8 @@ func (r *categoryRepository) Create(ctx context.Context, category *model.Categor
 	sql := r.categoryQry.CreateCategory(ctx)
 	var id int
 	var createdAt, updatedAt time.Time
-	err := r.pool.QueryRow(ctx, sql, category.Name, category.Prefix, category.Priority, category.ColorCode, category.Description, category.Icon, category.IsActive).Scan(&id, &createdAt, &updatedAt)
+	err := r.pool.QueryRow(ctx, sql, category.Name, category.Prefix, category.Priority, category.ColorCode, category.Description, category.Icon, category.IsActive,
+		category.SLAWaitMinutes, category.SLAServiceMinutes).Scan(&id, &createdAt, &updatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +70,8 @@ func (r *categoryRepository) Create(ctx context.Context, category *model.Categor
 
 func (r *categoryRepository) Update(ctx context.Context, category *model.Category) (*model.Category, error) {
 	sql := r.categoryQry.UpdateCategory(ctx)
-	_, err := r.pool.Exec(ctx, sql, category.Name, category.Prefix, category.Priority, category.ColorCode, category.Description, category.Icon, category.IsActive, category.ID)
+	_, err := r.pool.Exec(ctx, sql, category.Name, category.Prefix, category.Priority, category.ColorCode, category.Description, category.Icon, category.IsActive,
+		category.SLAWaitMinutes, category.SLAServiceMinutes, category.ID)
 	if err != nil {
 		return nil, err
 	}
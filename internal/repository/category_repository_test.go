@@ -24,8 +24,8 @@ func TestCategoryRepository_GetByID(t *testing.T) {
 
 	catID := 1
 	now := time.Now()
-	rows := pgxmock.NewRows([]string{"id", "name", "prefix", "priority", "color_code", "description", "icon", "is_active", "created_at", "updated_at"}).
-		AddRow(catID, "General", "A", 1, "#000000", "General Service", "box", true, now, now)
+	rows := pgxmock.NewRows([]string{"id", "name", "prefix", "priority", "color_code", "description", "icon", "is_active", "sla_wait_minutes", "sla_service_minutes", "created_at", "updated_at"}).
+		AddRow(catID, "General", "A", 1, "#000000", "General Service", "box", true, 15, 0, now, now)
 
 	mock.ExpectQuery("SELECT id, name, prefix").
 		WithArgs(catID).
@@ -38,6 +38,7 @@ func TestCategoryRepository_GetByID(t *testing.T) {
 	assert.NotNil(t, cat)
 	assert.Equal(t, "General", cat.Name)
 	assert.Equal(t, "A", cat.Prefix)
+	assert.Equal(t, 15, cat.SLAWaitMinutes)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
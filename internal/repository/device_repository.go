@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/query"
+)
+
+type DeviceRepository interface {
+	List(ctx context.Context) ([]model.Device, error)
+	GetByID(ctx context.Context, id int) (*model.Device, error)
+	GetByPairingCode(ctx context.Context, code string) (*model.Device, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*model.Device, error)
+	Create(ctx context.Context, device *model.Device) (*model.Device, error)
+	Update(ctx context.Context, device *model.Device) error
+	Delete(ctx context.Context, id int) error
+	SetPairingCode(ctx context.Context, id int, code string, expiresAt time.Time) error
+	CompletePairing(ctx context.Context, id int, code, tokenHash string) (bool, error)
+	RecordHeartbeat(ctx context.Context, device *model.Device) error
+	ListNewlySilent(ctx context.Context, silentFor time.Duration) ([]model.Device, error)
+	MarkSilent(ctx context.Context, id int, silentFor time.Duration) (bool, error)
+}
+
+type deviceRepository struct {
+	pool DB
+	qry  *query.DeviceQueries
+}
+
+func NewDeviceRepository(pool DB) DeviceRepository {
+	return &deviceRepository{
+		pool: pool,
+		qry:  query.NewDeviceQueries(),
+	}
+}
+
+func (r *deviceRepository) List(ctx context.Context) ([]model.Device, error) {
+	queryStr := r.qry.List(ctx)
+	rows, err := r.pool.Query(ctx, queryStr)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "List").Msg("Failed to list devices")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.Device])
+}
+
+func (r *deviceRepository) GetByID(ctx context.Context, id int) (*model.Device, error) {
+	return r.getOne(ctx, r.qry.GetByID(ctx), id)
+}
+
+func (r *deviceRepository) GetByPairingCode(ctx context.Context, code string) (*model.Device, error) {
+	return r.getOne(ctx, r.qry.GetByPairingCode(ctx), code)
+}
+
+func (r *deviceRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*model.Device, error) {
+	return r.getOne(ctx, r.qry.GetByTokenHash(ctx), tokenHash)
+}
+
+func (r *deviceRepository) getOne(ctx context.Context, queryStr string, arg any) (*model.Device, error) {
+	rows, err := r.pool.Query(ctx, queryStr, arg)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "getOne").Msg("Failed to get device")
+		return nil, err
+	}
+	defer rows.Close()
+
+	device, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[model.Device])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return device, nil
+}
+
+func (r *deviceRepository) Create(ctx context.Context, device *model.Device) (*model.Device, error) {
+	queryStr := r.qry.Create(ctx)
+	err := r.pool.QueryRow(ctx, queryStr, device.Name, device.Kind, device.DisplayProfileID, device.KioskProfileID, device.PairingCode, device.PairingExpiresAt).
+		Scan(&device.ID, &device.CreatedAt, &device.UpdatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("name", device.Name).Msg("Failed to create device")
+		return nil, err
+	}
+	return device, nil
+}
+
+func (r *deviceRepository) Update(ctx context.Context, device *model.Device) error {
+	queryStr := r.qry.Update(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, device.ID, device.Name, device.Kind, device.DisplayProfileID, device.KioskProfileID)
+	return err
+}
+
+func (r *deviceRepository) Delete(ctx context.Context, id int) error {
+	queryStr := r.qry.Delete(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id)
+	return err
+}
+
+func (r *deviceRepository) SetPairingCode(ctx context.Context, id int, code string, expiresAt time.Time) error {
+	queryStr := r.qry.SetPairingCode(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id, code, expiresAt)
+	return err
+}
+
+func (r *deviceRepository) CompletePairing(ctx context.Context, id int, code, tokenHash string) (bool, error) {
+	queryStr := r.qry.CompletePairing(ctx)
+	result, err := r.pool.Exec(ctx, queryStr, id, code, tokenHash)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("id", id).Msg("Failed to pair device")
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *deviceRepository) RecordHeartbeat(ctx context.Context, device *model.Device) error {
+	queryStr := r.qry.RecordHeartbeat(ctx)
+	_, err := r.pool.Exec(ctx, queryStr,
+		device.ID, device.AppVersion, device.UserAgent, device.CurrentURL, device.UptimeSeconds, device.LastEventSeq,
+	)
+	return err
+}
+
+func (r *deviceRepository) ListNewlySilent(ctx context.Context, silentFor time.Duration) ([]model.Device, error) {
+	queryStr := r.qry.ListNewlySilent(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, silentFor.Seconds())
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListNewlySilent").Msg("Failed to list silent devices")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.Device])
+}
+
+func (r *deviceRepository) MarkSilent(ctx context.Context, id int, silentFor time.Duration) (bool, error) {
+	queryStr := r.qry.MarkSilent(ctx)
+	var silentSince sql.NullTime
+	err := r.pool.QueryRow(ctx, queryStr, id, silentFor.Seconds()).Scan(&silentSince)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/query"
+)
+
+type DisplayProfileRepository interface {
+	List(ctx context.Context) ([]model.DisplayProfile, error)
+	GetByID(ctx context.Context, id int) (*model.DisplayProfile, error)
+	GetBySlug(ctx context.Context, slug string) (*model.DisplayProfile, error)
+	Create(ctx context.Context, profile *model.DisplayProfile) (*model.DisplayProfile, error)
+	Update(ctx context.Context, profile *model.DisplayProfile) error
+	Delete(ctx context.Context, id int) error
+}
+
+type displayProfileRepository struct {
+	pool DB
+	qry  *query.DisplayProfileQueries
+}
+
+func NewDisplayProfileRepository(pool DB) DisplayProfileRepository {
+	return &displayProfileRepository{
+		pool: pool,
+		qry:  query.NewDisplayProfileQueries(),
+	}
+}
+
+func (r *displayProfileRepository) List(ctx context.Context) ([]model.DisplayProfile, error) {
+	queryStr := r.qry.List(ctx)
+	rows, err := r.pool.Query(ctx, queryStr)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "List").Msg("Failed to list display profiles")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.DisplayProfile])
+}
+
+func (r *displayProfileRepository) GetByID(ctx context.Context, id int) (*model.DisplayProfile, error) {
+	queryStr := r.qry.GetByID(ctx)
+	return r.getOne(ctx, queryStr, id)
+}
+
+func (r *displayProfileRepository) GetBySlug(ctx context.Context, slug string) (*model.DisplayProfile, error) {
+	queryStr := r.qry.GetBySlug(ctx)
+	return r.getOne(ctx, queryStr, slug)
+}
+
+func (r *displayProfileRepository) getOne(ctx context.Context, queryStr string, arg any) (*model.DisplayProfile, error) {
+	rows, err := r.pool.Query(ctx, queryStr, arg)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Interface("key", arg).Msg("Failed to get display profile")
+		return nil, err
+	}
+	defer rows.Close()
+
+	profile, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[model.DisplayProfile])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (r *displayProfileRepository) Create(ctx context.Context, profile *model.DisplayProfile) (*model.DisplayProfile, error) {
+	queryStr := r.qry.Create(ctx)
+	err := r.pool.QueryRow(ctx, queryStr,
+		profile.Slug, profile.Name, profile.Layout, profile.CategoryIDs, profile.CounterIDs, profile.RecentCount,
+		profile.ShowWaiting, profile.AccentColor, profile.BackgroundColor, profile.TickerText, profile.IsActive,
+	).Scan(&profile.ID, &profile.CreatedAt, &profile.UpdatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("slug", profile.Slug).Msg("Failed to create display profile")
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (r *displayProfileRepository) Update(ctx context.Context, profile *model.DisplayProfile) error {
+	queryStr := r.qry.Update(ctx)
+	return r.pool.QueryRow(ctx, queryStr,
+		profile.ID, profile.Slug, profile.Name, profile.Layout, profile.CategoryIDs, profile.CounterIDs, profile.RecentCount,
+		profile.ShowWaiting, profile.AccentColor, profile.BackgroundColor, profile.TickerText, profile.IsActive,
+	).Scan(&profile.UpdatedAt)
+}
+
+func (r *displayProfileRepository) Delete(ctx context.Context, id int) error {
+	queryStr := r.qry.Delete(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id)
+	return err
+}
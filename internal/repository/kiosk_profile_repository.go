@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/query"
+)
+
+type KioskProfileRepository interface {
+	List(ctx context.Context) ([]model.KioskProfile, error)
+	GetByID(ctx context.Context, id int) (*model.KioskProfile, error)
+	GetBySlug(ctx context.Context, slug string) (*model.KioskProfile, error)
+	Create(ctx context.Context, profile *model.KioskProfile) (*model.KioskProfile, error)
+	Update(ctx context.Context, profile *model.KioskProfile) error
+	Delete(ctx context.Context, id int) error
+	ListCategories(ctx context.Context, profileID int) ([]model.KioskProfileCategory, error)
+	ReplaceCategories(ctx context.Context, profileID int, categories []model.KioskProfileCategory) error
+}
+
+type kioskProfileRepository struct {
+	pool DB
+	qry  *query.KioskProfileQueries
+}
+
+func NewKioskProfileRepository(pool DB) KioskProfileRepository {
+	return &kioskProfileRepository{
+		pool: pool,
+		qry:  query.NewKioskProfileQueries(),
+	}
+}
+
+func (r *kioskProfileRepository) List(ctx context.Context) ([]model.KioskProfile, error) {
+	queryStr := r.qry.List(ctx)
+	rows, err := r.pool.Query(ctx, queryStr)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "List").Msg("Failed to list kiosk profiles")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.KioskProfile])
+}
+
+func (r *kioskProfileRepository) GetByID(ctx context.Context, id int) (*model.KioskProfile, error) {
+	queryStr := r.qry.GetByID(ctx)
+	return r.getOne(ctx, queryStr, id)
+}
+
+func (r *kioskProfileRepository) GetBySlug(ctx context.Context, slug string) (*model.KioskProfile, error) {
+	queryStr := r.qry.GetBySlug(ctx)
+	return r.getOne(ctx, queryStr, slug)
+}
+
+func (r *kioskProfileRepository) getOne(ctx context.Context, queryStr string, arg any) (*model.KioskProfile, error) {
+	rows, err := r.pool.Query(ctx, queryStr, arg)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Interface("key", arg).Msg("Failed to get kiosk profile")
+		return nil, err
+	}
+	defer rows.Close()
+
+	profile, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[model.KioskProfile])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (r *kioskProfileRepository) Create(ctx context.Context, profile *model.KioskProfile) (*model.KioskProfile, error) {
+	queryStr := r.qry.Create(ctx)
+	err := r.pool.QueryRow(ctx, queryStr,
+		profile.Slug, profile.Name, profile.Language, profile.TicketOutput, profile.PrinterType, profile.PrinterTarget,
+		profile.IdleSeconds, profile.AttractTitle, profile.AttractText, profile.IsActive,
+	).Scan(&profile.ID, &profile.CreatedAt, &profile.UpdatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("slug", profile.Slug).Msg("Failed to create kiosk profile")
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (r *kioskProfileRepository) Update(ctx context.Context, profile *model.KioskProfile) error {
+	queryStr := r.qry.Update(ctx)
+	return r.pool.QueryRow(ctx, queryStr,
+		profile.ID, profile.Slug, profile.Name, profile.Language, profile.TicketOutput, profile.PrinterType,
+		profile.PrinterTarget, profile.IdleSeconds, profile.AttractTitle, profile.AttractText, profile.IsActive,
+	).Scan(&profile.UpdatedAt)
+}
+
+func (r *kioskProfileRepository) Delete(ctx context.Context, id int) error {
+	queryStr := r.qry.Delete(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id)
+	return err
+}
+
+func (r *kioskProfileRepository) ListCategories(ctx context.Context, profileID int) ([]model.KioskProfileCategory, error) {
+	queryStr := r.qry.ListCategories(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, profileID)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("kiosk_profile_id", profileID).Msg("Failed to list kiosk profile categories")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.KioskProfileCategory])
+}
+
+func (r *kioskProfileRepository) ReplaceCategories(ctx context.Context, profileID int, categories []model.KioskProfileCategory) error {
+	categoryIDs := make([]int, len(categories))
+	labels := make([]string, len(categories))
+	icons := make([]string, len(categories))
+	for i, category := range categories {
+		categoryIDs[i] = category.CategoryID
+		labels[i] = category.Label
+		icons[i] = category.Icon
+	}
+
+	queryStr := r.qry.ReplaceCategories(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, profileID, categoryIDs, labels, icons)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("kiosk_profile_id", profileID).Msg("Failed to replace kiosk profile categories")
+	}
+	return err
+}
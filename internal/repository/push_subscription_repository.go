@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/query"
+)
+
+type PushSubscriptionRepository interface {
+	Upsert(ctx context.Context, sub *model.PushSubscription) (*model.PushSubscription, error)
+	ListByTicketID(ctx context.Context, ticketID int) ([]model.PushSubscription, error)
+	ListNearlyUpPending(ctx context.Context, ticketIDs []int) ([]model.PushSubscription, error)
+	MarkNearlyUpNotified(ctx context.Context, id int) error
+	MarkCalledNotified(ctx context.Context, id int) error
+	DeleteByID(ctx context.Context, id int) error
+	DeleteByTicketAndEndpoint(ctx context.Context, ticketID int, endpoint string) error
+	DeleteExpired(ctx context.Context) (int, error)
+}
+
+type pushSubscriptionRepository struct {
+	pool DB
+	qry  *query.PushSubscriptionQueries
+}
+
+func NewPushSubscriptionRepository(pool DB) PushSubscriptionRepository {
+	return &pushSubscriptionRepository{
+		pool: pool,
+		qry:  query.NewPushSubscriptionQueries(),
+	}
+}
+
+func (r *pushSubscriptionRepository) Upsert(ctx context.Context, sub *model.PushSubscription) (*model.PushSubscription, error) {
+	queryStr := r.qry.Upsert(ctx)
+	var id int
+	var createdAt, updatedAt time.Time
+	err := r.pool.QueryRow(ctx, queryStr, sub.TicketID, sub.Endpoint, sub.P256dh, sub.Auth, sub.ExpiresAt).Scan(&id, &createdAt, &updatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("ticket_id", sub.TicketID).Msg("Failed to upsert push subscription")
+		return nil, err
+	}
+
+	sub.ID = id
+	sub.CreatedAt = createdAt
+	sub.UpdatedAt = updatedAt
+	return sub, nil
+}
+
+func (r *pushSubscriptionRepository) ListByTicketID(ctx context.Context, ticketID int) ([]model.PushSubscription, error) {
+	queryStr := r.qry.ListByTicketID(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, ticketID)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListByTicketID").Msg("Failed to list push subscriptions")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.PushSubscription])
+}
+
+func (r *pushSubscriptionRepository) ListNearlyUpPending(ctx context.Context, ticketIDs []int) ([]model.PushSubscription, error) {
+	if len(ticketIDs) == 0 {
+		return []model.PushSubscription{}, nil
+	}
+
+	queryStr := r.qry.ListNearlyUpPending(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, ticketIDs)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListNearlyUpPending").Msg("Failed to list push subscriptions")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.PushSubscription])
+}
+
+func (r *pushSubscriptionRepository) MarkNearlyUpNotified(ctx context.Context, id int) error {
+	queryStr := r.qry.MarkNearlyUpNotified(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id)
+	return err
+}
+
+func (r *pushSubscriptionRepository) MarkCalledNotified(ctx context.Context, id int) error {
+	queryStr := r.qry.MarkCalledNotified(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id)
+	return err
+}
+
+func (r *pushSubscriptionRepository) DeleteByID(ctx context.Context, id int) error {
+	queryStr := r.qry.DeleteByID(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id)
+	return err
+}
+
+func (r *pushSubscriptionRepository) DeleteByTicketAndEndpoint(ctx context.Context, ticketID int, endpoint string) error {
+	queryStr := r.qry.DeleteByTicketAndEndpoint(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, ticketID, endpoint)
+	return err
+}
+
+func (r *pushSubscriptionRepository) DeleteExpired(ctx context.Context) (int, error) {
+	queryStr := r.qry.DeleteExpired(ctx)
+	result, err := r.pool.Exec(ctx, queryStr)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected()), nil
+}
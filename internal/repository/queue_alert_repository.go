@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/query"
+)
+
+type QueueAlertRepository interface {
+	ListRules(ctx context.Context) ([]model.AlertRule, error)
+	ListActiveRules(ctx context.Context) ([]model.AlertRule, error)
+	GetRuleByID(ctx context.Context, id int) (*model.AlertRule, error)
+	CreateRule(ctx context.Context, rule *model.AlertRule) (*model.AlertRule, error)
+	UpdateRule(ctx context.Context, rule *model.AlertRule) error
+	DeleteRule(ctx context.Context, id int) error
+
+	QueueStats(ctx context.Context) ([]model.QueueStat, error)
+	// RecordAlert records an alert and starts the cooldown of its rule for its category.
+	// It returns nil when the rule is still cooling down there.
+	RecordAlert(ctx context.Context, alert *model.QueueAlert, cooldownMinutes int) (*model.QueueAlert, error)
+	// MarkAlertEmailed records that an alert was emailed, or why sending failed when errMsg is set
+	MarkAlertEmailed(ctx context.Context, id int, errMsg string) error
+	ListAlerts(ctx context.Context, ruleID, limit int) ([]model.QueueAlert, error)
+}
+
+type queueAlertRepository struct {
+	pool DB
+	qry  *query.QueueAlertQueries
+}
+
+func NewQueueAlertRepository(pool DB) QueueAlertRepository {
+	return &queueAlertRepository{
+		pool: pool,
+		qry:  query.NewQueueAlertQueries(),
+	}
+}
+
+func (r *queueAlertRepository) ListRules(ctx context.Context) ([]model.AlertRule, error) {
+	rows, err := r.pool.Query(ctx, r.qry.ListRules(ctx))
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListRules").Msg("Failed to list alert rules")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.AlertRule])
+}
+
+func (r *queueAlertRepository) ListActiveRules(ctx context.Context) ([]model.AlertRule, error) {
+	rows, err := r.pool.Query(ctx, r.qry.ListActiveRules(ctx))
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListActiveRules").Msg("Failed to list active alert rules")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.AlertRule])
+}
+
+func (r *queueAlertRepository) GetRuleByID(ctx context.Context, id int) (*model.AlertRule, error) {
+	rows, err := r.pool.Query(ctx, r.qry.GetRuleByID(ctx), id)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("id", id).Msg("Failed to get alert rule")
+		return nil, err
+	}
+	defer rows.Close()
+
+	rule, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[model.AlertRule])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return rule, err
+}
+
+func (r *queueAlertRepository) CreateRule(ctx context.Context, rule *model.AlertRule) (*model.AlertRule, error) {
+	err := r.pool.QueryRow(ctx, r.qry.CreateRule(ctx),
+		rule.Name, rule.Kind, rule.CategoryID, rule.Threshold, rule.CooldownMinutes,
+		rule.EmailRecipients, rule.NotifyWebhook, rule.IsActive,
+	).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("name", rule.Name).Msg("Failed to create alert rule")
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *queueAlertRepository) UpdateRule(ctx context.Context, rule *model.AlertRule) error {
+	_, err := r.pool.Exec(ctx, r.qry.UpdateRule(ctx),
+		rule.ID, rule.Name, rule.Kind, rule.CategoryID, rule.Threshold, rule.CooldownMinutes,
+		rule.EmailRecipients, rule.NotifyWebhook, rule.IsActive,
+	)
+	return err
+}
+
+func (r *queueAlertRepository) DeleteRule(ctx context.Context, id int) error {
+	_, err := r.pool.Exec(ctx, r.qry.DeleteRule(ctx), id)
+	return err
+}
+
+func (r *queueAlertRepository) QueueStats(ctx context.Context) ([]model.QueueStat, error) {
+	rows, err := r.pool.Query(ctx, r.qry.QueueStats(ctx))
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "QueueStats").Msg("Failed to read queue stats")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.QueueStat])
+}
+
+func (r *queueAlertRepository) RecordAlert(ctx context.Context, alert *model.QueueAlert, cooldownMinutes int) (*model.QueueAlert, error) {
+	rows, err := r.pool.Query(ctx, r.qry.RecordAlert(ctx),
+		alert.RuleID.Int64, alert.CategoryID.Int64, cooldownMinutes,
+		alert.RuleName, alert.Kind, alert.CategoryName, alert.Value, alert.Threshold, alert.Message, alert.NotifyWebhook,
+	)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "RecordAlert").Int64("rule_id", alert.RuleID.Int64).Msg("Failed to record queue alert")
+		return nil, err
+	}
+	defer rows.Close()
+
+	recorded, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[model.QueueAlert])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return recorded, err
+}
+
+func (r *queueAlertRepository) MarkAlertEmailed(ctx context.Context, id int, errMsg string) error {
+	_, err := r.pool.Exec(ctx, r.qry.MarkAlertEmailed(ctx), id, sql.NullString{String: errMsg, Valid: errMsg != ""})
+	return err
+}
+
+func (r *queueAlertRepository) ListAlerts(ctx context.Context, ruleID, limit int) ([]model.QueueAlert, error) {
+	rows, err := r.pool.Query(ctx, r.qry.ListAlerts(ctx), ruleID, limit)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListAlerts").Msg("Failed to list queue alerts")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.QueueAlert])
+}
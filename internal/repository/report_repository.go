@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/query"
+)
+
+// ticketFetchSize is how many tickets EachTicket fetches from its cursor at once
+const ticketFetchSize = 1000
+
+type ReportRepository interface {
+	Summary(ctx context.Context, filter model.ReportFilter) (*model.ReportSummary, error)
+	ByCategory(ctx context.Context, filter model.ReportFilter) ([]model.ReportCategory, error)
+	ByCounter(ctx context.Context, filter model.ReportFilter) ([]model.ReportCounter, error)
+	Daily(ctx context.Context, filter model.ReportFilter) ([]model.ReportDay, error)
+	ByStaff(ctx context.Context, filter model.ReportFilter) ([]model.ReportStaff, error)
+	Hourly(ctx context.Context, filter model.ReportFilter) ([]model.ReportHour, error)
+	Weekdays(ctx context.Context, filter model.ReportFilter) ([]model.ReportWeekday, error)
+	ListTickets(ctx context.Context, filter model.ReportFilter, limit int) ([]model.ReportTicket, error)
+	// EachTicket calls fn with every ticket of a filter, oldest first, as rows arrive;
+	// an error from fn stops the iteration and is returned
+	EachTicket(ctx context.Context, filter model.ReportFilter, fn func(*model.ReportTicket) error) error
+}
+
+type reportRepository struct {
+	pool DB
+	qry  *query.ReportQueries
+}
+
+func NewReportRepository(pool DB) ReportRepository {
+	return &reportRepository{
+		pool: pool,
+		qry:  query.NewReportQueries(),
+	}
+}
+
+func (r *reportRepository) Summary(ctx context.Context, filter model.ReportFilter) (*model.ReportSummary, error) {
+	q := r.qry.Summary(ctx, filter)
+	rows, err := r.pool.Query(ctx, q.Query, q.Args...)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "Summary").Msg("Failed to query report summary")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[model.ReportSummary])
+}
+
+func (r *reportRepository) ByCategory(ctx context.Context, filter model.ReportFilter) ([]model.ReportCategory, error) {
+	q := r.qry.ByCategory(ctx, filter)
+	return collectReport[model.ReportCategory](ctx, r.pool, q, "ByCategory")
+}
+
+func (r *reportRepository) ByCounter(ctx context.Context, filter model.ReportFilter) ([]model.ReportCounter, error) {
+	q := r.qry.ByCounter(ctx, filter)
+	return collectReport[model.ReportCounter](ctx, r.pool, q, "ByCounter")
+}
+
+func (r *reportRepository) Daily(ctx context.Context, filter model.ReportFilter) ([]model.ReportDay, error) {
+	q := r.qry.Daily(ctx, filter)
+	return collectReport[model.ReportDay](ctx, r.pool, q, "Daily")
+}
+
+func (r *reportRepository) ByStaff(ctx context.Context, filter model.ReportFilter) ([]model.ReportStaff, error) {
+	q := r.qry.ByStaff(ctx, filter)
+	return collectReport[model.ReportStaff](ctx, r.pool, q, "ByStaff")
+}
+
+func (r *reportRepository) Hourly(ctx context.Context, filter model.ReportFilter) ([]model.ReportHour, error) {
+	q := r.qry.Hourly(ctx, filter)
+	return collectReport[model.ReportHour](ctx, r.pool, q, "Hourly")
+}
+
+func (r *reportRepository) Weekdays(ctx context.Context, filter model.ReportFilter) ([]model.ReportWeekday, error) {
+	q := r.qry.Weekdays(ctx, filter)
+	return collectReport[model.ReportWeekday](ctx, r.pool, q, "Weekdays")
+}
+
+func (r *reportRepository) ListTickets(ctx context.Context, filter model.ReportFilter, limit int) ([]model.ReportTicket, error) {
+	q := r.qry.ListTickets(ctx, filter, limit)
+	return collectReport[model.ReportTicket](ctx, r.pool, q, "ListTickets")
+}
+
+func (r *reportRepository) EachTicket(ctx context.Context, filter model.ReportFilter, fn func(*model.ReportTicket) error) error {
+	// A cursor keeps the result on the server; only one batch is held here at a time
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "EachTicket").Msg("Failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.qry.DeclareTicketsCursor(ctx, filter)
+	if _, err := tx.Exec(ctx, q.Query, q.Args...); err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "EachTicket").Msg("Failed to declare report tickets cursor")
+		return err
+	}
+
+	for {
+		rows, err := tx.Query(ctx, r.qry.FetchTickets(ctx, ticketFetchSize))
+		if err != nil {
+			log.Error().Err(err).Str("layer", "repository").Str("func", "EachTicket").Msg("Failed to fetch report tickets")
+			return err
+		}
+		fetched := 0
+		for rows.Next() {
+			fetched++
+			ticket, err := pgx.RowToStructByName[model.ReportTicket](rows)
+			if err == nil {
+				err = fn(&ticket)
+			}
+			if err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if fetched < ticketFetchSize {
+			return nil
+		}
+	}
+}
+
+// collectReport runs a report query and collects its rows by column name
+func collectReport[T any](ctx context.Context, pool DB, q query.ReportQuery, fn string) ([]T, error) {
+	rows, err := pool.Query(ctx, q.Query, q.Args...)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", fn).Msg("Failed to query report")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[T])
+}
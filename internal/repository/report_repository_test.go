@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/query"
+)
+
+func TestReportRepository_EachTicket(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := &reportRepository{
+		pool: mock,
+		qry:  query.NewReportQueries(),
+	}
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	filter := model.ReportFilter{DateFrom: day, DateTo: day}
+	columns := []string{"ticket_number", "category_name", "counter_number", "staff_name", "status", "priority",
+		"created_at", "called_at", "completed_at", "wait_time", "service_time", "notes"}
+
+	// A full batch is followed by another fetch; a short one ends the iteration
+	full := pgxmock.NewRows(columns)
+	for i := 0; i < ticketFetchSize; i++ {
+		full.AddRow("A001", "Umum", "1", "Siti Aminah", "completed", 0, day, nil, nil, nil, nil, nil)
+	}
+	fetch := regexp.QuoteMeta("FETCH FORWARD 1000 FROM " + query.ReportTicketsCursor)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DECLARE "+query.ReportTicketsCursor+" NO SCROLL CURSOR FOR SELECT").
+		WithArgs(day, day.AddDate(0, 0, 1)).
+		WillReturnResult(pgxmock.NewResult("DECLARE CURSOR", 0))
+	mock.ExpectQuery(fetch).WillReturnRows(full)
+	mock.ExpectQuery(fetch).WillReturnRows(pgxmock.NewRows(columns).
+		AddRow("A002", "Umum", "", "", "waiting", 0, day, nil, nil, nil, nil, nil))
+	mock.ExpectRollback()
+
+	count := 0
+	var last string
+	err = repo.EachTicket(context.Background(), filter, func(ticket *model.ReportTicket) error {
+		count++
+		last = ticket.TicketNumber
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ticketFetchSize+1, count)
+	assert.Equal(t, "A002", last)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/query"
+)
+
+type ReportScheduleRepository interface {
+	ListSchedules(ctx context.Context) ([]model.ReportSchedule, error)
+	GetScheduleByID(ctx context.Context, id int) (*model.ReportSchedule, error)
+	CreateSchedule(ctx context.Context, schedule *model.ReportSchedule) (*model.ReportSchedule, error)
+	UpdateSchedule(ctx context.Context, schedule *model.ReportSchedule) error
+	DeleteSchedule(ctx context.Context, id int) error
+
+	ListDueSchedules(ctx context.Context, now time.Time) ([]model.ReportSchedule, error)
+	// EnqueueDueRun moves a schedule from its run at scheduledFor on to nextRunAt and queues that run.
+	// It returns false when another instance got there first.
+	EnqueueDueRun(ctx context.Context, scheduleID int, scheduledFor, nextRunAt, periodFrom, periodTo time.Time) (bool, error)
+	EnqueueRun(ctx context.Context, scheduleID int, scheduledFor, periodFrom, periodTo time.Time) (int64, error)
+	ClaimDueRuns(ctx context.Context, now time.Time, batchSize int, lease time.Duration) ([]model.ReportRun, error)
+	SetRunFile(ctx context.Context, id int64, fileName string, size int64) error
+	MarkRunSent(ctx context.Context, id int64) error
+	MarkRunFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt time.Time, failed bool) error
+	RetryRun(ctx context.Context, id int64, at time.Time) error
+	GetRunByID(ctx context.Context, id int64) (*model.ReportRun, error)
+	ListRuns(ctx context.Context, scheduleID, limit int) ([]model.ReportRun, error)
+}
+
+type reportScheduleRepository struct {
+	pool DB
+	qry  *query.ReportScheduleQueries
+}
+
+func NewReportScheduleRepository(pool DB) ReportScheduleRepository {
+	return &reportScheduleRepository{
+		pool: pool,
+		qry:  query.NewReportScheduleQueries(),
+	}
+}
+
+func (r *reportScheduleRepository) ListSchedules(ctx context.Context) ([]model.ReportSchedule, error) {
+	queryStr := r.qry.ListSchedules(ctx)
+	rows, err := r.pool.Query(ctx, queryStr)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListSchedules").Msg("Failed to list report schedules")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.ReportSchedule])
+}
+
+func (r *reportScheduleRepository) GetScheduleByID(ctx context.Context, id int) (*model.ReportSchedule, error) {
+	queryStr := r.qry.GetScheduleByID(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, id)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("id", id).Msg("Failed to get report schedule")
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedule, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[model.ReportSchedule])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return schedule, err
+}
+
+func (r *reportScheduleRepository) CreateSchedule(ctx context.Context, schedule *model.ReportSchedule) (*model.ReportSchedule, error) {
+	queryStr := r.qry.CreateSchedule(ctx)
+	err := r.pool.QueryRow(ctx, queryStr,
+		schedule.Name, schedule.ReportType, schedule.Format, schedule.CategoryID, schedule.CounterID,
+		schedule.Status, schedule.Recipients, schedule.Cron, schedule.IsActive, schedule.NextRunAt,
+	).Scan(&schedule.ID, &schedule.CreatedAt, &schedule.UpdatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("name", schedule.Name).Msg("Failed to create report schedule")
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (r *reportScheduleRepository) UpdateSchedule(ctx context.Context, schedule *model.ReportSchedule) error {
+	queryStr := r.qry.UpdateSchedule(ctx)
+	_, err := r.pool.Exec(ctx, queryStr,
+		schedule.ID, schedule.Name, schedule.ReportType, schedule.Format, schedule.CategoryID, schedule.CounterID,
+		schedule.Status, schedule.Recipients, schedule.Cron, schedule.IsActive, schedule.NextRunAt,
+	)
+	return err
+}
+
+func (r *reportScheduleRepository) DeleteSchedule(ctx context.Context, id int) error {
+	queryStr := r.qry.DeleteSchedule(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id)
+	return err
+}
+
+func (r *reportScheduleRepository) ListDueSchedules(ctx context.Context, now time.Time) ([]model.ReportSchedule, error) {
+	queryStr := r.qry.ListDueSchedules(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, now)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListDueSchedules").Msg("Failed to list due report schedules")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.ReportSchedule])
+}
+
+func (r *reportScheduleRepository) EnqueueDueRun(ctx context.Context, scheduleID int, scheduledFor, nextRunAt, periodFrom, periodTo time.Time) (bool, error) {
+	queryStr := r.qry.EnqueueDueRun(ctx)
+	var id int64
+	err := r.pool.QueryRow(ctx, queryStr, scheduleID, scheduledFor, nextRunAt, periodFrom, periodTo).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("schedule_id", scheduleID).Msg("Failed to queue report run")
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *reportScheduleRepository) EnqueueRun(ctx context.Context, scheduleID int, scheduledFor, periodFrom, periodTo time.Time) (int64, error) {
+	queryStr := r.qry.EnqueueRun(ctx)
+	var id int64
+	err := r.pool.QueryRow(ctx, queryStr, scheduleID, scheduledFor, periodFrom, periodTo).Scan(&id)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("schedule_id", scheduleID).Msg("Failed to queue report run")
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *reportScheduleRepository) ClaimDueRuns(ctx context.Context, now time.Time, batchSize int, lease time.Duration) ([]model.ReportRun, error) {
+	queryStr := r.qry.ClaimDueRuns(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, now, batchSize, int(lease.Seconds()))
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ClaimDueRuns").Msg("Failed to claim report runs")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.ReportRun])
+}
+
+func (r *reportScheduleRepository) SetRunFile(ctx context.Context, id int64, fileName string, size int64) error {
+	queryStr := r.qry.SetRunFile(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id, fileName, size)
+	return err
+}
+
+func (r *reportScheduleRepository) MarkRunSent(ctx context.Context, id int64) error {
+	queryStr := r.qry.MarkRunSent(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id)
+	return err
+}
+
+func (r *reportScheduleRepository) MarkRunFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt time.Time, failed bool) error {
+	status := model.ReportRunPending
+	if failed {
+		status = model.ReportRunFailed
+	}
+
+	queryStr := r.qry.MarkRunFailed(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id, errMsg, nextAttemptAt, status)
+	return err
+}
+
+func (r *reportScheduleRepository) RetryRun(ctx context.Context, id int64, at time.Time) error {
+	queryStr := r.qry.RetryRun(ctx)
+	result, err := r.pool.Exec(ctx, queryStr, id, at)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *reportScheduleRepository) GetRunByID(ctx context.Context, id int64) (*model.ReportRun, error) {
+	queryStr := r.qry.GetRunByID(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, id)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int64("id", id).Msg("Failed to get report run")
+		return nil, err
+	}
+	defer rows.Close()
+
+	run, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[model.ReportRun])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return run, err
+}
+
+func (r *reportScheduleRepository) ListRuns(ctx context.Context, scheduleID, limit int) ([]model.ReportRun, error) {
+	queryStr := r.qry.ListRuns(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, scheduleID, limit)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListRuns").Msg("Failed to list report runs")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.ReportRun])
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/query"
+)
+
+type SignageRepository interface {
+	ListMedia(ctx context.Context) ([]model.SignageMedia, error)
+	GetMediaByID(ctx context.Context, id int) (*model.SignageMedia, error)
+	GetMediaByFilename(ctx context.Context, filename string) (*model.SignageMedia, error)
+	CreateMedia(ctx context.Context, media *model.SignageMedia) (*model.SignageMedia, error)
+	DeleteMedia(ctx context.Context, id int) error
+
+	ListPlaylists(ctx context.Context) ([]model.SignagePlaylist, error)
+	GetPlaylistByID(ctx context.Context, id int) (*model.SignagePlaylist, error)
+	CreatePlaylist(ctx context.Context, playlist *model.SignagePlaylist) (*model.SignagePlaylist, error)
+	UpdatePlaylist(ctx context.Context, playlist *model.SignagePlaylist) error
+	DeletePlaylist(ctx context.Context, id int) error
+	ListPlaylistItems(ctx context.Context, playlistID int) ([]model.SignagePlaylistItem, error)
+	ReplacePlaylistItems(ctx context.Context, playlistID int, items []model.SignagePlaylistItem) error
+
+	ListSchedules(ctx context.Context) ([]model.SignageSchedule, error)
+	ListSchedulesByProfile(ctx context.Context, profileID int) ([]model.SignageSchedule, error)
+	GetScheduleByID(ctx context.Context, id int) (*model.SignageSchedule, error)
+	CreateSchedule(ctx context.Context, schedule *model.SignageSchedule) (*model.SignageSchedule, error)
+	UpdateSchedule(ctx context.Context, schedule *model.SignageSchedule) error
+	DeleteSchedule(ctx context.Context, id int) error
+}
+
+type signageRepository struct {
+	pool DB
+	qry  *query.SignageQueries
+}
+
+func NewSignageRepository(pool DB) SignageRepository {
+	return &signageRepository{
+		pool: pool,
+		qry:  query.NewSignageQueries(),
+	}
+}
+
+func (r *signageRepository) ListMedia(ctx context.Context) ([]model.SignageMedia, error) {
+	queryStr := r.qry.ListMedia(ctx)
+	rows, err := r.pool.Query(ctx, queryStr)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListMedia").Msg("Failed to list signage media")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.SignageMedia])
+}
+
+func (r *signageRepository) GetMediaByID(ctx context.Context, id int) (*model.SignageMedia, error) {
+	return r.getMedia(ctx, r.qry.GetMediaByID(ctx), id)
+}
+
+func (r *signageRepository) GetMediaByFilename(ctx context.Context, filename string) (*model.SignageMedia, error) {
+	return r.getMedia(ctx, r.qry.GetMediaByFilename(ctx), filename)
+}
+
+func (r *signageRepository) getMedia(ctx context.Context, queryStr string, arg any) (*model.SignageMedia, error) {
+	rows, err := r.pool.Query(ctx, queryStr, arg)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Interface("key", arg).Msg("Failed to get signage media")
+		return nil, err
+	}
+	defer rows.Close()
+
+	media, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[model.SignageMedia])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return media, nil
+}
+
+func (r *signageRepository) CreateMedia(ctx context.Context, media *model.SignageMedia) (*model.SignageMedia, error) {
+	queryStr := r.qry.CreateMedia(ctx)
+	err := r.pool.QueryRow(ctx, queryStr, media.Name, media.Filename, media.ContentType, media.SizeBytes).
+		Scan(&media.ID, &media.CreatedAt, &media.UpdatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("filename", media.Filename).Msg("Failed to create signage media")
+		return nil, err
+	}
+	return media, nil
+}
+
+func (r *signageRepository) DeleteMedia(ctx context.Context, id int) error {
+	queryStr := r.qry.DeleteMedia(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id)
+	return err
+}
+
+func (r *signageRepository) ListPlaylists(ctx context.Context) ([]model.SignagePlaylist, error) {
+	queryStr := r.qry.ListPlaylists(ctx)
+	rows, err := r.pool.Query(ctx, queryStr)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListPlaylists").Msg("Failed to list signage playlists")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.SignagePlaylist])
+}
+
+func (r *signageRepository) GetPlaylistByID(ctx context.Context, id int) (*model.SignagePlaylist, error) {
+	queryStr := r.qry.GetPlaylistByID(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, id)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("id", id).Msg("Failed to get signage playlist")
+		return nil, err
+	}
+	defer rows.Close()
+
+	playlist, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[model.SignagePlaylist])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return playlist, nil
+}
+
+func (r *signageRepository) CreatePlaylist(ctx context.Context, playlist *model.SignagePlaylist) (*model.SignagePlaylist, error) {
+	queryStr := r.qry.CreatePlaylist(ctx)
+	err := r.pool.QueryRow(ctx, queryStr, playlist.Name).Scan(&playlist.ID, &playlist.CreatedAt, &playlist.UpdatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("name", playlist.Name).Msg("Failed to create signage playlist")
+		return nil, err
+	}
+	return playlist, nil
+}
+
+func (r *signageRepository) UpdatePlaylist(ctx context.Context, playlist *model.SignagePlaylist) error {
+	queryStr := r.qry.UpdatePlaylist(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, playlist.ID, playlist.Name)
+	return err
+}
+
+func (r *signageRepository) DeletePlaylist(ctx context.Context, id int) error {
+	queryStr := r.qry.DeletePlaylist(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id)
+	return err
+}
+
+func (r *signageRepository) ListPlaylistItems(ctx context.Context, playlistID int) ([]model.SignagePlaylistItem, error) {
+	queryStr := r.qry.ListPlaylistItems(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, playlistID)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("playlist_id", playlistID).Msg("Failed to list signage playlist items")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.SignagePlaylistItem])
+}
+
+func (r *signageRepository) ReplacePlaylistItems(ctx context.Context, playlistID int, items []model.SignagePlaylistItem) error {
+	mediaIDs := make([]int, len(items))
+	durations := make([]int, len(items))
+	for i, item := range items {
+		mediaIDs[i] = item.MediaID
+		durations[i] = item.DurationSeconds
+	}
+
+	queryStr := r.qry.ReplacePlaylistItems(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, playlistID, mediaIDs, durations)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("playlist_id", playlistID).Msg("Failed to replace signage playlist items")
+	}
+	return err
+}
+
+func (r *signageRepository) ListSchedules(ctx context.Context) ([]model.SignageSchedule, error) {
+	queryStr := r.qry.ListSchedules(ctx)
+	rows, err := r.pool.Query(ctx, queryStr)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListSchedules").Msg("Failed to list signage schedules")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.SignageSchedule])
+}
+
+func (r *signageRepository) ListSchedulesByProfile(ctx context.Context, profileID int) ([]model.SignageSchedule, error) {
+	queryStr := r.qry.ListSchedulesByProfile(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, profileID)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("profile_id", profileID).Msg("Failed to list signage schedules")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.SignageSchedule])
+}
+
+func (r *signageRepository) GetScheduleByID(ctx context.Context, id int) (*model.SignageSchedule, error) {
+	queryStr := r.qry.GetScheduleByID(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, id)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("id", id).Msg("Failed to get signage schedule")
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedule, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[model.SignageSchedule])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (r *signageRepository) CreateSchedule(ctx context.Context, schedule *model.SignageSchedule) (*model.SignageSchedule, error) {
+	queryStr := r.qry.CreateSchedule(ctx)
+	err := r.pool.QueryRow(ctx, queryStr,
+		schedule.DisplayProfileID, schedule.PlaylistID, schedule.DaysOfWeek,
+		schedule.StartTime, schedule.EndTime, schedule.Priority, schedule.IsActive,
+	).Scan(&schedule.ID, &schedule.CreatedAt, &schedule.UpdatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("profile_id", schedule.DisplayProfileID).Msg("Failed to create signage schedule")
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (r *signageRepository) UpdateSchedule(ctx context.Context, schedule *model.SignageSchedule) error {
+	queryStr := r.qry.UpdateSchedule(ctx)
+	_, err := r.pool.Exec(ctx, queryStr,
+		schedule.ID, schedule.DisplayProfileID, schedule.PlaylistID, schedule.DaysOfWeek,
+		schedule.StartTime, schedule.EndTime, schedule.Priority, schedule.IsActive,
+	)
+	return err
+}
+
+func (r *signageRepository) DeleteSchedule(ctx context.Context, id int) error {
+	queryStr := r.qry.DeleteSchedule(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id)
+	return err
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/query"
+)
+
+type SLARepository interface {
+	// LiveTickets lists the tickets that have used up at least warnFraction of their target,
+	// of the given categories or of all when categoryIDs is nil
+	LiveTickets(ctx context.Context, warnFraction float64, categoryIDs []int) ([]model.SLATicket, error)
+	// RecordBreaches records the tickets that went past a target since the last call
+	RecordBreaches(ctx context.Context) ([]model.SLABreach, error)
+}
+
+type slaRepository struct {
+	pool DB
+	qry  *query.SLAQueries
+}
+
+func NewSLARepository(pool DB) SLARepository {
+	return &slaRepository{
+		pool: pool,
+		qry:  query.NewSLAQueries(),
+	}
+}
+
+func (r *slaRepository) LiveTickets(ctx context.Context, warnFraction float64, categoryIDs []int) ([]model.SLATicket, error) {
+	args := []any{warnFraction}
+	if categoryIDs != nil {
+		args = append(args, categoryIDs)
+	}
+	rows, err := r.pool.Query(ctx, r.qry.LiveTickets(ctx, categoryIDs != nil), args...)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "LiveTickets").Msg("Failed to query live SLA tickets")
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.SLATicket])
+}
+
+func (r *slaRepository) RecordBreaches(ctx context.Context) ([]model.SLABreach, error) {
+	rows, err := r.pool.Query(ctx, r.qry.RecordBreaches(ctx))
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "RecordBreaches").Msg("Failed to record SLA breaches")
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.SLABreach])
+}
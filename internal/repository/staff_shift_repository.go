@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/query"
+)
+
+type StaffShiftRepository interface {
+	// RecordActivity extends the user's open shift at counterID to now, first closing it if it
+	// went stale before staleBefore or was at another counter, and opens one if none is open
+	RecordActivity(ctx context.Context, userID, counterID int, now, staleBefore time.Time) error
+	EndShift(ctx context.Context, userID int, now, staleBefore time.Time) error
+	StartPause(ctx context.Context, userID int, now time.Time) error
+	EndPause(ctx context.Context, userID int, now time.Time) error
+	Stats(ctx context.Context, filter model.StaffPerformanceFilter, now, staleBefore time.Time) ([]model.StaffShiftStats, error)
+}
+
+type staffShiftRepository struct {
+	pool DB
+	qry  *query.StaffShiftQueries
+}
+
+func NewStaffShiftRepository(pool DB) StaffShiftRepository {
+	return &staffShiftRepository{
+		pool: pool,
+		qry:  query.NewStaffShiftQueries(),
+	}
+}
+
+func (r *staffShiftRepository) RecordActivity(ctx context.Context, userID, counterID int, now, staleBefore time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "RecordActivity").Msg("Failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, r.qry.CloseStaleShift(ctx), userID, staleBefore, counterID); err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "RecordActivity").Int("user_id", userID).Msg("Failed to close stale shift")
+		return err
+	}
+	if _, err := tx.Exec(ctx, r.qry.TouchShift(ctx), userID, counterID, now); err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "RecordActivity").Int("user_id", userID).Msg("Failed to record shift activity")
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *staffShiftRepository) EndShift(ctx context.Context, userID int, now, staleBefore time.Time) error {
+	queryStr := r.qry.EndShift(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, userID, now, staleBefore)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "EndShift").Int("user_id", userID).Msg("Failed to end shift")
+	}
+	return err
+}
+
+func (r *staffShiftRepository) StartPause(ctx context.Context, userID int, now time.Time) error {
+	queryStr := r.qry.StartPause(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, userID, now)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "StartPause").Int("user_id", userID).Msg("Failed to start pause")
+	}
+	return err
+}
+
+func (r *staffShiftRepository) EndPause(ctx context.Context, userID int, now time.Time) error {
+	queryStr := r.qry.EndPause(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, userID, now)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "EndPause").Int("user_id", userID).Msg("Failed to end pause")
+	}
+	return err
+}
+
+func (r *staffShiftRepository) Stats(ctx context.Context, filter model.StaffPerformanceFilter, now, staleBefore time.Time) ([]model.StaffShiftStats, error) {
+	q := r.qry.Stats(ctx, filter, now, staleBefore)
+	rows, err := r.pool.Query(ctx, q.Query, q.Args...)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "Stats").Msg("Failed to load shift stats")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.StaffShiftStats])
+}
@@ -16,6 +16,7 @@ type StatsRepository interface {
 	GetQueueLengthByCategories(ctx context.Context, categoryIDs []int) ([]dto.CategoryQueueStats, error)
 	GetHourlyDistribution(ctx context.Context) ([]dto.HourlyStats, error)
 	GetCurrentlyServingTickets(ctx context.Context) ([]dto.DisplayTicket, error)
+	GetRecentlyCalledTickets(ctx context.Context, categoryIDs, counterIDs []int, limit int) ([]dto.DisplayTicket, error)
 }
 
 type statsRepository struct {
@@ -215,3 +216,22 @@ func (r *statsRepository) GetCurrentlyServingTickets(ctx context.Context) ([]dto
 
 	return result, nil
 }
+
+func (r *statsRepository) GetRecentlyCalledTickets(ctx context.Context, categoryIDs, counterIDs []int, limit int) ([]dto.DisplayTicket, error) {
+	// A nil slice is sent as NULL, which would match nothing instead of everything
+	if categoryIDs == nil {
+		categoryIDs = []int{}
+	}
+	if counterIDs == nil {
+		counterIDs = []int{}
+	}
+
+	sql := r.statsQry.GetRecentlyCalledTickets(ctx)
+	rows, err := r.pool.Query(ctx, sql, categoryIDs, counterIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[dto.DisplayTicket])
+}
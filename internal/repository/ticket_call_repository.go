@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/query"
+)
+
+type TicketCallRepository interface {
+	Create(ctx context.Context, call *model.TicketCall) (*model.TicketCall, error)
+	ListByTicketID(ctx context.Context, ticketID int) ([]model.TicketCall, error)
+	GetSummary(ctx context.Context, ticketID int) (*model.TicketCallSummary, error)
+	// ListUnanswered returns serving tickets recalled minRecalls times or silent for
+	// silentFor; a zero value turns its trigger off
+	ListUnanswered(ctx context.Context, minRecalls int, silentFor time.Duration) ([]int, error)
+}
+
+type ticketCallRepository struct {
+	pool DB
+	qry  *query.TicketCallQueries
+}
+
+func NewTicketCallRepository(pool DB) TicketCallRepository {
+	return &ticketCallRepository{
+		pool: pool,
+		qry:  query.NewTicketCallQueries(),
+	}
+}
+
+func (r *ticketCallRepository) Create(ctx context.Context, call *model.TicketCall) (*model.TicketCall, error) {
+	queryStr := r.qry.Create(ctx)
+	err := r.pool.QueryRow(ctx, queryStr, call.TicketID, call.CounterID, call.UserID, call.Kind).Scan(&call.ID, &call.CalledAt)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Int("ticket_id", call.TicketID).Str("kind", call.Kind).Msg("Failed to record ticket call")
+		return nil, err
+	}
+	return call, nil
+}
+
+func (r *ticketCallRepository) ListByTicketID(ctx context.Context, ticketID int) ([]model.TicketCall, error) {
+	queryStr := r.qry.ListByTicketID(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, ticketID)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListByTicketID").Msg("Failed to list ticket calls")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.TicketCall])
+}
+
+func (r *ticketCallRepository) GetSummary(ctx context.Context, ticketID int) (*model.TicketCallSummary, error) {
+	queryStr := r.qry.GetSummary(ctx)
+	var summary model.TicketCallSummary
+	err := r.pool.QueryRow(ctx, queryStr, ticketID).Scan(&summary.TicketID, &summary.RecallCount, &summary.LastCalledAt, &summary.AnsweredAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "GetSummary").Int("ticket_id", ticketID).Msg("Failed to load call summary")
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (r *ticketCallRepository) ListUnanswered(ctx context.Context, minRecalls int, silentFor time.Duration) ([]int, error) {
+	queryStr := r.qry.ListUnanswered(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, minRecalls, silentFor.Seconds())
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListUnanswered").Msg("Failed to list unanswered calls")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
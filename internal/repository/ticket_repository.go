@@ -20,13 +20,18 @@ type TicketRepository interface {
 	GetByTicketNumber(ctx context.Context, ticketNumber string) (*model.Ticket, error)
 	Create(ctx context.Context, ticket *model.Ticket) (*model.Ticket, error)
 	UpdateStatus(ctx context.Context, id int, status string) error
-	AssignToCounter(ctx context.Context, ticketID, counterID int) error
-	UpdateCalledAt(ctx context.Context, ticketID int) error
+	AssignToCounter(ctx context.Context, ticketID, counterID, userID int) error
+	// Finish finishes a ticket being served and returns the number of rows changed, 0 when
+	// the ticket was no longer being served
+	Finish(ctx context.Context, ticketID int, status string, userID int) (int, error)
+	Recall(ctx context.Context, ticketID int) error
+	MarkAnswered(ctx context.Context, ticketID int) error
 	GetNextTicket(ctx context.Context, categoryIDs []int) (*model.Ticket, error)
 	GetCurrentForCounter(ctx context.Context, counterID int) (*model.Ticket, error)
 	List(ctx context.Context, filters map[string]interface{}) ([]model.Ticket, error)
 	GetTodayCount(ctx context.Context) (int, error)
 	GetTodayCountByCategory(ctx context.Context, categoryID int) (int, error)
+	GetWaitingAheadCount(ctx context.Context, ticketID int) (int, error)
 	GenerateNumber(ctx context.Context, categoryID int, prefix string) (string, int, error)
 	GetWaitingPreview(ctx context.Context, limit int) ([]model.Ticket, error)
 	GetWaitingPreviewByCategories(ctx context.Context, categoryIDs []int, limit int) ([]model.Ticket, error)
@@ -190,14 +195,29 @@ func (r *ticketRepository) UpdateStatus(ctx context.Context, id int, status stri
 	return err
 }
 
-func (r *ticketRepository) AssignToCounter(ctx context.Context, ticketID, counterID int) error {
+func (r *ticketRepository) AssignToCounter(ctx context.Context, ticketID, counterID, userID int) error {
 	queryStr := r.ticketQry.AssignTicketToCounter(ctx)
-	_, err := r.pool.Exec(ctx, queryStr, counterID, ticketID)
+	_, err := r.pool.Exec(ctx, queryStr, counterID, ticketID, userID)
 	return err
 }
 
-func (r *ticketRepository) UpdateCalledAt(ctx context.Context, ticketID int) error {
-	queryStr := `UPDATE tickets SET called_at = NOW() WHERE id = $1`
+func (r *ticketRepository) Finish(ctx context.Context, ticketID int, status string, userID int) (int, error) {
+	queryStr := r.ticketQry.FinishTicket(ctx)
+	result, err := r.pool.Exec(ctx, queryStr, status, ticketID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected()), nil
+}
+
+func (r *ticketRepository) Recall(ctx context.Context, ticketID int) error {
+	queryStr := r.ticketQry.RecallTicket(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, ticketID)
+	return err
+}
+
+func (r *ticketRepository) MarkAnswered(ctx context.Context, ticketID int) error {
+	queryStr := r.ticketQry.MarkTicketAnswered(ctx)
 	_, err := r.pool.Exec(ctx, queryStr, ticketID)
 	return err
 }
@@ -294,6 +314,13 @@ func (r *ticketRepository) GetTodayCountByCategory(ctx context.Context, category
 	return count, err
 }
 
+func (r *ticketRepository) GetWaitingAheadCount(ctx context.Context, ticketID int) (int, error) {
+	sql := r.ticketQry.GetWaitingAheadCount(ctx)
+	var count int
+	err := r.pool.QueryRow(ctx, sql, ticketID).Scan(&count)
+	return count, err
+}
+
 func (r *ticketRepository) GenerateNumber(ctx context.Context, categoryID int, prefix string) (string, int, error) {
 	sql := r.ticketQry.GenerateTicketNumber(ctx)
 	var number int
@@ -88,3 +88,34 @@ func TestTicketRepository_Create(t *testing.T) {
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestTicketRepository_Finish(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := &ticketRepository{
+		pool:      mock,
+		ticketQry: query.NewTicketQueries(),
+	}
+
+	expectedSQL := `UPDATE tickets SET status = \$1, .* WHERE id = \$2 AND status = 'serving'`
+	mock.ExpectExec(expectedSQL).
+		WithArgs("no_show", 10, 0).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	// No longer being served
+	mock.ExpectExec(expectedSQL).
+		WithArgs("no_show", 11, 0).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	ctx := context.Background()
+	finished, err := repo.Finish(ctx, 10, "no_show", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, finished)
+
+	finished, err = repo.Finish(ctx, 11, "no_show", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, finished)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -166,7 +166,11 @@ func (r *userRepository) UpdateLastLogin(ctx context.Context, id int) error {
 
 func (r *userRepository) List(ctx context.Context, role string) ([]model.User, error) {
 	sql := r.userQry.ListUsers(ctx, role)
-	rows, err := r.pool.Query(ctx, sql)
+	var args []any
+	if role != "" {
+		args = append(args, role)
+	}
+	rows, err := r.pool.Query(ctx, sql, args...)
 	if err != nil {
 		log.Error().Err(err).Str("layer", "repository").Str("method", "List").Str("domain", "user").Msg("Failed to list users")
 		return nil, err
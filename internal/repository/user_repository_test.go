@@ -41,3 +41,30 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUserRepository_ListByRole(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := &userRepository{
+		pool:    mock,
+		userQry: query.NewUserQueries(),
+	}
+
+	now := time.Now()
+	rows := pgxmock.NewRows([]string{"id", "username", "full_name", "email", "phone", "role", "is_active", "created_at", "updated_at", "last_login"}).
+		AddRow(5, "sari", "Sari", "sari@example.com", "", "supervisor", true, now, now, nil)
+
+	mock.ExpectQuery("WHERE role = \\$1").
+		WithArgs("supervisor").
+		WillReturnRows(rows)
+
+	users, err := repo.List(context.Background(), "supervisor")
+
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "supervisor", users[0].Role)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/query"
+)
+
+type WebhookRepository interface {
+	ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	GetSubscriptionByID(ctx context.Context, id int) (*model.WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) (*model.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, sub *model.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id int) error
+
+	FanOutOutbox(ctx context.Context, batchSize int) (int, error)
+	ClaimDueDeliveries(ctx context.Context, batchSize int, lease time.Duration) ([]model.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, statusCode int) error
+	MarkFailed(ctx context.Context, id int64, statusCode int, errMsg string, nextAttemptAt time.Time, dead bool) error
+	Redeliver(ctx context.Context, id int64) error
+	ListDeliveries(ctx context.Context, status string, subscriptionID, limit int) ([]model.WebhookDelivery, error)
+	CountDeliveriesByStatus(ctx context.Context) (map[string]int, error)
+	PurgeProcessedOutbox(ctx context.Context, olderThan time.Duration) (int, error)
+}
+
+type webhookRepository struct {
+	pool DB
+	qry  *query.WebhookQueries
+}
+
+func NewWebhookRepository(pool DB) WebhookRepository {
+	return &webhookRepository{
+		pool: pool,
+		qry:  query.NewWebhookQueries(),
+	}
+}
+
+func (r *webhookRepository) ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	queryStr := r.qry.ListSubscriptions(ctx)
+	rows, err := r.pool.Query(ctx, queryStr)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListSubscriptions").Msg("Failed to list webhook subscriptions")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.WebhookSubscription])
+}
+
+func (r *webhookRepository) GetSubscriptionByID(ctx context.Context, id int) (*model.WebhookSubscription, error) {
+	queryStr := r.qry.GetSubscriptionByID(ctx)
+	row := r.pool.QueryRow(ctx, queryStr, id)
+
+	sub := &model.WebhookSubscription{}
+	err := row.Scan(
+		&sub.ID, &sub.Name, &sub.URL, &sub.Secret,
+		&sub.EventTypes, &sub.IsActive, &sub.CreatedAt, &sub.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		log.Error().Err(err).Int("id", id).Msg("Failed to scan webhook subscription")
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	queryStr := r.qry.CreateSubscription(ctx)
+	err := r.pool.QueryRow(ctx, queryStr, sub.Name, sub.URL, sub.Secret, sub.EventTypes, sub.IsActive).Scan(&sub.ID, &sub.CreatedAt, &sub.UpdatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("url", sub.URL).Msg("Failed to create webhook subscription")
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	queryStr := r.qry.UpdateSubscription(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, sub.ID, sub.Name, sub.URL, sub.Secret, sub.EventTypes, sub.IsActive)
+	return err
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id int) error {
+	queryStr := r.qry.DeleteSubscription(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id)
+	return err
+}
+
+func (r *webhookRepository) FanOutOutbox(ctx context.Context, batchSize int) (int, error) {
+	queryStr := r.qry.FanOutOutbox(ctx)
+	result, err := r.pool.Exec(ctx, queryStr, batchSize)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "FanOutOutbox").Msg("Failed to fan out outbox events")
+		return 0, err
+	}
+	return int(result.RowsAffected()), nil
+}
+
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, batchSize int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	queryStr := r.qry.ClaimDueDeliveries(ctx)
+	rows, err := r.pool.Query(ctx, queryStr, batchSize, int(lease.Seconds()))
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ClaimDueDeliveries").Msg("Failed to claim webhook deliveries")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.WebhookDelivery])
+}
+
+func (r *webhookRepository) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	queryStr := r.qry.MarkDelivered(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id, statusCode)
+	return err
+}
+
+func (r *webhookRepository) MarkFailed(ctx context.Context, id int64, statusCode int, errMsg string, nextAttemptAt time.Time, dead bool) error {
+	status := model.WebhookDeliveryPending
+	if dead {
+		status = model.WebhookDeliveryDead
+	}
+
+	var code *int
+	if statusCode > 0 {
+		code = &statusCode
+	}
+
+	queryStr := r.qry.MarkFailed(ctx)
+	_, err := r.pool.Exec(ctx, queryStr, id, code, errMsg, nextAttemptAt, status)
+	return err
+}
+
+func (r *webhookRepository) Redeliver(ctx context.Context, id int64) error {
+	queryStr := r.qry.Redeliver(ctx)
+	result, err := r.pool.Exec(ctx, queryStr, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, status string, subscriptionID, limit int) ([]model.WebhookDelivery, error) {
+	result := r.qry.ListDeliveries(ctx, status, subscriptionID, limit)
+	rows, err := r.pool.Query(ctx, result.Query, result.Args...)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "repository").Str("func", "ListDeliveries").Msg("Failed to list webhook deliveries")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.WebhookDelivery])
+}
+
+func (r *webhookRepository) CountDeliveriesByStatus(ctx context.Context) (map[string]int, error) {
+	queryStr := r.qry.CountDeliveriesByStatus(ctx)
+	rows, err := r.pool.Query(ctx, queryStr)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{
+		model.WebhookDeliveryPending:   0,
+		model.WebhookDeliveryDelivered: 0,
+		model.WebhookDeliveryDead:      0,
+	}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
+
+func (r *webhookRepository) PurgeProcessedOutbox(ctx context.Context, olderThan time.Duration) (int, error) {
+	queryStr := r.qry.PurgeProcessedOutbox(ctx)
+	result, err := r.pool.Exec(ctx, queryStr, int(olderThan.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected()), nil
+}
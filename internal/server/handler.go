@@ -1,24 +1,45 @@
 package server
 
 import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/audio"
+	"tenangantri/internal/backplane"
 	"tenangantri/internal/config"
+	"tenangantri/internal/event"
 	"tenangantri/internal/handler"
+	"tenangantri/internal/mail"
 	"tenangantri/internal/middleware"
 	"tenangantri/internal/repository"
 	"tenangantri/internal/service"
+	"tenangantri/internal/webhook"
+	"tenangantri/internal/webpush"
 	"tenangantri/internal/websocket"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Handlers struct {
-	Hub             *websocket.Hub
-	AuthHandler     *handler.AuthHandler
-	AdminHandler    *handler.AdminHandler
-	StaffHandler    *handler.StaffHandler
-	KioskHandler    *handler.KioskHandler
-	DisplayHandler  *handler.DisplayHandler
-	TrackingHandler *handler.TrackingHandler
+	Hub                     *websocket.Hub
+	AuthHandler             *handler.AuthHandler
+	AdminHandler            *handler.AdminHandler
+	StaffHandler            *handler.StaffHandler
+	KioskHandler            *handler.KioskHandler
+	DisplayHandler          *handler.DisplayHandler
+	TrackingHandler         *handler.TrackingHandler
+	WebhookHandler          *handler.WebhookHandler
+	RealtimeHandler         *handler.RealtimeHandler
+	DisplayProfileHandler   *handler.DisplayProfileHandler
+	SignageHandler          *handler.SignageHandler
+	DeviceHandler           *handler.DeviceHandler
+	KioskProfileHandler     *handler.KioskProfileHandler
+	ReportScheduleHandler   *handler.ReportScheduleHandler
+	StaffPerformanceHandler *handler.StaffPerformanceHandler
+	SLAHandler              *handler.SLAHandler
+	QueueAlertHandler       *handler.QueueAlertHandler
 }
 
 func BuildHandlers(cfg *config.Config, pool *pgxpool.Pool) *Handlers {
@@ -29,33 +50,135 @@ func BuildHandlers(cfg *config.Config, pool *pgxpool.Pool) *Handlers {
 	categoryRepo := repository.NewCategoryRepository(pool)
 	ticketRepo := repository.NewTicketRepository(pool)
 	statsRepo := repository.NewStatsRepository(pool)
+	pushSubscriptionRepo := repository.NewPushSubscriptionRepository(pool)
+	webhookRepo := repository.NewWebhookRepository(pool)
+	ticketCallRepo := repository.NewTicketCallRepository(pool)
+	displayProfileRepo := repository.NewDisplayProfileRepository(pool)
+	signageRepo := repository.NewSignageRepository(pool)
+	deviceRepo := repository.NewDeviceRepository(pool)
+	kioskProfileRepo := repository.NewKioskProfileRepository(pool)
+	reportRepo := repository.NewReportRepository(pool)
+	reportScheduleRepo := repository.NewReportScheduleRepository(pool)
+	staffShiftRepo := repository.NewStaffShiftRepository(pool)
+	slaRepo := repository.NewSLARepository(pool)
+	queueAlertRepo := repository.NewQueueAlertRepository(pool)
+
+	bus := event.NewBus()
 
 	userService := service.NewUserService(userRepo, userCounterRepo)
-	adminService := service.NewAdminService(userRepo, userCounterRepo, counterRepo, counterCategoryRepo, categoryRepo, ticketRepo, statsRepo)
-	staffService := service.NewStaffService(userRepo, userCounterRepo, counterRepo, counterCategoryRepo, ticketRepo, statsRepo, categoryRepo)
-	kioskService := service.NewKioskService(categoryRepo, ticketRepo, statsRepo)
+	adminService := service.NewAdminService(userRepo, userCounterRepo, counterRepo, counterCategoryRepo, categoryRepo, ticketRepo, statsRepo, bus)
+	staffPerformanceService := service.NewStaffPerformanceService(staffShiftRepo, userRepo, &cfg.Shifts)
+	staffService := service.NewStaffService(userRepo, userCounterRepo, counterRepo, counterCategoryRepo, ticketRepo, statsRepo, categoryRepo, ticketCallRepo, staffPerformanceService, bus, &cfg.Calls)
+	kioskService := service.NewKioskService(categoryRepo, ticketRepo, statsRepo, kioskProfileRepo, bus)
 	displayService := service.NewDisplayService(statsRepo, categoryRepo, counterRepo)
+	displayProfileService := service.NewDisplayProfileService(displayProfileRepo, statsRepo, categoryRepo, counterRepo, bus)
+	signageService := service.NewSignageService(signageRepo, displayProfileRepo, &cfg.Signage)
+	deviceService := service.NewDeviceService(deviceRepo, displayProfileRepo, kioskProfileRepo, bus, &cfg.Devices)
+	kioskProfileService := service.NewKioskProfileService(kioskProfileRepo, categoryRepo, bus)
+	ticketPrintService := service.NewTicketPrintService(ticketRepo, &cfg.Printer)
+	reportService := service.NewReportService(reportRepo, categoryRepo, counterRepo, &cfg.Reports)
 	trackingService := service.NewTrackingService(ticketRepo, categoryRepo, counterRepo)
+	announcementService := service.NewAnnouncementService(audio.NewLibrary(cfg.Announce.ClipsDir), &cfg.Announce)
+	if len(announcementService.Languages()) == 0 {
+		log.Warn().Str("dir", cfg.Announce.ClipsDir).Msg("No announcement clips installed; calls will not be announced")
+	}
+
+	vapidKeys := loadVAPIDKeys(&cfg.WebPush)
+	pushClient := webpush.NewClient(vapidKeys, cfg.WebPush.Subject)
+	pushService := service.NewPushService(pushSubscriptionRepo, ticketRepo, counterRepo, pushClient, &cfg.WebPush)
+	go pushService.RunPurger(context.Background(), 15*time.Minute)
+	go staffService.RunNoShowSweeper(context.Background(), 15*time.Second)
+	go deviceService.RunSilenceWatcher(context.Background(), 30*time.Second)
+
+	slaService := service.NewSLAService(slaRepo, ticketRepo, userCounterRepo, counterCategoryRepo, bus, &cfg.SLA)
+	go slaService.RunMonitor(context.Background())
+
+	webhookService := service.NewWebhookService(webhookRepo, webhook.NewSender(cfg.Webhook.Timeout), &cfg.Webhook)
+	go webhookService.RunDispatcher(context.Background())
+
+	mailer := mail.NewSender(&cfg.Mail)
+	if !mailer.Configured() {
+		log.Warn().Msg("SMTP_HOST is not set; scheduled report and queue alert emails will fail until it is configured")
+	}
+	reportScheduleService := service.NewReportScheduleService(reportScheduleRepo, reportService, mailer, &cfg.Reports)
+	go reportScheduleService.RunScheduler(context.Background())
+
+	queueAlertService := service.NewQueueAlertService(queueAlertRepo, categoryRepo, mailer, bus, &cfg.Alerts)
+	go queueAlertService.RunMonitor(context.Background())
 
 	middleware.InitAuth(&cfg.JWT)
 
-	hub := websocket.NewHub()
+	hub := websocket.NewHub(&cfg.WebSocket)
 	go hub.Run()
+	startBackplane(cfg, pool, hub)
 
-	authHandler := handler.NewAuthHandler(userService, &cfg.JWT)
-	adminHandler := handler.NewAdminHandler(adminService, hub)
-	staffHandler := handler.NewStaffHandler(staffService, hub)
-	kioskHandler := handler.NewKioskHandler(kioskService, hub)
-	displayHandler := handler.NewDisplayHandler(displayService)
-	trackingHandler := handler.NewTrackingHandler(trackingService)
+	statsCache := service.NewStatsCache(statsRepo)
+	subscribeConsumers(bus, hub, statsCache, counterRepo, announcementService, pushService, webhookService)
+
+	authHandler := handler.NewAuthHandler(userService, staffPerformanceService, &cfg.JWT)
+	adminHandler := handler.NewAdminHandler(adminService, reportService)
+	staffHandler := handler.NewStaffHandler(staffService)
+	kioskHandler := handler.NewKioskHandler(kioskService, ticketPrintService)
+	displayHandler := handler.NewDisplayHandler(displayService, announcementService)
+	trackingHandler := handler.NewTrackingHandler(trackingService, pushService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	realtimeHandler := handler.NewRealtimeHandler(hub, deviceService, &cfg.WebSocket)
+	displayProfileHandler := handler.NewDisplayProfileHandler(displayProfileService)
+	signageHandler := handler.NewSignageHandler(signageService, cfg.Signage.MaxUploadSize)
+	deviceHandler := handler.NewDeviceHandler(deviceService)
+	kioskProfileHandler := handler.NewKioskProfileHandler(kioskProfileService)
+	reportScheduleHandler := handler.NewReportScheduleHandler(reportScheduleService)
+	staffPerformanceHandler := handler.NewStaffPerformanceHandler(staffPerformanceService)
+	slaHandler := handler.NewSLAHandler(slaService)
+	queueAlertHandler := handler.NewQueueAlertHandler(queueAlertService)
 
 	return &Handlers{
-		Hub:             hub,
-		AuthHandler:     authHandler,
-		AdminHandler:    adminHandler,
-		StaffHandler:    staffHandler,
-		KioskHandler:    kioskHandler,
-		DisplayHandler:  displayHandler,
-		TrackingHandler: trackingHandler,
+		Hub:                     hub,
+		AuthHandler:             authHandler,
+		AdminHandler:            adminHandler,
+		StaffHandler:            staffHandler,
+		KioskHandler:            kioskHandler,
+		DisplayHandler:          displayHandler,
+		TrackingHandler:         trackingHandler,
+		WebhookHandler:          webhookHandler,
+		RealtimeHandler:         realtimeHandler,
+		DisplayProfileHandler:   displayProfileHandler,
+		SignageHandler:          signageHandler,
+		DeviceHandler:           deviceHandler,
+		KioskProfileHandler:     kioskProfileHandler,
+		ReportScheduleHandler:   reportScheduleHandler,
+		StaffPerformanceHandler: staffPerformanceHandler,
+		SLAHandler:              slaHandler,
+		QueueAlertHandler:       queueAlertHandler,
+	}
+}
+
+// startBackplane relays hub broadcasts to other instances when a driver is configured
+func startBackplane(cfg *config.Config, pool *pgxpool.Pool, hub *websocket.Hub) {
+	switch cfg.Backplane.Driver {
+	case "", "none":
+		return
+	case "postgres":
+		log.Info().Str("instance", hub.InstanceID()).Str("channel", cfg.Backplane.Channel).Msg("Using Postgres backplane")
+		go hub.UseBackplane(context.Background(), backplane.NewPostgres(pool, &cfg.Backplane))
+	default:
+		log.Fatal().Str("driver", cfg.Backplane.Driver).Msg("Unknown BACKPLANE_DRIVER")
+	}
+}
+
+// loadVAPIDKeys prefers the key from the environment and falls back to a generated key file
+func loadVAPIDKeys(cfg *config.WebPushConfig) *webpush.VAPIDKeys {
+	if cfg.PrivateKey != "" {
+		keys, err := webpush.ParseVAPIDPrivateKey(cfg.PrivateKey)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid WEBPUSH_VAPID_PRIVATE_KEY")
+		}
+		return keys
+	}
+
+	keys, err := webpush.LoadOrCreateVAPIDKeys(cfg.KeyFile)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", cfg.KeyFile).Msg("Failed to load VAPID keys")
 	}
+	return keys
 }
@@ -28,6 +28,14 @@ func BuildFuncMap() template.FuncMap {
 			// mins := minutes % 60
 			return "{{ . }}h {{ . }}m"
 		},
+		// formatSeconds writes a duration in seconds as hours and minutes, or minutes and seconds
+		"formatSeconds": func(seconds float64) string {
+			total := int(seconds + 0.5)
+			if total >= 3600 {
+				return fmt.Sprintf("%dj %dm", total/3600, total%3600/60)
+			}
+			return fmt.Sprintf("%dm %ds", total/60, total%60)
+		},
 		"add": func(a, b int) int {
 			return a + b
 		},
@@ -3,7 +3,6 @@ package server
 import (
 	"net/http"
 	"tenangantri/internal/middleware"
-	"tenangantri/internal/websocket"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
@@ -16,10 +15,25 @@ func NewRouter(handlers *Handlers) *gin.Engine {
 	kioskHandler := handlers.KioskHandler
 	displayHandler := handlers.DisplayHandler
 	trackingHandler := handlers.TrackingHandler
-	hub := handlers.Hub
+	webhookHandler := handlers.WebhookHandler
+	realtimeHandler := handlers.RealtimeHandler
+	displayProfileHandler := handlers.DisplayProfileHandler
+	signageHandler := handlers.SignageHandler
+	deviceHandler := handlers.DeviceHandler
+	kioskProfileHandler := handlers.KioskProfileHandler
+	reportScheduleHandler := handlers.ReportScheduleHandler
+	staffPerformanceHandler := handlers.StaffPerformanceHandler
+	slaHandler := handlers.SLAHandler
+	queueAlertHandler := handlers.QueueAlertHandler
 
 	r := gin.New()
-	r.Use(gin.Recovery())
+	r.Use(gin.CustomRecovery(func(c *gin.Context, err any) {
+		// Left to net/http, which cuts the connection of a response aborted mid-stream
+		if err == http.ErrAbortHandler {
+			panic(err)
+		}
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
 	r.Use(middleware.LoggerMiddleware())
 
 	// Add custom template functions
@@ -39,6 +53,9 @@ func NewRouter(handlers *Handlers) *gin.Engine {
 	r.Static("/static", "./web/static")
 	r.Static("/templates", "./web/templates")
 
+	// Served from the root so the push service worker can control /track
+	r.StaticFile("/push-sw.js", "./web/static/js/push-sw.js")
+
 	// Public routes
 	r.GET("/", func(c *gin.Context) {
 		c.Redirect(http.StatusPermanentRedirect, "/kiosk")
@@ -54,8 +71,9 @@ func NewRouter(handlers *Handlers) *gin.Engine {
 		kiosk.GET("/", kioskHandler.ShowKiosk)
 		kiosk.POST("/ticket", kioskHandler.GenerateTicket)
 		kiosk.GET("/ticket/:number", kioskHandler.GetTicketStatus)
-		kiosk.GET("/ticket/:number/print", kioskHandler.PrintTicket)
 		kiosk.GET("/queue-info", kioskHandler.GetQueueInfo)
+		kiosk.GET("/p/:slug", kioskHandler.ShowProfileKiosk)
+		kiosk.POST("/p/:slug/ticket", kioskHandler.GenerateProfileTicket)
 	}
 
 	// Display routes (public)
@@ -66,6 +84,10 @@ func NewRouter(handlers *Handlers) *gin.Engine {
 		display.GET("/stats", displayHandler.GetQueueStats)
 		display.GET("/waiting", displayHandler.GetWaitingByCategory)
 		display.GET("/category/:id", displayHandler.ShowCategoryDisplay)
+		display.GET("/announcements/:ticket/:counter", displayHandler.GetAnnouncement)
+		display.GET("/p/:slug", displayProfileHandler.ShowScreen)
+		display.GET("/p/:slug/playlist", signageHandler.GetPlayback)
+		display.GET("/media/:file", signageHandler.ServeMedia)
 	}
 
 	// Tracking routes (public)
@@ -73,12 +95,22 @@ func NewRouter(handlers *Handlers) *gin.Engine {
 	{
 		track.GET("/", trackingHandler.ShowTrackingPage)
 		track.GET("/info/:ticket_number", trackingHandler.GetTrackingInfo)
+		track.GET("/push/public-key", trackingHandler.GetPushPublicKey)
+		track.POST("/push/subscribe", trackingHandler.SubscribePush)
+		track.POST("/push/unsubscribe", trackingHandler.UnsubscribePush)
+	}
+
+	// Device routes (public; heartbeats authenticate with the device token)
+	device := r.Group("/device")
+	{
+		device.GET("/pair", deviceHandler.ShowPair)
+		device.POST("/api/pair", deviceHandler.Pair)
+		device.POST("/api/heartbeat", deviceHandler.Heartbeat)
 	}
 
 	// WebSocket endpoint
-	r.GET("/ws", func(c *gin.Context) {
-		websocket.ServeWs(hub, c.Writer, c.Request)
-	})
+	r.GET("/ws", realtimeHandler.ServeWs)
+	r.GET("/events", realtimeHandler.ServeSSE)
 
 	// Protected routes
 	protected := r.Group("/")
@@ -89,10 +121,11 @@ func NewRouter(handlers *Handlers) *gin.Engine {
 		protected.GET("/api/profile", authHandler.GetProfile)
 		protected.PUT("/api/profile", authHandler.UpdateProfile)
 		protected.POST("/api/change-password", authHandler.ChangePassword)
+		protected.GET("/api/stream-token", realtimeHandler.IssueStreamToken)
 
 		// Staff routes
 		staff := protected.Group("/staff")
-		staff.Use(middleware.RoleMiddleware("staff", "admin"))
+		staff.Use(middleware.RoleMiddleware("staff", "supervisor", "admin"))
 		{
 			staff.GET("/dashboard", staffHandler.Dashboard)
 			staff.GET("/tickets", staffHandler.TicketsPage)
@@ -100,12 +133,17 @@ func NewRouter(handlers *Handlers) *gin.Engine {
 			staff.POST("/call-again", staffHandler.CallAgain)
 			staff.POST("/complete", staffHandler.CompleteTicket)
 			staff.POST("/no-show", staffHandler.MarkNoShow)
+			staff.POST("/arrived", staffHandler.MarkArrived)
 			staff.POST("/pause", staffHandler.PauseCounter)
 			staff.POST("/resume", staffHandler.ResumeCounter)
 			staff.GET("/queue-status", staffHandler.GetQueueStatus)
 			staff.GET("/current-ticket", staffHandler.GetCurrentTicket)
+			staff.GET("/api/my-stats", staffPerformanceHandler.MyStats)
+			staff.GET("/api/sla", slaHandler.StaffLive)
+			staff.GET("/api/alerts", middleware.RoleMiddleware("supervisor", "admin"), queueAlertHandler.ListAlerts)
 			staff.POST("/transfer/:id", staffHandler.TransferTicket)
 			staff.GET("/api/tickets/:id", staffHandler.GetTicketDetail)
+			staff.GET("/api/tickets/:id/calls", staffHandler.GetTicketCalls)
 			staff.POST("/api/tickets/:id/cancel", staffHandler.CancelTicket)
 			staff.POST("/api/tickets/reset-yesterday", staffHandler.ResetYesterdayTickets)
 		}
@@ -117,6 +155,7 @@ func NewRouter(handlers *Handlers) *gin.Engine {
 			// Dashboard
 			admin.GET("/dashboard", adminHandler.Dashboard)
 			admin.GET("/api/stats", adminHandler.GetStats)
+			admin.GET("/api/sla/live", slaHandler.Live)
 
 			// Users
 			admin.GET("/users", adminHandler.ListUsers)
@@ -156,6 +195,76 @@ func NewRouter(handlers *Handlers) *gin.Engine {
 			admin.GET("/api/reports/data", adminHandler.GetReportData)
 			admin.GET("/api/export/tickets", adminHandler.ExportTickets)
 			admin.GET("/api/export/pdf", adminHandler.ExportPDF)
+			admin.GET("/api/export/xlsx", adminHandler.ExportXLSX)
+			admin.GET("/staff-performance", staffPerformanceHandler.ShowReport)
+			admin.GET("/api/reports/staff", staffPerformanceHandler.GetReport)
+
+			// Report schedules
+			admin.GET("/report-schedules", reportScheduleHandler.ListSchedules)
+			admin.GET("/api/report-schedules/:id", reportScheduleHandler.GetSchedule)
+			admin.POST("/api/report-schedules", reportScheduleHandler.CreateSchedule)
+			admin.PUT("/api/report-schedules/:id", reportScheduleHandler.UpdateSchedule)
+			admin.DELETE("/api/report-schedules/:id", reportScheduleHandler.DeleteSchedule)
+			admin.POST("/api/report-schedules/:id/run", reportScheduleHandler.RunSchedule)
+			admin.GET("/api/report-runs", reportScheduleHandler.ListRuns)
+			admin.GET("/api/report-runs/:id/download", reportScheduleHandler.DownloadRun)
+			admin.POST("/api/report-runs/:id/retry", reportScheduleHandler.RetryRun)
+
+			// Queue alerts
+			admin.GET("/alerts", queueAlertHandler.ListRules)
+			admin.GET("/api/alert-rules/:id", queueAlertHandler.GetRule)
+			admin.POST("/api/alert-rules", queueAlertHandler.CreateRule)
+			admin.PUT("/api/alert-rules/:id", queueAlertHandler.UpdateRule)
+			admin.DELETE("/api/alert-rules/:id", queueAlertHandler.DeleteRule)
+			admin.GET("/api/queue-alerts", queueAlertHandler.ListAlerts)
+
+			// Webhooks
+			admin.GET("/webhooks", webhookHandler.ListWebhooks)
+			admin.GET("/api/webhooks/:id", webhookHandler.GetWebhook)
+			admin.POST("/api/webhooks", webhookHandler.CreateWebhook)
+			admin.PUT("/api/webhooks/:id", webhookHandler.UpdateWebhook)
+			admin.DELETE("/api/webhooks/:id", webhookHandler.DeleteWebhook)
+			admin.GET("/api/webhook-deliveries", webhookHandler.ListDeliveries)
+			admin.POST("/api/webhook-deliveries/:id/redeliver", webhookHandler.RedeliverDelivery)
+
+			// Display profiles
+			admin.GET("/display-profiles", displayProfileHandler.ListProfiles)
+			admin.GET("/api/display-profiles/:id", displayProfileHandler.GetProfile)
+			admin.POST("/api/display-profiles", displayProfileHandler.CreateProfile)
+			admin.PUT("/api/display-profiles/:id", displayProfileHandler.UpdateProfile)
+			admin.DELETE("/api/display-profiles/:id", displayProfileHandler.DeleteProfile)
+
+			// Kiosk profiles
+			admin.GET("/kiosk-profiles", kioskProfileHandler.ListProfiles)
+			admin.GET("/api/kiosk-profiles/:id", kioskProfileHandler.GetProfile)
+			admin.POST("/api/kiosk-profiles", kioskProfileHandler.CreateProfile)
+			admin.PUT("/api/kiosk-profiles/:id", kioskProfileHandler.UpdateProfile)
+			admin.DELETE("/api/kiosk-profiles/:id", kioskProfileHandler.DeleteProfile)
+
+			// Signage
+			admin.GET("/signage", signageHandler.ShowSignage)
+			admin.POST("/api/signage/media", signageHandler.UploadMedia)
+			admin.DELETE("/api/signage/media/:id", signageHandler.DeleteMedia)
+			admin.GET("/api/signage/playlists/:id", signageHandler.GetPlaylist)
+			admin.POST("/api/signage/playlists", signageHandler.CreatePlaylist)
+			admin.PUT("/api/signage/playlists/:id", signageHandler.UpdatePlaylist)
+			admin.DELETE("/api/signage/playlists/:id", signageHandler.DeletePlaylist)
+			admin.GET("/api/signage/schedules/:id", signageHandler.GetSchedule)
+			admin.POST("/api/signage/schedules", signageHandler.CreateSchedule)
+			admin.PUT("/api/signage/schedules/:id", signageHandler.UpdateSchedule)
+			admin.DELETE("/api/signage/schedules/:id", signageHandler.DeleteSchedule)
+
+			// Devices
+			admin.GET("/devices", deviceHandler.ListDevices)
+			admin.GET("/api/devices/:id", deviceHandler.GetDevice)
+			admin.POST("/api/devices", deviceHandler.CreateDevice)
+			admin.PUT("/api/devices/:id", deviceHandler.UpdateDevice)
+			admin.DELETE("/api/devices/:id", deviceHandler.DeleteDevice)
+			admin.POST("/api/devices/:id/pairing-code", deviceHandler.NewPairingCode)
+			admin.POST("/api/devices/:id/commands", deviceHandler.SendCommand)
+
+			// Realtime
+			admin.GET("/api/realtime/metrics", realtimeHandler.Metrics)
 		}
 	}
 
@@ -0,0 +1,226 @@
+package server
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/event"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+	"tenangantri/internal/service"
+	"tenangantri/internal/websocket"
+)
+
+// ticketEvents lists every event that carries a ticket
+var ticketEvents = []string{
+	event.NameTicketIssued,
+	event.NameTicketCalled,
+	event.NameTicketRecalled,
+	event.NameTicketTransferred,
+	event.NameTicketCompleted,
+	event.NameTicketNoShow,
+	event.NameTicketCancelled,
+	event.NameTicketRequeued,
+}
+
+// announcedEvents are spoken on the display boards; recalls are announced again
+var announcedEvents = map[string]bool{
+	event.NameTicketCalled:      true,
+	event.NameTicketRecalled:    true,
+	event.NameTicketTransferred: true,
+}
+
+// subscribeConsumers wires every consumer of domain events to the bus.
+// The stats cache is subscribed first so the hub broadcasts fresh numbers.
+func subscribeConsumers(bus *event.Bus, hub *websocket.Hub, statsCache *service.StatsCache,
+	counterRepo repository.CounterRepository, announcementService *service.AnnouncementService,
+	pushService *service.PushService, webhookService *service.WebhookService) {
+	subscribeStatsCache(bus, statsCache)
+	subscribeHub(bus, hub, statsCache, counterRepo, announcementService)
+	subscribePush(bus, pushService)
+	subscribeWebhooks(bus, webhookService)
+	subscribeAudit(bus)
+}
+
+// subscribeStatsCache drops cached stats whenever the queue or counters change
+func subscribeStatsCache(bus *event.Bus, statsCache *service.StatsCache) {
+	names := append([]string{event.NameTicketsReset, event.NameCounterStatusChanged, event.NameCounterChanged}, ticketEvents...)
+	bus.Subscribe(func(ctx context.Context, e event.Event) {
+		statsCache.Invalidate()
+	}, names...)
+}
+
+// subscribeHub translates domain events into the WebSocket messages the pages already
+// consume and routes each one to the topics of what it touches
+func subscribeHub(bus *event.Bus, hub *websocket.Hub, statsCache *service.StatsCache,
+	counterRepo repository.CounterRepository, announcementService *service.AnnouncementService) {
+	publishStats := func(ctx context.Context) {
+		stats, err := statsCache.Get(ctx)
+		if err != nil {
+			log.Error().Err(err).Str("layer", "server").Str("func", "subscribeHub").Msg("Failed to load stats for broadcast")
+			return
+		}
+		hub.Publish([]string{websocket.TopicStats}, dto.RealtimeStatsUpdate, stats)
+	}
+
+	bus.Subscribe(func(ctx context.Context, e event.Event) {
+		ticket := event.TicketOf(e)
+		if ticket == nil {
+			return
+		}
+		payload := dto.NewTicketEvent(ticket)
+		if announcedEvents[e.Name()] && ticket.CounterID.Valid {
+			payload.Announcement = announcementURL(ctx, announcementService, counterRepo, ticket)
+		}
+		hub.PublishRedacted(ticketTopics(ctx, ticket), dto.RealtimeTicketUpdate, payload, payload.Public())
+		publishStats(ctx)
+	}, ticketEvents...)
+
+	event.On(bus, func(ctx context.Context, e event.TicketsReset) {
+		hub.Publish([]string{websocket.TopicDisplayAll}, dto.RealtimeTicketsReset, dto.TicketsResetEvent{Count: e.Count})
+		publishStats(ctx)
+	})
+
+	event.On(bus, func(ctx context.Context, e event.CounterStatusChanged) {
+		counter, err := counterRepo.GetByID(ctx, e.CounterID)
+		if err != nil || counter == nil {
+			log.Error().Err(err).Str("layer", "server").Int("counter_id", e.CounterID).Msg("Failed to load counter for broadcast")
+			return
+		}
+		hub.Publish([]string{websocket.TopicDisplayAll, websocket.CounterTopic(e.CounterID)}, dto.RealtimeCounterUpdate, dto.NewCounterEvent(counter))
+	})
+
+	event.On(bus, func(ctx context.Context, e event.CounterChanged) {
+		payload := dto.CounterEvent{ID: e.CounterID}
+		if e.Counter != nil {
+			payload = dto.NewCounterEvent(e.Counter)
+		}
+		hub.Publish([]string{websocket.TopicDisplayAll, websocket.CounterTopic(e.CounterID)}, "counter_"+e.Action, payload)
+		publishStats(ctx)
+	})
+
+	event.On(bus, func(ctx context.Context, e event.CategoryChanged) {
+		payload := dto.CategoryEvent{ID: e.CategoryID}
+		if e.Category != nil {
+			payload = dto.NewCategoryEvent(e.Category)
+		}
+		hub.Publish([]string{websocket.TopicDisplayAll, websocket.CategoryTopic(e.CategoryID)}, "category_"+e.Action, payload)
+	})
+
+	event.On(bus, func(ctx context.Context, e event.DisplayProfileChanged) {
+		topics := []string{websocket.ScreenTopic(e.Slug)}
+		if e.PreviousSlug != "" {
+			topics = append(topics, websocket.ScreenTopic(e.PreviousSlug))
+		}
+		payload := dto.DisplayReloadEvent{Slug: e.Slug}
+		if e.Action == event.ActionDeleted {
+			payload.Slug = ""
+		}
+		hub.Publish(topics, dto.RealtimeDisplayReload, payload)
+	})
+
+	event.On(bus, func(ctx context.Context, e event.KioskProfileChanged) {
+		topics := []string{websocket.KioskTopic(e.Slug)}
+		if e.PreviousSlug != "" {
+			topics = append(topics, websocket.KioskTopic(e.PreviousSlug))
+		}
+		payload := dto.KioskReloadEvent{Slug: e.Slug}
+		if e.Action == event.ActionDeleted {
+			payload.Slug = ""
+		}
+		hub.Publish(topics, dto.RealtimeKioskReload, payload)
+	})
+
+	event.On(bus, func(ctx context.Context, e event.DeviceCommand) {
+		hub.Publish([]string{websocket.DeviceTopic(e.DeviceID)}, dto.RealtimeDeviceCommand, dto.DeviceCommandEvent{Action: e.Action, URL: e.URL})
+	})
+
+	event.On(bus, func(ctx context.Context, e event.TicketSLABreached) {
+		var topics []string
+		if e.Ticket.CategoryID.Valid {
+			topics = append(topics, websocket.CategoryTopic(int(e.Ticket.CategoryID.Int64)))
+		}
+		if e.Ticket.CounterID.Valid {
+			topics = append(topics, websocket.CounterTopic(int(e.Ticket.CounterID.Int64)))
+		}
+		payload := dto.NewSLABreachEvent(e.Ticket, e.Kind, e.TargetSeconds, e.ElapsedSeconds)
+		hub.PublishRedacted(topics, dto.RealtimeSLABreach, payload, nil)
+	})
+
+	event.On(bus, func(ctx context.Context, e event.QueueAlertRaised) {
+		hub.PublishRedacted([]string{websocket.TopicAlerts}, dto.RealtimeQueueAlert, dto.NewQueueAlertEvent(e.Alert), nil)
+	})
+
+	event.On(bus, func(ctx context.Context, e event.DeviceSilent) {
+		hub.Publish([]string{websocket.TopicAdminAll}, dto.RealtimeDeviceAlert, dto.NewDeviceAlertEvent(e.Device, model.DeviceStatusOffline))
+	})
+
+	event.On(bus, func(ctx context.Context, e event.DeviceRecovered) {
+		hub.Publish([]string{websocket.TopicAdminAll}, dto.RealtimeDeviceAlert, dto.NewDeviceAlertEvent(e.Device, model.DeviceStatusOnline))
+	})
+}
+
+// announcementURL points displays at the audio for a ticket's call to its counter
+func announcementURL(ctx context.Context, announcementService *service.AnnouncementService,
+	counterRepo repository.CounterRepository, ticket *model.Ticket) string {
+	counter, err := counterRepo.GetByID(ctx, int(ticket.CounterID.Int64))
+	if err != nil || counter == nil {
+		log.Error().Err(err).Str("layer", "server").Int("ticket_id", ticket.ID).Msg("Failed to load counter for announcement")
+		return ""
+	}
+	return announcementService.URL(ticket.TicketNumber, counter.Number)
+}
+
+// ticketTopics lists everyone who shows this ticket: public boards, its category and
+// counter, anyone tracking it and the staff member who changed it
+func ticketTopics(ctx context.Context, ticket *model.Ticket) []string {
+	topics := []string{websocket.TopicDisplayAll, websocket.TicketTopic(ticket.TicketNumber)}
+	if ticket.CategoryID.Valid {
+		topics = append(topics, websocket.CategoryTopic(int(ticket.CategoryID.Int64)))
+	}
+	if ticket.CounterID.Valid {
+		topics = append(topics, websocket.CounterTopic(int(ticket.CounterID.Int64)))
+	}
+	if actorID := event.ActorFromContext(ctx); actorID != 0 {
+		topics = append(topics, websocket.StaffTopic(actorID))
+	}
+	return topics
+}
+
+// subscribePush notifies tracked tickets when they are called to a counter
+func subscribePush(bus *event.Bus, pushService *service.PushService) {
+	bus.Subscribe(func(ctx context.Context, e event.Event) {
+		go pushService.NotifyTicketCalled(ctx, event.TicketOf(e))
+	}, event.NameTicketCalled, event.NameTicketRecalled, event.NameTicketTransferred)
+}
+
+// subscribeWebhooks wakes the dispatcher so outbox rows written by the ticket and
+// SLA breach and queue alert triggers go out without waiting for the next poll
+func subscribeWebhooks(bus *event.Bus, webhookService *service.WebhookService) {
+	names := append([]string{event.NameTicketSLABreached}, ticketEvents...)
+	bus.Subscribe(func(ctx context.Context, e event.Event) {
+		webhookService.Wake()
+	}, names...)
+
+	event.On(bus, func(ctx context.Context, e event.QueueAlertRaised) {
+		if e.Alert.NotifyWebhook {
+			webhookService.Wake()
+		}
+	})
+}
+
+// subscribeAudit records every domain event together with the user who caused it
+func subscribeAudit(bus *event.Bus) {
+	bus.SubscribeAll(func(ctx context.Context, e event.Event) {
+		entry := log.Info().Str("component", "audit").Str("event", e.Name())
+		if actorID := event.ActorFromContext(ctx); actorID != 0 {
+			entry = entry.Int("actor_id", actorID)
+		}
+		if ticket := event.TicketOf(e); ticket != nil {
+			entry = entry.Int("ticket_id", ticket.ID).Str("ticket_number", ticket.TicketNumber)
+		}
+		entry.Msg("Domain event")
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"tenangantri/internal/dto"
+	"tenangantri/internal/event"
 	"tenangantri/internal/model"
 	"tenangantri/internal/repository"
 )
@@ -42,6 +43,7 @@ type AdminService struct {
 	categoryRepo        repository.CategoryRepository
 	ticketRepo          repository.TicketRepository
 	statsRepo           repository.StatsRepository
+	events              event.Publisher
 }
 
 func NewAdminService(userRepo repository.UserRepository,
@@ -50,7 +52,8 @@ func NewAdminService(userRepo repository.UserRepository,
 	counterCategoryRepo repository.CounterCategoryRepository,
 	categoryRepo repository.CategoryRepository,
 	ticketRepo repository.TicketRepository,
-	statsRepo repository.StatsRepository) *AdminService {
+	statsRepo repository.StatsRepository,
+	events event.Publisher) *AdminService {
 	return &AdminService{
 		userRepo:            userRepo,
 		userCounterRepo:     userCounterRepo,
@@ -59,6 +62,7 @@ func NewAdminService(userRepo repository.UserRepository,
 		categoryRepo:        categoryRepo,
 		ticketRepo:          ticketRepo,
 		statsRepo:           statsRepo,
+		events:              events,
 	}
 }
 
@@ -198,9 +202,19 @@ func (s *AdminService) CreateCategory(ctx context.Context, req *dto.CreateCatego
 		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
 		Icon:        sql.NullString{String: req.Icon, Valid: req.Icon != ""},
 		IsActive:    true,
+
+		SLAWaitMinutes:    req.SLAWaitMinutes,
+		SLAServiceMinutes: req.SLAServiceMinutes,
+	}
+
+	created, err := s.categoryRepo.Create(ctx, category)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.categoryRepo.Create(ctx, category)
+	s.events.Publish(ctx, event.CategoryChanged{Action: event.ActionCreated, CategoryID: created.ID, Category: created})
+
+	return created, nil
 }
 
 // UpdateCategory updates a category
@@ -216,8 +230,10 @@ func (s *AdminService) UpdateCategory(ctx context.Context, id int, req *dto.Crea
 	category.ColorCode = req.ColorCode
 	category.Description = sql.NullString{String: req.Description, Valid: req.Description != ""}
 	category.Icon = sql.NullString{String: req.Icon, Valid: req.Icon != ""}
+	category.SLAWaitMinutes = req.SLAWaitMinutes
+	category.SLAServiceMinutes = req.SLAServiceMinutes
 
-	return s.categoryRepo.Update(ctx, category)
+	return s.updateCategory(ctx, category)
 }
 
 // UpdateCategoryStatus updates only the status of a category
@@ -228,14 +244,31 @@ func (s *AdminService) UpdateCategoryStatus(ctx context.Context, id int, isActiv
 	}
 
 	category.IsActive = isActive
-	return s.categoryRepo.Update(ctx, category)
+	return s.updateCategory(ctx, category)
+}
+
+func (s *AdminService) updateCategory(ctx context.Context, category *model.Category) (*model.Category, error) {
+	updated, err := s.categoryRepo.Update(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(ctx, event.CategoryChanged{Action: event.ActionUpdated, CategoryID: updated.ID, Category: updated})
+
+	return updated, nil
 }
 
 // DeleteCategory deletes a category
 func (s *AdminService) DeleteCategory(ctx context.Context, id int) error {
 	// Delete counter-category associations first
 	_ = s.counterCategoryRepo.DeleteByCategoryID(ctx, id)
-	return s.categoryRepo.Delete(ctx, id)
+	if err := s.categoryRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.events.Publish(ctx, event.CategoryChanged{Action: event.ActionDeleted, CategoryID: id})
+
+	return nil
 }
 
 // GetCategory gets a category by ID
@@ -269,6 +302,8 @@ func (s *AdminService) CreateCounter(ctx context.Context, req *dto.CreateCounter
 		_, _ = s.counterCategoryRepo.Create(ctx, createdCounter.ID, categoryID)
 	}
 
+	s.events.Publish(ctx, event.CounterChanged{Action: event.ActionCreated, CounterID: createdCounter.ID, Counter: createdCounter})
+
 	return createdCounter, nil
 }
 
@@ -294,6 +329,8 @@ func (s *AdminService) UpdateCounter(ctx context.Context, id int, req *dto.Creat
 		_, _ = s.counterCategoryRepo.Create(ctx, id, categoryID)
 	}
 
+	s.events.Publish(ctx, event.CounterChanged{Action: event.ActionUpdated, CounterID: id, Counter: updatedCounter})
+
 	return updatedCounter, nil
 }
 
@@ -303,7 +340,13 @@ func (s *AdminService) DeleteCounter(ctx context.Context, id int) error {
 	_ = s.counterCategoryRepo.DeleteByCounterID(ctx, id)
 	// Delete user-counter associations
 	_ = s.userCounterRepo.DeleteByCounterID(ctx, id)
-	return s.counterRepo.Delete(ctx, id)
+	if err := s.counterRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.events.Publish(ctx, event.CounterChanged{Action: event.ActionDeleted, CounterID: id})
+
+	return nil
 }
 
 // ListCounters lists all counters
@@ -364,7 +407,14 @@ func (s *AdminService) UpdateCounterStatus(ctx context.Context, id int, status s
 	}
 
 	counter.Status = status
-	return s.counterRepo.Update(ctx, counter)
+	updated, err := s.counterRepo.Update(ctx, counter)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(ctx, event.CounterStatusChanged{CounterID: id, Status: status})
+
+	return updated, nil
 }
 
 // GetCounter gets a counter by ID
@@ -410,16 +460,40 @@ func (s *AdminService) CreateTicket(ctx context.Context, req *dto.CreateTicketRe
 		return nil, err
 	}
 
-	return s.ticketRepo.GetWithDetails(ctx, createdTicket.ID)
+	ticketWithDetails, err := s.ticketRepo.GetWithDetails(ctx, createdTicket.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(ctx, event.TicketIssued{Ticket: ticketWithDetails})
+
+	return ticketWithDetails, nil
 }
 
 // UpdateTicketStatus updates ticket status
 func (s *AdminService) UpdateTicketStatus(ctx context.Context, id int, req *dto.UpdateTicketStatusRequest) (*model.Ticket, error) {
-	err := s.ticketRepo.UpdateStatus(ctx, id, req.Status)
+	finished := 0
+	var err error
+	if req.Status == "completed" || req.Status == "no_show" {
+		// Credited to the staff member serving the ticket, not the admin
+		finished, err = s.ticketRepo.Finish(ctx, id, req.Status, 0)
+	}
+	// A ticket nobody is serving has no one to credit
+	if err == nil && finished == 0 {
+		err = s.ticketRepo.UpdateStatus(ctx, id, req.Status)
+	}
 	if err != nil {
 		return nil, err
 	}
-	return s.ticketRepo.GetWithDetails(ctx, id)
+
+	ticket, err := s.ticketRepo.GetWithDetails(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(ctx, ticketStatusEvent(ticket))
+
+	return ticket, nil
 }
 
 // CancelTicket cancels a ticket
@@ -428,5 +502,34 @@ func (s *AdminService) CancelTicket(ctx context.Context, id int) (*model.Ticket,
 	if err != nil {
 		return nil, err
 	}
-	return s.ticketRepo.GetWithDetails(ctx, id)
+
+	ticket, err := s.ticketRepo.GetWithDetails(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(ctx, event.TicketCancelled{Ticket: ticket})
+
+	return ticket, nil
+}
+
+// ticketStatusEvent maps an admin status override to the matching domain event
+func ticketStatusEvent(ticket *model.Ticket) event.Event {
+	counterID := 0
+	if ticket.CounterID.Valid {
+		counterID = int(ticket.CounterID.Int64)
+	}
+
+	switch ticket.Status {
+	case "serving":
+		return event.TicketCalled{Ticket: ticket, CounterID: counterID}
+	case "completed":
+		return event.TicketCompleted{Ticket: ticket, CounterID: counterID}
+	case "no_show":
+		return event.TicketNoShow{Ticket: ticket, CounterID: counterID}
+	case "cancelled":
+		return event.TicketCancelled{Ticket: ticket}
+	default:
+		return event.TicketRequeued{Ticket: ticket}
+	}
 }
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/audio"
+	"tenangantri/internal/config"
+)
+
+// announcementCacheSize bounds how many built announcements are kept; a called
+// ticket is usually fetched by every display within a few seconds
+const announcementCacheSize = 64
+
+// AnnouncementService builds the spoken "ticket A012, please go to counter 3"
+// announcement from recorded clips
+type AnnouncementService struct {
+	library *audio.Library
+	cfg     *config.AnnounceConfig
+
+	mu    sync.Mutex
+	cache map[string][]byte
+}
+
+func NewAnnouncementService(library *audio.Library, cfg *config.AnnounceConfig) *AnnouncementService {
+	return &AnnouncementService{
+		library: library,
+		cfg:     cfg,
+		cache:   make(map[string][]byte),
+	}
+}
+
+// Languages returns the configured languages that have a clip pack installed
+func (s *AnnouncementService) Languages() []string {
+	var langs []string
+	for _, lang := range s.cfg.Languages {
+		if s.library.HasLanguage(lang) {
+			langs = append(langs, lang)
+		}
+	}
+	return langs
+}
+
+// URL returns where displays fetch the announcement, or "" when no pack is installed
+func (s *AnnouncementService) URL(ticketNumber, counterNumber string) string {
+	if ticketNumber == "" || counterNumber == "" || len(s.Languages()) == 0 {
+		return ""
+	}
+	return "/display/announcements/" + url.PathEscape(ticketNumber) + "/" + url.PathEscape(counterNumber)
+}
+
+// Build returns the announcement as one WAV file, in each of langs in turn or in
+// every installed language when langs is empty
+func (s *AnnouncementService) Build(ticketNumber, counterNumber string, langs []string) ([]byte, error) {
+	if !isAnnounceable(ticketNumber) || !isAnnounceable(counterNumber) {
+		return nil, fmt.Errorf("invalid ticket or counter number")
+	}
+
+	available := s.Languages()
+	if len(langs) == 0 {
+		langs = available
+	} else {
+		langs = intersect(langs, available)
+	}
+	if len(langs) == 0 {
+		return nil, fmt.Errorf("no announcement clips installed")
+	}
+
+	key := strings.ToUpper(ticketNumber) + "|" + strings.ToUpper(counterNumber) + "|" + strings.Join(langs, ",")
+	s.mu.Lock()
+	wav, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok {
+		return wav, nil
+	}
+
+	var clips []*audio.Clip
+	for _, lang := range langs {
+		// A longer pause separates one language from the next
+		if len(clips) > 0 {
+			format := clips[0].Format
+			clips = append(clips, &audio.Clip{Format: format, Data: audio.Silence(format, 3*s.cfg.Gap)})
+		}
+		for _, name := range s.library.Script(lang, ticketNumber, counterNumber) {
+			clip, err := s.library.Clip(lang, name)
+			if err != nil {
+				log.Error().Err(err).Str("layer", "service").Str("func", "Build").Str("lang", lang).Msg("Failed to load announcement clip")
+				return nil, fmt.Errorf("announcement clips incomplete")
+			}
+			clips = append(clips, clip)
+		}
+	}
+
+	wav, err := audio.Concat(clips, s.cfg.Gap)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "Build").Msg("Failed to join announcement clips")
+		return nil, fmt.Errorf("announcement clips incomplete")
+	}
+
+	s.mu.Lock()
+	if len(s.cache) >= announcementCacheSize {
+		s.cache = make(map[string][]byte)
+	}
+	s.cache[key] = wav
+	s.mu.Unlock()
+	return wav, nil
+}
+
+// isAnnounceable accepts the short letter and digit codes used for tickets and counters
+func isAnnounceable(text string) bool {
+	if text == "" || len(text) > 16 {
+		return false
+	}
+	for _, r := range text {
+		if !(r >= '0' && r <= '9') && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func intersect(want, have []string) []string {
+	var out []string
+	for _, w := range want {
+		for _, h := range have {
+			if w == h {
+				out = append(out, w)
+				break
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/audio"
+	"tenangantri/internal/config"
+)
+
+// recordPack writes one-frame clips for every name the test announcements need
+func recordPack(t *testing.T, dir, lang string) {
+	format := audio.Format{AudioFormat: 1, Channels: 1, SampleRate: 8000, BitsPerSample: 8}
+	names := []string{"phrases/ticket", "phrases/counter", "letters/a", "digits/0", "digits/1", "digits/2", "digits/3"}
+	for _, name := range names {
+		path := filepath.Join(dir, lang, filepath.FromSlash(name)+".wav")
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, audio.Encode(format, []byte{1}), 0o644))
+	}
+}
+
+func TestAnnouncementService_Build(t *testing.T) {
+	dir := t.TempDir()
+	recordPack(t, dir, "id")
+	recordPack(t, dir, "en")
+	svc := NewAnnouncementService(audio.NewLibrary(dir), &config.AnnounceConfig{ClipsDir: dir, Languages: []string{"id", "en", "jv"}})
+
+	assert.Equal(t, []string{"id", "en"}, svc.Languages())
+	assert.Equal(t, "/display/announcements/A012/3", svc.URL("A012", "3"))
+
+	wav, err := svc.Build("A012", "3", nil)
+	require.NoError(t, err)
+	clip, err := audio.Decode(wav)
+	require.NoError(t, err)
+	// Seven clips of one sample per language
+	assert.Len(t, clip.Data, 14)
+
+	wav, err = svc.Build("A012", "3", []string{"en"})
+	require.NoError(t, err)
+	clip, err = audio.Decode(wav)
+	require.NoError(t, err)
+	assert.Len(t, clip.Data, 7)
+
+	_, err = svc.Build("A012", "3", []string{"jv"})
+	assert.Error(t, err)
+	_, err = svc.Build("../etc", "3", nil)
+	assert.Error(t, err)
+	// No recording of the letter B
+	_, err = svc.Build("B012", "3", nil)
+	assert.Error(t, err)
+}
+
+func TestAnnouncementService_NoPacks(t *testing.T) {
+	dir := t.TempDir()
+	svc := NewAnnouncementService(audio.NewLibrary(dir), &config.AnnounceConfig{ClipsDir: dir, Languages: []string{"id"}})
+
+	assert.Empty(t, svc.URL("A012", "3"))
+	_, err := svc.Build("A012", "3", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,414 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/event"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+)
+
+// pairingAlphabet leaves out characters that are easily misread on a screen
+const (
+	pairingAlphabet   = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+	pairingCodeLength = 6
+)
+
+// DeviceService pairs kiosks and displays, records their heartbeats, relays remote
+// actions and raises an alert when a paired device goes silent during opening hours
+type DeviceService struct {
+	deviceRepo       repository.DeviceRepository
+	profileRepo      repository.DisplayProfileRepository
+	kioskProfileRepo repository.KioskProfileRepository
+	events           event.Publisher
+	cfg              *config.DeviceConfig
+
+	// Parsed opening hours; an empty start means alerts are raised at any time
+	openingDays  []int
+	openingStart string
+	openingEnd   string
+}
+
+func NewDeviceService(
+	deviceRepo repository.DeviceRepository,
+	profileRepo repository.DisplayProfileRepository,
+	kioskProfileRepo repository.KioskProfileRepository,
+	events event.Publisher,
+	cfg *config.DeviceConfig) *DeviceService {
+	s := &DeviceService{
+		deviceRepo:       deviceRepo,
+		profileRepo:      profileRepo,
+		kioskProfileRepo: kioskProfileRepo,
+		events:           events,
+		cfg:              cfg,
+	}
+
+	start, end, ok := strings.Cut(cfg.OpeningHours, "-")
+	_, startErr := time.Parse("15:04", strings.TrimSpace(start))
+	_, endErr := time.Parse("15:04", strings.TrimSpace(end))
+	if ok && startErr == nil && endErr == nil {
+		s.openingStart, s.openingEnd = strings.TrimSpace(start), strings.TrimSpace(end)
+	} else if cfg.OpeningHours != "" {
+		log.Warn().Str("value", cfg.OpeningHours).Msg("Invalid DEVICE_OPENING_HOURS; silent devices are reported at any time")
+	}
+	for _, value := range cfg.OpeningDays {
+		day, err := strconv.Atoi(value)
+		if err != nil || day < 0 || day > 6 {
+			log.Warn().Str("value", value).Msg("Ignoring invalid day in DEVICE_OPENING_DAYS")
+			continue
+		}
+		s.openingDays = append(s.openingDays, day)
+	}
+	return s
+}
+
+// ListDevices returns every device with its status at now
+func (s *DeviceService) ListDevices(ctx context.Context, now time.Time) ([]model.Device, error) {
+	devices, err := s.deviceRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range devices {
+		devices[i].Status = s.status(&devices[i], now)
+	}
+	return devices, nil
+}
+
+// GetDevice returns a device by ID
+func (s *DeviceService) GetDevice(ctx context.Context, id int) (*model.Device, error) {
+	device, err := s.deviceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if device == nil {
+		return nil, fmt.Errorf("device not found")
+	}
+	device.Status = s.status(device, time.Now())
+	return device, nil
+}
+
+// Authenticate returns the paired device a device token belongs to, or nil when the
+// token is empty or not known
+func (s *DeviceService) Authenticate(ctx context.Context, token string) (*model.Device, error) {
+	if token == "" {
+		return nil, nil
+	}
+	return s.deviceRepo.GetByTokenHash(ctx, hashDeviceToken(token))
+}
+
+// ListProfiles returns the display profiles a display device can show
+func (s *DeviceService) ListProfiles(ctx context.Context) ([]model.DisplayProfile, error) {
+	return s.profileRepo.List(ctx)
+}
+
+// ListKioskProfiles returns the kiosk profiles a kiosk can be assigned
+func (s *DeviceService) ListKioskProfiles(ctx context.Context) ([]model.KioskProfile, error) {
+	return s.kioskProfileRepo.List(ctx)
+}
+
+// CreateDevice registers a device and issues its first pairing code
+func (s *DeviceService) CreateDevice(ctx context.Context, req *dto.DeviceRequest) (*model.Device, error) {
+	device := &model.Device{}
+	if err := s.apply(ctx, device, req); err != nil {
+		return nil, err
+	}
+
+	code, err := generatePairingCode()
+	if err != nil {
+		return nil, err
+	}
+	device.PairingCode = sql.NullString{String: code, Valid: true}
+	device.PairingExpiresAt = sql.NullTime{Time: time.Now().Add(s.cfg.PairingTTL), Valid: true}
+
+	created, err := s.deviceRepo.Create(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetDevice(ctx, created.ID)
+}
+
+// UpdateDevice renames a device or changes what it shows. A paired device is sent to
+// its new page straight away.
+func (s *DeviceService) UpdateDevice(ctx context.Context, id int, req *dto.DeviceRequest) (*model.Device, error) {
+	device, err := s.GetDevice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previousURL := device.HomeURL()
+
+	if err := s.apply(ctx, device, req); err != nil {
+		return nil, err
+	}
+	if err := s.deviceRepo.Update(ctx, device); err != nil {
+		return nil, err
+	}
+
+	if device.IsPaired() && device.HomeURL() != previousURL {
+		s.events.Publish(ctx, event.DeviceCommand{DeviceID: id, Action: model.DeviceActionNavigate, URL: device.HomeURL()})
+	}
+	return device, nil
+}
+
+// DeleteDevice deletes a device; its token stops working
+func (s *DeviceService) DeleteDevice(ctx context.Context, id int) error {
+	return s.deviceRepo.Delete(ctx, id)
+}
+
+// apply validates a request and copies it onto device, resolving the profile slugs
+func (s *DeviceService) apply(ctx context.Context, device *model.Device, req *dto.DeviceRequest) error {
+	device.Name = strings.TrimSpace(req.Name)
+	if device.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	device.Kind = req.Kind
+	device.DisplayProfileID = sql.NullInt64{}
+	device.ProfileSlug = sql.NullString{}
+	device.KioskProfileID = sql.NullInt64{}
+	device.KioskProfileSlug = sql.NullString{}
+
+	if req.Kind == model.DeviceKindDisplay && req.DisplayProfileID != nil && *req.DisplayProfileID > 0 {
+		profile, err := s.profileRepo.GetByID(ctx, *req.DisplayProfileID)
+		if err != nil {
+			return err
+		}
+		if profile == nil {
+			return fmt.Errorf("display profile not found")
+		}
+		device.DisplayProfileID = sql.NullInt64{Int64: int64(profile.ID), Valid: true}
+		device.ProfileSlug = sql.NullString{String: profile.Slug, Valid: true}
+	}
+	if req.Kind == model.DeviceKindKiosk && req.KioskProfileID != nil && *req.KioskProfileID > 0 {
+		profile, err := s.kioskProfileRepo.GetByID(ctx, *req.KioskProfileID)
+		if err != nil {
+			return err
+		}
+		if profile == nil {
+			return fmt.Errorf("kiosk profile not found")
+		}
+		device.KioskProfileID = sql.NullInt64{Int64: int64(profile.ID), Valid: true}
+		device.KioskProfileSlug = sql.NullString{String: profile.Slug, Valid: true}
+	}
+	return nil
+}
+
+// NewPairingCode issues a fresh pairing code and revokes the device's token, e.g. when
+// a screen is replaced
+func (s *DeviceService) NewPairingCode(ctx context.Context, id int) (*model.Device, error) {
+	if _, err := s.GetDevice(ctx, id); err != nil {
+		return nil, err
+	}
+
+	code, err := generatePairingCode()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.deviceRepo.SetPairingCode(ctx, id, code, time.Now().Add(s.cfg.PairingTTL)); err != nil {
+		return nil, err
+	}
+	return s.GetDevice(ctx, id)
+}
+
+// Pair exchanges a valid pairing code for a device token
+func (s *DeviceService) Pair(ctx context.Context, code string) (*dto.DevicePairResponse, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	device, err := s.deviceRepo.GetByPairingCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if device == nil {
+		return nil, fmt.Errorf("invalid or expired pairing code")
+	}
+
+	token, err := generateDeviceToken()
+	if err != nil {
+		return nil, err
+	}
+	paired, err := s.deviceRepo.CompletePairing(ctx, device.ID, code, hashDeviceToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if !paired {
+		return nil, fmt.Errorf("invalid or expired pairing code")
+	}
+
+	log.Info().Int("device_id", device.ID).Str("name", device.Name).Msg("Device paired")
+	return &dto.DevicePairResponse{Token: token, Device: s.info(device)}, nil
+}
+
+// Heartbeat records what a device reports about itself. It returns nil when the token
+// is not known, e.g. after the device was deleted or re-paired.
+func (s *DeviceService) Heartbeat(ctx context.Context, token, userAgent string, req *dto.DeviceHeartbeatRequest) (*dto.DeviceInfo, error) {
+	if token == "" {
+		return nil, nil
+	}
+	device, err := s.deviceRepo.GetByTokenHash(ctx, hashDeviceToken(token))
+	if err != nil || device == nil {
+		return nil, err
+	}
+
+	wasSilent := device.SilentSince.Valid
+	device.AppVersion = req.Version
+	device.UserAgent = truncate(userAgent, 255)
+	device.CurrentURL = req.URL
+	device.UptimeSeconds = req.UptimeSeconds
+	device.LastEventSeq = req.LastEventSeq
+	if err := s.deviceRepo.RecordHeartbeat(ctx, device); err != nil {
+		return nil, err
+	}
+
+	if wasSilent {
+		device.LastSeenAt = sql.NullTime{Time: time.Now(), Valid: true}
+		device.SilentSince = sql.NullTime{}
+		s.events.Publish(ctx, event.DeviceRecovered{Device: device})
+	}
+
+	info := s.info(device)
+	return &info, nil
+}
+
+// SendCommand sends a remote action to a paired device
+func (s *DeviceService) SendCommand(ctx context.Context, id int, action string) error {
+	device, err := s.GetDevice(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !device.IsPaired() {
+		return fmt.Errorf("device is not paired")
+	}
+
+	s.events.Publish(ctx, event.DeviceCommand{DeviceID: id, Action: action})
+	return nil
+}
+
+// RunSilenceWatcher reports devices that stop sending heartbeats during opening hours
+func (s *DeviceService) RunSilenceWatcher(ctx context.Context, interval time.Duration) {
+	if s.cfg.SilentAfter <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			count, err := s.SweepSilent(ctx, now)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to check for silent devices")
+				continue
+			}
+			if count > 0 {
+				log.Warn().Int("count", count).Msg("Devices stopped sending heartbeats")
+			}
+		}
+	}
+}
+
+// SweepSilent raises one alert for each device that went silent, if now is within
+// opening hours. Devices that were already silent when the site opened are reported
+// on the first sweep after opening.
+func (s *DeviceService) SweepSilent(ctx context.Context, now time.Time) (int, error) {
+	if !s.IsOpen(now) {
+		return 0, nil
+	}
+
+	devices, err := s.deviceRepo.ListNewlySilent(ctx, s.cfg.SilentAfter)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := range devices {
+		device := &devices[i]
+		marked, err := s.deviceRepo.MarkSilent(ctx, device.ID, s.cfg.SilentAfter)
+		if err != nil {
+			log.Error().Err(err).Str("layer", "service").Str("func", "SweepSilent").Int("device_id", device.ID).Msg("Failed to mark device silent")
+			continue
+		}
+		if !marked {
+			continue
+		}
+		device.Status = model.DeviceStatusOffline
+		s.events.Publish(ctx, event.DeviceSilent{Device: device})
+		count++
+	}
+	return count, nil
+}
+
+// IsOpen reports whether silent devices raise alerts at now
+func (s *DeviceService) IsOpen(now time.Time) bool {
+	if s.openingStart == "" {
+		return true
+	}
+	return weeklyWindowCovers(s.openingDays, s.openingStart, s.openingEnd, now)
+}
+
+// status says whether a device is waiting to pair, online or offline at now
+func (s *DeviceService) status(device *model.Device, now time.Time) string {
+	if !device.IsPaired() {
+		return model.DeviceStatusUnpaired
+	}
+	if device.LastSeenAt.Valid && now.Sub(device.LastSeenAt.Time) < s.cfg.SilentAfter {
+		return model.DeviceStatusOnline
+	}
+	return model.DeviceStatusOffline
+}
+
+func (s *DeviceService) info(device *model.Device) dto.DeviceInfo {
+	return dto.DeviceInfo{
+		ID:                device.ID,
+		Name:              device.Name,
+		Kind:              device.Kind,
+		HomeURL:           device.HomeURL(),
+		HeartbeatInterval: int(s.cfg.HeartbeatInterval.Seconds()),
+	}
+}
+
+func generatePairingCode() (string, error) {
+	code := make([]byte, pairingCodeLength)
+	max := big.NewInt(int64(len(pairingAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = pairingAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+func generateDeviceToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "dev_" + hex.EncodeToString(buf), nil
+}
+
+// hashDeviceToken is what is stored for a token, so a database leak does not reveal tokens
+func hashDeviceToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/event"
+	"tenangantri/internal/model"
+)
+
+func newDeviceService(bus *event.Bus) (*DeviceService, *MockDeviceRepository, *MockDisplayProfileRepository) {
+	mockDeviceRepo := new(MockDeviceRepository)
+	mockProfileRepo := new(MockDisplayProfileRepository)
+	cfg := &config.DeviceConfig{
+		PairingTTL:        15 * time.Minute,
+		HeartbeatInterval: 30 * time.Second,
+		SilentAfter:       2 * time.Minute,
+		OpeningHours:      "08:00-17:00",
+		OpeningDays:       []string{"1", "2", "3", "4", "5"},
+	}
+	return NewDeviceService(mockDeviceRepo, mockProfileRepo, new(MockKioskProfileRepository), bus, cfg), mockDeviceRepo, mockProfileRepo
+}
+
+func TestDeviceService_Pair(t *testing.T) {
+	service, mockDeviceRepo, _ := newDeviceService(event.NewBus())
+	ctx := context.Background()
+
+	device := &model.Device{ID: 3, Name: "Lobi 1", Kind: model.DeviceKindDisplay, ProfileSlug: sql.NullString{String: "lobi", Valid: true}}
+	mockDeviceRepo.On("GetByPairingCode", ctx, "ABC234").Return(device, nil)
+	mockDeviceRepo.On("GetByPairingCode", ctx, "ZZZZZZ").Return(nil, nil)
+	var storedHash string
+	mockDeviceRepo.On("CompletePairing", ctx, 3, "ABC234", mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { storedHash = args.String(3) }).
+		Return(true, nil)
+
+	resp, err := service.Pair(ctx, " abc234 ")
+	require.NoError(t, err)
+	assert.Equal(t, dto.DeviceInfo{ID: 3, Name: "Lobi 1", Kind: "display", HomeURL: "/display/p/lobi", HeartbeatInterval: 30}, resp.Device)
+	// Only the hash of the token is stored
+	assert.NotEqual(t, resp.Token, storedHash)
+	assert.Equal(t, hashDeviceToken(resp.Token), storedHash)
+
+	_, err = service.Pair(ctx, "zzzzzz")
+	assert.Error(t, err)
+}
+
+func TestDeviceService_HeartbeatRecovers(t *testing.T) {
+	bus := event.NewBus()
+	var recovered []event.DeviceRecovered
+	event.On(bus, func(ctx context.Context, e event.DeviceRecovered) {
+		recovered = append(recovered, e)
+	})
+	service, mockDeviceRepo, _ := newDeviceService(bus)
+	ctx := context.Background()
+
+	device := &model.Device{
+		ID:          5,
+		Kind:        model.DeviceKindKiosk,
+		TokenHash:   sql.NullString{String: hashDeviceToken("dev_good"), Valid: true},
+		SilentSince: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+	}
+	mockDeviceRepo.On("GetByTokenHash", ctx, hashDeviceToken("dev_good")).Return(device, nil)
+	mockDeviceRepo.On("GetByTokenHash", ctx, hashDeviceToken("dev_gone")).Return(nil, nil)
+	mockDeviceRepo.On("RecordHeartbeat", ctx, device).Return(nil)
+
+	info, err := service.Heartbeat(ctx, "dev_good", "Chrome", &dto.DeviceHeartbeatRequest{Version: "1", UptimeSeconds: 90, LastEventSeq: 42, URL: "/kiosk"})
+	require.NoError(t, err)
+	assert.Equal(t, "/kiosk", info.HomeURL)
+	assert.Equal(t, int64(42), device.LastEventSeq)
+	require.Len(t, recovered, 1)
+	assert.Equal(t, 5, recovered[0].Device.ID)
+
+	// Unknown and missing tokens are not devices
+	info, err = service.Heartbeat(ctx, "dev_gone", "", &dto.DeviceHeartbeatRequest{})
+	require.NoError(t, err)
+	assert.Nil(t, info)
+	info, err = service.Heartbeat(ctx, "", "", &dto.DeviceHeartbeatRequest{})
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
+
+func TestDeviceService_UpdateSendsDisplayToNewProfile(t *testing.T) {
+	bus := event.NewBus()
+	var commands []event.DeviceCommand
+	event.On(bus, func(ctx context.Context, e event.DeviceCommand) {
+		commands = append(commands, e)
+	})
+	service, mockDeviceRepo, mockProfileRepo := newDeviceService(bus)
+	ctx := context.Background()
+
+	mockDeviceRepo.On("GetByID", ctx, 2).Return(&model.Device{
+		ID: 2, Name: "Lobi", Kind: model.DeviceKindDisplay, TokenHash: sql.NullString{String: "x", Valid: true},
+	}, nil)
+	mockDeviceRepo.On("Update", ctx, mock.AnythingOfType("*model.Device")).Return(nil)
+	mockProfileRepo.On("GetByID", ctx, 7).Return(&model.DisplayProfile{ID: 7, Slug: "poli-anak"}, nil)
+
+	profileID := 7
+	device, err := service.UpdateDevice(ctx, 2, &dto.DeviceRequest{Name: "Lobi", Kind: model.DeviceKindDisplay, DisplayProfileID: &profileID})
+	require.NoError(t, err)
+	assert.Equal(t, "/display/p/poli-anak", device.HomeURL())
+	assert.Equal(t, []event.DeviceCommand{{DeviceID: 2, Action: model.DeviceActionNavigate, URL: "/display/p/poli-anak"}}, commands)
+}
+
+func TestDeviceService_SweepSilent(t *testing.T) {
+	bus := event.NewBus()
+	var silent []event.DeviceSilent
+	event.On(bus, func(ctx context.Context, e event.DeviceSilent) {
+		silent = append(silent, e)
+	})
+	service, mockDeviceRepo, _ := newDeviceService(bus)
+	ctx := context.Background()
+
+	// 2026-10-19 is a Monday
+	monday := time.Date(2026, 10, 19, 10, 0, 0, 0, time.Local)
+	mockDeviceRepo.On("ListNewlySilent", ctx, 2*time.Minute).Return([]model.Device{{ID: 1}, {ID: 2}}, nil)
+	mockDeviceRepo.On("MarkSilent", ctx, 1, 2*time.Minute).Return(true, nil)
+	// Device 2 sent a heartbeat in the meantime
+	mockDeviceRepo.On("MarkSilent", ctx, 2, 2*time.Minute).Return(false, nil)
+
+	count, err := service.SweepSilent(ctx, monday)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.Len(t, silent, 1)
+	assert.Equal(t, 1, silent[0].Device.ID)
+
+	// Outside opening hours nothing is checked
+	for _, closed := range []time.Time{monday.Add(8 * time.Hour), monday.AddDate(0, 0, 6)} {
+		count, err = service.SweepSilent(ctx, closed)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	}
+	mockDeviceRepo.AssertNumberOfCalls(t, "ListNewlySilent", 1)
+}
+
+func TestDeviceService_Status(t *testing.T) {
+	service, _, _ := newDeviceService(event.NewBus())
+	now := time.Now()
+	paired := sql.NullString{String: "x", Valid: true}
+
+	assert.Equal(t, model.DeviceStatusUnpaired, service.status(&model.Device{}, now))
+	assert.Equal(t, model.DeviceStatusOffline, service.status(&model.Device{TokenHash: paired}, now))
+	assert.Equal(t, model.DeviceStatusOnline, service.status(&model.Device{TokenHash: paired, LastSeenAt: sql.NullTime{Time: now.Add(-time.Minute), Valid: true}}, now))
+	assert.Equal(t, model.DeviceStatusOffline, service.status(&model.Device{TokenHash: paired, LastSeenAt: sql.NullTime{Time: now.Add(-5 * time.Minute), Valid: true}}, now))
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/event"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+)
+
+var (
+	displaySlugPattern  = regexp.MustCompile(`^[a-z0-9-]{1,50}$`)
+	displayColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// DisplayProfileService manages display profiles and assembles the boards that show them
+type DisplayProfileService struct {
+	profileRepo  repository.DisplayProfileRepository
+	statsRepo    repository.StatsRepository
+	categoryRepo repository.CategoryRepository
+	counterRepo  repository.CounterRepository
+	events       event.Publisher
+}
+
+func NewDisplayProfileService(
+	profileRepo repository.DisplayProfileRepository,
+	statsRepo repository.StatsRepository,
+	categoryRepo repository.CategoryRepository,
+	counterRepo repository.CounterRepository,
+	events event.Publisher) *DisplayProfileService {
+	return &DisplayProfileService{
+		profileRepo:  profileRepo,
+		statsRepo:    statsRepo,
+		categoryRepo: categoryRepo,
+		counterRepo:  counterRepo,
+		events:       events,
+	}
+}
+
+// ListProfiles returns all display profiles
+func (s *DisplayProfileService) ListProfiles(ctx context.Context) ([]model.DisplayProfile, error) {
+	return s.profileRepo.List(ctx)
+}
+
+// GetProfile returns a display profile by ID
+func (s *DisplayProfileService) GetProfile(ctx context.Context, id int) (*model.DisplayProfile, error) {
+	profile, err := s.profileRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("display profile not found")
+	}
+	return profile, nil
+}
+
+// CreateProfile creates a display profile
+func (s *DisplayProfileService) CreateProfile(ctx context.Context, req *dto.DisplayProfileRequest) (*model.DisplayProfile, error) {
+	profile := &model.DisplayProfile{IsActive: true}
+	if err := s.apply(ctx, profile, req); err != nil {
+		return nil, err
+	}
+
+	created, err := s.profileRepo.Create(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(ctx, event.DisplayProfileChanged{Action: event.ActionCreated, ProfileID: created.ID, Slug: created.Slug})
+	return created, nil
+}
+
+// UpdateProfile updates a display profile; screens showing it reload, following a new slug
+func (s *DisplayProfileService) UpdateProfile(ctx context.Context, id int, req *dto.DisplayProfileRequest) (*model.DisplayProfile, error) {
+	profile, err := s.GetProfile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	previousSlug := profile.Slug
+	if err := s.apply(ctx, profile, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.profileRepo.Update(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	changed := event.DisplayProfileChanged{Action: event.ActionUpdated, ProfileID: profile.ID, Slug: profile.Slug}
+	if previousSlug != profile.Slug {
+		changed.PreviousSlug = previousSlug
+	}
+	s.events.Publish(ctx, changed)
+	return profile, nil
+}
+
+// DeleteProfile deletes a display profile
+func (s *DisplayProfileService) DeleteProfile(ctx context.Context, id int) error {
+	profile, err := s.GetProfile(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.profileRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.events.Publish(ctx, event.DisplayProfileChanged{Action: event.ActionDeleted, ProfileID: id, Slug: profile.Slug})
+	return nil
+}
+
+// apply validates a request and copies it onto profile
+func (s *DisplayProfileService) apply(ctx context.Context, profile *model.DisplayProfile, req *dto.DisplayProfileRequest) error {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !displaySlugPattern.MatchString(slug) {
+		return fmt.Errorf("slug may only contain lowercase letters, digits and dashes")
+	}
+	if !displayColorPattern.MatchString(req.AccentColor) || !displayColorPattern.MatchString(req.BackgroundColor) {
+		return fmt.Errorf("colors must be in #RRGGBB format")
+	}
+	if req.Layout != model.DisplayLayoutLandscape && req.Layout != model.DisplayLayoutPortrait {
+		return fmt.Errorf("invalid layout: %s", req.Layout)
+	}
+
+	existing, err := s.profileRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != profile.ID {
+		return fmt.Errorf("slug %q is already used by %s", slug, existing.Name)
+	}
+
+	profile.Slug = slug
+	profile.Name = strings.TrimSpace(req.Name)
+	profile.Layout = req.Layout
+	profile.CategoryIDs = uniqueIDs(req.CategoryIDs)
+	profile.CounterIDs = uniqueIDs(req.CounterIDs)
+	profile.RecentCount = req.RecentCount
+	profile.ShowWaiting = req.ShowWaiting
+	profile.AccentColor = strings.ToUpper(req.AccentColor)
+	profile.BackgroundColor = strings.ToUpper(req.BackgroundColor)
+	profile.TickerText = strings.TrimSpace(req.TickerText)
+	if req.IsActive != nil {
+		profile.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// GetScreen loads what the board for an active profile shows. It returns nil when
+// no active profile has the slug.
+func (s *DisplayProfileService) GetScreen(ctx context.Context, slug string) (*dto.DisplayScreen, error) {
+	profile, err := s.profileRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil || !profile.IsActive {
+		return nil, nil
+	}
+
+	screen := &dto.DisplayScreen{Profile: profile}
+
+	screen.Tickets, err = s.statsRepo.GetRecentlyCalledTickets(ctx, profile.CategoryIDs, profile.CounterIDs, profile.RecentCount)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "GetScreen").Msg("Failed to get recently called tickets")
+		screen.Tickets = []dto.DisplayTicket{}
+	}
+
+	if profile.ShowWaiting {
+		if len(profile.CategoryIDs) == 0 {
+			screen.Waiting, err = s.statsRepo.GetQueueLengthByCategory(ctx)
+		} else {
+			screen.Waiting, err = s.statsRepo.GetQueueLengthByCategories(ctx, profile.CategoryIDs)
+		}
+		if err != nil {
+			log.Error().Err(err).Str("layer", "service").Str("func", "GetScreen").Msg("Failed to get waiting counts")
+			screen.Waiting = []dto.CategoryQueueStats{}
+		}
+	}
+
+	counters, err := s.counterRepo.List(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "GetScreen").Msg("Failed to get counters")
+		counters = []model.Counter{}
+	}
+	screen.Counters = make([]model.Counter, 0, len(counters))
+	for _, counter := range counters {
+		if len(profile.CounterIDs) == 0 || slices.Contains(profile.CounterIDs, counter.ID) {
+			screen.Counters = append(screen.Counters, counter)
+		}
+	}
+
+	return screen, nil
+}
+
+// ListChoices returns the categories and counters a profile can be limited to
+func (s *DisplayProfileService) ListChoices(ctx context.Context) ([]model.Category, []model.Counter, error) {
+	categories, err := s.categoryRepo.List(ctx, false, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	counters, err := s.counterRepo.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return categories, counters, nil
+}
+
+// uniqueIDs drops non-positive and repeated IDs, keeping the order given
+func uniqueIDs(ids []int) []int {
+	result := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id > 0 && !slices.Contains(result, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/event"
+	"tenangantri/internal/model"
+)
+
+func newDisplayProfileRequest(slug string) *dto.DisplayProfileRequest {
+	return &dto.DisplayProfileRequest{
+		Slug:            slug,
+		Name:            "Lobi",
+		Layout:          model.DisplayLayoutPortrait,
+		CategoryIDs:     []int{2, 2, 0, 1},
+		RecentCount:     5,
+		AccentColor:     "#ff0000",
+		BackgroundColor: "#000000",
+	}
+}
+
+func TestDisplayProfileService_UpdatePublishesRename(t *testing.T) {
+	mockProfileRepo := new(MockDisplayProfileRepository)
+	bus := event.NewBus()
+	var changes []event.DisplayProfileChanged
+	event.On(bus, func(ctx context.Context, e event.DisplayProfileChanged) {
+		changes = append(changes, e)
+	})
+
+	service := NewDisplayProfileService(mockProfileRepo, new(MockStatsRepository), new(MockCategoryRepository), new(MockCounterRepository), bus)
+	ctx := context.Background()
+
+	mockProfileRepo.On("GetByID", ctx, 4).Return(&model.DisplayProfile{ID: 4, Slug: "lobi", IsActive: true}, nil)
+	mockProfileRepo.On("GetBySlug", ctx, "lobi-timur").Return(nil, nil)
+	mockProfileRepo.On("Update", ctx, mock.AnythingOfType("*model.DisplayProfile")).Return(nil)
+
+	profile, err := service.UpdateProfile(ctx, 4, newDisplayProfileRequest(" Lobi-Timur "))
+	require.NoError(t, err)
+
+	assert.Equal(t, "lobi-timur", profile.Slug)
+	assert.Equal(t, []int{2, 1}, profile.CategoryIDs)
+	assert.Equal(t, "#FF0000", profile.AccentColor)
+	assert.Equal(t, []event.DisplayProfileChanged{
+		{Action: event.ActionUpdated, ProfileID: 4, Slug: "lobi-timur", PreviousSlug: "lobi"},
+	}, changes)
+}
+
+func TestDisplayProfileService_RejectsInvalidProfiles(t *testing.T) {
+	mockProfileRepo := new(MockDisplayProfileRepository)
+	service := NewDisplayProfileService(mockProfileRepo, new(MockStatsRepository), new(MockCategoryRepository), new(MockCounterRepository), event.NewBus())
+	ctx := context.Background()
+
+	_, err := service.CreateProfile(ctx, newDisplayProfileRequest("lobi utama"))
+	assert.Error(t, err)
+
+	req := newDisplayProfileRequest("lobi")
+	req.AccentColor = "red"
+	_, err = service.CreateProfile(ctx, req)
+	assert.Error(t, err)
+
+	mockProfileRepo.On("GetBySlug", ctx, "taken").Return(&model.DisplayProfile{ID: 9, Slug: "taken", Name: "Lain"}, nil)
+	_, err = service.CreateProfile(ctx, newDisplayProfileRequest("taken"))
+	assert.Error(t, err)
+	mockProfileRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestDisplayProfileService_GetScreen(t *testing.T) {
+	mockProfileRepo := new(MockDisplayProfileRepository)
+	mockStatsRepo := new(MockStatsRepository)
+	mockCounterRepo := new(MockCounterRepository)
+	service := NewDisplayProfileService(mockProfileRepo, mockStatsRepo, new(MockCategoryRepository), mockCounterRepo, event.NewBus())
+	ctx := context.Background()
+
+	profile := &model.DisplayProfile{ID: 1, Slug: "lobi", CategoryIDs: []int{1}, CounterIDs: []int{3}, RecentCount: 6, ShowWaiting: true, IsActive: true}
+	mockProfileRepo.On("GetBySlug", ctx, "lobi").Return(profile, nil)
+	mockProfileRepo.On("GetBySlug", ctx, "off").Return(&model.DisplayProfile{Slug: "off"}, nil)
+	mockStatsRepo.On("GetRecentlyCalledTickets", ctx, []int{1}, []int{3}, 6).Return([]dto.DisplayTicket{{TicketNumber: "A001"}}, nil)
+	mockStatsRepo.On("GetQueueLengthByCategories", ctx, []int{1}).Return([]dto.CategoryQueueStats{{CategoryID: 1, WaitingCount: 4}}, nil)
+	mockCounterRepo.On("List", ctx).Return([]model.Counter{{ID: 2, Number: "2"}, {ID: 3, Number: "3"}}, nil)
+
+	screen, err := service.GetScreen(ctx, "lobi")
+	require.NoError(t, err)
+	assert.Equal(t, profile, screen.Profile)
+	assert.Len(t, screen.Tickets, 1)
+	assert.Equal(t, 4, screen.Waiting[0].WaitingCount)
+	assert.Equal(t, []model.Counter{{ID: 3, Number: "3"}}, screen.Counters)
+
+	// Inactive profiles are not shown
+	screen, err = service.GetScreen(ctx, "off")
+	require.NoError(t, err)
+	assert.Nil(t, screen)
+}
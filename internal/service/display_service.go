@@ -85,5 +85,12 @@ func (s *DisplayService) GetCategoryDisplayData(ctx context.Context, categoryID
 		return selectedCategory, []dto.DisplayTicket{}, nil
 	}
 
-	return selectedCategory, tickets, nil
+	categoryTickets := make([]dto.DisplayTicket, 0, len(tickets))
+	for _, ticket := range tickets {
+		if ticket.CategoryID == categoryID {
+			categoryTickets = append(categoryTickets, ticket)
+		}
+	}
+
+	return selectedCategory, categoryTickets, nil
 }
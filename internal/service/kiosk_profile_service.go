@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/escpos"
+	"tenangantri/internal/event"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+)
+
+// kioskIconPattern matches Font Awesome icon names as used by categories
+var kioskIconPattern = regexp.MustCompile(`^[a-z0-9-]{0,50}$`)
+
+// spoolNamePattern matches spool file names; they cannot leave the spool directory
+var spoolNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,99}$`)
+
+// KioskProfileService manages kiosk profiles
+type KioskProfileService struct {
+	profileRepo  repository.KioskProfileRepository
+	categoryRepo repository.CategoryRepository
+	events       event.Publisher
+}
+
+func NewKioskProfileService(
+	profileRepo repository.KioskProfileRepository,
+	categoryRepo repository.CategoryRepository,
+	events event.Publisher) *KioskProfileService {
+	return &KioskProfileService{
+		profileRepo:  profileRepo,
+		categoryRepo: categoryRepo,
+		events:       events,
+	}
+}
+
+// ListProfiles returns all kiosk profiles
+func (s *KioskProfileService) ListProfiles(ctx context.Context) ([]model.KioskProfile, error) {
+	return s.profileRepo.List(ctx)
+}
+
+// GetProfile returns a kiosk profile by ID with its categories
+func (s *KioskProfileService) GetProfile(ctx context.Context, id int) (*model.KioskProfile, error) {
+	profile, err := s.profileRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("kiosk profile not found")
+	}
+
+	profile.Categories, err = s.profileRepo.ListCategories(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// CreateProfile creates a kiosk profile with the given categories
+func (s *KioskProfileService) CreateProfile(ctx context.Context, req *dto.KioskProfileRequest) (*model.KioskProfile, error) {
+	profile := &model.KioskProfile{IsActive: true}
+	categories, err := s.apply(ctx, profile, req)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.profileRepo.Create(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.profileRepo.ReplaceCategories(ctx, created.ID, categories); err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(ctx, event.KioskProfileChanged{Action: event.ActionCreated, ProfileID: created.ID, Slug: created.Slug})
+	return s.GetProfile(ctx, created.ID)
+}
+
+// UpdateProfile updates a kiosk profile and replaces its categories; kiosks showing it
+// reload, following a new slug
+func (s *KioskProfileService) UpdateProfile(ctx context.Context, id int, req *dto.KioskProfileRequest) (*model.KioskProfile, error) {
+	profile, err := s.GetProfile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	previousSlug := profile.Slug
+	categories, err := s.apply(ctx, profile, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.profileRepo.Update(ctx, profile); err != nil {
+		return nil, err
+	}
+	if err := s.profileRepo.ReplaceCategories(ctx, id, categories); err != nil {
+		return nil, err
+	}
+
+	changed := event.KioskProfileChanged{Action: event.ActionUpdated, ProfileID: id, Slug: profile.Slug}
+	if previousSlug != profile.Slug {
+		changed.PreviousSlug = previousSlug
+	}
+	s.events.Publish(ctx, changed)
+	return s.GetProfile(ctx, id)
+}
+
+// DeleteProfile deletes a kiosk profile; devices assigned to it fall back to the default kiosk
+func (s *KioskProfileService) DeleteProfile(ctx context.Context, id int) error {
+	profile, err := s.GetProfile(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.profileRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.events.Publish(ctx, event.KioskProfileChanged{Action: event.ActionDeleted, ProfileID: id, Slug: profile.Slug})
+	return nil
+}
+
+// ListCategories returns the categories a profile can offer
+func (s *KioskProfileService) ListCategories(ctx context.Context) ([]model.Category, error) {
+	return s.categoryRepo.List(ctx, false, false)
+}
+
+// apply validates a request and copies it onto profile. It returns the profile's
+// categories in order, without repeats.
+func (s *KioskProfileService) apply(ctx context.Context, profile *model.KioskProfile, req *dto.KioskProfileRequest) ([]model.KioskProfileCategory, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !displaySlugPattern.MatchString(slug) {
+		return nil, fmt.Errorf("slug may only contain lowercase letters, digits and dashes")
+	}
+	if req.Language != model.KioskLanguageIndonesian && req.Language != model.KioskLanguageEnglish {
+		return nil, fmt.Errorf("invalid language: %s", req.Language)
+	}
+	switch req.TicketOutput {
+	case model.KioskOutputPrint, model.KioskOutputQR, model.KioskOutputBoth:
+	default:
+		return nil, fmt.Errorf("invalid ticket output: %s", req.TicketOutput)
+	}
+
+	printerType := req.PrinterType
+	printerTarget := strings.TrimSpace(req.PrinterTarget)
+	switch printerType {
+	case "", model.KioskPrinterBrowser:
+		printerType, printerTarget = model.KioskPrinterBrowser, ""
+	case model.KioskPrinterNetwork:
+		if !validPrinterAddress(printerTarget) {
+			return nil, fmt.Errorf("printer address must be a host or host:port")
+		}
+	case model.KioskPrinterFile:
+		if !spoolNamePattern.MatchString(printerTarget) {
+			return nil, fmt.Errorf("spool file may only contain letters, digits, dots, dashes and underscores")
+		}
+	default:
+		return nil, fmt.Errorf("invalid printer type: %s", req.PrinterType)
+	}
+
+	existing, err := s.profileRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ID != profile.ID {
+		return nil, fmt.Errorf("slug %q is already used by %s", slug, existing.Name)
+	}
+
+	categories := make([]model.KioskProfileCategory, 0, len(req.Categories))
+	seen := make(map[int]bool, len(req.Categories))
+	for _, reqCategory := range req.Categories {
+		if seen[reqCategory.CategoryID] {
+			continue
+		}
+		seen[reqCategory.CategoryID] = true
+
+		category, err := s.categoryRepo.GetByID(ctx, reqCategory.CategoryID)
+		if err != nil || category == nil {
+			return nil, fmt.Errorf("category %d not found", reqCategory.CategoryID)
+		}
+		icon := strings.ToLower(strings.TrimSpace(reqCategory.Icon))
+		if !kioskIconPattern.MatchString(icon) {
+			return nil, fmt.Errorf("icon may only contain lowercase letters, digits and dashes")
+		}
+		categories = append(categories, model.KioskProfileCategory{
+			CategoryID: category.ID,
+			Label:      strings.TrimSpace(reqCategory.Label),
+			Icon:       strings.TrimPrefix(icon, "fa-"),
+		})
+	}
+
+	profile.Slug = slug
+	profile.Name = strings.TrimSpace(req.Name)
+	profile.Language = req.Language
+	profile.TicketOutput = req.TicketOutput
+	profile.PrinterType = printerType
+	profile.PrinterTarget = printerTarget
+	profile.IdleSeconds = req.IdleSeconds
+	profile.AttractTitle = strings.TrimSpace(req.AttractTitle)
+	profile.AttractText = strings.TrimSpace(req.AttractText)
+	if req.IsActive != nil {
+		profile.IsActive = *req.IsActive
+	}
+	return categories, nil
+}
+
+// validPrinterAddress reports whether addr is a host with an optional port
+func validPrinterAddress(addr string) bool {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		host, port = addr, escpos.DefaultPort
+	}
+	number, err := strconv.Atoi(port)
+	return host != "" && !strings.ContainsAny(host, " /") && err == nil && number > 0 && number <= 65535
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/event"
+	"tenangantri/internal/model"
+)
+
+func TestKioskProfileService_UpdateProfile(t *testing.T) {
+	mockProfileRepo := new(MockKioskProfileRepository)
+	mockCatRepo := new(MockCategoryRepository)
+	bus := event.NewBus()
+	var changes []event.KioskProfileChanged
+	event.On(bus, func(ctx context.Context, e event.KioskProfileChanged) {
+		changes = append(changes, e)
+	})
+	service := NewKioskProfileService(mockProfileRepo, mockCatRepo, bus)
+	ctx := context.Background()
+
+	mockProfileRepo.On("GetByID", ctx, 4).Return(&model.KioskProfile{ID: 4, Slug: "lantai-1", IsActive: true}, nil)
+	mockProfileRepo.On("ListCategories", ctx, 4).Return([]model.KioskProfileCategory{}, nil)
+	mockProfileRepo.On("GetBySlug", ctx, "lantai-dasar").Return(nil, nil)
+	mockCatRepo.On("GetByID", ctx, 1).Return(&model.Category{ID: 1, Name: "General"}, nil)
+	mockCatRepo.On("GetByID", ctx, 2).Return(&model.Category{ID: 2, Name: "Billing"}, nil)
+	mockProfileRepo.On("Update", ctx, mock.AnythingOfType("*model.KioskProfile")).Return(nil)
+	mockProfileRepo.On("ReplaceCategories", ctx, 4, []model.KioskProfileCategory{
+		{CategoryID: 2, Label: "Pembayaran", Icon: "cash-register"},
+		{CategoryID: 1},
+	}).Return(nil)
+
+	_, err := service.UpdateProfile(ctx, 4, &dto.KioskProfileRequest{
+		Slug:         " Lantai-Dasar ",
+		Name:         "Lantai Dasar",
+		Language:     model.KioskLanguageEnglish,
+		TicketOutput: model.KioskOutputQR,
+		IdleSeconds:  90,
+		Categories: []dto.KioskProfileCategoryRequest{
+			{CategoryID: 2, Label: " Pembayaran ", Icon: "fa-cash-register"},
+			{CategoryID: 1},
+			// Repeats keep the first position
+			{CategoryID: 2, Label: "Kasir"},
+		},
+	})
+	require.NoError(t, err)
+	mockProfileRepo.AssertExpectations(t)
+	assert.Equal(t, []event.KioskProfileChanged{{Action: event.ActionUpdated, ProfileID: 4, Slug: "lantai-dasar", PreviousSlug: "lantai-1"}}, changes)
+
+	_, err = service.UpdateProfile(ctx, 4, &dto.KioskProfileRequest{
+		Slug: "lantai-dasar", Name: "Lantai Dasar", Language: "id", TicketOutput: "print",
+		Categories: []dto.KioskProfileCategoryRequest{{CategoryID: 1, Icon: "<b>"}},
+	})
+	assert.Error(t, err)
+
+	for _, printer := range []struct{ kind, target string }{
+		{model.KioskPrinterNetwork, ""},
+		{model.KioskPrinterNetwork, "printer.local:99999"},
+		{model.KioskPrinterFile, "../kiosk.prn"},
+		{"usb", "lp0"},
+	} {
+		_, err = service.UpdateProfile(ctx, 4, &dto.KioskProfileRequest{
+			Slug: "lantai-dasar", Name: "Lantai Dasar", Language: "id", TicketOutput: "print",
+			PrinterType: printer.kind, PrinterTarget: printer.target,
+		})
+		assert.Error(t, err, "printer %s %q", printer.kind, printer.target)
+	}
+}
@@ -3,27 +3,38 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"tenangantri/internal/dto"
+	"tenangantri/internal/event"
 	"tenangantri/internal/model"
 	"tenangantri/internal/repository"
 )
 
+// ErrKioskProfilesInUse is returned for a ticket from the unprofiled kiosk once an active
+// kiosk profile exists, so that a profile's kiosk cannot sidestep the categories it offers
+var ErrKioskProfilesInUse = errors.New("kiosk profiles are in use")
+
 // KioskService handles kiosk-related business logic
 type KioskService struct {
 	categoryRepo repository.CategoryRepository
 	ticketRepo   repository.TicketRepository
 	statsRepo    repository.StatsRepository
+	profileRepo  repository.KioskProfileRepository
+	events       event.Publisher
 }
 
-func NewKioskService(categoryRepo repository.CategoryRepository, ticketRepo repository.TicketRepository, statsRepo repository.StatsRepository) *KioskService {
+func NewKioskService(categoryRepo repository.CategoryRepository, ticketRepo repository.TicketRepository, statsRepo repository.StatsRepository, profileRepo repository.KioskProfileRepository, events event.Publisher) *KioskService {
 	return &KioskService{
 		categoryRepo: categoryRepo,
 		ticketRepo:   ticketRepo,
 		statsRepo:    statsRepo,
+		profileRepo:  profileRepo,
+		events:       events,
 	}
 }
 
@@ -38,8 +49,91 @@ func (s *KioskService) GetCategories(ctx context.Context) ([]model.Category, err
 	return categories, nil
 }
 
-// GenerateTicket generates a new ticket from kiosk
-func (s *KioskService) GenerateTicket(ctx context.Context, req *dto.CreateTicketRequest) (*model.Ticket, int, int, error) {
+// GetCategory gets a category by ID
+func (s *KioskService) GetCategory(ctx context.Context, id int) (*model.Category, error) {
+	return s.categoryRepo.GetByID(ctx, id)
+}
+
+// GetKiosk loads the kiosk an active profile configures: the categories it offers that
+// are open at the kiosk, in the profile's order and with its labels and icons. A profile
+// without categories offers all of them. It returns nil when no active profile has the slug.
+func (s *KioskService) GetKiosk(ctx context.Context, slug string) (*dto.KioskScreen, error) {
+	profile, err := s.profileRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil || !profile.IsActive {
+		return nil, nil
+	}
+
+	available, err := s.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	profile.Categories, err = s.profileRepo.ListCategories(ctx, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	screen := &dto.KioskScreen{Profile: profile}
+	if len(profile.Categories) == 0 {
+		screen.Categories = available
+		return screen, nil
+	}
+
+	byID := make(map[int]model.Category, len(available))
+	for _, category := range available {
+		byID[category.ID] = category
+	}
+	screen.Categories = make([]model.Category, 0, len(profile.Categories))
+	for _, offered := range profile.Categories {
+		category, ok := byID[offered.CategoryID]
+		if !ok {
+			continue
+		}
+		if offered.Label != "" {
+			category.Name = offered.Label
+		}
+		if offered.Icon != "" {
+			category.Icon = sql.NullString{String: offered.Icon, Valid: true}
+		}
+		screen.Categories = append(screen.Categories, category)
+	}
+	return screen, nil
+}
+
+// DefaultKioskOpen reports whether the unprofiled kiosk is in use, which it is only
+// while no active kiosk profile exists
+func (s *KioskService) DefaultKioskOpen(ctx context.Context) (bool, error) {
+	profiles, err := s.profileRepo.List(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, profile := range profiles {
+		if profile.IsActive {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// GenerateTicket generates a new ticket from kiosk. When screen is set, only the
+// categories that kiosk offers can be issued; without one, tickets are only issued
+// while no kiosk profile is active.
+func (s *KioskService) GenerateTicket(ctx context.Context, req *dto.CreateTicketRequest, screen *dto.KioskScreen) (*model.Ticket, int, int, error) {
+	if screen != nil && !screen.Offers(req.CategoryID) {
+		return nil, 0, 0, fmt.Errorf("category %d is not offered at kiosk %s", req.CategoryID, screen.Profile.Slug)
+	}
+	if screen == nil {
+		open, err := s.DefaultKioskOpen(ctx)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if !open {
+			return nil, 0, 0, ErrKioskProfilesInUse
+		}
+	}
+
 	// Get category to validate and get prefix
 	category, err := s.categoryRepo.GetByID(ctx, req.CategoryID)
 	if err != nil {
@@ -84,6 +178,8 @@ func (s *KioskService) GenerateTicket(ctx context.Context, req *dto.CreateTicket
 	}
 	queuePosition := waitingCount
 
+	s.events.Publish(ctx, event.TicketIssued{Ticket: ticketWithDetails, QueuePosition: queuePosition})
+
 	// Get estimated wait time (simple calculation: avg wait time * position)
 	stats, err := s.statsRepo.GetDashboardStats(ctx)
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"tenangantri/internal/dto"
+	"tenangantri/internal/event"
 	"tenangantri/internal/model"
 )
 
@@ -16,9 +17,18 @@ func TestKioskService_GenerateTicket(t *testing.T) {
 	mockTicketRepo := new(MockTicketRepository)
 	mockStatsRepo := new(MockStatsRepository)
 
-	service := NewKioskService(mockCatRepo, mockTicketRepo, mockStatsRepo)
+	bus := event.NewBus()
+	var issued []event.TicketIssued
+	event.On(bus, func(ctx context.Context, e event.TicketIssued) {
+		issued = append(issued, e)
+	})
+
+	mockProfileRepo := new(MockKioskProfileRepository)
+	service := NewKioskService(mockCatRepo, mockTicketRepo, mockStatsRepo, mockProfileRepo, bus)
 
 	ctx := context.Background()
+	// Only an inactive profile, so the unprofiled kiosk is still in use
+	mockProfileRepo.On("List", ctx).Return([]model.KioskProfile{{ID: 1, Slug: "lobby"}}, nil)
 	catID := 1
 	category := &model.Category{
 		ID:     catID,
@@ -46,15 +56,77 @@ func TestKioskService_GenerateTicket(t *testing.T) {
 		AvgWaitTime: 600, // 10 mins
 	}, nil)
 
-	ticket, position, waitTime, err := service.GenerateTicket(ctx, req)
+	ticket, position, waitTime, err := service.GenerateTicket(ctx, req, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, ticket)
 	assert.Equal(t, "A001", ticket.TicketNumber)
 	assert.Equal(t, 5, position)
 	assert.Equal(t, 50, waitTime) // (600 * 5) / 60 = 50
+	if assert.Len(t, issued, 1) {
+		assert.Equal(t, "A001", issued[0].Ticket.TicketNumber)
+		assert.Equal(t, 5, issued[0].QueuePosition)
+	}
 
 	mockCatRepo.AssertExpectations(t)
 	mockTicketRepo.AssertExpectations(t)
 	mockStatsRepo.AssertExpectations(t)
 }
+
+func TestKioskService_GenerateTicketProfilesInUse(t *testing.T) {
+	mockCatRepo := new(MockCategoryRepository)
+	mockProfileRepo := new(MockKioskProfileRepository)
+	service := NewKioskService(mockCatRepo, new(MockTicketRepository), new(MockStatsRepository), mockProfileRepo, event.NewBus())
+
+	ctx := context.Background()
+	mockProfileRepo.On("List", ctx).Return([]model.KioskProfile{{ID: 1, Slug: "lobby", IsActive: true}}, nil)
+
+	_, _, _, err := service.GenerateTicket(ctx, &dto.CreateTicketRequest{CategoryID: 1}, nil)
+
+	assert.ErrorIs(t, err, ErrKioskProfilesInUse)
+	mockCatRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestKioskService_GetKiosk(t *testing.T) {
+	mockCatRepo := new(MockCategoryRepository)
+	mockProfileRepo := new(MockKioskProfileRepository)
+	service := NewKioskService(mockCatRepo, new(MockTicketRepository), new(MockStatsRepository), mockProfileRepo, event.NewBus())
+	ctx := context.Background()
+
+	mockCatRepo.On("List", ctx, true, true).Return([]model.Category{
+		{ID: 1, Name: "General", Prefix: "A"},
+		{ID: 2, Name: "Billing", Prefix: "B"},
+		{ID: 3, Name: "Consultation", Prefix: "C"},
+	}, nil)
+	mockProfileRepo.On("GetBySlug", ctx, "lantai-1").Return(&model.KioskProfile{ID: 4, Slug: "lantai-1", IsActive: true}, nil)
+	mockProfileRepo.On("GetBySlug", ctx, "tutup").Return(&model.KioskProfile{ID: 5, Slug: "tutup"}, nil)
+	mockProfileRepo.On("ListCategories", ctx, 4).Return([]model.KioskProfileCategory{
+		{CategoryID: 2, Label: "Pembayaran", Icon: "cash-register"},
+		{CategoryID: 9},
+		{CategoryID: 1},
+	}, nil)
+
+	screen, err := service.GetKiosk(ctx, "lantai-1")
+	assert.NoError(t, err)
+	// Profile order and labels; a category without a staffed counter is left out
+	if assert.Len(t, screen.Categories, 2) {
+		assert.Equal(t, "Pembayaran", screen.Categories[0].Name)
+		assert.Equal(t, "cash-register", screen.Categories[0].Icon.String)
+		assert.Equal(t, "General", screen.Categories[1].Name)
+	}
+	assert.True(t, screen.Offers(1))
+	assert.False(t, screen.Offers(3))
+
+	// Inactive profiles are not served
+	screen, err = service.GetKiosk(ctx, "tutup")
+	assert.NoError(t, err)
+	assert.Nil(t, screen)
+
+	// Tickets for categories the kiosk does not offer are refused before a number is drawn
+	_, _, _, err = service.GenerateTicket(ctx, &dto.CreateTicketRequest{CategoryID: 3}, &dto.KioskScreen{
+		Profile:    &model.KioskProfile{Slug: "lantai-1"},
+		Categories: []model.Category{{ID: 1}, {ID: 2}},
+	})
+	assert.Error(t, err)
+	mockCatRepo.AssertNotCalled(t, "GetByID", ctx, 3)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
@@ -52,11 +53,26 @@ func (m *MockTicketRepository) UpdateStatus(ctx context.Context, id int, status
 	return args.Error(0)
 }
 
-func (m *MockTicketRepository) AssignToCounter(ctx context.Context, ticketID, counterID int) error {
-	args := m.Called(ctx, ticketID, counterID)
+func (m *MockTicketRepository) Recall(ctx context.Context, ticketID int) error {
+	args := m.Called(ctx, ticketID)
 	return args.Error(0)
 }
 
+func (m *MockTicketRepository) MarkAnswered(ctx context.Context, ticketID int) error {
+	args := m.Called(ctx, ticketID)
+	return args.Error(0)
+}
+
+func (m *MockTicketRepository) AssignToCounter(ctx context.Context, ticketID, counterID, userID int) error {
+	args := m.Called(ctx, ticketID, counterID, userID)
+	return args.Error(0)
+}
+
+func (m *MockTicketRepository) Finish(ctx context.Context, ticketID int, status string, userID int) (int, error) {
+	args := m.Called(ctx, ticketID, status, userID)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockTicketRepository) GetNextTicket(ctx context.Context, categoryIDs []int) (*model.Ticket, error) {
 	args := m.Called(ctx, categoryIDs)
 	if args.Get(0) == nil {
@@ -88,6 +104,11 @@ func (m *MockTicketRepository) GetTodayCountByCategory(ctx context.Context, cate
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockTicketRepository) GetWaitingAheadCount(ctx context.Context, ticketID int) (int, error) {
+	args := m.Called(ctx, ticketID)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockTicketRepository) GenerateNumber(ctx context.Context, categoryID int, prefix string) (string, int, error) {
 	args := m.Called(ctx, categoryID, prefix)
 	return args.String(0), args.Int(1), args.Error(2)
@@ -208,6 +229,11 @@ func (m *MockStatsRepository) GetCurrentlyServingTickets(ctx context.Context) ([
 	return args.Get(0).([]dto.DisplayTicket), args.Error(1)
 }
 
+func (m *MockStatsRepository) GetRecentlyCalledTickets(ctx context.Context, categoryIDs, counterIDs []int, limit int) ([]dto.DisplayTicket, error) {
+	args := m.Called(ctx, categoryIDs, counterIDs, limit)
+	return args.Get(0).([]dto.DisplayTicket), args.Error(1)
+}
+
 type MockUserRepository struct {
 	mock.Mock
 }
@@ -440,3 +466,694 @@ func (m *MockCounterCategoryRepository) ListAll(ctx context.Context) ([]model.Co
 	args := m.Called(ctx)
 	return args.Get(0).([]model.CounterCategory), args.Error(1)
 }
+
+type MockPushSubscriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPushSubscriptionRepository) Upsert(ctx context.Context, sub *model.PushSubscription) (*model.PushSubscription, error) {
+	args := m.Called(ctx, sub)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PushSubscription), args.Error(1)
+}
+
+func (m *MockPushSubscriptionRepository) ListByTicketID(ctx context.Context, ticketID int) ([]model.PushSubscription, error) {
+	args := m.Called(ctx, ticketID)
+	return args.Get(0).([]model.PushSubscription), args.Error(1)
+}
+
+func (m *MockPushSubscriptionRepository) ListNearlyUpPending(ctx context.Context, ticketIDs []int) ([]model.PushSubscription, error) {
+	args := m.Called(ctx, ticketIDs)
+	return args.Get(0).([]model.PushSubscription), args.Error(1)
+}
+
+func (m *MockPushSubscriptionRepository) MarkNearlyUpNotified(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPushSubscriptionRepository) MarkCalledNotified(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPushSubscriptionRepository) DeleteByID(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPushSubscriptionRepository) DeleteByTicketAndEndpoint(ctx context.Context, ticketID int, endpoint string) error {
+	args := m.Called(ctx, ticketID, endpoint)
+	return args.Error(0)
+}
+
+func (m *MockPushSubscriptionRepository) DeleteExpired(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetSubscriptionByID(ctx context.Context, id int) (*model.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	args := m.Called(ctx, sub)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) FanOutOutbox(ctx context.Context, batchSize int) (int, error) {
+	args := m.Called(ctx, batchSize)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(ctx context.Context, batchSize int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	args := m.Called(ctx, batchSize, lease)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	args := m.Called(ctx, id, statusCode)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) MarkFailed(ctx context.Context, id int64, statusCode int, errMsg string, nextAttemptAt time.Time, dead bool) error {
+	args := m.Called(ctx, id, statusCode, errMsg, nextAttemptAt, dead)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) Redeliver(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, status string, subscriptionID, limit int) ([]model.WebhookDelivery, error) {
+	args := m.Called(ctx, status, subscriptionID, limit)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) CountDeliveriesByStatus(ctx context.Context) (map[string]int, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockWebhookRepository) PurgeProcessedOutbox(ctx context.Context, olderThan time.Duration) (int, error) {
+	args := m.Called(ctx, olderThan)
+	return args.Int(0), args.Error(1)
+}
+
+type MockTicketCallRepository struct {
+	mock.Mock
+}
+
+func (m *MockTicketCallRepository) Create(ctx context.Context, call *model.TicketCall) (*model.TicketCall, error) {
+	args := m.Called(ctx, call)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TicketCall), args.Error(1)
+}
+
+func (m *MockTicketCallRepository) ListByTicketID(ctx context.Context, ticketID int) ([]model.TicketCall, error) {
+	args := m.Called(ctx, ticketID)
+	return args.Get(0).([]model.TicketCall), args.Error(1)
+}
+
+func (m *MockTicketCallRepository) GetSummary(ctx context.Context, ticketID int) (*model.TicketCallSummary, error) {
+	args := m.Called(ctx, ticketID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TicketCallSummary), args.Error(1)
+}
+
+func (m *MockTicketCallRepository) ListUnanswered(ctx context.Context, minRecalls int, silentFor time.Duration) ([]int, error) {
+	args := m.Called(ctx, minRecalls, silentFor)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+type MockDisplayProfileRepository struct {
+	mock.Mock
+}
+
+func (m *MockDisplayProfileRepository) List(ctx context.Context) ([]model.DisplayProfile, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.DisplayProfile), args.Error(1)
+}
+
+func (m *MockDisplayProfileRepository) GetByID(ctx context.Context, id int) (*model.DisplayProfile, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.DisplayProfile), args.Error(1)
+}
+
+func (m *MockDisplayProfileRepository) GetBySlug(ctx context.Context, slug string) (*model.DisplayProfile, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.DisplayProfile), args.Error(1)
+}
+
+func (m *MockDisplayProfileRepository) Create(ctx context.Context, profile *model.DisplayProfile) (*model.DisplayProfile, error) {
+	args := m.Called(ctx, profile)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.DisplayProfile), args.Error(1)
+}
+
+func (m *MockDisplayProfileRepository) Update(ctx context.Context, profile *model.DisplayProfile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
+
+func (m *MockDisplayProfileRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockKioskProfileRepository struct {
+	mock.Mock
+}
+
+func (m *MockKioskProfileRepository) List(ctx context.Context) ([]model.KioskProfile, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.KioskProfile), args.Error(1)
+}
+
+func (m *MockKioskProfileRepository) GetByID(ctx context.Context, id int) (*model.KioskProfile, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.KioskProfile), args.Error(1)
+}
+
+func (m *MockKioskProfileRepository) GetBySlug(ctx context.Context, slug string) (*model.KioskProfile, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.KioskProfile), args.Error(1)
+}
+
+func (m *MockKioskProfileRepository) Create(ctx context.Context, profile *model.KioskProfile) (*model.KioskProfile, error) {
+	args := m.Called(ctx, profile)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.KioskProfile), args.Error(1)
+}
+
+func (m *MockKioskProfileRepository) Update(ctx context.Context, profile *model.KioskProfile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
+
+func (m *MockKioskProfileRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockKioskProfileRepository) ListCategories(ctx context.Context, profileID int) ([]model.KioskProfileCategory, error) {
+	args := m.Called(ctx, profileID)
+	return args.Get(0).([]model.KioskProfileCategory), args.Error(1)
+}
+
+func (m *MockKioskProfileRepository) ReplaceCategories(ctx context.Context, profileID int, categories []model.KioskProfileCategory) error {
+	args := m.Called(ctx, profileID, categories)
+	return args.Error(0)
+}
+
+type MockSignageRepository struct {
+	mock.Mock
+}
+
+func (m *MockSignageRepository) ListMedia(ctx context.Context) ([]model.SignageMedia, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.SignageMedia), args.Error(1)
+}
+
+func (m *MockSignageRepository) GetMediaByID(ctx context.Context, id int) (*model.SignageMedia, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SignageMedia), args.Error(1)
+}
+
+func (m *MockSignageRepository) GetMediaByFilename(ctx context.Context, filename string) (*model.SignageMedia, error) {
+	args := m.Called(ctx, filename)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SignageMedia), args.Error(1)
+}
+
+func (m *MockSignageRepository) CreateMedia(ctx context.Context, media *model.SignageMedia) (*model.SignageMedia, error) {
+	args := m.Called(ctx, media)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SignageMedia), args.Error(1)
+}
+
+func (m *MockSignageRepository) DeleteMedia(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSignageRepository) ListPlaylists(ctx context.Context) ([]model.SignagePlaylist, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.SignagePlaylist), args.Error(1)
+}
+
+func (m *MockSignageRepository) GetPlaylistByID(ctx context.Context, id int) (*model.SignagePlaylist, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SignagePlaylist), args.Error(1)
+}
+
+func (m *MockSignageRepository) CreatePlaylist(ctx context.Context, playlist *model.SignagePlaylist) (*model.SignagePlaylist, error) {
+	args := m.Called(ctx, playlist)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SignagePlaylist), args.Error(1)
+}
+
+func (m *MockSignageRepository) UpdatePlaylist(ctx context.Context, playlist *model.SignagePlaylist) error {
+	args := m.Called(ctx, playlist)
+	return args.Error(0)
+}
+
+func (m *MockSignageRepository) DeletePlaylist(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSignageRepository) ListPlaylistItems(ctx context.Context, playlistID int) ([]model.SignagePlaylistItem, error) {
+	args := m.Called(ctx, playlistID)
+	return args.Get(0).([]model.SignagePlaylistItem), args.Error(1)
+}
+
+func (m *MockSignageRepository) ReplacePlaylistItems(ctx context.Context, playlistID int, items []model.SignagePlaylistItem) error {
+	args := m.Called(ctx, playlistID, items)
+	return args.Error(0)
+}
+
+func (m *MockSignageRepository) ListSchedules(ctx context.Context) ([]model.SignageSchedule, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.SignageSchedule), args.Error(1)
+}
+
+func (m *MockSignageRepository) ListSchedulesByProfile(ctx context.Context, profileID int) ([]model.SignageSchedule, error) {
+	args := m.Called(ctx, profileID)
+	return args.Get(0).([]model.SignageSchedule), args.Error(1)
+}
+
+func (m *MockSignageRepository) GetScheduleByID(ctx context.Context, id int) (*model.SignageSchedule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SignageSchedule), args.Error(1)
+}
+
+func (m *MockSignageRepository) CreateSchedule(ctx context.Context, schedule *model.SignageSchedule) (*model.SignageSchedule, error) {
+	args := m.Called(ctx, schedule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SignageSchedule), args.Error(1)
+}
+
+func (m *MockSignageRepository) UpdateSchedule(ctx context.Context, schedule *model.SignageSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockSignageRepository) DeleteSchedule(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockDeviceRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeviceRepository) List(ctx context.Context) ([]model.Device, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) GetByID(ctx context.Context, id int) (*model.Device, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) GetByPairingCode(ctx context.Context, code string) (*model.Device, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*model.Device, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) Create(ctx context.Context, device *model.Device) (*model.Device, error) {
+	args := m.Called(ctx, device)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) Update(ctx context.Context, device *model.Device) error {
+	args := m.Called(ctx, device)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) SetPairingCode(ctx context.Context, id int, code string, expiresAt time.Time) error {
+	args := m.Called(ctx, id, code, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) CompletePairing(ctx context.Context, id int, code, tokenHash string) (bool, error) {
+	args := m.Called(ctx, id, code, tokenHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDeviceRepository) RecordHeartbeat(ctx context.Context, device *model.Device) error {
+	args := m.Called(ctx, device)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) ListNewlySilent(ctx context.Context, silentFor time.Duration) ([]model.Device, error) {
+	args := m.Called(ctx, silentFor)
+	return args.Get(0).([]model.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) MarkSilent(ctx context.Context, id int, silentFor time.Duration) (bool, error) {
+	args := m.Called(ctx, id, silentFor)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockReportRepository) Summary(ctx context.Context, filter model.ReportFilter) (*model.ReportSummary, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ReportSummary), args.Error(1)
+}
+
+func (m *MockReportRepository) ByCategory(ctx context.Context, filter model.ReportFilter) ([]model.ReportCategory, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]model.ReportCategory), args.Error(1)
+}
+
+func (m *MockReportRepository) ByCounter(ctx context.Context, filter model.ReportFilter) ([]model.ReportCounter, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]model.ReportCounter), args.Error(1)
+}
+
+func (m *MockReportRepository) Daily(ctx context.Context, filter model.ReportFilter) ([]model.ReportDay, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]model.ReportDay), args.Error(1)
+}
+
+func (m *MockReportRepository) ByStaff(ctx context.Context, filter model.ReportFilter) ([]model.ReportStaff, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]model.ReportStaff), args.Error(1)
+}
+
+func (m *MockReportRepository) Hourly(ctx context.Context, filter model.ReportFilter) ([]model.ReportHour, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]model.ReportHour), args.Error(1)
+}
+
+func (m *MockReportRepository) Weekdays(ctx context.Context, filter model.ReportFilter) ([]model.ReportWeekday, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]model.ReportWeekday), args.Error(1)
+}
+
+func (m *MockReportRepository) ListTickets(ctx context.Context, filter model.ReportFilter, limit int) ([]model.ReportTicket, error) {
+	args := m.Called(ctx, filter, limit)
+	return args.Get(0).([]model.ReportTicket), args.Error(1)
+}
+
+// EachTicket hands the tickets given to Return to fn
+func (m *MockReportRepository) EachTicket(ctx context.Context, filter model.ReportFilter, fn func(*model.ReportTicket) error) error {
+	args := m.Called(ctx, filter)
+	for _, ticket := range args.Get(0).([]model.ReportTicket) {
+		if err := fn(&ticket); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+type MockReportScheduleRepository struct {
+	mock.Mock
+}
+
+func (m *MockReportScheduleRepository) ListSchedules(ctx context.Context) ([]model.ReportSchedule, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.ReportSchedule), args.Error(1)
+}
+
+func (m *MockReportScheduleRepository) GetScheduleByID(ctx context.Context, id int) (*model.ReportSchedule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ReportSchedule), args.Error(1)
+}
+
+func (m *MockReportScheduleRepository) CreateSchedule(ctx context.Context, schedule *model.ReportSchedule) (*model.ReportSchedule, error) {
+	args := m.Called(ctx, schedule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ReportSchedule), args.Error(1)
+}
+
+func (m *MockReportScheduleRepository) UpdateSchedule(ctx context.Context, schedule *model.ReportSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockReportScheduleRepository) DeleteSchedule(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockReportScheduleRepository) ListDueSchedules(ctx context.Context, now time.Time) ([]model.ReportSchedule, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]model.ReportSchedule), args.Error(1)
+}
+
+func (m *MockReportScheduleRepository) EnqueueDueRun(ctx context.Context, scheduleID int, scheduledFor, nextRunAt, periodFrom, periodTo time.Time) (bool, error) {
+	args := m.Called(ctx, scheduleID, scheduledFor, nextRunAt, periodFrom, periodTo)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReportScheduleRepository) EnqueueRun(ctx context.Context, scheduleID int, scheduledFor, periodFrom, periodTo time.Time) (int64, error) {
+	args := m.Called(ctx, scheduleID, scheduledFor, periodFrom, periodTo)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReportScheduleRepository) ClaimDueRuns(ctx context.Context, now time.Time, batchSize int, lease time.Duration) ([]model.ReportRun, error) {
+	args := m.Called(ctx, now, batchSize, lease)
+	return args.Get(0).([]model.ReportRun), args.Error(1)
+}
+
+func (m *MockReportScheduleRepository) SetRunFile(ctx context.Context, id int64, fileName string, size int64) error {
+	args := m.Called(ctx, id, fileName, size)
+	return args.Error(0)
+}
+
+func (m *MockReportScheduleRepository) MarkRunSent(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockReportScheduleRepository) MarkRunFailed(ctx context.Context, id int64, errMsg string, nextAttemptAt time.Time, failed bool) error {
+	args := m.Called(ctx, id, errMsg, nextAttemptAt, failed)
+	return args.Error(0)
+}
+
+func (m *MockReportScheduleRepository) RetryRun(ctx context.Context, id int64, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockReportScheduleRepository) GetRunByID(ctx context.Context, id int64) (*model.ReportRun, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ReportRun), args.Error(1)
+}
+
+func (m *MockReportScheduleRepository) ListRuns(ctx context.Context, scheduleID, limit int) ([]model.ReportRun, error) {
+	args := m.Called(ctx, scheduleID, limit)
+	return args.Get(0).([]model.ReportRun), args.Error(1)
+}
+
+type MockStaffShiftRepository struct {
+	mock.Mock
+}
+
+func (m *MockStaffShiftRepository) RecordActivity(ctx context.Context, userID, counterID int, now, staleBefore time.Time) error {
+	args := m.Called(ctx, userID, counterID, now, staleBefore)
+	return args.Error(0)
+}
+
+func (m *MockStaffShiftRepository) EndShift(ctx context.Context, userID int, now, staleBefore time.Time) error {
+	args := m.Called(ctx, userID, now, staleBefore)
+	return args.Error(0)
+}
+
+func (m *MockStaffShiftRepository) StartPause(ctx context.Context, userID int, now time.Time) error {
+	args := m.Called(ctx, userID, now)
+	return args.Error(0)
+}
+
+func (m *MockStaffShiftRepository) EndPause(ctx context.Context, userID int, now time.Time) error {
+	args := m.Called(ctx, userID, now)
+	return args.Error(0)
+}
+
+func (m *MockStaffShiftRepository) Stats(ctx context.Context, filter model.StaffPerformanceFilter, now, staleBefore time.Time) ([]model.StaffShiftStats, error) {
+	args := m.Called(ctx, filter, now, staleBefore)
+	return args.Get(0).([]model.StaffShiftStats), args.Error(1)
+}
+
+type MockSLARepository struct {
+	mock.Mock
+}
+
+func (m *MockSLARepository) LiveTickets(ctx context.Context, warnFraction float64, categoryIDs []int) ([]model.SLATicket, error) {
+	args := m.Called(ctx, warnFraction, categoryIDs)
+	return args.Get(0).([]model.SLATicket), args.Error(1)
+}
+
+func (m *MockSLARepository) RecordBreaches(ctx context.Context) ([]model.SLABreach, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.SLABreach), args.Error(1)
+}
+
+type MockQueueAlertRepository struct {
+	mock.Mock
+}
+
+func (m *MockQueueAlertRepository) ListRules(ctx context.Context) ([]model.AlertRule, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.AlertRule), args.Error(1)
+}
+
+func (m *MockQueueAlertRepository) ListActiveRules(ctx context.Context) ([]model.AlertRule, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.AlertRule), args.Error(1)
+}
+
+func (m *MockQueueAlertRepository) GetRuleByID(ctx context.Context, id int) (*model.AlertRule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AlertRule), args.Error(1)
+}
+
+func (m *MockQueueAlertRepository) CreateRule(ctx context.Context, rule *model.AlertRule) (*model.AlertRule, error) {
+	args := m.Called(ctx, rule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AlertRule), args.Error(1)
+}
+
+func (m *MockQueueAlertRepository) UpdateRule(ctx context.Context, rule *model.AlertRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockQueueAlertRepository) DeleteRule(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockQueueAlertRepository) QueueStats(ctx context.Context) ([]model.QueueStat, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.QueueStat), args.Error(1)
+}
+
+func (m *MockQueueAlertRepository) RecordAlert(ctx context.Context, alert *model.QueueAlert, cooldownMinutes int) (*model.QueueAlert, error) {
+	args := m.Called(ctx, alert, cooldownMinutes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.QueueAlert), args.Error(1)
+}
+
+func (m *MockQueueAlertRepository) MarkAlertEmailed(ctx context.Context, id int, errMsg string) error {
+	args := m.Called(ctx, id, errMsg)
+	return args.Error(0)
+}
+
+func (m *MockQueueAlertRepository) ListAlerts(ctx context.Context, ruleID, limit int) ([]model.QueueAlert, error) {
+	args := m.Called(ctx, ruleID, limit)
+	return args.Get(0).([]model.QueueAlert), args.Error(1)
+}
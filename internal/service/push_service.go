@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+	"tenangantri/internal/webpush"
+)
+
+// PushSender delivers a single Web Push message
+type PushSender interface {
+	PublicKey() string
+	// CheckEndpoint returns webpush.ErrEndpointNotAllowed for an endpoint the sender refuses to send to
+	CheckEndpoint(ctx context.Context, endpoint string) error
+	Send(ctx context.Context, sub webpush.Subscription, payload []byte, opts webpush.Options) error
+}
+
+// PushService handles Web Push subscriptions for tracked tickets
+type PushService struct {
+	pushRepo    repository.PushSubscriptionRepository
+	ticketRepo  repository.TicketRepository
+	counterRepo repository.CounterRepository
+	sender      PushSender
+	cfg         *config.WebPushConfig
+}
+
+func NewPushService(pushRepo repository.PushSubscriptionRepository,
+	ticketRepo repository.TicketRepository,
+	counterRepo repository.CounterRepository,
+	sender PushSender,
+	cfg *config.WebPushConfig) *PushService {
+	return &PushService{
+		pushRepo:    pushRepo,
+		ticketRepo:  ticketRepo,
+		counterRepo: counterRepo,
+		sender:      sender,
+		cfg:         cfg,
+	}
+}
+
+// PublicKey returns the VAPID application server key for the browser
+func (s *PushService) PublicKey() string {
+	return s.sender.PublicKey()
+}
+
+// Subscribe attaches a browser subscription to a ticket; it expires at the end of the ticket's queue day
+func (s *PushService) Subscribe(ctx context.Context, req *dto.PushSubscribeRequest) error {
+	ticket, err := s.ticketRepo.GetByTicketNumber(ctx, req.TicketNumber)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "Subscribe").Str("ticket_number", req.TicketNumber).Msg("Failed to load ticket")
+		return fmt.Errorf("ticket not found")
+	}
+
+	if ticket.Status != "waiting" && ticket.Status != "serving" {
+		return fmt.Errorf("ticket is no longer in the queue")
+	}
+
+	if err := s.sender.CheckEndpoint(ctx, req.Subscription.Endpoint); err != nil {
+		return err
+	}
+
+	queueDate := ticket.QueueDate
+	expiresAt := time.Date(queueDate.Year(), queueDate.Month(), queueDate.Day(), 23, 59, 59, 0, time.Local)
+
+	_, err = s.pushRepo.Upsert(ctx, &model.PushSubscription{
+		TicketID:  ticket.ID,
+		Endpoint:  req.Subscription.Endpoint,
+		P256dh:    req.Subscription.Keys.P256dh,
+		Auth:      req.Subscription.Keys.Auth,
+		ExpiresAt: expiresAt,
+	})
+	return err
+}
+
+// Unsubscribe removes a browser subscription from a ticket
+func (s *PushService) Unsubscribe(ctx context.Context, ticketNumber, endpoint string) error {
+	ticket, err := s.ticketRepo.GetByTicketNumber(ctx, ticketNumber)
+	if err != nil {
+		return fmt.Errorf("ticket not found")
+	}
+	return s.pushRepo.DeleteByTicketAndEndpoint(ctx, ticket.ID, endpoint)
+}
+
+// NotifyTicketCalled pushes a "your turn" notification for the called ticket, then
+// warns the tickets that have moved close to the front of the same category
+func (s *PushService) NotifyTicketCalled(ctx context.Context, ticket *model.Ticket) {
+	if ticket == nil {
+		return
+	}
+
+	counterLabel := ""
+	if ticket.CounterID.Valid {
+		counter, err := s.counterRepo.GetByID(ctx, int(ticket.CounterID.Int64))
+		if err == nil && counter != nil {
+			counterLabel = counter.Number
+		}
+	}
+
+	subs, err := s.pushRepo.ListByTicketID(ctx, ticket.ID)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "NotifyTicketCalled").Msg("Failed to load push subscriptions")
+		return
+	}
+
+	body := fmt.Sprintf("Tiket %s, silakan menuju loket", ticket.TicketNumber)
+	if counterLabel != "" {
+		body = fmt.Sprintf("Tiket %s, silakan menuju Loket %s", ticket.TicketNumber, counterLabel)
+	}
+
+	message := dto.PushMessage{
+		Title: "Giliran Anda!",
+		Body:  body,
+		URL:   "/track/?ticket=" + ticket.TicketNumber,
+		Tag:   "ticket-" + ticket.TicketNumber,
+	}
+
+	for _, sub := range subs {
+		if err := s.send(ctx, sub, message, "high"); err == nil {
+			_ = s.pushRepo.MarkCalledNotified(ctx, sub.ID)
+		}
+	}
+
+	if ticket.CategoryID.Valid {
+		s.notifyNearlyUp(ctx, int(ticket.CategoryID.Int64))
+	}
+}
+
+// notifyNearlyUp sends a one-time heads-up to waiting tickets with only a few people ahead
+func (s *PushService) notifyNearlyUp(ctx context.Context, categoryID int) {
+	ahead := s.cfg.NearlyUpAhead
+	if ahead <= 0 {
+		return
+	}
+
+	waiting, err := s.ticketRepo.GetWaitingPreviewByCategories(ctx, []int{categoryID}, ahead)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "notifyNearlyUp").Msg("Failed to load waiting tickets")
+		return
+	}
+
+	positions := make(map[int]int, len(waiting))
+	ticketNumbers := make(map[int]string, len(waiting))
+	ticketIDs := make([]int, 0, len(waiting))
+	for i, t := range waiting {
+		positions[t.ID] = i
+		ticketNumbers[t.ID] = t.TicketNumber
+		ticketIDs = append(ticketIDs, t.ID)
+	}
+
+	subs, err := s.pushRepo.ListNearlyUpPending(ctx, ticketIDs)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "notifyNearlyUp").Msg("Failed to load push subscriptions")
+		return
+	}
+
+	for _, sub := range subs {
+		number := ticketNumbers[sub.TicketID]
+		message := dto.PushMessage{
+			Title: "Hampir giliran Anda",
+			Body:  fmt.Sprintf("Tiket %s: %d antrian lagi sebelum Anda dipanggil", number, positions[sub.TicketID]),
+			URL:   "/track/?ticket=" + number,
+			Tag:   "ticket-" + number,
+		}
+		if err := s.send(ctx, sub, message, "normal"); err == nil {
+			_ = s.pushRepo.MarkNearlyUpNotified(ctx, sub.ID)
+		}
+	}
+}
+
+func (s *PushService) send(ctx context.Context, sub model.PushSubscription, message dto.PushMessage, urgency string) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	err = s.sender.Send(ctx, webpush.Subscription{
+		Endpoint: sub.Endpoint,
+		P256dh:   sub.P256dh,
+		Auth:     sub.Auth,
+	}, payload, webpush.Options{TTL: s.cfg.TTL, Urgency: urgency})
+
+	if errors.Is(err, webpush.ErrSubscriptionGone) || errors.Is(err, webpush.ErrEndpointNotAllowed) {
+		_ = s.pushRepo.DeleteByID(ctx, sub.ID)
+		return err
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("layer", "service").Int("subscription_id", sub.ID).Msg("Failed to send push notification")
+	}
+	return err
+}
+
+// PurgeExpired deletes subscriptions whose ticket has left the queue or whose day has ended
+func (s *PushService) PurgeExpired(ctx context.Context) (int, error) {
+	return s.pushRepo.DeleteExpired(ctx)
+}
+
+// RunPurger periodically removes expired subscriptions until ctx is cancelled
+func (s *PushService) RunPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.PurgeExpired(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to purge expired push subscriptions")
+				continue
+			}
+			if count > 0 {
+				log.Info().Int("count", count).Msg("Purged expired push subscriptions")
+			}
+		}
+	}
+}
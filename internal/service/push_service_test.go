@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+	"tenangantri/internal/webpush"
+)
+
+type fakePushSender struct {
+	sent map[string]dto.PushMessage
+	gone map[string]bool
+}
+
+func newFakePushSender() *fakePushSender {
+	return &fakePushSender{sent: map[string]dto.PushMessage{}, gone: map[string]bool{}}
+}
+
+func (f *fakePushSender) PublicKey() string { return "test-key" }
+
+func (f *fakePushSender) CheckEndpoint(ctx context.Context, endpoint string) error {
+	if !strings.HasPrefix(endpoint, "https://push.example/") {
+		return webpush.ErrEndpointNotAllowed
+	}
+	return nil
+}
+
+func (f *fakePushSender) Send(ctx context.Context, sub webpush.Subscription, payload []byte, opts webpush.Options) error {
+	if f.gone[sub.Endpoint] {
+		return webpush.ErrSubscriptionGone
+	}
+	var msg dto.PushMessage
+	_ = json.Unmarshal(payload, &msg)
+	f.sent[sub.Endpoint] = msg
+	return nil
+}
+
+func TestPushService_Subscribe(t *testing.T) {
+	mockPushRepo := new(MockPushSubscriptionRepository)
+	mockTicketRepo := new(MockTicketRepository)
+	mockCounterRepo := new(MockCounterRepository)
+
+	service := NewPushService(mockPushRepo, mockTicketRepo, mockCounterRepo, newFakePushSender(), &config.WebPushConfig{NearlyUpAhead: 3})
+
+	ctx := context.Background()
+	queueDate := time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local)
+
+	mockTicketRepo.On("GetByTicketNumber", ctx, "A001").Return(&model.Ticket{ID: 1, TicketNumber: "A001", Status: "waiting", QueueDate: queueDate}, nil)
+	mockPushRepo.On("Upsert", ctx, mock.MatchedBy(func(sub *model.PushSubscription) bool {
+		return sub.TicketID == 1 && sub.Endpoint == "https://push.example/1" && sub.ExpiresAt.Equal(queueDate.Add(24*time.Hour-time.Second))
+	})).Return(&model.PushSubscription{ID: 1}, nil)
+
+	err := service.Subscribe(ctx, &dto.PushSubscribeRequest{
+		TicketNumber: "A001",
+		Subscription: dto.PushSubscription{
+			Endpoint: "https://push.example/1",
+			Keys:     dto.PushSubscriptionKeys{P256dh: "p", Auth: "a"},
+		},
+	})
+
+	assert.NoError(t, err)
+	mockPushRepo.AssertExpectations(t)
+}
+
+func TestPushService_Subscribe_LocalEndpoint(t *testing.T) {
+	mockPushRepo := new(MockPushSubscriptionRepository)
+	mockTicketRepo := new(MockTicketRepository)
+
+	service := NewPushService(mockPushRepo, mockTicketRepo, new(MockCounterRepository), newFakePushSender(), &config.WebPushConfig{})
+
+	ctx := context.Background()
+	mockTicketRepo.On("GetByTicketNumber", ctx, "A001").Return(&model.Ticket{ID: 1, Status: "waiting"}, nil)
+
+	err := service.Subscribe(ctx, &dto.PushSubscribeRequest{
+		TicketNumber: "A001",
+		Subscription: dto.PushSubscription{
+			Endpoint: "https://169.254.169.254/latest/meta-data",
+			Keys:     dto.PushSubscriptionKeys{P256dh: "p", Auth: "a"},
+		},
+	})
+
+	assert.ErrorIs(t, err, webpush.ErrEndpointNotAllowed)
+	mockPushRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestPushService_Subscribe_TicketDone(t *testing.T) {
+	mockPushRepo := new(MockPushSubscriptionRepository)
+	mockTicketRepo := new(MockTicketRepository)
+	mockCounterRepo := new(MockCounterRepository)
+
+	service := NewPushService(mockPushRepo, mockTicketRepo, mockCounterRepo, newFakePushSender(), &config.WebPushConfig{})
+
+	ctx := context.Background()
+	mockTicketRepo.On("GetByTicketNumber", ctx, "A001").Return(&model.Ticket{ID: 1, Status: "completed"}, nil)
+
+	err := service.Subscribe(ctx, &dto.PushSubscribeRequest{TicketNumber: "A001"})
+
+	assert.Error(t, err)
+	mockPushRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestPushService_NotifyTicketCalled(t *testing.T) {
+	mockPushRepo := new(MockPushSubscriptionRepository)
+	mockTicketRepo := new(MockTicketRepository)
+	mockCounterRepo := new(MockCounterRepository)
+	sender := newFakePushSender()
+	sender.gone["https://push.example/stale"] = true
+
+	service := NewPushService(mockPushRepo, mockTicketRepo, mockCounterRepo, sender, &config.WebPushConfig{NearlyUpAhead: 2})
+
+	ctx := context.Background()
+	ticket := &model.Ticket{
+		ID:           10,
+		TicketNumber: "A010",
+		CounterID:    sql.NullInt64{Int64: 3, Valid: true},
+		CategoryID:   sql.NullInt64{Int64: 1, Valid: true},
+	}
+
+	mockCounterRepo.On("GetByID", ctx, 3).Return(&model.Counter{ID: 3, Number: "3"}, nil)
+	mockPushRepo.On("ListByTicketID", ctx, 10).Return([]model.PushSubscription{
+		{ID: 1, TicketID: 10, Endpoint: "https://push.example/called"},
+		{ID: 2, TicketID: 10, Endpoint: "https://push.example/stale"},
+	}, nil)
+	mockPushRepo.On("MarkCalledNotified", ctx, 1).Return(nil)
+	mockPushRepo.On("DeleteByID", ctx, 2).Return(nil)
+
+	mockTicketRepo.On("GetWaitingPreviewByCategories", ctx, []int{1}, 2).Return([]model.Ticket{
+		{ID: 11, TicketNumber: "A011"},
+		{ID: 12, TicketNumber: "A012"},
+	}, nil)
+	mockPushRepo.On("ListNearlyUpPending", ctx, []int{11, 12}).Return([]model.PushSubscription{
+		{ID: 5, TicketID: 12, Endpoint: "https://push.example/soon"},
+	}, nil)
+	mockPushRepo.On("MarkNearlyUpNotified", ctx, 5).Return(nil)
+
+	service.NotifyTicketCalled(ctx, ticket)
+
+	assert.Equal(t, "Tiket A010, silakan menuju Loket 3", sender.sent["https://push.example/called"].Body)
+	assert.Equal(t, "/track/?ticket=A012", sender.sent["https://push.example/soon"].URL)
+	assert.Contains(t, sender.sent["https://push.example/soon"].Body, "1 antrian lagi")
+	mockPushRepo.AssertExpectations(t)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/event"
+	"tenangantri/internal/mail"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+)
+
+// defaultAlertCooldown is how long a rule stays quiet after firing when a request sets no cooldown
+const defaultAlertCooldown = 15
+
+// QueueAlertService manages queue alert rules and checks them against the live queue
+type QueueAlertService struct {
+	alertRepo    repository.QueueAlertRepository
+	categoryRepo repository.CategoryRepository
+	mailer       ReportMailer
+	events       event.Publisher
+	cfg          *config.AlertConfig
+}
+
+func NewQueueAlertService(
+	alertRepo repository.QueueAlertRepository,
+	categoryRepo repository.CategoryRepository,
+	mailer ReportMailer,
+	events event.Publisher,
+	cfg *config.AlertConfig) *QueueAlertService {
+	return &QueueAlertService{
+		alertRepo:    alertRepo,
+		categoryRepo: categoryRepo,
+		mailer:       mailer,
+		events:       events,
+		cfg:          cfg,
+	}
+}
+
+// ListRules returns all alert rules
+func (s *QueueAlertService) ListRules(ctx context.Context) ([]model.AlertRule, error) {
+	return s.alertRepo.ListRules(ctx)
+}
+
+// GetRule returns an alert rule by ID
+func (s *QueueAlertService) GetRule(ctx context.Context, id int) (*model.AlertRule, error) {
+	rule, err := s.alertRepo.GetRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("alert rule not found")
+	}
+	return rule, nil
+}
+
+// CreateRule creates an alert rule
+func (s *QueueAlertService) CreateRule(ctx context.Context, req *dto.AlertRuleRequest) (*model.AlertRule, error) {
+	rule := &model.AlertRule{IsActive: true, CooldownMinutes: defaultAlertCooldown}
+	if err := applyAlertRule(rule, req); err != nil {
+		return nil, err
+	}
+	return s.alertRepo.CreateRule(ctx, rule)
+}
+
+// UpdateRule updates an alert rule; a cooldown already running is kept
+func (s *QueueAlertService) UpdateRule(ctx context.Context, id int, req *dto.AlertRuleRequest) (*model.AlertRule, error) {
+	rule, err := s.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyAlertRule(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.alertRepo.UpdateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule deletes an alert rule; its alerts stay in the history
+func (s *QueueAlertService) DeleteRule(ctx context.Context, id int) error {
+	return s.alertRepo.DeleteRule(ctx, id)
+}
+
+// ListAlerts returns the latest alerts, of one rule when ruleID is not 0
+func (s *QueueAlertService) ListAlerts(ctx context.Context, ruleID, limit int) ([]model.QueueAlert, error) {
+	return s.alertRepo.ListAlerts(ctx, ruleID, limit)
+}
+
+// ListCategories returns the categories a rule can watch
+func (s *QueueAlertService) ListCategories(ctx context.Context) ([]model.Category, error) {
+	return s.categoryRepo.List(ctx, false, false)
+}
+
+// applyAlertRule validates req and copies it onto rule
+func applyAlertRule(rule *model.AlertRule, req *dto.AlertRuleRequest) error {
+	if !slices.Contains(model.AlertKinds, req.Kind) {
+		return fmt.Errorf("unknown alert kind: %s", req.Kind)
+	}
+	threshold := req.Threshold
+	if req.Kind == model.AlertKindNoOpenCounter {
+		threshold = 0
+	} else if threshold <= 0 {
+		return fmt.Errorf("threshold must be greater than 0")
+	}
+	recipients, err := parseRecipients(req.EmailRecipients)
+	if err != nil {
+		return err
+	}
+
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.Kind = req.Kind
+	rule.CategoryID = sql.NullInt64{Int64: int64(req.CategoryID), Valid: req.CategoryID != 0}
+	rule.Threshold = threshold
+	if req.CooldownMinutes != nil {
+		rule.CooldownMinutes = *req.CooldownMinutes
+	}
+	rule.EmailRecipients = recipients
+	rule.NotifyWebhook = req.NotifyWebhook
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// RunMonitor checks the alert rules against the live queue until ctx is cancelled
+func (s *QueueAlertService) RunMonitor(ctx context.Context) {
+	if s.cfg.CheckInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.CheckRules(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to check queue alert rules")
+				continue
+			}
+			if count > 0 {
+				log.Warn().Int("count", count).Msg("Queue alerts raised")
+			}
+		}
+	}
+}
+
+// CheckRules evaluates every active rule against the live queue of its categories and
+// raises an alert for each rule and category past its threshold and out of its cooldown
+func (s *QueueAlertService) CheckRules(ctx context.Context) (int, error) {
+	rules, err := s.alertRepo.ListActiveRules(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(rules) == 0 {
+		return 0, nil
+	}
+	stats, err := s.alertRepo.QueueStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	raised := 0
+	for _, rule := range rules {
+		for _, stat := range stats {
+			if rule.CategoryID.Valid && int(rule.CategoryID.Int64) != stat.CategoryID {
+				continue
+			}
+			value, fired := evaluateAlertRule(rule, stat)
+			if !fired {
+				continue
+			}
+
+			alert, err := s.alertRepo.RecordAlert(ctx, &model.QueueAlert{
+				RuleID:        sql.NullInt64{Int64: int64(rule.ID), Valid: true},
+				RuleName:      rule.Name,
+				Kind:          rule.Kind,
+				CategoryID:    sql.NullInt64{Int64: int64(stat.CategoryID), Valid: true},
+				CategoryName:  stat.CategoryName,
+				Value:         value,
+				Threshold:     rule.Threshold,
+				Message:       alertMessage(rule.Kind, stat.CategoryName, value, rule.Threshold),
+				NotifyWebhook: rule.NotifyWebhook,
+			}, rule.CooldownMinutes)
+			if err != nil {
+				log.Error().Err(err).Str("layer", "service").Str("func", "CheckRules").Int("rule_id", rule.ID).Msg("Failed to record queue alert")
+				continue
+			}
+			if alert == nil {
+				continue
+			}
+
+			raised++
+			s.events.Publish(ctx, event.QueueAlertRaised{Alert: alert})
+			if len(rule.EmailRecipients) > 0 {
+				s.email(ctx, alert, rule.EmailRecipients)
+			}
+		}
+	}
+	return raised, nil
+}
+
+// evaluateAlertRule measures stat in the unit of rule's threshold and reports whether it is past it
+func evaluateAlertRule(rule model.AlertRule, stat model.QueueStat) (int, bool) {
+	switch rule.Kind {
+	case model.AlertKindWaitingCount:
+		return stat.Waiting, stat.Waiting > rule.Threshold
+	case model.AlertKindOldestWait:
+		return stat.OldestWaitSeconds / 60, stat.Waiting > 0 && stat.OldestWaitSeconds > rule.Threshold*60
+	case model.AlertKindNoOpenCounter:
+		return stat.Waiting, stat.Waiting > 0 && stat.OpenCounters == 0
+	}
+	return 0, false
+}
+
+// alertMessage describes an alert for the people it is shown or emailed to
+func alertMessage(kind, categoryName string, value, threshold int) string {
+	switch kind {
+	case model.AlertKindWaitingCount:
+		return fmt.Sprintf("%d tiket menunggu di %s (batas %d)", value, categoryName, threshold)
+	case model.AlertKindOldestWait:
+		return fmt.Sprintf("Tiket terlama di %s sudah menunggu %d menit (batas %d menit)", categoryName, value, threshold)
+	case model.AlertKindNoOpenCounter:
+		return fmt.Sprintf("%d tiket menunggu di %s tanpa loket yang buka", value, categoryName)
+	}
+	return categoryName
+}
+
+// email sends an alert to its rule's recipients and records the outcome in the history
+func (s *QueueAlertService) email(ctx context.Context, alert *model.QueueAlert, recipients []string) {
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s.\n\n", alert.Message)
+	fmt.Fprintf(&body, "Aturan \"%s\" terpicu pada %s.\n\n", alert.RuleName, alert.TriggeredAt.Format("02/01/2006 15:04"))
+	body.WriteString("Email ini dikirim otomatis oleh TenangAntri.\n")
+
+	errMsg := ""
+	err := s.mailer.Send(ctx, &mail.Message{
+		To:      recipients,
+		Subject: fmt.Sprintf("Peringatan antrian: %s (%s)", alert.RuleName, alert.CategoryName),
+		Body:    body.String(),
+	})
+	if err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "email").Int("alert_id", alert.ID).Msg("Failed to email queue alert")
+		errMsg = err.Error()
+	}
+	if err := s.alertRepo.MarkAlertEmailed(ctx, alert.ID, errMsg); err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "email").Int("alert_id", alert.ID).Msg("Failed to record queue alert email")
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/event"
+	"tenangantri/internal/model"
+)
+
+// alertFor matches the alert a rule raises for a category
+func alertFor(ruleID, categoryID int) interface{} {
+	return mock.MatchedBy(func(alert *model.QueueAlert) bool {
+		return alert.RuleID.Int64 == int64(ruleID) && alert.CategoryID.Int64 == int64(categoryID)
+	})
+}
+
+func TestQueueAlertService_CheckRules(t *testing.T) {
+	mockAlertRepo := new(MockQueueAlertRepository)
+	mailer := &fakeMailer{}
+
+	bus := event.NewBus()
+	var raised []event.QueueAlertRaised
+	event.On(bus, func(ctx context.Context, e event.QueueAlertRaised) {
+		raised = append(raised, e)
+	})
+
+	service := NewQueueAlertService(mockAlertRepo, new(MockCategoryRepository), mailer, bus, &config.AlertConfig{})
+
+	ctx := context.Background()
+	mockAlertRepo.On("ListActiveRules", ctx).Return([]model.AlertRule{
+		{ID: 1, Name: "Antrian panjang", Kind: model.AlertKindWaitingCount, Threshold: 5, CooldownMinutes: 15,
+			EmailRecipients: []string{"supervisor@example.com"}},
+		{ID: 2, Name: "Menunggu lama", Kind: model.AlertKindOldestWait, CategoryID: sql.NullInt64{Int64: 2, Valid: true},
+			Threshold: 10, CooldownMinutes: 30},
+		{ID: 3, Name: "Loket tutup", Kind: model.AlertKindNoOpenCounter, CooldownMinutes: 5},
+	}, nil)
+	mockAlertRepo.On("QueueStats", ctx).Return([]model.QueueStat{
+		{CategoryID: 1, CategoryName: "Umum", Waiting: 6, OldestWaitSeconds: 300, OpenCounters: 1},
+		{CategoryID: 2, CategoryName: "Prioritas", Waiting: 2, OldestWaitSeconds: 900, OpenCounters: 0},
+		// Nobody waiting, so a closed counter is no reason to alert
+		{CategoryID: 3, CategoryName: "Teller", Waiting: 0, OpenCounters: 0},
+	}, nil)
+
+	mockAlertRepo.On("RecordAlert", ctx, alertFor(1, 1), 15).Return(&model.QueueAlert{
+		ID: 7, RuleName: "Antrian panjang", CategoryName: "Umum", Message: "6 tiket menunggu di Umum (batas 5)",
+	}, nil)
+	// Still cooling down from an earlier alert
+	mockAlertRepo.On("RecordAlert", ctx, alertFor(2, 2), 30).Return(nil, nil)
+	mockAlertRepo.On("RecordAlert", ctx, alertFor(3, 2), 5).Return(&model.QueueAlert{ID: 8}, nil)
+	mockAlertRepo.On("MarkAlertEmailed", ctx, 7, "").Return(nil)
+
+	count, err := service.CheckRules(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, raised, 2)
+	assert.Equal(t, 7, raised[0].Alert.ID)
+	assert.Equal(t, 8, raised[1].Alert.ID)
+
+	require.Len(t, mailer.messages, 1)
+	assert.Equal(t, []string{"supervisor@example.com"}, mailer.messages[0].To)
+	assert.Contains(t, mailer.messages[0].Body, "6 tiket menunggu di Umum (batas 5)")
+
+	mockAlertRepo.AssertExpectations(t)
+	mockAlertRepo.AssertNumberOfCalls(t, "RecordAlert", 3)
+	mockAlertRepo.AssertCalled(t, "RecordAlert", ctx, mock.MatchedBy(func(alert *model.QueueAlert) bool {
+		return alert.RuleID.Int64 == 2 && alert.Value == 15 && alert.Threshold == 10
+	}), 30)
+}
+
+func TestQueueAlertService_CheckRulesRecordsEmailFailure(t *testing.T) {
+	mockAlertRepo := new(MockQueueAlertRepository)
+	mailer := &fakeMailer{err: fmt.Errorf("SMTP is not configured")}
+	service := NewQueueAlertService(mockAlertRepo, new(MockCategoryRepository), mailer, event.NewBus(), &config.AlertConfig{})
+
+	ctx := context.Background()
+	mockAlertRepo.On("ListActiveRules", ctx).Return([]model.AlertRule{
+		{ID: 1, Kind: model.AlertKindWaitingCount, CategoryID: sql.NullInt64{Int64: 1, Valid: true}, Threshold: 5,
+			CooldownMinutes: 15, EmailRecipients: []string{"supervisor@example.com"}},
+	}, nil)
+	mockAlertRepo.On("QueueStats", ctx).Return([]model.QueueStat{
+		{CategoryID: 1, CategoryName: "Umum", Waiting: 9, OpenCounters: 2},
+		{CategoryID: 2, CategoryName: "Prioritas", Waiting: 20, OpenCounters: 1},
+	}, nil)
+	mockAlertRepo.On("RecordAlert", ctx, alertFor(1, 1), 15).Return(&model.QueueAlert{ID: 3}, nil)
+	mockAlertRepo.On("MarkAlertEmailed", ctx, 3, "SMTP is not configured").Return(nil)
+
+	count, err := service.CheckRules(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	mockAlertRepo.AssertExpectations(t)
+}
+
+func TestQueueAlertService_CreateRule(t *testing.T) {
+	mockAlertRepo := new(MockQueueAlertRepository)
+	service := NewQueueAlertService(mockAlertRepo, new(MockCategoryRepository), &fakeMailer{}, event.NewBus(), &config.AlertConfig{})
+	ctx := context.Background()
+
+	_, err := service.CreateRule(ctx, &dto.AlertRuleRequest{Name: "Antrian", Kind: model.AlertKindWaitingCount})
+	assert.EqualError(t, err, "threshold must be greater than 0")
+
+	_, err = service.CreateRule(ctx, &dto.AlertRuleRequest{Name: "Antrian", Kind: model.AlertKindWaitingCount, Threshold: 5,
+		EmailRecipients: []string{"bukan-email"}})
+	assert.EqualError(t, err, "invalid recipient: bukan-email")
+
+	var rule *model.AlertRule
+	mockAlertRepo.On("CreateRule", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		rule = args.Get(1).(*model.AlertRule)
+	}).Return(&model.AlertRule{ID: 4}, nil)
+
+	_, err = service.CreateRule(ctx, &dto.AlertRuleRequest{Name: " Loket tutup ", Kind: model.AlertKindNoOpenCounter, Threshold: 4,
+		CategoryID: 2, EmailRecipients: []string{"a@example.com", " ", "A Supervisor <a@example.com>"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Loket tutup", rule.Name)
+	// The threshold means nothing when no counter is open
+	assert.Equal(t, 0, rule.Threshold)
+	assert.Equal(t, defaultAlertCooldown, rule.CooldownMinutes)
+	assert.Equal(t, sql.NullInt64{Int64: 2, Valid: true}, rule.CategoryID)
+	assert.Equal(t, []string{"a@example.com"}, rule.EmailRecipients)
+	assert.True(t, rule.IsActive)
+}
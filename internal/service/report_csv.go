@@ -0,0 +1,115 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+)
+
+// csvTimeLayout is how CSV exports write timestamps, in local time
+const csvTimeLayout = "2006-01-02 15:04:05"
+
+// utf8BOM lets spreadsheets such as Excel recognise a CSV file as UTF-8
+const utf8BOM = "\ufeff"
+
+// csvDelimiters are the field separators a CSV export may use, by name
+var csvDelimiters = map[string]rune{
+	"comma":     ',',
+	"semicolon": ';',
+	"tab":       '\t',
+}
+
+// CSVFormat is how a CSV export is written
+type CSVFormat struct {
+	Delimiter rune
+	// BOM starts the file with a UTF-8 byte order mark
+	BOM bool
+}
+
+// CSVFormat resolves the format of a CSV export request, falling back to the configured one
+func (s *ReportService) CSVFormat(req *dto.CSVExportRequest) (CSVFormat, error) {
+	delimiter, encoding := req.Delimiter, req.Encoding
+	if delimiter == "" {
+		delimiter = s.cfg.CSVDelimiter
+	}
+	if encoding == "" {
+		encoding = s.cfg.CSVEncoding
+	}
+
+	var format CSVFormat
+	var ok bool
+	if format.Delimiter, ok = csvDelimiters[delimiter]; !ok {
+		return format, fmt.Errorf("invalid delimiter: %s", delimiter)
+	}
+	switch encoding {
+	case "utf-8":
+	case "utf-8-bom":
+		format.BOM = true
+	default:
+		return format, fmt.Errorf("invalid encoding: %s", encoding)
+	}
+	return format, nil
+}
+
+// WriteCSV writes a filter's tickets as CSV, one per line, oldest first. Tickets are
+// streamed from the database into w, so memory use does not grow with the range.
+func (s *ReportService) WriteCSV(ctx context.Context, w io.Writer, filter model.ReportFilter, format CSVFormat) error {
+	// Buffered so that a query failing before the first tickets arrive leaves w untouched
+	buf := bufio.NewWriter(w)
+	if format.BOM {
+		buf.WriteString(utf8BOM)
+	}
+	out := csv.NewWriter(buf)
+	out.Comma = format.Delimiter
+
+	if err := out.Write([]string{
+		"Ticket Number", "Category", "Counter", "Staff", "Status", "Priority",
+		"Created At", "Called At", "Completed At", "Wait Seconds", "Service Seconds", "Notes",
+	}); err != nil {
+		return err
+	}
+
+	record := make([]string, 12)
+	err := s.reportRepo.EachTicket(ctx, filter, func(ticket *model.ReportTicket) error {
+		record[0] = ticket.TicketNumber
+		record[1] = ticket.CategoryName
+		record[2] = ticket.CounterNumber
+		record[3] = ticket.StaffName
+		record[4] = ticket.Status
+		record[5] = strconv.Itoa(ticket.Priority)
+		record[6] = ticket.CreatedAt.Local().Format(csvTimeLayout)
+		record[7] = nullCSVTime(ticket.CalledAt.Time, ticket.CalledAt.Valid)
+		record[8] = nullCSVTime(ticket.CompletedAt.Time, ticket.CompletedAt.Valid)
+		record[9] = nullCSVInt(ticket.WaitTime.Int64, ticket.WaitTime.Valid)
+		record[10] = nullCSVInt(ticket.ServiceTime.Int64, ticket.ServiceTime.Valid)
+		record[11] = ticket.Notes.String
+		return out.Write(record)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write tickets: %w", err)
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+func nullCSVTime(t time.Time, valid bool) string {
+	if !valid {
+		return ""
+	}
+	return t.Local().Format(csvTimeLayout)
+}
+
+func nullCSVInt(n int64, valid bool) string {
+	if !valid {
+		return ""
+	}
+	return strconv.FormatInt(n, 10)
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/pdf"
+)
+
+// Report PDF layout, in points
+const (
+	reportMargin      = 40.0
+	reportFooter      = 24.0
+	reportRowHeight   = 16.0
+	reportChartHeight = 130.0
+)
+
+var (
+	reportInk    = pdf.RGB(31, 41, 55)
+	reportMuted  = pdf.RGB(107, 114, 128)
+	reportRule   = pdf.RGB(209, 213, 219)
+	reportShade  = pdf.RGB(243, 244, 246)
+	reportAccent = pdf.RGB(37, 99, 235)
+	reportWarm   = pdf.RGB(234, 88, 12)
+)
+
+// reportStatusLabels are the admin pages' names for ticket statuses
+var reportStatusLabels = map[string]string{
+	"waiting":   "Menunggu",
+	"serving":   "Dilayani",
+	"completed": "Selesai",
+	"no_show":   "Tidak Hadir",
+	"cancelled": "Dibatalkan",
+}
+
+func reportStatusLabel(status string) string {
+	if label, ok := reportStatusLabels[status]; ok {
+		return label
+	}
+	return status
+}
+
+// reportColumn is one column of a PDF table
+type reportColumn struct {
+	Title string
+	Width float64
+	Right bool
+}
+
+// reportPDF lays a report out top to bottom, starting new pages as it fills them
+type reportPDF struct {
+	doc  *pdf.Document
+	page *pdf.Page
+	y    float64
+}
+
+// renderReportPDF draws a report: header, KPIs, hourly charts, breakdowns and the ticket appendix
+func renderReportPDF(report *model.Report, filters []string, tickets []model.ReportTicket, now time.Time) *pdf.Document {
+	period := report.Filter.DateFrom.Format("02/01/2006") + " – " + report.Filter.DateTo.Format("02/01/2006")
+	r := &reportPDF{doc: pdf.New(pdf.A4Width, pdf.A4Height)}
+	r.doc.Title = "Laporan Antrian " + period
+	r.doc.CreatedAt = now
+	r.newPage()
+
+	r.y += 18
+	r.page.Text(reportMargin, r.y, pdf.HelveticaBold, 20, reportInk, "Laporan Antrian")
+	r.y += 18
+	r.page.Text(reportMargin, r.y, pdf.Helvetica, 11, reportInk, "Periode "+period)
+	r.y += 14
+	details := append([]string{"Dibuat " + now.Format("02/01/2006 15:04")}, filters...)
+	// A capped appendix is called out up front, so the report is not taken for a full listing
+	truncated := len(tickets) < report.Summary.TotalTickets
+	if truncated {
+		details = append(details, fmt.Sprintf("Lampiran: %d dari %d tiket", len(tickets), report.Summary.TotalTickets))
+	}
+	r.page.Text(reportMargin, r.y, pdf.Helvetica, 9, reportMuted, strings.Join(details, "  ·  "))
+	r.y += 10
+	r.page.Line(reportMargin, r.y, r.right(), r.y, 1, reportRule)
+	r.y += 16
+
+	r.kpis(report)
+
+	r.heading("Tiket per Jam")
+	hours := reportHourRange(report.Hourly)
+	if hours == nil {
+		r.note("Tidak ada tiket pada periode ini.")
+	} else {
+		labels := make([]string, len(hours))
+		issued := make([]float64, len(hours))
+		waits := make([]float64, len(hours))
+		for i, hour := range hours {
+			labels[i] = fmt.Sprintf("%02d", hour.Hour)
+			issued[i] = float64(hour.Tickets)
+			waits[i] = hour.AvgWaitSeconds / 60
+		}
+		r.barChart(labels, issued, 1, reportAccent, func(v float64) string { return strconv.Itoa(int(math.Round(v))) })
+		r.heading("Rata-rata Waktu Tunggu per Jam (menit)")
+		r.barChart(labels, waits, 0.1, reportWarm, func(v float64) string { return strconv.FormatFloat(v, 'f', 1, 64) })
+	}
+
+	r.heading("Per Kategori")
+	categoryRows := make([][]string, len(report.Categories))
+	for i, category := range report.Categories {
+		name := category.Name
+		if category.CategoryID == 0 {
+			name = "(kategori dihapus)"
+		} else if category.Prefix != "" {
+			name = category.Prefix + " – " + name
+		}
+		categoryRows[i] = []string{name, strconv.Itoa(category.Total), strconv.Itoa(category.Completed),
+			strconv.Itoa(category.NoShow), formatReportDuration(category.AvgWaitSeconds), formatReportDuration(category.AvgServiceSeconds),
+			formatReportSLA(category.SLAWaitMet, category.SLAWaitTickets), formatReportSLA(category.SLAServiceMet, category.SLAServiceTickets)}
+	}
+	r.table([]reportColumn{
+		{Title: "Kategori", Width: 135}, {Title: "Tiket", Width: 45, Right: true}, {Title: "Selesai", Width: 50, Right: true},
+		{Title: "Tidak Hadir", Width: 60, Right: true}, {Title: "Rata Tunggu", Width: 65, Right: true}, {Title: "Rata Layanan", Width: 70, Right: true},
+		{Title: "SLA Tunggu", Width: 45, Right: true}, {Title: "SLA Layanan", Width: 45, Right: true},
+	}, categoryRows, "Tidak ada tiket pada periode ini.")
+
+	r.heading("Per Loket")
+	counterRows := make([][]string, len(report.Counters))
+	for i, counter := range report.Counters {
+		counterRows[i] = []string{counter.Number, counter.Name, strconv.Itoa(counter.Total), strconv.Itoa(counter.Completed),
+			strconv.Itoa(counter.NoShow), formatReportDuration(counter.AvgServiceSeconds)}
+	}
+	r.table([]reportColumn{
+		{Title: "Loket", Width: 60}, {Title: "Nama", Width: 170}, {Title: "Tiket", Width: 55, Right: true},
+		{Title: "Selesai", Width: 60, Right: true}, {Title: "Tidak Hadir", Width: 65, Right: true}, {Title: "Rata Layanan", Width: 105, Right: true},
+	}, counterRows, "Belum ada tiket yang dipanggil pada periode ini.")
+
+	r.heading("Kepatuhan SLA per Hari")
+	dayRows := make([][]string, len(report.Days))
+	for i, day := range report.Days {
+		dayRows[i] = []string{day.Day.Format("02/01/2006"), strconv.Itoa(day.Total),
+			strconv.Itoa(day.SLAWaitTickets), formatReportSLA(day.SLAWaitMet, day.SLAWaitTickets),
+			strconv.Itoa(day.SLAServiceTickets), formatReportSLA(day.SLAServiceMet, day.SLAServiceTickets)}
+	}
+	r.table([]reportColumn{
+		{Title: "Tanggal", Width: 95}, {Title: "Tiket", Width: 60, Right: true},
+		{Title: "Diukur Tunggu", Width: 90, Right: true}, {Title: "SLA Tunggu", Width: 90, Right: true},
+		{Title: "Diukur Layanan", Width: 90, Right: true}, {Title: "SLA Layanan", Width: 90, Right: true},
+	}, dayRows, "Tidak ada tiket pada periode ini.")
+
+	r.newPage()
+	if truncated {
+		r.heading(fmt.Sprintf("Lampiran: Daftar Tiket, %d pertama dari %d", len(tickets), report.Summary.TotalTickets))
+		r.note(fmt.Sprintf("Menampilkan %d tiket pertama dari %d. Gunakan ekspor CSV atau Excel untuk daftar lengkap.",
+			len(tickets), report.Summary.TotalTickets))
+	} else {
+		r.heading("Lampiran: Daftar Tiket")
+	}
+	ticketRows := make([][]string, len(tickets))
+	for i, ticket := range tickets {
+		wait, service := "-", "-"
+		if ticket.WaitTime.Valid {
+			wait = formatReportDuration(float64(ticket.WaitTime.Int64))
+		}
+		if ticket.ServiceTime.Valid {
+			service = formatReportDuration(float64(ticket.ServiceTime.Int64))
+		}
+		ticketRows[i] = []string{ticket.CreatedAt.Format("02/01/2006 15:04"), ticket.TicketNumber, ticket.CategoryName,
+			ticket.CounterNumber, reportStatusLabel(ticket.Status), wait, service}
+	}
+	r.table([]reportColumn{
+		{Title: "Waktu", Width: 85}, {Title: "Nomor", Width: 55}, {Title: "Kategori", Width: 130}, {Title: "Loket", Width: 45},
+		{Title: "Status", Width: 70}, {Title: "Tunggu", Width: 65, Right: true}, {Title: "Layanan", Width: 65, Right: true},
+	}, ticketRows, "Tidak ada tiket pada periode ini.")
+
+	pages := r.doc.Pages()
+	for i, page := range pages {
+		y := r.doc.Height - reportMargin + 10
+		page.Text(reportMargin, y, pdf.Helvetica, 8, reportMuted, "Laporan Antrian "+period)
+		page.TextRight(r.right(), y, pdf.Helvetica, 8, reportMuted, fmt.Sprintf("Halaman %d dari %d", i+1, len(pages)))
+	}
+	return r.doc
+}
+
+func (r *reportPDF) newPage() {
+	r.page = r.doc.AddPage()
+	r.y = reportMargin
+}
+
+func (r *reportPDF) right() float64 {
+	return r.doc.Width - reportMargin
+}
+
+// fits starts a new page unless height more points fit on this one; it reports whether they did
+func (r *reportPDF) fits(height float64) bool {
+	if r.y+height <= r.doc.Height-reportMargin-reportFooter {
+		return true
+	}
+	r.newPage()
+	return false
+}
+
+// heading starts a section, keeping it together with at least its first lines
+func (r *reportPDF) heading(text string) {
+	r.fits(80)
+	r.y += 18
+	r.page.Text(reportMargin, r.y, pdf.HelveticaBold, 13, reportInk, text)
+	r.y += 10
+}
+
+func (r *reportPDF) note(text string) {
+	r.fits(reportRowHeight)
+	r.y += 12
+	r.page.Text(reportMargin, r.y, pdf.Helvetica, 9, reportMuted, text)
+	r.y += 8
+}
+
+// kpis draws the summary as two rows of four cards
+func (r *reportPDF) kpis(report *model.Report) {
+	summary := report.Summary
+	peak := "-"
+	busiest := 0
+	for _, hour := range report.Hourly {
+		if hour.Tickets > busiest {
+			busiest = hour.Tickets
+			peak = fmt.Sprintf("%02d:00", hour.Hour)
+		}
+	}
+	cards := [][2]string{
+		{"Total Tiket", strconv.Itoa(summary.TotalTickets)},
+		{"Selesai", strconv.Itoa(summary.Completed)},
+		{"Tidak Hadir", strconv.Itoa(summary.NoShow)},
+		{"Dibatalkan", strconv.Itoa(summary.Cancelled)},
+		{"Rata-rata Tunggu", formatReportDuration(summary.AvgWaitSeconds)},
+		{"Rata-rata Layanan", formatReportDuration(summary.AvgServiceSeconds)},
+		{"Tingkat Penyelesaian", strconv.FormatFloat(summary.CompletionRate, 'f', 1, 64) + "%"},
+		{"Jam Tersibuk", peak},
+	}
+
+	const gap, height = 8.0, 46.0
+	width := (r.right() - reportMargin - 3*gap) / 4
+	for i, card := range cards {
+		x := reportMargin + float64(i%4)*(width+gap)
+		y := r.y + float64(i/4)*(height+gap)
+		r.page.Rect(x, y, width, height, reportShade)
+		r.page.Text(x+8, y+16, pdf.Helvetica, 8, reportMuted, card[0])
+		r.page.Text(x+8, y+36, pdf.HelveticaBold, 16, reportInk, card[1])
+	}
+	r.y += 2*height + gap + 8
+}
+
+// barChart draws one bar per label with a value axis of four steps of at least minStep
+func (r *reportPDF) barChart(labels []string, values []float64, minStep float64, color pdf.Color, format func(float64) string) {
+	r.fits(reportChartHeight + 30)
+	top := r.y + 12
+	left := reportMargin + 30
+	width := r.right() - left
+	bottom := top + reportChartHeight
+
+	peak := 0.0
+	for _, value := range values {
+		peak = math.Max(peak, value)
+	}
+	step := math.Max(reportAxisStep(peak/4), minStep)
+	for i := 0; i <= 4; i++ {
+		y := bottom - float64(i)*reportChartHeight/4
+		r.page.Line(left, y, r.right(), y, 0.5, reportRule)
+		r.page.TextRight(left-4, y+3, pdf.Helvetica, 7, reportMuted, format(float64(i)*step))
+	}
+
+	slot := width / float64(len(values))
+	barWidth := slot * 0.7
+	for i, value := range values {
+		x := left + float64(i)*slot + (slot-barWidth)/2
+		height := value / (4 * step) * reportChartHeight
+		if height > 0 {
+			r.page.Rect(x, bottom-height, barWidth, height, color)
+			if barWidth >= 14 {
+				r.page.TextCenter(x+barWidth/2, bottom-height-3, pdf.Helvetica, 6.5, reportInk, format(value))
+			}
+		}
+		r.page.TextCenter(x+barWidth/2, bottom+10, pdf.Helvetica, 7, reportMuted, labels[i])
+	}
+	r.page.Line(left, bottom, r.right(), bottom, 0.8, reportMuted)
+	r.y = bottom + 18
+}
+
+// table draws rows under a header that repeats on every page the table runs onto
+func (r *reportPDF) table(columns []reportColumn, rows [][]string, empty string) {
+	if len(rows) == 0 {
+		r.note(empty)
+		return
+	}
+
+	header := func() {
+		r.page.Rect(reportMargin, r.y, r.right()-reportMargin, reportRowHeight, reportShade)
+		r.cells(columns, nil, pdf.HelveticaBold, reportInk)
+	}
+	r.fits(2 * reportRowHeight)
+	header()
+	for _, row := range rows {
+		if !r.fits(reportRowHeight) {
+			header()
+		}
+		r.cells(columns, row, pdf.Helvetica, reportInk)
+		r.page.Line(reportMargin, r.y, r.right(), r.y, 0.3, reportRule)
+	}
+	r.y += 6
+}
+
+// cells writes one table row, or the column titles when row is nil
+func (r *reportPDF) cells(columns []reportColumn, row []string, font pdf.Font, color pdf.Color) {
+	const size, pad = 8.0, 4.0
+	x := reportMargin
+	for i, column := range columns {
+		text := column.Title
+		if row != nil {
+			text = row[i]
+		}
+		text = pdf.Truncate(font, size, text, column.Width-2*pad)
+		if column.Right {
+			r.page.TextRight(x+column.Width-pad, r.y+11, font, size, color, text)
+		} else {
+			r.page.Text(x+pad, r.y+11, font, size, color, text)
+		}
+		x += column.Width
+	}
+	r.y += reportRowHeight
+}
+
+// reportHourRange returns the hours from the first to the last with tickets, or nil without any
+func reportHourRange(hourly []model.ReportHour) []model.ReportHour {
+	first, last := -1, -1
+	for i, hour := range hourly {
+		if hour.Tickets > 0 {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+	return hourly[first : last+1]
+}
+
+// reportAxisStep rounds a step up to 1, 2 or 5 times a power of ten
+func reportAxisStep(raw float64) float64 {
+	if raw <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, factor := range []float64{1, 2, 5, 10} {
+		if step := factor * magnitude; step >= raw {
+			return step
+		}
+	}
+	return 10 * magnitude
+}
+
+// formatReportSLA writes the share of measured tickets that met their target, or a dash
+// when none were measured
+func formatReportSLA(met, measured int) string {
+	if measured == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(float64(met)*100/float64(measured), 'f', 1, 64) + "%"
+}
+
+// formatReportDuration writes seconds as minutes and seconds, or hours and minutes
+func formatReportDuration(seconds float64) string {
+	total := int(math.Round(seconds))
+	if total >= 3600 {
+		return fmt.Sprintf("%dj %02dm", total/3600, total%3600/60)
+	}
+	return fmt.Sprintf("%dm %02ds", total/60, total%60)
+}
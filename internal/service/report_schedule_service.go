@@ -0,0 +1,457 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/cron"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/mail"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+	"tenangantri/internal/webhook"
+)
+
+// reportRunBatch is how many report runs the scheduler claims at once
+const reportRunBatch = 5
+
+// reportRunLease is how long a claimed run is kept from other instances while it renders and sends
+const reportRunLease = 10 * time.Minute
+
+// ReportMailer sends report emails
+type ReportMailer interface {
+	Send(ctx context.Context, msg *mail.Message) error
+}
+
+// ReportScheduleService manages report schedules and renders and emails their runs
+type ReportScheduleService struct {
+	scheduleRepo  repository.ReportScheduleRepository
+	reportService *ReportService
+	mailer        ReportMailer
+	cfg           *config.ReportConfig
+	wake          chan struct{}
+	now           func() time.Time
+}
+
+func NewReportScheduleService(
+	scheduleRepo repository.ReportScheduleRepository,
+	reportService *ReportService,
+	mailer ReportMailer,
+	cfg *config.ReportConfig) *ReportScheduleService {
+	return &ReportScheduleService{
+		scheduleRepo:  scheduleRepo,
+		reportService: reportService,
+		mailer:        mailer,
+		cfg:           cfg,
+		wake:          make(chan struct{}, 1),
+		now:           time.Now,
+	}
+}
+
+// ListSchedules returns all report schedules
+func (s *ReportScheduleService) ListSchedules(ctx context.Context) ([]model.ReportSchedule, error) {
+	return s.scheduleRepo.ListSchedules(ctx)
+}
+
+// GetSchedule returns a report schedule by ID
+func (s *ReportScheduleService) GetSchedule(ctx context.Context, id int) (*model.ReportSchedule, error) {
+	schedule, err := s.scheduleRepo.GetScheduleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, fmt.Errorf("report schedule not found")
+	}
+	return schedule, nil
+}
+
+// CreateSchedule creates a report schedule, due at the next time its cron expression fires
+func (s *ReportScheduleService) CreateSchedule(ctx context.Context, req *dto.ReportScheduleRequest) (*model.ReportSchedule, error) {
+	schedule := &model.ReportSchedule{IsActive: true}
+	if err := s.apply(schedule, req); err != nil {
+		return nil, err
+	}
+	return s.scheduleRepo.CreateSchedule(ctx, schedule)
+}
+
+// UpdateSchedule updates a report schedule; its next run follows the new cron expression
+func (s *ReportScheduleService) UpdateSchedule(ctx context.Context, id int, req *dto.ReportScheduleRequest) (*model.ReportSchedule, error) {
+	schedule, err := s.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(schedule, req); err != nil {
+		return nil, err
+	}
+	if err := s.scheduleRepo.UpdateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// DeleteSchedule deletes a report schedule and its run history
+func (s *ReportScheduleService) DeleteSchedule(ctx context.Context, id int) error {
+	return s.scheduleRepo.DeleteSchedule(ctx, id)
+}
+
+// apply validates req and copies it onto schedule
+func (s *ReportScheduleService) apply(schedule *model.ReportSchedule, req *dto.ReportScheduleRequest) error {
+	if !slices.Contains(model.ReportTypes, req.ReportType) {
+		return fmt.Errorf("unknown report type: %s", req.ReportType)
+	}
+	if !slices.Contains(model.ReportFormats, req.Format) {
+		return fmt.Errorf("unknown format: %s", req.Format)
+	}
+	expr := strings.TrimSpace(req.Cron)
+	cronSchedule, err := cron.Parse(expr)
+	if err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	next := cronSchedule.Next(s.now())
+	if next.IsZero() {
+		return fmt.Errorf("schedule never runs: %s", expr)
+	}
+
+	recipients, err := parseRecipients(req.Recipients)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return fmt.Errorf("add at least one recipient")
+	}
+
+	schedule.Name = strings.TrimSpace(req.Name)
+	schedule.ReportType = req.ReportType
+	schedule.Format = req.Format
+	schedule.CategoryID = sql.NullInt64{Int64: int64(req.CategoryID), Valid: req.CategoryID != 0}
+	schedule.CounterID = sql.NullInt64{Int64: int64(req.CounterID), Valid: req.CounterID != 0}
+	schedule.Status = req.Status
+	schedule.Recipients = recipients
+	schedule.Cron = expr
+	schedule.NextRunAt = sql.NullTime{Time: next, Valid: true}
+	if req.IsActive != nil {
+		schedule.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// parseRecipients validates email addresses and drops blanks and duplicates
+func parseRecipients(list []string) ([]string, error) {
+	recipients := make([]string, 0, len(list))
+	for _, recipient := range list {
+		recipient = strings.TrimSpace(recipient)
+		if recipient == "" {
+			continue
+		}
+		addr, err := netmail.ParseAddress(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient: %s", recipient)
+		}
+		if !slices.Contains(recipients, addr.Address) {
+			recipients = append(recipients, addr.Address)
+		}
+	}
+	return recipients, nil
+}
+
+// ListChoices returns the categories and counters a schedule's report can be filtered by
+func (s *ReportScheduleService) ListChoices(ctx context.Context) ([]model.Category, []model.Counter, error) {
+	categories, err := s.reportService.categoryRepo.List(ctx, false, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	counters, err := s.reportService.counterRepo.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return categories, counters, nil
+}
+
+// RunNow queues a run of a schedule for the period before today, whatever its timing
+func (s *ReportScheduleService) RunNow(ctx context.Context, id int) (int64, error) {
+	schedule, err := s.GetSchedule(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	now := s.now()
+	from, to := reportPeriod(schedule.ReportType, now)
+	runID, err := s.scheduleRepo.EnqueueRun(ctx, schedule.ID, now, from, to)
+	if err != nil {
+		return 0, err
+	}
+	s.Wake()
+	return runID, nil
+}
+
+// ListRuns returns the latest runs, of one schedule when scheduleID is not 0
+func (s *ReportScheduleService) ListRuns(ctx context.Context, scheduleID, limit int) ([]model.ReportRun, error) {
+	return s.scheduleRepo.ListRuns(ctx, scheduleID, limit)
+}
+
+// GetRun returns a report run by ID
+func (s *ReportScheduleService) GetRun(ctx context.Context, id int64) (*model.ReportRun, error) {
+	run, err := s.scheduleRepo.GetRunByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, fmt.Errorf("report run not found")
+	}
+	return run, nil
+}
+
+// RunFile is where a run's rendered report is kept
+func (s *ReportScheduleService) RunFile(run *model.ReportRun) string {
+	return filepath.Join(s.cfg.ScheduleDir, filepath.Base(run.FileName))
+}
+
+// RetryRun queues a run again, including failed ones
+func (s *ReportScheduleService) RetryRun(ctx context.Context, id int64) error {
+	if err := s.scheduleRepo.RetryRun(ctx, id, s.now()); err != nil {
+		return err
+	}
+	s.Wake()
+	return nil
+}
+
+// Wake asks the scheduler to run now instead of waiting for the next check
+func (s *ReportScheduleService) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// RunScheduler queues due schedules and delivers their runs until ctx is cancelled
+func (s *ReportScheduleService) RunScheduler(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.ScheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// RunOnce queues a run for every due schedule and delivers every run that is due
+func (s *ReportScheduleService) RunOnce(ctx context.Context) {
+	now := s.now()
+	schedules, err := s.scheduleRepo.ListDueSchedules(ctx, now)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "RunOnce").Msg("Failed to list due report schedules")
+		return
+	}
+	for _, schedule := range schedules {
+		s.enqueue(ctx, &schedule, now)
+	}
+
+	for ctx.Err() == nil {
+		runs, err := s.scheduleRepo.ClaimDueRuns(ctx, s.now(), reportRunBatch, reportRunLease)
+		if err != nil {
+			log.Error().Err(err).Str("layer", "service").Str("func", "RunOnce").Msg("Failed to claim due report runs")
+			return
+		}
+		if len(runs) == 0 {
+			return
+		}
+		for _, run := range runs {
+			s.deliver(ctx, &run)
+		}
+		if len(runs) < reportRunBatch {
+			return
+		}
+	}
+}
+
+// enqueue queues the due run of a schedule and moves it on to its next run after now.
+// Runs missed while the server was down are not caught up; only the latest is sent.
+func (s *ReportScheduleService) enqueue(ctx context.Context, schedule *model.ReportSchedule, now time.Time) {
+	scheduledFor := schedule.NextRunAt.Time
+	var next time.Time
+	if cronSchedule, err := cron.Parse(schedule.Cron); err == nil {
+		next = cronSchedule.Next(now)
+	}
+	if next.IsZero() {
+		// Never again; a far off run keeps the schedule from being due on every check
+		next = now.AddDate(100, 0, 0)
+	}
+
+	from, to := reportPeriod(schedule.ReportType, scheduledFor)
+	queued, err := s.scheduleRepo.EnqueueDueRun(ctx, schedule.ID, scheduledFor, next, from, to)
+	if err != nil {
+		return
+	}
+	if queued {
+		log.Info().Int("schedule_id", schedule.ID).Time("scheduled_for", scheduledFor).Time("next_run_at", next).Msg("Queued scheduled report")
+	}
+}
+
+// deliver renders a run's report, unless an earlier attempt already did, and emails it
+func (s *ReportScheduleService) deliver(ctx context.Context, run *model.ReportRun) {
+	err := s.render(ctx, run)
+	if err == nil {
+		err = s.send(ctx, run)
+	}
+	if err == nil {
+		if err := s.scheduleRepo.MarkRunSent(ctx, run.ID); err != nil {
+			log.Error().Err(err).Str("layer", "service").Int64("run_id", run.ID).Msg("Failed to mark report run sent")
+		}
+		return
+	}
+
+	failed := run.Attempts >= s.cfg.ScheduleMaxAttempts
+	nextAttemptAt := s.now().Add(webhook.Backoff(run.Attempts, s.cfg.ScheduleBackoffBase, s.cfg.ScheduleBackoffMax))
+
+	log.Warn().Err(err).Str("layer", "service").Int64("run_id", run.ID).Int("attempts", run.Attempts).Bool("failed", failed).Msg("Report delivery failed")
+
+	if err := s.scheduleRepo.MarkRunFailed(ctx, run.ID, err.Error(), nextAttemptAt, failed); err != nil {
+		log.Error().Err(err).Str("layer", "service").Int64("run_id", run.ID).Msg("Failed to record report delivery failure")
+	}
+}
+
+// render writes a run's report into the schedule directory and records its file
+func (s *ReportScheduleService) render(ctx context.Context, run *model.ReportRun) error {
+	if run.FileName != "" {
+		if _, err := os.Stat(s.RunFile(run)); err == nil {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(s.cfg.ScheduleDir, 0o755); err != nil {
+		return err
+	}
+
+	filter := model.ReportFilter{
+		DateFrom:   run.PeriodFrom,
+		DateTo:     run.PeriodTo,
+		CategoryID: int(run.CategoryID.Int64),
+		CounterID:  int(run.CounterID.Int64),
+		Status:     run.StatusFilter,
+	}
+	name := fmt.Sprintf("%d_laporan_%s_%s_%s.%s", run.ID, run.ReportType,
+		run.PeriodFrom.Format(reportDateLayout), run.PeriodTo.Format(reportDateLayout), run.Format)
+
+	// Written under a temporary name, so a file with the final name is always complete
+	tmp, err := os.CreateTemp(s.cfg.ScheduleDir, ".run-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	switch run.Format {
+	case model.ReportFormatPDF:
+		err = s.reportService.WritePDF(ctx, tmp, filter)
+	case model.ReportFormatXLSX:
+		err = s.reportService.WriteXLSX(ctx, tmp, filter)
+	case model.ReportFormatCSV:
+		var format CSVFormat
+		if format, err = s.reportService.CSVFormat(&dto.CSVExportRequest{}); err == nil {
+			err = s.reportService.WriteCSV(ctx, tmp, filter, format)
+		}
+	default:
+		err = fmt.Errorf("unknown format: %s", run.Format)
+	}
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("render report: %w", err)
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.cfg.ScheduleDir, name)); err != nil {
+		return err
+	}
+
+	run.FileName = name
+	run.FileSize = info.Size()
+	return s.scheduleRepo.SetRunFile(ctx, run.ID, run.FileName, run.FileSize)
+}
+
+// send emails a run's rendered report to its recipients
+func (s *ReportScheduleService) send(ctx context.Context, run *model.ReportRun) error {
+	file, err := os.Open(s.RunFile(run))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	period := run.PeriodFrom.Format("02/01/2006")
+	if !run.PeriodTo.Equal(run.PeriodFrom) {
+		period += " – " + run.PeriodTo.Format("02/01/2006")
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Laporan antrian %s \"%s\" untuk periode %s terlampir.\n\n", reportTypeLabel(run.ReportType), run.ScheduleName, period)
+	fmt.Fprintf(&body, "Unduh laporan ini kapan saja dari riwayat jadwal laporan:\n%s/admin/api/report-runs/%d/download\n\n", s.cfg.PublicURL, run.ID)
+	body.WriteString("Email ini dikirim otomatis oleh TenangAntri.\n")
+
+	return s.mailer.Send(ctx, &mail.Message{
+		To:      run.Recipients,
+		Subject: fmt.Sprintf("Laporan %s: %s (%s)", reportTypeLabel(run.ReportType), run.ScheduleName, period),
+		Body:    body.String(),
+		Attachments: []mail.Attachment{{
+			Name:        run.FileName[strings.IndexByte(run.FileName, '_')+1:],
+			ContentType: reportContentType(run.Format),
+			Content:     io.Reader(file),
+		}},
+	})
+}
+
+// reportPeriod is the range of days a report of reportType covers when it runs at t:
+// the day before, the seven days before or the calendar month before
+func reportPeriod(reportType string, t time.Time) (from, to time.Time) {
+	today := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch reportType {
+	case model.ReportTypeWeekly:
+		return today.AddDate(0, 0, -7), today.AddDate(0, 0, -1)
+	case model.ReportTypeMonthly:
+		first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		return first.AddDate(0, -1, 0), first.AddDate(0, 0, -1)
+	default:
+		yesterday := today.AddDate(0, 0, -1)
+		return yesterday, yesterday
+	}
+}
+
+func reportTypeLabel(reportType string) string {
+	switch reportType {
+	case model.ReportTypeWeekly:
+		return "mingguan"
+	case model.ReportTypeMonthly:
+		return "bulanan"
+	default:
+		return "harian"
+	}
+}
+
+func reportContentType(format string) string {
+	switch format {
+	case model.ReportFormatPDF:
+		return "application/pdf"
+	case model.ReportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv"
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/mail"
+	"tenangantri/internal/model"
+)
+
+// fakeMailer records the messages it is given, reading their attachments
+type fakeMailer struct {
+	messages    []*mail.Message
+	attachments []string
+	err         error
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg *mail.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.messages = append(m.messages, msg)
+	for _, attachment := range msg.Attachments {
+		content, err := io.ReadAll(attachment.Content)
+		if err != nil {
+			return err
+		}
+		m.attachments = append(m.attachments, string(content))
+	}
+	return nil
+}
+
+func newTestReportScheduleService(t *testing.T, mailer ReportMailer) (*ReportScheduleService, *MockReportScheduleRepository, *MockReportRepository) {
+	mockScheduleRepo := new(MockReportScheduleRepository)
+	mockReportRepo := new(MockReportRepository)
+	cfg := &config.ReportConfig{
+		CSVDelimiter:        "comma",
+		CSVEncoding:         "utf-8",
+		ScheduleDir:         t.TempDir(),
+		ScheduleMaxAttempts: 3,
+		ScheduleBackoffBase: time.Minute,
+		ScheduleBackoffMax:  time.Hour,
+		PublicURL:           "https://antri.example.com",
+	}
+	reportService := NewReportService(mockReportRepo, new(MockCategoryRepository), new(MockCounterRepository), cfg)
+	service := NewReportScheduleService(mockScheduleRepo, reportService, mailer, cfg)
+	service.now = func() time.Time { return time.Date(2026, 10, 19, 7, 0, 0, 0, time.Local) }
+	return service, mockScheduleRepo, mockReportRepo
+}
+
+func TestReportScheduleService_CreateSchedule(t *testing.T) {
+	service, mockScheduleRepo, _ := newTestReportScheduleService(t, &fakeMailer{})
+	ctx := context.Background()
+
+	t.Run("normalises recipients and computes the next run", func(t *testing.T) {
+		mockScheduleRepo.On("CreateSchedule", ctx, mock.AnythingOfType("*model.ReportSchedule")).
+			Return(&model.ReportSchedule{ID: 1}, nil).Once()
+
+		_, err := service.CreateSchedule(ctx, &dto.ReportScheduleRequest{
+			Name: " Harian ", ReportType: model.ReportTypeDaily, Format: model.ReportFormatPDF,
+			Recipients: []string{"Manajer <manajer@example.com>", "manajer@example.com", " "},
+			Cron:       "0 6 * * *",
+		})
+		require.NoError(t, err)
+		schedule := mockScheduleRepo.Calls[0].Arguments.Get(1).(*model.ReportSchedule)
+		assert.Equal(t, "Harian", schedule.Name)
+		assert.Equal(t, []string{"manajer@example.com"}, schedule.Recipients)
+		assert.True(t, schedule.IsActive)
+		assert.Equal(t, time.Date(2026, 10, 20, 6, 0, 0, 0, time.Local), schedule.NextRunAt.Time)
+	})
+
+	for name, req := range map[string]dto.ReportScheduleRequest{
+		"invalid cron":      {ReportType: model.ReportTypeDaily, Format: model.ReportFormatPDF, Recipients: []string{"a@example.com"}, Cron: "0 25 * * *"},
+		"invalid recipient": {ReportType: model.ReportTypeDaily, Format: model.ReportFormatPDF, Recipients: []string{"manajer"}, Cron: "@daily"},
+		"no recipient":      {ReportType: model.ReportTypeDaily, Format: model.ReportFormatPDF, Recipients: []string{""}, Cron: "@daily"},
+		"unknown format":    {ReportType: model.ReportTypeDaily, Format: "docx", Recipients: []string{"a@example.com"}, Cron: "@daily"},
+	} {
+		t.Run("rejects "+name, func(t *testing.T) {
+			_, err := service.CreateSchedule(ctx, &req)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReportPeriod(t *testing.T) {
+	at := time.Date(2026, 3, 2, 6, 0, 0, 0, time.Local)
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.Local) }
+
+	from, to := reportPeriod(model.ReportTypeDaily, at)
+	assert.Equal(t, day(3, 1), from)
+	assert.Equal(t, day(3, 1), to)
+
+	from, to = reportPeriod(model.ReportTypeWeekly, at)
+	assert.Equal(t, day(2, 23), from)
+	assert.Equal(t, day(3, 1), to)
+
+	from, to = reportPeriod(model.ReportTypeMonthly, at)
+	assert.Equal(t, day(2, 1), from)
+	assert.Equal(t, day(2, 28), to)
+}
+
+func TestReportScheduleService_RunOnce_DeliversDueRuns(t *testing.T) {
+	mailer := &fakeMailer{}
+	service, mockScheduleRepo, mockReportRepo := newTestReportScheduleService(t, mailer)
+	ctx := context.Background()
+	now := service.now()
+	due := time.Date(2026, 10, 19, 6, 0, 0, 0, time.Local)
+	yesterday := time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)
+
+	mockScheduleRepo.On("ListDueSchedules", ctx, now).Return([]model.ReportSchedule{{
+		ID: 1, ReportType: model.ReportTypeDaily, Cron: "0 6 * * *", NextRunAt: sql.NullTime{Time: due, Valid: true},
+	}}, nil)
+	mockScheduleRepo.On("EnqueueDueRun", ctx, 1, due, due.AddDate(0, 0, 1), yesterday, yesterday).Return(true, nil)
+
+	run := model.ReportRun{
+		ID: 7, ScheduleID: 1, ScheduleName: "Harian", ReportType: model.ReportTypeDaily, Format: model.ReportFormatCSV,
+		Recipients: []string{"manajer@example.com"}, PeriodFrom: yesterday, PeriodTo: yesterday, Attempts: 1,
+	}
+	mockScheduleRepo.On("ClaimDueRuns", ctx, now, reportRunBatch, reportRunLease).Return([]model.ReportRun{run}, nil)
+	mockReportRepo.On("EachTicket", ctx, model.ReportFilter{DateFrom: yesterday, DateTo: yesterday}).Return([]model.ReportTicket{}, nil)
+	mockScheduleRepo.On("SetRunFile", ctx, int64(7), "7_laporan_daily_2026-10-18_2026-10-18.csv", mock.AnythingOfType("int64")).Return(nil)
+	mockScheduleRepo.On("MarkRunSent", ctx, int64(7)).Return(nil)
+
+	service.RunOnce(ctx)
+
+	require.Len(t, mailer.messages, 1)
+	msg := mailer.messages[0]
+	assert.Equal(t, []string{"manajer@example.com"}, msg.To)
+	assert.Equal(t, "Laporan harian: Harian (18/10/2026)", msg.Subject)
+	assert.Contains(t, msg.Body, "https://antri.example.com/admin/api/report-runs/7/download")
+	assert.Equal(t, "laporan_daily_2026-10-18_2026-10-18.csv", msg.Attachments[0].Name)
+	assert.Contains(t, mailer.attachments[0], "Ticket Number,Category")
+
+	_, err := os.Stat(filepath.Join(service.cfg.ScheduleDir, "7_laporan_daily_2026-10-18_2026-10-18.csv"))
+	assert.NoError(t, err)
+	mockScheduleRepo.AssertExpectations(t)
+}
+
+func TestReportScheduleService_RunOnce_RetriesFailedDelivery(t *testing.T) {
+	mailer := &fakeMailer{err: fmt.Errorf("connection refused")}
+	service, mockScheduleRepo, _ := newTestReportScheduleService(t, mailer)
+	ctx := context.Background()
+	now := service.now()
+
+	// Rendered by an earlier attempt, so only the email is retried
+	require.NoError(t, os.WriteFile(filepath.Join(service.cfg.ScheduleDir, "3_laporan.pdf"), []byte("%PDF"), 0o644))
+	runs := []model.ReportRun{
+		{ID: 3, Format: model.ReportFormatPDF, FileName: "3_laporan.pdf", Attempts: 1},
+		{ID: 4, Format: model.ReportFormatPDF, FileName: "3_laporan.pdf", Attempts: 3},
+	}
+
+	mockScheduleRepo.On("ListDueSchedules", ctx, now).Return([]model.ReportSchedule{}, nil)
+	mockScheduleRepo.On("ClaimDueRuns", ctx, now, reportRunBatch, reportRunLease).Return(runs, nil)
+	mockScheduleRepo.On("MarkRunFailed", ctx, int64(3), "connection refused", now.Add(time.Minute), false).Return(nil)
+	mockScheduleRepo.On("MarkRunFailed", ctx, int64(4), "connection refused", now.Add(4*time.Minute), true).Return(nil)
+
+	service.RunOnce(ctx)
+
+	mockScheduleRepo.AssertExpectations(t)
+	mockScheduleRepo.AssertNotCalled(t, "SetRunFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
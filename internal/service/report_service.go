@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+)
+
+// reportDateLayout is how report dates are written in requests
+const reportDateLayout = "2006-01-02"
+
+// ReportService builds queue reports from aggregate queries
+type ReportService struct {
+	reportRepo   repository.ReportRepository
+	categoryRepo repository.CategoryRepository
+	counterRepo  repository.CounterRepository
+	cfg          *config.ReportConfig
+}
+
+func NewReportService(
+	reportRepo repository.ReportRepository,
+	categoryRepo repository.CategoryRepository,
+	counterRepo repository.CounterRepository,
+	cfg *config.ReportConfig) *ReportService {
+	return &ReportService{
+		reportRepo:   reportRepo,
+		categoryRepo: categoryRepo,
+		counterRepo:  counterRepo,
+		cfg:          cfg,
+	}
+}
+
+// Filter validates report request filters. An empty range is the last seven days up to today.
+func (s *ReportService) Filter(req *dto.ReportRequest) (model.ReportFilter, error) {
+	today := time.Now()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+
+	filter := model.ReportFilter{
+		DateFrom:   today.AddDate(0, 0, -7),
+		DateTo:     today,
+		CategoryID: req.CategoryID,
+		CounterID:  req.CounterID,
+		Status:     req.Status,
+	}
+	if req.DateFrom != "" {
+		from, err := time.ParseInLocation(reportDateLayout, req.DateFrom, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid date_from: %s", req.DateFrom)
+		}
+		filter.DateFrom = from
+	}
+	if req.DateTo != "" {
+		to, err := time.ParseInLocation(reportDateLayout, req.DateTo, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid date_to: %s", req.DateTo)
+		}
+		filter.DateTo = to
+	}
+	if filter.DateTo.Before(filter.DateFrom) {
+		return filter, fmt.Errorf("date_to is before date_from")
+	}
+	return filter, nil
+}
+
+// GetReport aggregates the tickets a filter selects
+func (s *ReportService) GetReport(ctx context.Context, filter model.ReportFilter) (*model.Report, error) {
+	summary, err := s.reportRepo.Summary(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.reportRepo.ByCategory(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	counters, err := s.reportRepo.ByCounter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	daily, err := s.reportRepo.Daily(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	hours, err := s.reportRepo.Hourly(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	days, err := s.reportRepo.Weekdays(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	hourly := make([]model.ReportHour, 24)
+	for hour := range hourly {
+		hourly[hour].Hour = hour
+	}
+	for _, hour := range hours {
+		if hour.Hour >= 0 && hour.Hour < 24 {
+			hourly[hour.Hour] = hour
+		}
+	}
+	weekdays := make([]model.ReportWeekday, 7)
+	for i := range weekdays {
+		weekdays[i].Weekday = i + 1
+	}
+	for _, day := range days {
+		if day.Weekday >= 1 && day.Weekday <= 7 {
+			weekdays[day.Weekday-1] = day
+		}
+	}
+
+	return &model.Report{
+		Filter:     filter,
+		Summary:    *summary,
+		Categories: categories,
+		Counters:   counters,
+		Days:       daily,
+		Hourly:     hourly,
+		Weekdays:   weekdays,
+	}, nil
+}
+
+// WritePDF writes the report of a filter as a PDF with the first tickets as an appendix
+func (s *ReportService) WritePDF(ctx context.Context, w io.Writer, filter model.ReportFilter) error {
+	report, err := s.GetReport(ctx, filter)
+	if err != nil {
+		return err
+	}
+	tickets, err := s.reportRepo.ListTickets(ctx, filter, s.cfg.PDFMaxTickets)
+	if err != nil {
+		return err
+	}
+
+	doc := renderReportPDF(report, s.filterNames(ctx, filter), tickets, time.Now())
+	_, err = doc.WriteTo(w)
+	return err
+}
+
+// filterNames describes the filters besides the date range, for the report header
+func (s *ReportService) filterNames(ctx context.Context, filter model.ReportFilter) []string {
+	var names []string
+	if filter.CategoryID != 0 {
+		name := fmt.Sprintf("#%d", filter.CategoryID)
+		if category, err := s.categoryRepo.GetByID(ctx, filter.CategoryID); err == nil && category != nil {
+			name = category.Name
+		}
+		names = append(names, "Kategori: "+name)
+	}
+	if filter.CounterID != 0 {
+		name := fmt.Sprintf("#%d", filter.CounterID)
+		if counter, err := s.counterRepo.GetByID(ctx, filter.CounterID); err == nil && counter != nil {
+			name = counter.Number
+		}
+		names = append(names, "Loket: "+name)
+	}
+	if filter.Status != "" {
+		names = append(names, "Status: "+reportStatusLabel(filter.Status))
+	}
+	return names
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+)
+
+func TestReportService_Filter(t *testing.T) {
+	service := NewReportService(new(MockReportRepository), new(MockCategoryRepository), new(MockCounterRepository), &config.ReportConfig{})
+
+	t.Run("defaults to the last seven days", func(t *testing.T) {
+		filter, err := service.Filter(&dto.ReportRequest{})
+		require.NoError(t, err)
+		now := time.Now()
+		assert.Equal(t, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local), filter.DateTo)
+		assert.Equal(t, filter.DateTo.AddDate(0, 0, -7), filter.DateFrom)
+	})
+
+	t.Run("parses dates and filters", func(t *testing.T) {
+		filter, err := service.Filter(&dto.ReportRequest{DateFrom: "2026-10-01", DateTo: "2026-10-15", CategoryID: 2, Status: "completed"})
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local), filter.DateFrom)
+		assert.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, time.Local), filter.DateTo)
+		assert.Equal(t, 2, filter.CategoryID)
+		assert.Equal(t, "completed", filter.Status)
+	})
+
+	t.Run("rejects invalid dates", func(t *testing.T) {
+		_, err := service.Filter(&dto.ReportRequest{DateFrom: "01/10/2026"})
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects a reversed range", func(t *testing.T) {
+		_, err := service.Filter(&dto.ReportRequest{DateFrom: "2026-10-15", DateTo: "2026-10-01"})
+		assert.Error(t, err)
+	})
+}
+
+func TestReportService_GetReport(t *testing.T) {
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockCategoryRepository), new(MockCounterRepository), &config.ReportConfig{})
+	ctx := context.Background()
+	filter := model.ReportFilter{DateFrom: time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local), DateTo: time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)}
+
+	mockReportRepo.On("Summary", ctx, filter).Return(&model.ReportSummary{TotalTickets: 4, Completed: 3, CompletionRate: 75}, nil)
+	mockReportRepo.On("ByCategory", ctx, filter).Return([]model.ReportCategory{{CategoryID: 1, Name: "Umum", Total: 4}}, nil)
+	mockReportRepo.On("ByCounter", ctx, filter).Return([]model.ReportCounter{}, nil)
+	mockReportRepo.On("Daily", ctx, filter).Return([]model.ReportDay{{Day: filter.DateFrom, Total: 4,
+		ReportSLA: model.ReportSLA{SLAWaitTickets: 4, SLAWaitMet: 3, SLAWaitCompliance: 75}}}, nil)
+	mockReportRepo.On("Hourly", ctx, filter).Return([]model.ReportHour{{Hour: 9, Tickets: 3}, {Hour: 14, Tickets: 1}}, nil)
+	mockReportRepo.On("Weekdays", ctx, filter).Return([]model.ReportWeekday{{Weekday: 4, Tickets: 4}}, nil)
+
+	report, err := service.GetReport(ctx, filter)
+	require.NoError(t, err)
+	assert.Equal(t, 75.0, report.Summary.CompletionRate)
+	require.Len(t, report.Days, 1)
+	assert.Equal(t, 75.0, report.Days[0].SLAWaitCompliance)
+	require.Len(t, report.Hourly, 24)
+	assert.Equal(t, 0, report.Hourly[8].Tickets)
+	assert.Equal(t, 3, report.Hourly[9].Tickets)
+	assert.Equal(t, 14, report.Hourly[14].Hour)
+	assert.Equal(t, 23, report.Hourly[23].Hour)
+	require.Len(t, report.Weekdays, 7)
+	assert.Equal(t, 1, report.Weekdays[0].Weekday)
+	assert.Equal(t, 4, report.Weekdays[3].Tickets)
+	assert.Equal(t, 7, report.Weekdays[6].Weekday)
+	mockReportRepo.AssertExpectations(t)
+}
+
+var pdfStreamPattern = regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`)
+
+// pdfText inflates and joins the content streams of a PDF
+func pdfText(t *testing.T, data []byte) string {
+	t.Helper()
+	var text strings.Builder
+	for _, match := range pdfStreamPattern.FindAllSubmatch(data, -1) {
+		zr, err := zlib.NewReader(bytes.NewReader(match[1]))
+		require.NoError(t, err)
+		content, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		text.Write(content)
+	}
+	return text.String()
+}
+
+func TestReportService_WritePDF(t *testing.T) {
+	mockReportRepo := new(MockReportRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewReportService(mockReportRepo, mockCategoryRepo, new(MockCounterRepository), &config.ReportConfig{PDFMaxTickets: 200})
+	ctx := context.Background()
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	filter := model.ReportFilter{DateFrom: day, DateTo: day, CategoryID: 1}
+
+	tickets := make([]model.ReportTicket, 200)
+	for i := range tickets {
+		created := day.Add(8*time.Hour + time.Duration(i)*time.Minute)
+		tickets[i] = model.ReportTicket{
+			TicketNumber: fmt.Sprintf("A%03d", i+1), CategoryName: "Umum", CounterNumber: "1", Status: "completed",
+			CreatedAt: created, CalledAt: sql.NullTime{Time: created.Add(5 * time.Minute), Valid: true},
+			WaitTime: sql.NullInt64{Int64: 300, Valid: true}, ServiceTime: sql.NullInt64{Int64: 120, Valid: true},
+		}
+	}
+	mockReportRepo.On("Summary", ctx, filter).Return(&model.ReportSummary{TotalTickets: 250, Completed: 250, AvgWaitSeconds: 300}, nil)
+	mockReportRepo.On("ByCategory", ctx, filter).Return([]model.ReportCategory{{CategoryID: 1, Name: "Umum", Total: 250}}, nil)
+	mockReportRepo.On("ByCounter", ctx, filter).Return([]model.ReportCounter{{CounterID: 1, Number: "1", Name: "Loket 1", Total: 250}}, nil)
+	mockReportRepo.On("Daily", ctx, filter).Return([]model.ReportDay{{Day: day, Total: 250}}, nil)
+	mockReportRepo.On("Hourly", ctx, filter).Return([]model.ReportHour{{Hour: 8, Tickets: 60, AvgWaitSeconds: 300}}, nil)
+	mockReportRepo.On("Weekdays", ctx, filter).Return([]model.ReportWeekday{}, nil)
+	mockReportRepo.On("ListTickets", ctx, filter, 200).Return(tickets, nil)
+	mockCategoryRepo.On("GetByID", ctx, 1).Return(&model.Category{ID: 1, Name: "Umum"}, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.WritePDF(ctx, &buf, filter))
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+	assert.True(t, bytes.HasSuffix(bytes.TrimSpace(buf.Bytes()), []byte("%%EOF")))
+
+	// 250 tickets but an appendix capped at 200 says so on the first page and in the appendix
+	text := pdfText(t, buf.Bytes())
+	assert.Contains(t, text, "Lampiran: 200 dari 250 tiket")
+	assert.Contains(t, text, "Lampiran: Daftar Tiket, 200 pertama dari 250")
+	mockReportRepo.AssertExpectations(t)
+	mockCategoryRepo.AssertExpectations(t)
+}
+
+func TestReportService_WriteXLSX(t *testing.T) {
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockCategoryRepository), new(MockCounterRepository), &config.ReportConfig{})
+	ctx := context.Background()
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	filter := model.ReportFilter{DateFrom: day, DateTo: day}
+
+	mockReportRepo.On("Summary", ctx, filter).Return(&model.ReportSummary{TotalTickets: 1, Completed: 1}, nil)
+	mockReportRepo.On("ByCategory", ctx, filter).Return([]model.ReportCategory{{CategoryID: 1, Name: "Umum", Total: 1, Completed: 1}}, nil)
+	mockReportRepo.On("ByCounter", ctx, filter).Return([]model.ReportCounter{{CounterID: 1, Number: "1", Total: 1}}, nil)
+	mockReportRepo.On("Hourly", ctx, filter).Return([]model.ReportHour{{Hour: 8, Tickets: 1}}, nil)
+	mockReportRepo.On("Weekdays", ctx, filter).Return([]model.ReportWeekday{}, nil)
+	mockReportRepo.On("Daily", ctx, filter).Return([]model.ReportDay{{Day: day, Total: 1, Completed: 1}}, nil)
+	mockReportRepo.On("ByStaff", ctx, filter).Return([]model.ReportStaff{{UserID: 2, FullName: "Siti Aminah", Total: 1}}, nil)
+	mockReportRepo.On("EachTicket", ctx, filter).Return([]model.ReportTicket{{
+		TicketNumber: "A001", CategoryName: "Umum", CounterNumber: "1", StaffName: "Siti Aminah", Status: "completed",
+		CreatedAt: day.Add(8 * time.Hour), WaitTime: sql.NullInt64{Int64: 90, Valid: true},
+		Notes: sql.NullString{String: "datang, lalu pergi", Valid: true},
+	}}, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.WriteXLSX(ctx, &buf, filter))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	parts := map[string]string{}
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		parts[file.Name] = string(content)
+	}
+	for _, sheet := range []string{"Tiket", "Harian", "Per Kategori", "Per Loket", "Per Petugas", "Per Jam"} {
+		assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="`+sheet+`"`)
+	}
+	tickets := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, tickets, "Siti Aminah")
+	assert.Contains(t, tickets, "datang, lalu pergi")
+	assert.Contains(t, tickets, "Selesai")
+	assert.Contains(t, parts["xl/worksheets/sheet2.xml"], "SLA Tunggu")
+	assert.Contains(t, parts["xl/worksheets/sheet6.xml"], "08:00–09:00")
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestReportService_CSVFormat(t *testing.T) {
+	service := NewReportService(new(MockReportRepository), new(MockCategoryRepository), new(MockCounterRepository),
+		&config.ReportConfig{CSVDelimiter: "comma", CSVEncoding: "utf-8-bom"})
+
+	format, err := service.CSVFormat(&dto.CSVExportRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, CSVFormat{Delimiter: ',', BOM: true}, format)
+
+	format, err = service.CSVFormat(&dto.CSVExportRequest{Delimiter: "semicolon", Encoding: "utf-8"})
+	require.NoError(t, err)
+	assert.Equal(t, CSVFormat{Delimiter: ';'}, format)
+
+	_, err = service.CSVFormat(&dto.CSVExportRequest{Delimiter: "pipe"})
+	assert.Error(t, err)
+}
+
+func TestReportService_WriteCSV(t *testing.T) {
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockCategoryRepository), new(MockCounterRepository), &config.ReportConfig{})
+	ctx := context.Background()
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	filter := model.ReportFilter{DateFrom: day, DateTo: day}
+
+	mockReportRepo.On("EachTicket", ctx, filter).Return([]model.ReportTicket{{
+		TicketNumber: "A001", CategoryName: "Umum", CounterNumber: "1", StaffName: "Siti Aminah", Status: "completed",
+		Priority: 1, CreatedAt: day.Add(8 * time.Hour),
+		CalledAt:    sql.NullTime{Time: day.Add(8*time.Hour + 90*time.Second), Valid: true},
+		CompletedAt: sql.NullTime{Time: day.Add(8*time.Hour + 390*time.Second), Valid: true},
+		WaitTime:    sql.NullInt64{Int64: 90, Valid: true}, ServiceTime: sql.NullInt64{Int64: 300, Valid: true},
+		Notes: sql.NullString{String: "datang; bilang \"nanti\"", Valid: true},
+	}, {
+		TicketNumber: "A002", CategoryName: "Umum", Status: "waiting", CreatedAt: day.Add(9 * time.Hour),
+	}}, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.WriteCSV(ctx, &buf, filter, CSVFormat{Delimiter: ';', BOM: true}))
+
+	assert.Equal(t, "\ufeff"+
+		"Ticket Number;Category;Counter;Staff;Status;Priority;Created At;Called At;Completed At;Wait Seconds;Service Seconds;Notes\n"+
+		"A001;Umum;1;Siti Aminah;completed;1;2026-10-01 08:00:00;2026-10-01 08:01:30;2026-10-01 08:06:30;90;300;\"datang; bilang \"\"nanti\"\"\"\n"+
+		"A002;Umum;;;waiting;0;2026-10-01 09:00:00;;;;;\n", buf.String())
+	mockReportRepo.AssertExpectations(t)
+}
+
+func TestReportService_WriteCSV_LeavesWriterUntouchedOnQueryError(t *testing.T) {
+	mockReportRepo := new(MockReportRepository)
+	service := NewReportService(mockReportRepo, new(MockCategoryRepository), new(MockCounterRepository), &config.ReportConfig{})
+	ctx := context.Background()
+	filter := model.ReportFilter{}
+
+	mockReportRepo.On("EachTicket", ctx, filter).Return([]model.ReportTicket{}, fmt.Errorf("connection lost"))
+
+	var buf bytes.Buffer
+	assert.Error(t, service.WriteCSV(ctx, &buf, filter, CSVFormat{Delimiter: ',', BOM: true}))
+	assert.Zero(t, buf.Len())
+}
+
+func TestRenderReportPDF_PagesTicketAppendix(t *testing.T) {
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	report := &model.Report{Filter: model.ReportFilter{DateFrom: day, DateTo: day}, Hourly: make([]model.ReportHour, 24)}
+
+	few := renderReportPDF(report, nil, nil, day)
+	many := renderReportPDF(report, nil, make([]model.ReportTicket, 300), day)
+	assert.Greater(t, len(many.Pages()), len(few.Pages())+1, "300 tickets should span several appendix pages")
+}
+
+func TestFormatReportDuration(t *testing.T) {
+	assert.Equal(t, "0m 00s", formatReportDuration(0))
+	assert.Equal(t, "4m 05s", formatReportDuration(245))
+	assert.Equal(t, "1j 02m", formatReportDuration(3720))
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"tenangantri/internal/model"
+	"tenangantri/internal/xlsx"
+)
+
+// WriteXLSX writes a workbook of a filter's tickets, one per row, followed by sheets
+// per day, category, counter, staff member and hour. Tickets are streamed from the
+// database into w, so the workbook never sits in memory whatever its range.
+func (s *ReportService) WriteXLSX(ctx context.Context, w io.Writer, filter model.ReportFilter) error {
+	// The breakdowns are small; querying them before anything is written lets a failing
+	// database surface as an error instead of a truncated download
+	report, err := s.GetReport(ctx, filter)
+	if err != nil {
+		return err
+	}
+	staff, err := s.reportRepo.ByStaff(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	book := xlsx.NewWriter(w)
+
+	sheet, err := book.AddSheet("Tiket", []xlsx.Column{
+		{Header: "Nomor Tiket", Width: 12}, {Header: "Kategori", Width: 22}, {Header: "Loket", Width: 8},
+		{Header: "Petugas", Width: 22}, {Header: "Status", Width: 12}, {Header: "Prioritas", Width: 9},
+		{Header: "Diambil", Width: 19}, {Header: "Dipanggil", Width: 19}, {Header: "Selesai", Width: 19},
+		{Header: "Waktu Tunggu", Width: 13}, {Header: "Waktu Layanan", Width: 13}, {Header: "Catatan", Width: 40},
+	})
+	if err != nil {
+		return err
+	}
+	err = s.reportRepo.EachTicket(ctx, filter, func(ticket *model.ReportTicket) error {
+		return sheet.WriteRow(
+			xlsx.String(ticket.TicketNumber),
+			xlsx.String(ticket.CategoryName),
+			xlsx.String(ticket.CounterNumber),
+			xlsx.String(ticket.StaffName),
+			xlsx.String(reportStatusLabel(ticket.Status)),
+			xlsx.Int(int64(ticket.Priority)),
+			xlsx.DateTime(ticket.CreatedAt),
+			nullDateTimeCell(ticket.CalledAt.Time, ticket.CalledAt.Valid),
+			nullDateTimeCell(ticket.CompletedAt.Time, ticket.CompletedAt.Valid),
+			nullDurationCell(ticket.WaitTime.Int64, ticket.WaitTime.Valid),
+			nullDurationCell(ticket.ServiceTime.Int64, ticket.ServiceTime.Valid),
+			xlsx.String(ticket.Notes.String),
+		)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write tickets: %w", err)
+	}
+
+	if sheet, err = book.AddSheet("Harian", []xlsx.Column{
+		{Header: "Tanggal", Width: 12}, {Header: "Total", Width: 9}, {Header: "Selesai", Width: 9},
+		{Header: "Tidak Hadir", Width: 11}, {Header: "Dibatalkan", Width: 11}, {Header: "Penyelesaian", Width: 13},
+		{Header: "Rata-rata Tunggu", Width: 16}, {Header: "Rata-rata Layanan", Width: 17},
+		{Header: "SLA Tunggu", Width: 11}, {Header: "SLA Layanan", Width: 12},
+	}); err != nil {
+		return err
+	}
+	for _, day := range report.Days {
+		if err := sheet.WriteRow(
+			xlsx.Date(day.Day), xlsx.Int(int64(day.Total)), xlsx.Int(int64(day.Completed)),
+			xlsx.Int(int64(day.NoShow)), xlsx.Int(int64(day.Cancelled)), rateCell(day.Completed, day.Total),
+			xlsx.Duration(day.AvgWaitSeconds), xlsx.Duration(day.AvgServiceSeconds),
+			rateCell(day.SLAWaitMet, day.SLAWaitTickets), rateCell(day.SLAServiceMet, day.SLAServiceTickets),
+		); err != nil {
+			return err
+		}
+	}
+
+	if sheet, err = book.AddSheet("Per Kategori", []xlsx.Column{
+		{Header: "Kategori", Width: 24}, {Header: "Prefix", Width: 8}, {Header: "Total", Width: 9},
+		{Header: "Selesai", Width: 9}, {Header: "Tidak Hadir", Width: 11}, {Header: "Penyelesaian", Width: 13},
+		{Header: "Rata-rata Tunggu", Width: 16}, {Header: "Rata-rata Layanan", Width: 17},
+		{Header: "SLA Tunggu", Width: 11}, {Header: "SLA Layanan", Width: 12},
+	}); err != nil {
+		return err
+	}
+	for _, category := range report.Categories {
+		name := category.Name
+		if category.CategoryID == 0 {
+			name = "(kategori dihapus)"
+		}
+		if err := sheet.WriteRow(
+			xlsx.String(name), xlsx.String(category.Prefix), xlsx.Int(int64(category.Total)),
+			xlsx.Int(int64(category.Completed)), xlsx.Int(int64(category.NoShow)), rateCell(category.Completed, category.Total),
+			xlsx.Duration(category.AvgWaitSeconds), xlsx.Duration(category.AvgServiceSeconds),
+			rateCell(category.SLAWaitMet, category.SLAWaitTickets), rateCell(category.SLAServiceMet, category.SLAServiceTickets),
+		); err != nil {
+			return err
+		}
+	}
+
+	if sheet, err = book.AddSheet("Per Loket", []xlsx.Column{
+		{Header: "Loket", Width: 8}, {Header: "Nama", Width: 22}, {Header: "Total", Width: 9},
+		{Header: "Selesai", Width: 9}, {Header: "Tidak Hadir", Width: 11}, {Header: "Penyelesaian", Width: 13},
+		{Header: "Rata-rata Layanan", Width: 17},
+	}); err != nil {
+		return err
+	}
+	for _, counter := range report.Counters {
+		if err := sheet.WriteRow(
+			xlsx.String(counter.Number), xlsx.String(counter.Name), xlsx.Int(int64(counter.Total)),
+			xlsx.Int(int64(counter.Completed)), xlsx.Int(int64(counter.NoShow)), rateCell(counter.Completed, counter.Total),
+			xlsx.Duration(counter.AvgServiceSeconds),
+		); err != nil {
+			return err
+		}
+	}
+
+	if sheet, err = book.AddSheet("Per Petugas", []xlsx.Column{
+		{Header: "Petugas", Width: 24}, {Header: "Username", Width: 16}, {Header: "Total", Width: 9},
+		{Header: "Selesai", Width: 9}, {Header: "Tidak Hadir", Width: 11}, {Header: "Penyelesaian", Width: 13},
+		{Header: "Rata-rata Layanan", Width: 17},
+	}); err != nil {
+		return err
+	}
+	for _, member := range staff {
+		name := member.FullName
+		if member.UserID == 0 {
+			name = "(petugas dihapus)"
+		}
+		if err := sheet.WriteRow(
+			xlsx.String(name), xlsx.String(member.Username), xlsx.Int(int64(member.Total)),
+			xlsx.Int(int64(member.Completed)), xlsx.Int(int64(member.NoShow)), rateCell(member.Completed, member.Total),
+			xlsx.Duration(member.AvgServiceSeconds),
+		); err != nil {
+			return err
+		}
+	}
+
+	if sheet, err = book.AddSheet("Per Jam", []xlsx.Column{
+		{Header: "Jam", Width: 13}, {Header: "Tiket", Width: 9}, {Header: "Rata-rata Tunggu", Width: 16},
+	}); err != nil {
+		return err
+	}
+	for _, hour := range report.Hourly {
+		if err := sheet.WriteRow(
+			xlsx.String(fmt.Sprintf("%02d:00–%02d:00", hour.Hour, (hour.Hour+1)%24)), xlsx.Int(int64(hour.Tickets)), xlsx.Duration(hour.AvgWaitSeconds),
+		); err != nil {
+			return err
+		}
+	}
+
+	return book.Close()
+}
+
+// rateCell is part of a total as a percentage, blank when there is no total
+func rateCell(part, total int) xlsx.Cell {
+	if total == 0 {
+		return xlsx.Blank()
+	}
+	return xlsx.Percent(float64(part) / float64(total))
+}
+
+func nullDateTimeCell(t time.Time, valid bool) xlsx.Cell {
+	if !valid {
+		return xlsx.Blank()
+	}
+	return xlsx.DateTime(t)
+}
+
+func nullDurationCell(seconds int64, valid bool) xlsx.Cell {
+	if !valid {
+		return xlsx.Blank()
+	}
+	return xlsx.Duration(float64(seconds))
+}
@@ -0,0 +1,412 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+)
+
+// signageMediaTypes maps the content types displays can play to the stored file extension
+var signageMediaTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+	"video/mp4":  ".mp4",
+}
+
+var signageFilenamePattern = regexp.MustCompile(`^[0-9a-f]{64}\.(jpg|png|gif|webp|mp4)$`)
+
+// defaultSignageImageDuration is used for images added without a duration
+const defaultSignageImageDuration = 10
+
+// SignageService manages signage media, playlists and schedules and picks what each display plays
+type SignageService struct {
+	signageRepo repository.SignageRepository
+	profileRepo repository.DisplayProfileRepository
+	cfg         *config.SignageConfig
+}
+
+func NewSignageService(
+	signageRepo repository.SignageRepository,
+	profileRepo repository.DisplayProfileRepository,
+	cfg *config.SignageConfig) *SignageService {
+	return &SignageService{
+		signageRepo: signageRepo,
+		profileRepo: profileRepo,
+		cfg:         cfg,
+	}
+}
+
+// ListMedia returns all uploaded media
+func (s *SignageService) ListMedia(ctx context.Context) ([]model.SignageMedia, error) {
+	return s.signageRepo.ListMedia(ctx)
+}
+
+// UploadMedia stores an image or MP4 under its content hash. Uploading the same file
+// twice returns the existing media.
+func (s *SignageService) UploadMedia(ctx context.Context, name string, r io.Reader) (*model.SignageMedia, error) {
+	if err := os.MkdirAll(s.cfg.MediaDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(s.cfg.MediaDir, ".upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	// Sniff the type from the content; the browser's claim is not trusted
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	ext, ok := signageMediaTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported media type %s; upload JPEG, PNG, GIF, WebP or MP4", contentType)
+	}
+
+	hash := sha256.New()
+	limited := io.LimitReader(io.MultiReader(bytes.NewReader(head), r), s.cfg.MaxUploadSize+1)
+	size, err := io.Copy(io.MultiWriter(tmp, hash), limited)
+	if err != nil {
+		return nil, err
+	}
+	if size > s.cfg.MaxUploadSize {
+		return nil, fmt.Errorf("file is larger than %d MB", s.cfg.MaxUploadSize>>20)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	filename := hex.EncodeToString(hash.Sum(nil)) + ext
+	existing, err := s.signageRepo.GetMediaByFilename(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(s.cfg.MediaDir, filename)); err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = filename
+	}
+	return s.signageRepo.CreateMedia(ctx, &model.SignageMedia{
+		Name:        name,
+		Filename:    filename,
+		ContentType: contentType,
+		SizeBytes:   size,
+	})
+}
+
+// DeleteMedia removes media from every playlist and deletes its file
+func (s *SignageService) DeleteMedia(ctx context.Context, id int) error {
+	media, err := s.signageRepo.GetMediaByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if media == nil {
+		return fmt.Errorf("media not found")
+	}
+
+	if err := s.signageRepo.DeleteMedia(ctx, id); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(s.cfg.MediaDir, media.Filename)); err != nil && !os.IsNotExist(err) {
+		log.Error().Err(err).Str("layer", "service").Str("func", "DeleteMedia").Str("filename", media.Filename).Msg("Failed to remove media file")
+	}
+	return nil
+}
+
+// MediaPath returns where a stored media file lives on disk
+func (s *SignageService) MediaPath(filename string) (string, error) {
+	if !signageFilenamePattern.MatchString(filename) {
+		return "", fmt.Errorf("media not found")
+	}
+	return filepath.Join(s.cfg.MediaDir, filename), nil
+}
+
+// MediaCacheMaxAge is how long displays may cache media files
+func (s *SignageService) MediaCacheMaxAge() time.Duration {
+	return s.cfg.CacheMaxAge
+}
+
+// ListPlaylists returns all playlists with their item counts
+func (s *SignageService) ListPlaylists(ctx context.Context) ([]model.SignagePlaylist, error) {
+	return s.signageRepo.ListPlaylists(ctx)
+}
+
+// GetPlaylist returns a playlist with its items
+func (s *SignageService) GetPlaylist(ctx context.Context, id int) (*model.SignagePlaylist, error) {
+	playlist, err := s.signageRepo.GetPlaylistByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if playlist == nil {
+		return nil, fmt.Errorf("playlist not found")
+	}
+
+	playlist.Items, err = s.signageRepo.ListPlaylistItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return playlist, nil
+}
+
+// CreatePlaylist creates a playlist with the given items
+func (s *SignageService) CreatePlaylist(ctx context.Context, req *dto.SignagePlaylistRequest) (*model.SignagePlaylist, error) {
+	items, err := s.playlistItems(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist, err := s.signageRepo.CreatePlaylist(ctx, &model.SignagePlaylist{Name: strings.TrimSpace(req.Name)})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.signageRepo.ReplacePlaylistItems(ctx, playlist.ID, items); err != nil {
+		return nil, err
+	}
+	return s.GetPlaylist(ctx, playlist.ID)
+}
+
+// UpdatePlaylist renames a playlist and replaces its items. Displays pick the change
+// up when their current loop ends.
+func (s *SignageService) UpdatePlaylist(ctx context.Context, id int, req *dto.SignagePlaylistRequest) (*model.SignagePlaylist, error) {
+	items, err := s.playlistItems(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist, err := s.GetPlaylist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist.Name = strings.TrimSpace(req.Name)
+	if err := s.signageRepo.UpdatePlaylist(ctx, playlist); err != nil {
+		return nil, err
+	}
+	if err := s.signageRepo.ReplacePlaylistItems(ctx, id, items); err != nil {
+		return nil, err
+	}
+	return s.GetPlaylist(ctx, id)
+}
+
+// DeletePlaylist deletes a playlist and the schedules that play it
+func (s *SignageService) DeletePlaylist(ctx context.Context, id int) error {
+	return s.signageRepo.DeletePlaylist(ctx, id)
+}
+
+// playlistItems checks every item's media exists and fills in image durations
+func (s *SignageService) playlistItems(ctx context.Context, reqItems []dto.SignagePlaylistItemRequest) ([]model.SignagePlaylistItem, error) {
+	items := make([]model.SignagePlaylistItem, 0, len(reqItems))
+	for _, reqItem := range reqItems {
+		media, err := s.signageRepo.GetMediaByID(ctx, reqItem.MediaID)
+		if err != nil {
+			return nil, err
+		}
+		if media == nil {
+			return nil, fmt.Errorf("media %d not found", reqItem.MediaID)
+		}
+
+		duration := reqItem.DurationSeconds
+		if duration == 0 && !media.IsVideo() {
+			duration = defaultSignageImageDuration
+		}
+		items = append(items, model.SignagePlaylistItem{MediaID: media.ID, DurationSeconds: duration})
+	}
+	return items, nil
+}
+
+// ListProfiles returns the display profiles schedules can target
+func (s *SignageService) ListProfiles(ctx context.Context) ([]model.DisplayProfile, error) {
+	return s.profileRepo.List(ctx)
+}
+
+// ListSchedules returns all schedules with their profile and playlist names
+func (s *SignageService) ListSchedules(ctx context.Context) ([]model.SignageSchedule, error) {
+	return s.signageRepo.ListSchedules(ctx)
+}
+
+// GetSchedule returns a schedule by ID
+func (s *SignageService) GetSchedule(ctx context.Context, id int) (*model.SignageSchedule, error) {
+	schedule, err := s.signageRepo.GetScheduleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, fmt.Errorf("schedule not found")
+	}
+	return schedule, nil
+}
+
+// CreateSchedule assigns a playlist to a display profile for a weekly time window
+func (s *SignageService) CreateSchedule(ctx context.Context, req *dto.SignageScheduleRequest) (*model.SignageSchedule, error) {
+	schedule := &model.SignageSchedule{IsActive: true}
+	if err := applySignageSchedule(schedule, req); err != nil {
+		return nil, err
+	}
+	return s.signageRepo.CreateSchedule(ctx, schedule)
+}
+
+// UpdateSchedule updates a schedule
+func (s *SignageService) UpdateSchedule(ctx context.Context, id int, req *dto.SignageScheduleRequest) (*model.SignageSchedule, error) {
+	schedule, err := s.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applySignageSchedule(schedule, req); err != nil {
+		return nil, err
+	}
+	if err := s.signageRepo.UpdateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// DeleteSchedule deletes a schedule
+func (s *SignageService) DeleteSchedule(ctx context.Context, id int) error {
+	return s.signageRepo.DeleteSchedule(ctx, id)
+}
+
+// applySignageSchedule validates a request and copies it onto schedule
+func applySignageSchedule(schedule *model.SignageSchedule, req *dto.SignageScheduleRequest) error {
+	for _, value := range []string{req.StartTime, req.EndTime} {
+		if _, err := time.Parse("15:04", value); err != nil {
+			return fmt.Errorf("invalid time %q, use HH:MM", value)
+		}
+	}
+	days := []int{}
+	for _, day := range req.DaysOfWeek {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("invalid day of week %d", day)
+		}
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+	slices.Sort(days)
+
+	schedule.DisplayProfileID = req.DisplayProfileID
+	schedule.PlaylistID = req.PlaylistID
+	schedule.DaysOfWeek = days
+	schedule.StartTime = req.StartTime
+	schedule.EndTime = req.EndTime
+	schedule.Priority = req.Priority
+	if req.IsActive != nil {
+		schedule.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// CurrentPlayback returns the playlist the display for a profile should loop at now.
+// Of the schedules covering now, the highest priority wins.
+func (s *SignageService) CurrentPlayback(ctx context.Context, slug string, now time.Time) (*dto.SignagePlayback, error) {
+	playback := &dto.SignagePlayback{Items: []dto.SignagePlaybackItem{}}
+
+	profile, err := s.profileRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil || !profile.IsActive {
+		return nil, fmt.Errorf("display profile not found")
+	}
+
+	schedules, err := s.signageRepo.ListSchedulesByProfile(ctx, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Schedules come ordered by priority, so the first that covers now wins
+	for _, schedule := range schedules {
+		if !scheduleCovers(schedule, now) {
+			continue
+		}
+
+		items, err := s.signageRepo.ListPlaylistItems(ctx, schedule.PlaylistID)
+		if err != nil {
+			return nil, err
+		}
+
+		playback.PlaylistID = schedule.PlaylistID
+		playback.Name = schedule.PlaylistName
+		for _, item := range items {
+			kind := "image"
+			if strings.HasPrefix(item.ContentType, "video/") {
+				kind = "video"
+			}
+			playback.Items = append(playback.Items, dto.SignagePlaybackItem{
+				URL:      "/display/media/" + item.Filename,
+				Type:     kind,
+				Duration: item.DurationSeconds,
+			})
+		}
+		break
+	}
+
+	return playback, nil
+}
+
+// scheduleCovers reports whether a schedule's weekly window includes now
+func scheduleCovers(schedule model.SignageSchedule, now time.Time) bool {
+	return weeklyWindowCovers(schedule.DaysOfWeek, schedule.StartTime, schedule.EndTime, now)
+}
+
+// weeklyWindowCovers reports whether now falls between start and end ("15:04") on one of
+// days (0 = Sunday; none means every day). A window whose end is not after its start runs
+// past midnight and belongs to the day it started.
+func weeklyWindowCovers(days []int, startTime, endTime string, now time.Time) bool {
+	start, err := time.Parse("15:04", startTime)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse("15:04", endTime)
+	if err != nil {
+		return false
+	}
+
+	startMin := start.Hour()*60 + start.Minute()
+	endMin := end.Hour()*60 + end.Minute()
+	nowMin := now.Hour()*60 + now.Minute()
+	day := int(now.Weekday())
+
+	onDay := func(d int) bool {
+		return len(days) == 0 || slices.Contains(days, d)
+	}
+
+	if endMin > startMin {
+		return onDay(day) && nowMin >= startMin && nowMin < endMin
+	}
+	// Overnight or all day: the part after the start today, or the tail of yesterday's window
+	if nowMin >= startMin {
+		return onDay(day)
+	}
+	return nowMin < endMin && onDay((day+6)%7)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+)
+
+func newSignageService(t *testing.T) (*SignageService, *MockSignageRepository, *MockDisplayProfileRepository) {
+	mockSignageRepo := new(MockSignageRepository)
+	mockProfileRepo := new(MockDisplayProfileRepository)
+	cfg := &config.SignageConfig{MediaDir: t.TempDir(), MaxUploadSize: 1 << 10, CacheMaxAge: time.Hour}
+	return NewSignageService(mockSignageRepo, mockProfileRepo, cfg), mockSignageRepo, mockProfileRepo
+}
+
+func TestScheduleCovers(t *testing.T) {
+	// 2026-10-19 is a Monday
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 19, hour, minute, 0, 0, time.Local)
+	}
+	weekdays := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name     string
+		schedule model.SignageSchedule
+		now      time.Time
+		want     bool
+	}{
+		{"inside window", model.SignageSchedule{StartTime: "08:00", EndTime: "17:00", DaysOfWeek: weekdays}, at(9, 30), true},
+		{"end is exclusive", model.SignageSchedule{StartTime: "08:00", EndTime: "17:00", DaysOfWeek: weekdays}, at(17, 0), false},
+		{"other day", model.SignageSchedule{StartTime: "08:00", EndTime: "17:00", DaysOfWeek: []int{0, 6}}, at(9, 30), false},
+		{"every day when no days", model.SignageSchedule{StartTime: "08:00", EndTime: "17:00"}, at(9, 30), true},
+		{"overnight after start", model.SignageSchedule{StartTime: "22:00", EndTime: "06:00", DaysOfWeek: []int{1}}, at(23, 0), true},
+		{"overnight tail of sunday", model.SignageSchedule{StartTime: "22:00", EndTime: "06:00", DaysOfWeek: []int{0}}, at(5, 0), true},
+		{"overnight tail of monday is not monday morning", model.SignageSchedule{StartTime: "22:00", EndTime: "06:00", DaysOfWeek: []int{1}}, at(5, 0), false},
+		{"all day", model.SignageSchedule{StartTime: "00:00", EndTime: "00:00", DaysOfWeek: []int{1}}, at(12, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, scheduleCovers(tt.schedule, tt.now))
+		})
+	}
+}
+
+func TestSignageService_CurrentPlayback(t *testing.T) {
+	service, mockSignageRepo, mockProfileRepo := newSignageService(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.Local)
+
+	mockProfileRepo.On("GetBySlug", ctx, "lobi").Return(&model.DisplayProfile{ID: 1, Slug: "lobi", IsActive: true}, nil)
+	mockProfileRepo.On("GetBySlug", ctx, "off").Return(&model.DisplayProfile{ID: 2, Slug: "off"}, nil)
+	// Ordered by priority; the first does not cover now
+	mockSignageRepo.On("ListSchedulesByProfile", ctx, 1).Return([]model.SignageSchedule{
+		{PlaylistID: 7, PlaylistName: "Malam", StartTime: "18:00", EndTime: "22:00", Priority: 5},
+		{PlaylistID: 3, PlaylistName: "Pagi", StartTime: "08:00", EndTime: "12:00", Priority: 1},
+		{PlaylistID: 4, PlaylistName: "Umum", StartTime: "00:00", EndTime: "00:00"},
+	}, nil)
+	mockSignageRepo.On("ListPlaylistItems", ctx, 3).Return([]model.SignagePlaylistItem{
+		{Filename: "a.jpg", ContentType: "image/jpeg", DurationSeconds: 8},
+		{Filename: "b.mp4", ContentType: "video/mp4"},
+	}, nil)
+
+	playback, err := service.CurrentPlayback(ctx, "lobi", now)
+	require.NoError(t, err)
+	assert.Equal(t, &dto.SignagePlayback{
+		PlaylistID: 3,
+		Name:       "Pagi",
+		Items: []dto.SignagePlaybackItem{
+			{URL: "/display/media/a.jpg", Type: "image", Duration: 8},
+			{URL: "/display/media/b.mp4", Type: "video"},
+		},
+	}, playback)
+	mockSignageRepo.AssertNotCalled(t, "ListPlaylistItems", ctx, 4)
+
+	_, err = service.CurrentPlayback(ctx, "off", now)
+	assert.Error(t, err)
+}
+
+func TestSignageService_UploadMedia(t *testing.T) {
+	service, mockSignageRepo, _ := newSignageService(t)
+	ctx := context.Background()
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 100)...)
+
+	mockSignageRepo.On("GetMediaByFilename", ctx, mock.AnythingOfType("string")).Return(nil, nil).Once()
+	created := &model.SignageMedia{}
+	mockSignageRepo.On("CreateMedia", ctx, mock.AnythingOfType("*model.SignageMedia")).
+		Run(func(args mock.Arguments) { *created = *args.Get(1).(*model.SignageMedia) }).
+		Return(created, nil)
+
+	media, err := service.UploadMedia(ctx, " Promo ", bytes.NewReader(png))
+	require.NoError(t, err)
+	require.Same(t, created, media)
+	assert.Equal(t, "Promo", media.Name)
+	assert.Equal(t, "image/png", media.ContentType)
+	assert.Equal(t, int64(len(png)), media.SizeBytes)
+	assert.Regexp(t, `^[0-9a-f]{64}\.png$`, media.Filename)
+
+	path, err := service.MediaPath(media.Filename)
+	require.NoError(t, err)
+	stored, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, png, stored)
+
+	// The same file again returns the existing media
+	mockSignageRepo.On("GetMediaByFilename", ctx, media.Filename).Return(media, nil).Once()
+	again, err := service.UploadMedia(ctx, "Lagi", bytes.NewReader(png))
+	require.NoError(t, err)
+	assert.Same(t, media, again)
+	mockSignageRepo.AssertNumberOfCalls(t, "CreateMedia", 1)
+
+	// Unsupported and oversized files are rejected and leave nothing behind
+	_, err = service.UploadMedia(ctx, "script", bytes.NewReader([]byte("<html><script>alert(1)</script>")))
+	assert.Error(t, err)
+	_, err = service.UploadMedia(ctx, "big", bytes.NewReader(append(png, bytes.Repeat([]byte{0}, 1<<10)...)))
+	assert.Error(t, err)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	_, err = service.MediaPath("../secret.png")
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/event"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+)
+
+// SLAService flags tickets that approach or pass the wait and service targets of their
+// category and raises one breach event per ticket and target
+type SLAService struct {
+	slaRepo             repository.SLARepository
+	ticketRepo          repository.TicketRepository
+	userCounterRepo     repository.UserCounterRepository
+	counterCategoryRepo repository.CounterCategoryRepository
+	events              event.Publisher
+	cfg                 *config.SLAConfig
+}
+
+func NewSLAService(
+	slaRepo repository.SLARepository,
+	ticketRepo repository.TicketRepository,
+	userCounterRepo repository.UserCounterRepository,
+	counterCategoryRepo repository.CounterCategoryRepository,
+	events event.Publisher,
+	cfg *config.SLAConfig) *SLAService {
+	return &SLAService{
+		slaRepo:             slaRepo,
+		ticketRepo:          ticketRepo,
+		userCounterRepo:     userCounterRepo,
+		counterCategoryRepo: counterCategoryRepo,
+		events:              events,
+		cfg:                 cfg,
+	}
+}
+
+// Live lists every ticket that is near or past its target
+func (s *SLAService) Live(ctx context.Context) ([]model.SLATicket, error) {
+	return s.live(ctx, nil)
+}
+
+// LiveForStaff lists the flagged tickets of the categories served at the staff member's counter
+func (s *SLAService) LiveForStaff(ctx context.Context, userID int) ([]model.SLATicket, error) {
+	counterID, err := s.userCounterRepo.GetCounterIDByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !counterID.Valid {
+		return []model.SLATicket{}, nil
+	}
+	categoryIDs, err := s.counterCategoryRepo.GetCategoryIDsByCounterID(ctx, int(counterID.Int64))
+	if err != nil {
+		return nil, err
+	}
+	if len(categoryIDs) == 0 {
+		return []model.SLATicket{}, nil
+	}
+	return s.live(ctx, categoryIDs)
+}
+
+func (s *SLAService) live(ctx context.Context, categoryIDs []int) ([]model.SLATicket, error) {
+	tickets, err := s.slaRepo.LiveTickets(ctx, s.warnFraction(), categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tickets {
+		tickets[i].Level = model.SLALevelWarning
+		if tickets[i].ElapsedSeconds > tickets[i].TargetSeconds {
+			tickets[i].Level = model.SLALevelBreached
+		}
+	}
+	return tickets, nil
+}
+
+// warnFraction is the share of a target after which a ticket is flagged
+func (s *SLAService) warnFraction() float64 {
+	percent := s.cfg.WarningPercent
+	if percent <= 0 || percent > 100 {
+		percent = 100
+	}
+	return float64(percent) / 100
+}
+
+// RunMonitor checks live tickets for breaches until ctx is cancelled
+func (s *SLAService) RunMonitor(ctx context.Context) {
+	if s.cfg.CheckInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.CheckBreaches(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to check SLA breaches")
+				continue
+			}
+			if count > 0 {
+				log.Warn().Int("count", count).Msg("Tickets breached their SLA target")
+			}
+		}
+	}
+}
+
+// CheckBreaches records the tickets that went past a target since the last check and
+// publishes one event for each
+func (s *SLAService) CheckBreaches(ctx context.Context) (int, error) {
+	breaches, err := s.slaRepo.RecordBreaches(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, breach := range breaches {
+		ticket, err := s.ticketRepo.GetWithDetails(ctx, breach.TicketID)
+		if err != nil || ticket == nil {
+			log.Error().Err(err).Str("layer", "service").Str("func", "CheckBreaches").Int("ticket_id", breach.TicketID).Msg("Failed to load breached ticket")
+			continue
+		}
+		s.events.Publish(ctx, event.TicketSLABreached{
+			Ticket:         ticket,
+			Kind:           breach.Kind,
+			TargetSeconds:  breach.TargetSeconds,
+			ElapsedSeconds: breach.ElapsedSeconds,
+		})
+	}
+	return len(breaches), nil
+}
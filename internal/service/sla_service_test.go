@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/event"
+	"tenangantri/internal/model"
+)
+
+func TestSLAService_LiveForStaff(t *testing.T) {
+	mockSLARepo := new(MockSLARepository)
+	mockUserCounterRepo := new(MockUserCounterRepository)
+	mockCounterCategoryRepo := new(MockCounterCategoryRepository)
+	service := NewSLAService(mockSLARepo, new(MockTicketRepository), mockUserCounterRepo, mockCounterCategoryRepo,
+		event.NewBus(), &config.SLAConfig{WarningPercent: 80})
+
+	ctx := context.Background()
+	mockUserCounterRepo.On("GetCounterIDByUserID", ctx, 4).Return(sql.NullInt64{Int64: 2, Valid: true}, nil)
+	mockCounterCategoryRepo.On("GetCategoryIDsByCounterID", ctx, 2).Return([]int{1, 3}, nil)
+	mockSLARepo.On("LiveTickets", ctx, 0.8, []int{1, 3}).Return([]model.SLATicket{
+		{TicketID: 10, Kind: model.SLAKindWait, TargetSeconds: 600, ElapsedSeconds: 700},
+		{TicketID: 11, Kind: model.SLAKindWait, TargetSeconds: 600, ElapsedSeconds: 600},
+	}, nil)
+
+	tickets, err := service.LiveForStaff(ctx, 4)
+
+	require.NoError(t, err)
+	require.Len(t, tickets, 2)
+	assert.Equal(t, model.SLALevelBreached, tickets[0].Level)
+	// Reaching the target exactly is not yet a breach
+	assert.Equal(t, model.SLALevelWarning, tickets[1].Level)
+	mockSLARepo.AssertExpectations(t)
+}
+
+func TestSLAService_LiveForStaffWithoutCounter(t *testing.T) {
+	mockSLARepo := new(MockSLARepository)
+	mockUserCounterRepo := new(MockUserCounterRepository)
+	service := NewSLAService(mockSLARepo, new(MockTicketRepository), mockUserCounterRepo, new(MockCounterCategoryRepository),
+		event.NewBus(), &config.SLAConfig{WarningPercent: 80})
+
+	ctx := context.Background()
+	mockUserCounterRepo.On("GetCounterIDByUserID", ctx, 4).Return(sql.NullInt64{}, nil)
+
+	tickets, err := service.LiveForStaff(ctx, 4)
+
+	require.NoError(t, err)
+	assert.Empty(t, tickets)
+	mockSLARepo.AssertNotCalled(t, "LiveTickets")
+}
+
+func TestSLAService_CheckBreaches(t *testing.T) {
+	mockSLARepo := new(MockSLARepository)
+	mockTicketRepo := new(MockTicketRepository)
+
+	bus := event.NewBus()
+	var breaches []event.TicketSLABreached
+	event.On(bus, func(ctx context.Context, e event.TicketSLABreached) {
+		breaches = append(breaches, e)
+	})
+
+	service := NewSLAService(mockSLARepo, mockTicketRepo, new(MockUserCounterRepository), new(MockCounterCategoryRepository),
+		bus, &config.SLAConfig{WarningPercent: 80})
+
+	ctx := context.Background()
+	mockSLARepo.On("RecordBreaches", ctx).Return([]model.SLABreach{
+		{TicketID: 10, Kind: model.SLAKindWait, TargetSeconds: 600, ElapsedSeconds: 615},
+		{TicketID: 11, Kind: model.SLAKindService, TargetSeconds: 300, ElapsedSeconds: 310},
+	}, nil)
+	mockTicketRepo.On("GetWithDetails", ctx, 10).Return(&model.Ticket{ID: 10, TicketNumber: "A010", Status: "waiting"}, nil)
+	// Deleted between the check and the lookup
+	mockTicketRepo.On("GetWithDetails", ctx, 11).Return(nil, nil)
+
+	count, err := service.CheckBreaches(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, breaches, 1)
+	assert.Equal(t, "A010", breaches[0].Ticket.TicketNumber)
+	assert.Equal(t, model.SLAKindWait, breaches[0].Kind)
+	assert.Equal(t, 615, breaches[0].ElapsedSeconds)
+	mockTicketRepo.AssertExpectations(t)
+}
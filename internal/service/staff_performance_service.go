@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+)
+
+// StaffPerformanceService records staff shifts and pauses and reports how staff perform in them
+type StaffPerformanceService struct {
+	shiftRepo repository.StaffShiftRepository
+	userRepo  repository.UserRepository
+	cfg       *config.ShiftConfig
+	now       func() time.Time
+}
+
+func NewStaffPerformanceService(
+	shiftRepo repository.StaffShiftRepository,
+	userRepo repository.UserRepository,
+	cfg *config.ShiftConfig) *StaffPerformanceService {
+	return &StaffPerformanceService{
+		shiftRepo: shiftRepo,
+		userRepo:  userRepo,
+		cfg:       cfg,
+		now:       time.Now,
+	}
+}
+
+// staleBefore is the last activity before which an open shift counts as ended
+func (s *StaffPerformanceService) staleBefore(now time.Time) time.Time {
+	return now.Add(-s.cfg.IdleGap)
+}
+
+// RecordActivity keeps the staff member's shift at counterID going, starting one if needed.
+// The action it records has already happened, so a failure is logged rather than returned.
+func (s *StaffPerformanceService) RecordActivity(ctx context.Context, userID, counterID int) {
+	now := s.now()
+	if err := s.shiftRepo.RecordActivity(ctx, userID, counterID, now, s.staleBefore(now)); err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "RecordActivity").Int("user_id", userID).Msg("Failed to record shift activity")
+	}
+}
+
+// RecordPause starts a pause in the staff member's shift
+func (s *StaffPerformanceService) RecordPause(ctx context.Context, userID, counterID int) {
+	s.RecordActivity(ctx, userID, counterID)
+	if err := s.shiftRepo.StartPause(ctx, userID, s.now()); err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "RecordPause").Int("user_id", userID).Msg("Failed to record pause")
+	}
+}
+
+// RecordResume ends the pause in the staff member's shift
+func (s *StaffPerformanceService) RecordResume(ctx context.Context, userID, counterID int) {
+	// A pause that outlasted the idle gap has already ended with its shift
+	s.RecordActivity(ctx, userID, counterID)
+	if err := s.shiftRepo.EndPause(ctx, userID, s.now()); err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "RecordResume").Int("user_id", userID).Msg("Failed to record resume")
+	}
+}
+
+// EndShift ends the staff member's open shift, if any
+func (s *StaffPerformanceService) EndShift(ctx context.Context, userID int) {
+	now := s.now()
+	if err := s.shiftRepo.EndShift(ctx, userID, now, s.staleBefore(now)); err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "EndShift").Int("user_id", userID).Msg("Failed to end shift")
+	}
+}
+
+// Filter validates staff performance filters. An empty range is the last seven days up to today.
+func (s *StaffPerformanceService) Filter(req *dto.StaffPerformanceRequest) (model.StaffPerformanceFilter, error) {
+	today := s.now()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+
+	filter := model.StaffPerformanceFilter{
+		DateFrom: today.AddDate(0, 0, -7),
+		DateTo:   today,
+		UserID:   req.UserID,
+	}
+	if req.DateFrom != "" {
+		from, err := time.ParseInLocation(reportDateLayout, req.DateFrom, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid date_from: %s", req.DateFrom)
+		}
+		filter.DateFrom = from
+	}
+	if req.DateTo != "" {
+		to, err := time.ParseInLocation(reportDateLayout, req.DateTo, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid date_to: %s", req.DateTo)
+		}
+		filter.DateTo = to
+	}
+	if filter.DateTo.Before(filter.DateFrom) {
+		return filter, fmt.Errorf("date_to is before date_from")
+	}
+	return filter, nil
+}
+
+// Report returns the performance of every staff member with a shift in the filter's range
+func (s *StaffPerformanceService) Report(ctx context.Context, filter model.StaffPerformanceFilter) (*model.StaffPerformanceReport, error) {
+	now := s.now()
+	rows, err := s.shiftRepo.Stats(ctx, filter, now, s.staleBefore(now))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.StaffPerformanceReport{Filter: filter, Staff: []model.StaffPerformance{}}
+	// Rows come grouped by staff member
+	for start := 0; start < len(rows); {
+		end := start
+		for end < len(rows) && rows[end].UserID == rows[start].UserID {
+			end++
+		}
+		report.Staff = append(report.Staff, staffPerformance(rows[start:end]))
+		start = end
+	}
+	return report, nil
+}
+
+// Today returns the staff member's performance in today's shifts
+func (s *StaffPerformanceService) Today(ctx context.Context, userID int) (*model.StaffPerformance, error) {
+	now := s.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	rows, err := s.shiftRepo.Stats(ctx, model.StaffPerformanceFilter{DateFrom: today, DateTo: today, UserID: userID}, now, s.staleBefore(now))
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &model.StaffPerformance{UserID: userID, Shifts: []model.StaffShiftPerformance{}}, nil
+	}
+	performance := staffPerformance(rows)
+	return &performance, nil
+}
+
+// ListStaff returns the staff members and supervisors a report can be limited to
+func (s *StaffPerformanceService) ListStaff(ctx context.Context) ([]model.User, error) {
+	users, err := s.userRepo.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	staff := make([]model.User, 0, len(users))
+	for _, user := range users {
+		if user.Role != "admin" {
+			staff = append(staff, user)
+		}
+	}
+	return staff, nil
+}
+
+// staffPerformance sums up the shifts of one staff member
+func staffPerformance(rows []model.StaffShiftStats) model.StaffPerformance {
+	performance := model.StaffPerformance{
+		UserID:   rows[0].UserID,
+		FullName: rows[0].FullName,
+		Username: rows[0].Username,
+		Shifts:   make([]model.StaffShiftPerformance, 0, len(rows)),
+	}
+	for _, row := range rows {
+		performance.Shifts = append(performance.Shifts, model.StaffShiftPerformance{
+			ShiftID:               row.ShiftID,
+			CounterNumber:         row.CounterNumber,
+			StartedAt:             row.StartedAt,
+			EndedAt:               row.EndedAt,
+			Open:                  row.Open,
+			StaffPerformanceStats: staffStats(row),
+		})
+	}
+	performance.StaffPerformanceStats = staffStats(rows...)
+	return performance
+}
+
+// staffStats derives the performance figures of shifts from their raw activity
+func staffStats(rows ...model.StaffShiftStats) model.StaffPerformanceStats {
+	var stats model.StaffPerformanceStats
+	var serviceSeconds float64
+	for _, row := range rows {
+		stats.TicketsServed += row.Completed
+		stats.NoShow += row.NoShow
+		serviceSeconds += row.ServiceSeconds
+		stats.ShiftSeconds += math.Max(row.EndedAt.Sub(row.StartedAt).Seconds(), 0)
+		stats.BusySeconds += row.BusySeconds
+		stats.PauseSeconds += row.PauseSeconds
+	}
+
+	if finished := stats.TicketsServed + stats.NoShow; finished > 0 {
+		stats.NoShowRate = float64(stats.NoShow) * 100 / float64(finished)
+	}
+	if stats.TicketsServed > 0 {
+		stats.AvgServiceSeconds = serviceSeconds / float64(stats.TicketsServed)
+	}
+	// Pauses and service may overlap when a counter is paused mid-ticket; busy time wins
+	available := math.Max(stats.ShiftSeconds-stats.PauseSeconds, 0)
+	stats.IdleSeconds = math.Max(available-stats.BusySeconds, 0)
+	if available > 0 {
+		stats.Utilisation = math.Min(stats.BusySeconds*100/available, 100)
+	}
+	return stats
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/model"
+)
+
+func TestStaffPerformanceService_Report(t *testing.T) {
+	mockShiftRepo := new(MockStaffShiftRepository)
+	service := NewStaffPerformanceService(mockShiftRepo, new(MockUserRepository), &config.ShiftConfig{IdleGap: 2 * time.Hour})
+	now := time.Date(2026, 10, 2, 15, 0, 0, 0, time.Local)
+	service.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	filter := model.StaffPerformanceFilter{DateFrom: day, DateTo: day}
+	start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.Local)
+
+	mockShiftRepo.On("Stats", ctx, filter, now, now.Add(-2*time.Hour)).Return([]model.StaffShiftStats{
+		// Four hours with half an hour paused and two hours serving
+		{ShiftID: 1, UserID: 3, FullName: "Budi", CounterNumber: "1", StartedAt: start, EndedAt: start.Add(4 * time.Hour),
+			Completed: 8, NoShow: 2, ServiceSeconds: 4800, BusySeconds: 7200, PauseSeconds: 1800},
+		{ShiftID: 2, UserID: 3, FullName: "Budi", CounterNumber: "2", StartedAt: start.Add(5 * time.Hour), EndedAt: start.Add(6 * time.Hour),
+			Completed: 2, ServiceSeconds: 1200, BusySeconds: 3600},
+		{ShiftID: 3, UserID: 4, FullName: "Sari", CounterNumber: "3", StartedAt: start, EndedAt: start.Add(time.Hour)},
+	}, nil)
+
+	report, err := service.Report(ctx, filter)
+
+	require.NoError(t, err)
+	require.Len(t, report.Staff, 2)
+
+	budi := report.Staff[0]
+	assert.Equal(t, 3, budi.UserID)
+	require.Len(t, budi.Shifts, 2)
+	assert.Equal(t, 8, budi.Shifts[0].TicketsServed)
+	assert.InDelta(t, 20, budi.Shifts[0].NoShowRate, 0.001)
+	assert.InDelta(t, 600, budi.Shifts[0].AvgServiceSeconds, 0.001)
+	assert.InDelta(t, 5400, budi.Shifts[0].IdleSeconds, 0.001)
+	assert.InDelta(t, 7200.0*100/12600, budi.Shifts[0].Utilisation, 0.001)
+	// Busy for the whole of the second shift
+	assert.InDelta(t, 100, budi.Shifts[1].Utilisation, 0.001)
+
+	assert.Equal(t, 10, budi.TicketsServed)
+	assert.Equal(t, 2, budi.NoShow)
+	assert.InDelta(t, 600, budi.AvgServiceSeconds, 0.001)
+	assert.InDelta(t, 5*3600, budi.ShiftSeconds, 0.001)
+	assert.InDelta(t, 1800, budi.PauseSeconds, 0.001)
+	assert.InDelta(t, 5400, budi.IdleSeconds, 0.001)
+	assert.InDelta(t, 10800.0*100/16200, budi.Utilisation, 0.001)
+
+	sari := report.Staff[1]
+	assert.Equal(t, 0, sari.TicketsServed)
+	assert.Zero(t, sari.NoShowRate)
+	assert.InDelta(t, 3600, sari.IdleSeconds, 0.001)
+	assert.Zero(t, sari.Utilisation)
+	mockShiftRepo.AssertExpectations(t)
+}
+
+func TestStaffPerformanceService_Today(t *testing.T) {
+	mockShiftRepo := new(MockStaffShiftRepository)
+	service := NewStaffPerformanceService(mockShiftRepo, new(MockUserRepository), &config.ShiftConfig{IdleGap: time.Hour})
+	now := time.Date(2026, 10, 2, 9, 30, 0, 0, time.Local)
+	service.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	today := time.Date(2026, 10, 2, 0, 0, 0, 0, time.Local)
+	mockShiftRepo.On("Stats", ctx, model.StaffPerformanceFilter{DateFrom: today, DateTo: today, UserID: 5}, now, now.Add(-time.Hour)).
+		Return([]model.StaffShiftStats{}, nil)
+
+	stats, err := service.Today(ctx, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, stats.UserID)
+	assert.Empty(t, stats.Shifts)
+	mockShiftRepo.AssertExpectations(t)
+}
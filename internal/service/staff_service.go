@@ -2,10 +2,14 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
+	"tenangantri/internal/config"
 	"tenangantri/internal/dto"
+	"tenangantri/internal/event"
 	"tenangantri/internal/model"
 	"tenangantri/internal/repository"
 )
@@ -26,6 +30,10 @@ type StaffService struct {
 	ticketRepo          repository.TicketRepository
 	statsRepo           repository.StatsRepository
 	categoryRepo        repository.CategoryRepository
+	ticketCallRepo      repository.TicketCallRepository
+	performance         *StaffPerformanceService
+	events              event.Publisher
+	policy              *config.CallPolicyConfig
 }
 
 func NewStaffService(userRepo repository.UserRepository,
@@ -34,7 +42,11 @@ func NewStaffService(userRepo repository.UserRepository,
 	counterCategoryRepo repository.CounterCategoryRepository,
 	ticketRepo repository.TicketRepository,
 	statsRepo repository.StatsRepository,
-	categoryRepo repository.CategoryRepository) *StaffService {
+	categoryRepo repository.CategoryRepository,
+	ticketCallRepo repository.TicketCallRepository,
+	performance *StaffPerformanceService,
+	events event.Publisher,
+	policy *config.CallPolicyConfig) *StaffService {
 	return &StaffService{
 		userRepo:            userRepo,
 		userCounterRepo:     userCounterRepo,
@@ -43,6 +55,10 @@ func NewStaffService(userRepo repository.UserRepository,
 		ticketRepo:          ticketRepo,
 		statsRepo:           statsRepo,
 		categoryRepo:        categoryRepo,
+		ticketCallRepo:      ticketCallRepo,
+		performance:         performance,
+		events:              events,
+		policy:              policy,
 	}
 }
 
@@ -85,6 +101,15 @@ func (s *StaffService) GetDashboardData(ctx context.Context, userID int) (*dto.S
 	}
 	log.Info().Interface("currentTicket", currentTicket).Msg("Current ticket")
 
+	var currentCalls *model.TicketCallSummary
+	if currentTicket != nil {
+		currentCalls, err = s.ticketCallRepo.GetSummary(ctx, currentTicket.ID)
+		if err != nil {
+			log.Error().Err(err).Str("layer", "service").Str("func", "GetDashboardData").Msg("Failed to load current ticket calls")
+			return nil, err
+		}
+	}
+
 	// Get waiting tickets preview
 	waitingTickets, err := s.ticketRepo.GetWaitingPreviewByCategories(ctx, categoryIDs, 5)
 	if err != nil {
@@ -108,14 +133,24 @@ func (s *StaffService) GetDashboardData(ctx context.Context, userID int) (*dto.S
 		return nil, err
 	}
 
+	// Opening the dashboard starts a shift
+	s.performance.RecordActivity(ctx, userID, counter.ID)
+	myStats, err := s.performance.Today(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "GetDashboardData").Msg("Failed to load staff stats")
+		return nil, err
+	}
+
 	response := &dto.StaffDashboardResponse{
 		User:             user,
 		Counter:          counter,
 		CurrentTicket:    currentTicket,
+		CurrentCalls:     currentCalls,
 		WaitingTickets:   waitingTickets,
 		QueueStats:       queueStats,
 		CompletedTickets: completedTickets,
 		CategoryIDs:      categoryIDs,
+		MyStats:          myStats,
 	}
 
 	return response, nil
@@ -163,6 +198,8 @@ func (s *StaffService) CallNext(ctx context.Context, userID int) (*model.Ticket,
 		return nil, nil // No category assigned
 	}
 
+	s.performance.RecordActivity(ctx, userID, counter.ID)
+
 	// Update counter status to serving
 	_ = s.counterRepo.UpdateStatus(ctx, counter.ID, model.CounterStatusServing)
 
@@ -179,13 +216,22 @@ func (s *StaffService) CallNext(ctx context.Context, userID int) (*model.Ticket,
 	}
 
 	// Assign ticket to counter
-	err = s.ticketRepo.AssignToCounter(ctx, nextTicket.ID, counter.ID)
+	err = s.ticketRepo.AssignToCounter(ctx, nextTicket.ID, counter.ID, userID)
 	if err != nil {
 		return nil, err
 	}
+	s.recordCall(ctx, nextTicket.ID, counter.ID, userID, model.TicketCallKindCall)
 
 	// Get full ticket details
-	return s.ticketRepo.GetWithDetails(ctx, nextTicket.ID)
+	ticket, err := s.ticketRepo.GetWithDetails(ctx, nextTicket.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(ctx, event.CounterStatusChanged{CounterID: counter.ID, Status: model.CounterStatusServing})
+	s.events.Publish(ctx, event.TicketCalled{Ticket: ticket, CounterID: counter.ID})
+
+	return ticket, nil
 }
 
 // CallAgain calls the current ticket again (re-calls)
@@ -214,14 +260,23 @@ func (s *StaffService) CallAgain(ctx context.Context, userID int) (*model.Ticket
 		return nil, nil // No ticket currently being served
 	}
 
-	// Update called_at to now (re-call)
-	err = s.ticketRepo.UpdateCalledAt(ctx, currentTicket.ID)
+	// Log the recall; called_at keeps the first call so wait time is not reset
+	err = s.ticketRepo.Recall(ctx, currentTicket.ID)
 	if err != nil {
 		return nil, err
 	}
+	s.recordCall(ctx, currentTicket.ID, counter.ID, userID, model.TicketCallKindRecall)
+	s.performance.RecordActivity(ctx, userID, counter.ID)
 
 	// Get full ticket details
-	return s.ticketRepo.GetWithDetails(ctx, currentTicket.ID)
+	ticket, err := s.ticketRepo.GetWithDetails(ctx, currentTicket.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(ctx, event.TicketRecalled{Ticket: ticket, CounterID: counter.ID})
+
+	return ticket, nil
 }
 
 // CompleteTicket completes the current ticket and sets counter to IDLE
@@ -247,13 +302,24 @@ func (s *StaffService) CompleteTicket(ctx context.Context, userID int) error {
 	}
 
 	// Update ticket to completed
-	err = s.ticketRepo.UpdateStatus(ctx, currentTicket.ID, "completed")
+	finished, err := s.ticketRepo.Finish(ctx, currentTicket.ID, "completed", userID)
 	if err != nil {
 		return err
 	}
+	if finished == 0 {
+		return nil // Finished elsewhere in the meantime
+	}
+	currentTicket.Status = "completed"
+	s.performance.RecordActivity(ctx, userID, counterIDInt)
+	s.events.Publish(ctx, event.TicketCompleted{Ticket: currentTicket, CounterID: counterIDInt})
 
 	// Set counter back to IDLE
-	return s.counterRepo.UpdateStatus(ctx, counterIDInt, model.CounterStatusIdle)
+	if err := s.counterRepo.UpdateStatus(ctx, counterIDInt, model.CounterStatusIdle); err != nil {
+		return err
+	}
+	s.events.Publish(ctx, event.CounterStatusChanged{CounterID: counterIDInt, Status: model.CounterStatusIdle})
+
+	return nil
 }
 
 // MarkNoShow marks current ticket as no-show
@@ -276,7 +342,126 @@ func (s *StaffService) MarkNoShow(ctx context.Context, userID int) error {
 		return nil // No ticket being served
 	}
 
-	return s.ticketRepo.UpdateStatus(ctx, currentTicket.ID, "no_show")
+	finished, err := s.ticketRepo.Finish(ctx, currentTicket.ID, "no_show", userID)
+	if err != nil {
+		return err
+	}
+	if finished == 0 {
+		return nil // Finished elsewhere in the meantime
+	}
+	currentTicket.Status = "no_show"
+	s.performance.RecordActivity(ctx, userID, int(counterID.Int64))
+	s.events.Publish(ctx, event.TicketNoShow{Ticket: currentTicket, CounterID: int(counterID.Int64)})
+
+	return nil
+}
+
+// MarkArrived records that the called customer came to the counter, which stops
+// the no-show policy from giving up on the ticket
+func (s *StaffService) MarkArrived(ctx context.Context, userID int) (*model.Ticket, error) {
+	counterID, err := s.userCounterRepo.GetCounterIDByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !counterID.Valid {
+		return nil, nil // No counter assigned
+	}
+
+	currentTicket, err := s.ticketRepo.GetCurrentForCounter(ctx, int(counterID.Int64))
+	if err != nil {
+		return nil, err
+	}
+
+	if currentTicket == nil {
+		return nil, nil // No ticket being served
+	}
+
+	if err := s.ticketRepo.MarkAnswered(ctx, currentTicket.ID); err != nil {
+		return nil, err
+	}
+	s.performance.RecordActivity(ctx, userID, int(counterID.Int64))
+
+	return currentTicket, nil
+}
+
+// GetTicketCalls lists every call, recall and transfer of a ticket
+func (s *StaffService) GetTicketCalls(ctx context.Context, ticketID int) ([]model.TicketCall, error) {
+	return s.ticketCallRepo.ListByTicketID(ctx, ticketID)
+}
+
+// recordCall adds an entry to the ticket's call log. The call itself has already
+// happened, so a failure is logged rather than returned.
+func (s *StaffService) recordCall(ctx context.Context, ticketID, counterID, userID int, kind string) {
+	call := &model.TicketCall{
+		TicketID:  ticketID,
+		CounterID: sql.NullInt64{Int64: int64(counterID), Valid: counterID != 0},
+		UserID:    sql.NullInt64{Int64: int64(userID), Valid: userID != 0},
+		Kind:      kind,
+	}
+	if _, err := s.ticketCallRepo.Create(ctx, call); err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "recordCall").Int("ticket_id", ticketID).Msg("Failed to log ticket call")
+	}
+}
+
+// RunNoShowSweeper marks unanswered calls as no-shows according to the call policy,
+// unless both of its triggers are off
+func (s *StaffService) RunNoShowSweeper(ctx context.Context, interval time.Duration) {
+	if s.policy.NoShowAfterRecalls <= 0 && s.policy.NoShowTimeout <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.SweepUnanswered(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to sweep unanswered calls")
+				continue
+			}
+			if count > 0 {
+				log.Info().Int("count", count).Msg("Marked unanswered calls as no-show")
+			}
+		}
+	}
+}
+
+// SweepUnanswered gives up on serving tickets whose customer has not arrived after
+// NoShowAfterRecalls recalls or NoShowTimeout since the last call, whichever comes first
+func (s *StaffService) SweepUnanswered(ctx context.Context) (int, error) {
+	ticketIDs, err := s.ticketCallRepo.ListUnanswered(ctx, s.policy.NoShowAfterRecalls, s.policy.NoShowTimeout)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, ticketID := range ticketIDs {
+		ticket, err := s.ticketRepo.GetWithDetails(ctx, ticketID)
+		if err != nil || ticket == nil || ticket.Status != "serving" {
+			continue
+		}
+
+		// Credited to the staff member who called it
+		finished, err := s.ticketRepo.Finish(ctx, ticket.ID, "no_show", 0)
+		if err != nil {
+			log.Error().Err(err).Str("layer", "service").Str("func", "SweepUnanswered").Int("ticket_id", ticket.ID).Msg("Failed to mark no-show")
+			continue
+		}
+		// Completed or recalled at the counter since it was read above
+		if finished != 1 {
+			continue
+		}
+		ticket.Status = "no_show"
+		s.events.Publish(ctx, event.TicketNoShow{Ticket: ticket, CounterID: int(ticket.CounterID.Int64)})
+		count++
+	}
+
+	return count, nil
 }
 
 // PauseCounter pauses the counter (staff on break)
@@ -290,7 +475,13 @@ func (s *StaffService) PauseCounter(ctx context.Context, userID int) error {
 		return nil // No counter assigned
 	}
 
-	return s.counterRepo.UpdateStatus(ctx, int(counterID.Int64), model.CounterStatusPaused)
+	if err := s.counterRepo.UpdateStatus(ctx, int(counterID.Int64), model.CounterStatusPaused); err != nil {
+		return err
+	}
+	s.performance.RecordPause(ctx, userID, int(counterID.Int64))
+	s.events.Publish(ctx, event.CounterStatusChanged{CounterID: int(counterID.Int64), Status: model.CounterStatusPaused})
+
+	return nil
 }
 
 // ResumeCounter resumes the counter from paused
@@ -304,7 +495,13 @@ func (s *StaffService) ResumeCounter(ctx context.Context, userID int) error {
 		return nil // No counter assigned
 	}
 
-	return s.counterRepo.UpdateStatus(ctx, int(counterID.Int64), model.CounterStatusIdle)
+	if err := s.counterRepo.UpdateStatus(ctx, int(counterID.Int64), model.CounterStatusIdle); err != nil {
+		return err
+	}
+	s.performance.RecordResume(ctx, userID, int(counterID.Int64))
+	s.events.Publish(ctx, event.CounterStatusChanged{CounterID: int(counterID.Int64), Status: model.CounterStatusIdle})
+
+	return nil
 }
 
 // GetQueueStatus gets queue status for staff
@@ -367,12 +564,22 @@ func (s *StaffService) GetCurrentTicket(ctx context.Context, userID int) (*model
 
 // TransferTicket transfers a ticket to another counter
 func (s *StaffService) TransferTicket(ctx context.Context, ticketID, counterID int) (*model.Ticket, error) {
-	err := s.ticketRepo.AssignToCounter(ctx, ticketID, counterID)
+	// No user is passed, so the ticket stays with the staff member who called it until
+	// someone at the new counter finishes it
+	err := s.ticketRepo.AssignToCounter(ctx, ticketID, counterID, 0)
 	if err != nil {
 		return nil, err
 	}
+	s.recordCall(ctx, ticketID, counterID, event.ActorFromContext(ctx), model.TicketCallKindTransfer)
+
+	ticket, err := s.ticketRepo.GetWithDetails(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(ctx, event.TicketTransferred{Ticket: ticket, CounterID: counterID})
 
-	return s.ticketRepo.GetWithDetails(ctx, ticketID)
+	return ticket, nil
 }
 
 // GetTicketDetail gets detailed information about a ticket including timing metrics
@@ -456,10 +663,28 @@ func (s *StaffService) GetAllTickets(ctx context.Context, userID int, filters ma
 
 // CancelTicket cancels a ticket
 func (s *StaffService) CancelTicket(ctx context.Context, ticketID int) error {
-	return s.ticketRepo.UpdateStatus(ctx, ticketID, "cancelled")
+	if err := s.ticketRepo.UpdateStatus(ctx, ticketID, "cancelled"); err != nil {
+		return err
+	}
+
+	ticket, err := s.ticketRepo.GetWithDetails(ctx, ticketID)
+	if err != nil {
+		log.Error().Err(err).Str("layer", "service").Str("func", "CancelTicket").Msg("Failed to load cancelled ticket")
+		ticket = &model.Ticket{ID: ticketID, Status: "cancelled"}
+	}
+	s.events.Publish(ctx, event.TicketCancelled{Ticket: ticket})
+
+	return nil
 }
 
 // ResetYesterdayTickets resets all yesterday's waiting tickets
 func (s *StaffService) ResetYesterdayTickets(ctx context.Context) (int, error) {
-	return s.ticketRepo.CancelYesterdayWaiting(ctx)
+	count, err := s.ticketRepo.CancelYesterdayWaiting(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	s.events.Publish(ctx, event.TicketsReset{Count: count})
+
+	return count, nil
 }
@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
+	"tenangantri/internal/config"
+	"tenangantri/internal/event"
 	"tenangantri/internal/model"
 )
 
@@ -18,8 +22,17 @@ func TestStaffService_CallNext(t *testing.T) {
 	mockTicketRepo := new(MockTicketRepository)
 	mockStatsRepo := new(MockStatsRepository)
 	mockCatRepo := new(MockCategoryRepository)
+	mockCallRepo := new(MockTicketCallRepository)
+	mockShiftRepo := new(MockStaffShiftRepository)
 
-	service := NewStaffService(mockUserRepo, mockUserCounterRepo, mockCounterRepo, mockCounterCategoryRepo, mockTicketRepo, mockStatsRepo, mockCatRepo)
+	bus := event.NewBus()
+	var published []string
+	bus.SubscribeAll(func(ctx context.Context, e event.Event) {
+		published = append(published, e.Name())
+	})
+
+	performance := NewStaffPerformanceService(mockShiftRepo, mockUserRepo, &config.ShiftConfig{IdleGap: 2 * time.Hour})
+	service := NewStaffService(mockUserRepo, mockUserCounterRepo, mockCounterRepo, mockCounterCategoryRepo, mockTicketRepo, mockStatsRepo, mockCatRepo, mockCallRepo, performance, bus, &config.CallPolicyConfig{})
 
 	ctx := context.Background()
 	staffID := 1
@@ -41,7 +54,12 @@ func TestStaffService_CallNext(t *testing.T) {
 		TicketNumber: "A010",
 	}, nil)
 
-	mockTicketRepo.On("AssignToCounter", ctx, 10, counterID).Return(nil)
+	mockTicketRepo.On("AssignToCounter", ctx, 10, counterID, staffID).Return(nil)
+	mockShiftRepo.On("RecordActivity", ctx, staffID, counterID, mock.Anything, mock.Anything).Return(nil)
+	mockCallRepo.On("Create", ctx, mock.MatchedBy(func(call *model.TicketCall) bool {
+		return call.TicketID == 10 && call.Kind == model.TicketCallKindCall &&
+			call.CounterID.Int64 == int64(counterID) && call.UserID.Int64 == int64(staffID)
+	})).Return(&model.TicketCall{ID: 1}, nil)
 	mockCounterRepo.On("UpdateStatus", ctx, counterID, "serving").Return(nil)
 	mockTicketRepo.On("GetWithDetails", ctx, 10).Return(&model.Ticket{
 		ID:           10,
@@ -56,7 +74,110 @@ func TestStaffService_CallNext(t *testing.T) {
 	assert.NotNil(t, ticket)
 	assert.Equal(t, "A010", ticket.TicketNumber)
 	assert.Equal(t, "serving", ticket.Status)
+	assert.Equal(t, []string{event.NameCounterStatusChanged, event.NameTicketCalled}, published)
 
 	mockCounterRepo.AssertExpectations(t)
 	mockTicketRepo.AssertExpectations(t)
+	mockCallRepo.AssertExpectations(t)
+	mockShiftRepo.AssertExpectations(t)
+}
+
+func TestStaffService_CallAgain(t *testing.T) {
+	mockUserCounterRepo := new(MockUserCounterRepository)
+	mockCounterRepo := new(MockCounterRepository)
+	mockTicketRepo := new(MockTicketRepository)
+	mockCallRepo := new(MockTicketCallRepository)
+	mockShiftRepo := new(MockStaffShiftRepository)
+
+	bus := event.NewBus()
+	var published []string
+	bus.SubscribeAll(func(ctx context.Context, e event.Event) {
+		published = append(published, e.Name())
+	})
+
+	performance := NewStaffPerformanceService(mockShiftRepo, new(MockUserRepository), &config.ShiftConfig{IdleGap: 2 * time.Hour})
+	service := NewStaffService(new(MockUserRepository), mockUserCounterRepo, mockCounterRepo, new(MockCounterCategoryRepository),
+		mockTicketRepo, new(MockStatsRepository), new(MockCategoryRepository), mockCallRepo, performance, bus, &config.CallPolicyConfig{})
+
+	ctx := context.Background()
+	mockUserCounterRepo.On("GetCounterIDByUserID", ctx, 1).Return(sql.NullInt64{Int64: 2, Valid: true}, nil)
+	mockCounterRepo.On("GetByID", ctx, 2).Return(&model.Counter{ID: 2, Status: "serving"}, nil)
+	mockTicketRepo.On("GetCurrentForCounter", ctx, 2).Return(&model.Ticket{ID: 10, Status: "serving"}, nil)
+	mockTicketRepo.On("Recall", ctx, 10).Return(nil)
+	mockCallRepo.On("Create", ctx, mock.MatchedBy(func(call *model.TicketCall) bool {
+		return call.TicketID == 10 && call.Kind == model.TicketCallKindRecall
+	})).Return(&model.TicketCall{ID: 2}, nil)
+	mockTicketRepo.On("GetWithDetails", ctx, 10).Return(&model.Ticket{ID: 10, Status: "serving"}, nil)
+	mockShiftRepo.On("RecordActivity", ctx, 1, 2, mock.Anything, mock.Anything).Return(nil)
+
+	ticket, err := service.CallAgain(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 10, ticket.ID)
+	assert.Equal(t, []string{event.NameTicketRecalled}, published)
+	mockTicketRepo.AssertExpectations(t)
+	mockCallRepo.AssertExpectations(t)
+}
+
+func TestStaffService_SweepUnanswered(t *testing.T) {
+	mockTicketRepo := new(MockTicketRepository)
+	mockCallRepo := new(MockTicketCallRepository)
+
+	bus := event.NewBus()
+	var noShows []int
+	event.On(bus, func(ctx context.Context, e event.TicketNoShow) {
+		noShows = append(noShows, e.Ticket.ID)
+	})
+
+	policy := &config.CallPolicyConfig{NoShowAfterRecalls: 2, NoShowTimeout: 2 * time.Minute}
+	service := NewStaffService(new(MockUserRepository), new(MockUserCounterRepository), new(MockCounterRepository), new(MockCounterCategoryRepository),
+		mockTicketRepo, new(MockStatsRepository), new(MockCategoryRepository), mockCallRepo, nil, bus, policy)
+
+	ctx := context.Background()
+	mockCallRepo.On("ListUnanswered", ctx, 2, 2*time.Minute).Return([]int{10, 11}, nil)
+	mockTicketRepo.On("GetWithDetails", ctx, 10).Return(&model.Ticket{
+		ID: 10, Status: "serving", CounterID: sql.NullInt64{Int64: 3, Valid: true},
+	}, nil)
+	// Completed by staff after the sweep query ran
+	mockTicketRepo.On("GetWithDetails", ctx, 11).Return(&model.Ticket{ID: 11, Status: "completed"}, nil)
+	// Left to the staff member who called it
+	mockTicketRepo.On("Finish", ctx, 10, "no_show", 0).Return(1, nil)
+
+	count, err := service.SweepUnanswered(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []int{10}, noShows)
+	mockTicketRepo.AssertExpectations(t)
+	mockTicketRepo.AssertNotCalled(t, "Finish", ctx, 11, "no_show", 0)
+}
+
+func TestStaffService_SweepUnansweredFinishedMeanwhile(t *testing.T) {
+	mockTicketRepo := new(MockTicketRepository)
+	mockCallRepo := new(MockTicketCallRepository)
+
+	bus := event.NewBus()
+	var noShows []int
+	event.On(bus, func(ctx context.Context, e event.TicketNoShow) {
+		noShows = append(noShows, e.Ticket.ID)
+	})
+
+	policy := &config.CallPolicyConfig{NoShowAfterRecalls: 2, NoShowTimeout: 2 * time.Minute}
+	service := NewStaffService(new(MockUserRepository), new(MockUserCounterRepository), new(MockCounterRepository), new(MockCounterCategoryRepository),
+		mockTicketRepo, new(MockStatsRepository), new(MockCategoryRepository), mockCallRepo, nil, bus, policy)
+
+	ctx := context.Background()
+	mockCallRepo.On("ListUnanswered", ctx, 2, 2*time.Minute).Return([]int{12}, nil)
+	mockTicketRepo.On("GetWithDetails", ctx, 12).Return(&model.Ticket{
+		ID: 12, Status: "serving", CounterID: sql.NullInt64{Int64: 3, Valid: true},
+	}, nil)
+	// Completed at the counter between the read and the update, so no row changes
+	mockTicketRepo.On("Finish", ctx, 12, "no_show", 0).Return(0, nil)
+
+	count, err := service.SweepUnanswered(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.Empty(t, noShows)
+	mockTicketRepo.AssertExpectations(t)
 }
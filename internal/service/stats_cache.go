@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"tenangantri/internal/dto"
+	"tenangantri/internal/repository"
+)
+
+// StatsCache keeps the last dashboard stats snapshot so every consumer of a
+// state change reads the same numbers without re-querying the database
+type StatsCache struct {
+	statsRepo repository.StatsRepository
+
+	mu    sync.Mutex
+	stats *dto.DashboardStats
+}
+
+func NewStatsCache(statsRepo repository.StatsRepository) *StatsCache {
+	return &StatsCache{statsRepo: statsRepo}
+}
+
+// Get returns the cached stats, loading them on first use or after Invalidate
+func (c *StatsCache) Get(ctx context.Context) (*dto.DashboardStats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stats != nil {
+		return c.stats, nil
+	}
+
+	stats, err := c.statsRepo.GetDashboardStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.stats = stats
+	return stats, nil
+}
+
+// Invalidate drops the cached stats so the next Get reloads them
+func (c *StatsCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats = nil
+}
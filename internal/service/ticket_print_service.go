@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/escpos"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+)
+
+// printerTexts holds the printed ticket's wording per profile language
+var printerTexts = map[string]map[string]string{
+	model.KioskLanguageIndonesian: {
+		"title": "Nomor Antrian",
+		"ahead": "Orang di depan Anda: %d",
+		"scan":  "Pindai untuk melacak antrian Anda",
+	},
+	model.KioskLanguageEnglish: {
+		"title": "Ticket Number",
+		"ahead": "People ahead of you: %d",
+		"scan":  "Scan to track your ticket",
+	},
+}
+
+// TicketPrintService prints kiosk tickets as ESC/POS on the printer a kiosk profile names
+type TicketPrintService struct {
+	ticketRepo repository.TicketRepository
+	cfg        *config.PrinterConfig
+	logo       *escpos.Bitmap
+}
+
+func NewTicketPrintService(
+	ticketRepo repository.TicketRepository,
+	cfg *config.PrinterConfig) *TicketPrintService {
+	s := &TicketPrintService{
+		ticketRepo: ticketRepo,
+		cfg:        cfg,
+	}
+
+	if cfg.LogoPath != "" {
+		logo, err := escpos.LoadBitmap(cfg.LogoPath, cfg.PaperWidth)
+		if err != nil {
+			log.Warn().Err(err).Str("path", cfg.LogoPath).Msg("Failed to load printer logo, tickets print without it")
+		} else {
+			s.logo = logo
+		}
+	}
+	return s
+}
+
+// PrintTicket prints a ticket on the profile's printer. categoryName is the category as
+// the kiosk labels it; kioskURL is the kiosk's own address, which the QR code links to
+// unless a public URL is configured.
+func (s *TicketPrintService) PrintTicket(ctx context.Context, profile *model.KioskProfile, ticket *model.Ticket, categoryName, kioskURL string) error {
+	printer, err := s.printer(profile)
+	if err != nil {
+		return err
+	}
+
+	text := printerTexts[profile.Language]
+	if text == nil {
+		text = printerTexts[model.KioskLanguageIndonesian]
+	}
+
+	details := []string{ticket.CreatedAt.Format("02/01/2006 15:04")}
+	ahead, err := s.ticketRepo.GetWaitingAheadCount(ctx, ticket.ID)
+	if err != nil {
+		log.Warn().Err(err).Int("ticket_id", ticket.ID).Msg("Failed to count tickets ahead, printing without")
+	} else {
+		details = append(details, fmt.Sprintf(text["ahead"], ahead))
+	}
+
+	baseURL := s.cfg.PublicURL
+	if baseURL == "" {
+		baseURL = kioskURL
+	}
+
+	job := (&escpos.Ticket{
+		Logo:     s.logo,
+		Title:    text["title"],
+		Number:   ticket.TicketNumber,
+		Category: categoryName,
+		Details:  details,
+		QRCode:   baseURL + "/track?ticket=" + url.QueryEscape(ticket.TicketNumber),
+		QRLabel:  text["scan"],
+	}).Encode()
+
+	if s.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+	if err := printer.Print(ctx, job); err != nil {
+		return fmt.Errorf("print ticket %s at kiosk %s: %w", ticket.TicketNumber, profile.Slug, err)
+	}
+	return nil
+}
+
+// printer returns the printer a profile prints to
+func (s *TicketPrintService) printer(profile *model.KioskProfile) (escpos.Printer, error) {
+	switch profile.PrinterType {
+	case model.KioskPrinterNetwork:
+		return &escpos.NetworkPrinter{Addr: profile.PrinterTarget, Timeout: s.cfg.Timeout}, nil
+	case model.KioskPrinterFile:
+		if err := os.MkdirAll(s.cfg.SpoolDir, 0o755); err != nil {
+			return nil, fmt.Errorf("create printer spool directory: %w", err)
+		}
+		return &escpos.FilePrinter{Path: filepath.Join(s.cfg.SpoolDir, profile.PrinterTarget)}, nil
+	default:
+		return nil, fmt.Errorf("kiosk %s prints from the browser", profile.Slug)
+	}
+}
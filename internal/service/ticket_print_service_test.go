@@ -0,0 +1,76 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/model"
+)
+
+func TestTicketPrintService_PrintTicket(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	jobs := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		job, _ := io.ReadAll(conn)
+		jobs <- job
+	}()
+
+	mockTicketRepo := new(MockTicketRepository)
+	service := NewTicketPrintService(mockTicketRepo, &config.PrinterConfig{PaperWidth: 576, Timeout: time.Second})
+	ctx := context.Background()
+	mockTicketRepo.On("GetWaitingAheadCount", ctx, 31).Return(3, nil)
+
+	profile := &model.KioskProfile{
+		Slug: "lantai-1", Language: model.KioskLanguageEnglish, TicketOutput: model.KioskOutputPrint,
+		PrinterType: model.KioskPrinterNetwork, PrinterTarget: listener.Addr().String(),
+	}
+	ticket := &model.Ticket{
+		ID: 31, TicketNumber: "B007", CategoryID: sql.NullInt64{Int64: 2, Valid: true},
+		CreatedAt: time.Date(2026, 10, 19, 8, 15, 0, 0, time.Local),
+	}
+	require.NoError(t, service.PrintTicket(ctx, profile, ticket, "Billing", "http://10.0.0.5:8080"))
+
+	var job []byte
+	select {
+	case job = <-jobs:
+	case <-time.After(2 * time.Second):
+		t.Fatal("printer received nothing")
+	}
+	for _, text := range []string{"Ticket Number\n", "B007\n", "Billing\n", "19/10/2026 08:15\n", "People ahead of you: 3\n",
+		"http://10.0.0.5:8080/track?ticket=B007"} {
+		assert.True(t, bytes.Contains(job, []byte(text)), "job is missing %q", text)
+	}
+	mockTicketRepo.AssertExpectations(t)
+}
+
+func TestTicketPrintService_PrinterDown(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	mockTicketRepo := new(MockTicketRepository)
+	service := NewTicketPrintService(mockTicketRepo, &config.PrinterConfig{Timeout: time.Second})
+	ctx := context.Background()
+	mockTicketRepo.On("GetWaitingAheadCount", ctx, 31).Return(0, nil)
+
+	profile := &model.KioskProfile{Slug: "lantai-1", PrinterType: model.KioskPrinterNetwork, PrinterTarget: addr}
+	err = service.PrintTicket(ctx, profile, &model.Ticket{ID: 31, TicketNumber: "B007"}, "Billing", "http://localhost")
+	assert.Error(t, err)
+}
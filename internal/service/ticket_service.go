@@ -8,6 +8,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"tenangantri/internal/dto"
+	"tenangantri/internal/event"
 	"tenangantri/internal/model"
 	"tenangantri/internal/repository"
 )
@@ -86,7 +87,7 @@ func (s *TicketService) GetNextTicket(ctx context.Context, categoryIDs []int) (*
 
 // AssignTicketToCounter assigns a ticket to a counter
 func (s *TicketService) AssignTicketToCounter(ctx context.Context, ticketID, counterID int) (*model.Ticket, error) {
-	err := s.ticketRepo.AssignToCounter(ctx, ticketID, counterID)
+	err := s.ticketRepo.AssignToCounter(ctx, ticketID, counterID, event.ActorFromContext(ctx))
 	if err != nil {
 		return nil, err
 	}
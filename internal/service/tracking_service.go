@@ -55,6 +55,7 @@ func (s *TrackingService) GetTicketTrackingInfo(ctx context.Context, ticketNumbe
 	// Add category information
 	if ticket.CategoryID.Valid {
 		catID := int(ticket.CategoryID.Int64)
+		trackingInfo.CategoryID = catID
 		category, err := s.categoryRepo.GetByID(ctx, catID)
 		if err == nil && category != nil {
 			trackingInfo.CategoryName = category.Name
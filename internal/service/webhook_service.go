@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+	"tenangantri/internal/repository"
+	"tenangantri/internal/webhook"
+)
+
+// WebhookSender posts one signed payload to a subscriber
+type WebhookSender interface {
+	Send(ctx context.Context, url, secret string, deliveryID int64, eventType string, body []byte) (int, error)
+}
+
+// WebhookService manages webhook subscriptions and dispatches outbox events to them
+type WebhookService struct {
+	webhookRepo repository.WebhookRepository
+	sender      WebhookSender
+	cfg         *config.WebhookConfig
+	wake        chan struct{}
+	now         func() time.Time
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository, sender WebhookSender, cfg *config.WebhookConfig) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+		cfg:         cfg,
+		wake:        make(chan struct{}, 1),
+		now:         time.Now,
+	}
+}
+
+// ListSubscriptions returns all webhook subscriptions
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	return s.webhookRepo.ListSubscriptions(ctx)
+}
+
+// GetSubscription returns a webhook subscription by ID
+func (s *WebhookService) GetSubscription(ctx context.Context, id int) (*model.WebhookSubscription, error) {
+	sub, err := s.webhookRepo.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, fmt.Errorf("webhook subscription not found")
+	}
+	return sub, nil
+}
+
+// CreateSubscription creates a webhook subscription, generating a secret when none is given
+func (s *WebhookService) CreateSubscription(ctx context.Context, req *dto.WebhookSubscriptionRequest) (*model.WebhookSubscription, error) {
+	if err := validateWebhookEventTypes(req.EventTypes); err != nil {
+		return nil, err
+	}
+
+	secret := strings.TrimSpace(req.Secret)
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	return s.webhookRepo.CreateSubscription(ctx, &model.WebhookSubscription{
+		Name:       strings.TrimSpace(req.Name),
+		URL:        strings.TrimSpace(req.URL),
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		IsActive:   isActive,
+	})
+}
+
+// UpdateSubscription updates a webhook subscription; an empty secret keeps the current one
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id int, req *dto.WebhookSubscriptionRequest) (*model.WebhookSubscription, error) {
+	if err := validateWebhookEventTypes(req.EventTypes); err != nil {
+		return nil, err
+	}
+
+	sub, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.Name = strings.TrimSpace(req.Name)
+	sub.URL = strings.TrimSpace(req.URL)
+	sub.EventTypes = req.EventTypes
+	if secret := strings.TrimSpace(req.Secret); secret != "" {
+		sub.Secret = secret
+	}
+	if req.IsActive != nil {
+		sub.IsActive = *req.IsActive
+	}
+
+	if err := s.webhookRepo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// DeleteSubscription deletes a webhook subscription and its delivery log
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id int) error {
+	return s.webhookRepo.DeleteSubscription(ctx, id)
+}
+
+// ListDeliveries returns the most recent deliveries, optionally filtered by status and subscription
+func (s *WebhookService) ListDeliveries(ctx context.Context, status string, subscriptionID, limit int) ([]model.WebhookDelivery, error) {
+	return s.webhookRepo.ListDeliveries(ctx, status, subscriptionID, limit)
+}
+
+// DeliveryCounts returns the number of deliveries per status
+func (s *WebhookService) DeliveryCounts(ctx context.Context) (map[string]int, error) {
+	return s.webhookRepo.CountDeliveriesByStatus(ctx)
+}
+
+// Redeliver queues a delivery again, including dead-lettered ones
+func (s *WebhookService) Redeliver(ctx context.Context, id int64) error {
+	if err := s.webhookRepo.Redeliver(ctx, id); err != nil {
+		return err
+	}
+	s.Wake()
+	return nil
+}
+
+// Wake asks the dispatcher to run now instead of waiting for the next poll
+func (s *WebhookService) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// RunDispatcher delivers outbox events until ctx is cancelled
+func (s *WebhookService) RunDispatcher(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	purgeTicker := time.NewTicker(time.Hour)
+	defer purgeTicker.Stop()
+
+	for {
+		s.DispatchOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		case <-purgeTicker.C:
+			count, err := s.webhookRepo.PurgeProcessedOutbox(ctx, s.cfg.OutboxRetention)
+			if err != nil {
+				log.Error().Err(err).Str("layer", "service").Str("func", "RunDispatcher").Msg("Failed to purge outbox events")
+			} else if count > 0 {
+				log.Info().Int("count", count).Msg("Purged processed outbox events")
+			}
+		}
+	}
+}
+
+// DispatchOnce fans out pending outbox events and sends every delivery that is due
+func (s *WebhookService) DispatchOnce(ctx context.Context) {
+	for {
+		processed, err := s.webhookRepo.FanOutOutbox(ctx, s.cfg.BatchSize)
+		if err != nil || processed < s.cfg.BatchSize {
+			break
+		}
+	}
+
+	for ctx.Err() == nil {
+		deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, s.cfg.BatchSize, 2*s.cfg.Timeout)
+		if err != nil || len(deliveries) == 0 {
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			go func(d model.WebhookDelivery) {
+				defer wg.Done()
+				s.deliver(ctx, d)
+			}(delivery)
+		}
+		wg.Wait()
+
+		if len(deliveries) < s.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+func (s *WebhookService) deliver(ctx context.Context, d model.WebhookDelivery) {
+	body, err := json.Marshal(dto.WebhookEvent{
+		ID:         d.EventID,
+		Type:       d.EventType,
+		OccurredAt: d.OccurredAt.Format(time.RFC3339),
+		Data:       d.Payload,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("layer", "service").Int64("delivery_id", d.ID).Msg("Failed to encode webhook payload")
+		return
+	}
+
+	statusCode, sendErr := s.sender.Send(ctx, d.URL, d.Secret, d.ID, d.EventType, body)
+	if sendErr == nil {
+		if err := s.webhookRepo.MarkDelivered(ctx, d.ID, statusCode); err != nil {
+			log.Error().Err(err).Str("layer", "service").Int64("delivery_id", d.ID).Msg("Failed to mark webhook delivered")
+		}
+		return
+	}
+
+	dead := d.Attempts >= s.cfg.MaxAttempts
+	nextAttemptAt := s.now().Add(webhook.Backoff(d.Attempts, s.cfg.BackoffBase, s.cfg.BackoffMax))
+
+	log.Warn().Err(sendErr).Str("layer", "service").Int64("delivery_id", d.ID).Int("attempts", d.Attempts).Bool("dead", dead).Msg("Webhook delivery failed")
+
+	if err := s.webhookRepo.MarkFailed(ctx, d.ID, statusCode, sendErr.Error(), nextAttemptAt, dead); err != nil {
+		log.Error().Err(err).Str("layer", "service").Int64("delivery_id", d.ID).Msg("Failed to record webhook failure")
+	}
+}
+
+func validateWebhookEventTypes(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return fmt.Errorf("select at least one event type")
+	}
+	for _, eventType := range eventTypes {
+		if !slices.Contains(model.WebhookEventTypes, eventType) {
+			return fmt.Errorf("unknown event type: %s", eventType)
+		}
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
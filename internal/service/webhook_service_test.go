@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+	"tenangantri/internal/model"
+)
+
+type fakeWebhookSender struct {
+	status int
+	err    error
+	bodies [][]byte
+}
+
+func (f *fakeWebhookSender) Send(ctx context.Context, url, secret string, deliveryID int64, eventType string, body []byte) (int, error) {
+	f.bodies = append(f.bodies, body)
+	return f.status, f.err
+}
+
+func testWebhookConfig() *config.WebhookConfig {
+	return &config.WebhookConfig{
+		BatchSize:   10,
+		MaxAttempts: 3,
+		BackoffBase: 30 * time.Second,
+		BackoffMax:  time.Hour,
+		Timeout:     5 * time.Second,
+	}
+}
+
+func TestWebhookService_DispatchOnce_Delivered(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	sender := &fakeWebhookSender{status: http.StatusOK}
+	service := NewWebhookService(mockRepo, sender, testWebhookConfig())
+
+	ctx := context.Background()
+	occurredAt := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	mockRepo.On("FanOutOutbox", ctx, 10).Return(1, nil)
+	mockRepo.On("ClaimDueDeliveries", ctx, 10, 10*time.Second).Return([]model.WebhookDelivery{
+		{ID: 7, EventID: 3, EventType: model.WebhookEventTicketCalled, Payload: json.RawMessage(`{"ticket_number":"A001"}`), OccurredAt: occurredAt, Attempts: 1, URL: "http://hook", Secret: "s"},
+	}, nil).Once()
+	mockRepo.On("MarkDelivered", ctx, int64(7), http.StatusOK).Return(nil)
+
+	service.DispatchOnce(ctx)
+
+	mockRepo.AssertExpectations(t)
+	assert.Len(t, sender.bodies, 1)
+
+	var event dto.WebhookEvent
+	assert.NoError(t, json.Unmarshal(sender.bodies[0], &event))
+	assert.Equal(t, int64(3), event.ID)
+	assert.Equal(t, model.WebhookEventTicketCalled, event.Type)
+	assert.Equal(t, "2026-01-05T09:00:00Z", event.OccurredAt)
+}
+
+func TestWebhookService_DispatchOnce_RetryWithBackoff(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	sender := &fakeWebhookSender{status: http.StatusBadGateway, err: errors.New("subscriber responded 502")}
+	service := NewWebhookService(mockRepo, sender, testWebhookConfig())
+	now := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	mockRepo.On("FanOutOutbox", ctx, 10).Return(0, nil)
+	mockRepo.On("ClaimDueDeliveries", ctx, 10, 10*time.Second).Return([]model.WebhookDelivery{
+		{ID: 8, Attempts: 2, URL: "http://hook"},
+	}, nil).Once()
+	mockRepo.On("MarkFailed", ctx, int64(8), http.StatusBadGateway, "subscriber responded 502", now.Add(time.Minute), false).Return(nil)
+
+	service.DispatchOnce(ctx)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_DispatchOnce_DeadLetter(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	sender := &fakeWebhookSender{err: errors.New("connection refused")}
+	service := NewWebhookService(mockRepo, sender, testWebhookConfig())
+
+	ctx := context.Background()
+
+	mockRepo.On("FanOutOutbox", ctx, 10).Return(0, nil)
+	mockRepo.On("ClaimDueDeliveries", ctx, 10, 10*time.Second).Return([]model.WebhookDelivery{
+		{ID: 9, Attempts: 3, URL: "http://hook"},
+	}, nil).Once()
+	mockRepo.On("MarkFailed", ctx, int64(9), 0, "connection refused", mock.Anything, true).Return(nil)
+
+	service.DispatchOnce(ctx)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_CreateSubscription_GeneratesSecret(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	service := NewWebhookService(mockRepo, &fakeWebhookSender{}, testWebhookConfig())
+
+	ctx := context.Background()
+	mockRepo.On("CreateSubscription", ctx, mock.MatchedBy(func(sub *model.WebhookSubscription) bool {
+		return sub.Secret != "" && sub.IsActive && sub.URL == "https://his.example/hook"
+	})).Return(&model.WebhookSubscription{ID: 1}, nil)
+
+	_, err := service.CreateSubscription(ctx, &dto.WebhookSubscriptionRequest{
+		Name:       "HIS",
+		URL:        " https://his.example/hook ",
+		EventTypes: []string{model.WebhookEventTicketIssued},
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_CreateSubscription_UnknownEvent(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	service := NewWebhookService(mockRepo, &fakeWebhookSender{}, testWebhookConfig())
+
+	_, err := service.CreateSubscription(context.Background(), &dto.WebhookSubscriptionRequest{
+		Name:       "HIS",
+		URL:        "https://his.example/hook",
+		EventTypes: []string{"ticket.exploded"},
+	})
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"ticket.called"}`)
+
+	header := Sign("s3cret", now, body)
+
+	assert.True(t, Verify("s3cret", header, body, now, 5*time.Minute))
+	assert.False(t, Verify("other", header, body, now, 5*time.Minute))
+	assert.False(t, Verify("s3cret", header, []byte(`{}`), now, 5*time.Minute))
+	assert.False(t, Verify("s3cret", header, body, now.Add(10*time.Minute), 5*time.Minute))
+}
+
+func TestSender_Send(t *testing.T) {
+	var gotHeader http.Header
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	body := []byte(`{"id":1}`)
+	status, err := NewSender(time.Second).Send(context.Background(), server.URL, "s3cret", 42, "ticket.issued", body)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Equal(t, "ticket.issued", gotHeader.Get(HeaderEvent))
+	assert.Equal(t, "42", gotHeader.Get(HeaderDelivery))
+	assert.True(t, Verify("s3cret", gotHeader.Get(HeaderSignature), gotBody, time.Now(), time.Minute))
+}
+
+func TestSender_SendNon2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	status, err := NewSender(time.Second).Send(context.Background(), server.URL, "s3cret", 1, "ticket.issued", []byte(`{}`))
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadGateway, status)
+}
+
+func TestBackoff(t *testing.T) {
+	base := 30 * time.Second
+	max := 10 * time.Minute
+
+	assert.Equal(t, 30*time.Second, Backoff(1, base, max))
+	assert.Equal(t, 60*time.Second, Backoff(2, base, max))
+	assert.Equal(t, 4*time.Minute, Backoff(4, base, max))
+	assert.Equal(t, max, Backoff(10, base, max))
+}
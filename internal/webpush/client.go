@@ -0,0 +1,114 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrSubscriptionGone is returned when the push service reports the subscription no longer exists
+var ErrSubscriptionGone = errors.New("push subscription expired or unsubscribed")
+
+// Subscription identifies a browser push endpoint and its encryption keys
+type Subscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// Options control how the push service treats a message
+type Options struct {
+	TTL     time.Duration
+	Urgency string
+	Topic   string
+}
+
+// Client sends encrypted, VAPID-signed messages to push services
+type Client struct {
+	keys       *VAPIDKeys
+	subject    string
+	httpClient *http.Client
+	// checkEndpoint vets an endpoint before every send; tests talking to a local stub replace it
+	checkEndpoint func(ctx context.Context, endpoint string) error
+}
+
+func NewClient(keys *VAPIDKeys, subject string) *Client {
+	return &Client{
+		keys:          keys,
+		subject:       subject,
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+		checkEndpoint: CheckEndpoint,
+	}
+}
+
+// PublicKey returns the VAPID public key browsers subscribe with
+func (c *Client) PublicKey() string {
+	return c.keys.PublicKeyString()
+}
+
+// CheckEndpoint returns ErrEndpointNotAllowed for an endpoint the client refuses to send to
+func (c *Client) CheckEndpoint(ctx context.Context, endpoint string) error {
+	return c.checkEndpoint(ctx, endpoint)
+}
+
+// Send encrypts payload for sub and delivers it to the subscription endpoint. The endpoint
+// is checked again here, as its host may resolve elsewhere than when it was subscribed.
+func (c *Client) Send(ctx context.Context, sub Subscription, payload []byte, opts Options) error {
+	if err := c.checkEndpoint(ctx, sub.Endpoint); err != nil {
+		return err
+	}
+
+	body, err := encrypt(payload, sub.P256dh, sub.Auth)
+	if err != nil {
+		return fmt.Errorf("encrypt push payload: %w", err)
+	}
+
+	authorization, err := c.keys.authorization(sub.Endpoint, c.subject, 12*time.Hour)
+	if err != nil {
+		return fmt.Errorf("sign push request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	urgency := opts.Urgency
+	if urgency == "" {
+		urgency = "normal"
+	}
+
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(ttl.Seconds())))
+	req.Header.Set("Urgency", urgency)
+	if opts.Topic != "" {
+		req.Header.Set("Topic", opts.Topic)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	default:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("push service responded %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+}
@@ -0,0 +1,188 @@
+package webpush
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pushStub plays the part of a browser push service and the user agent behind it
+type pushStub struct {
+	uaPrivate *ecdh.PrivateKey
+	auth      []byte
+	status    int
+
+	received  []byte
+	headers   http.Header
+	plaintext []byte
+}
+
+func newPushStub(t *testing.T) *pushStub {
+	uaPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	auth := make([]byte, 16)
+	_, err = rand.Read(auth)
+	require.NoError(t, err)
+
+	return &pushStub{uaPrivate: uaPrivate, auth: auth, status: http.StatusCreated}
+}
+
+func (s *pushStub) subscription(endpoint string) Subscription {
+	return Subscription{
+		Endpoint: endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(s.uaPrivate.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(s.auth),
+	}
+}
+
+func (s *pushStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.headers = r.Header.Clone()
+	s.received, _ = io.ReadAll(r.Body)
+	w.WriteHeader(s.status)
+}
+
+// decrypt reverses the aes128gcm content coding as a browser would
+func (s *pushStub) decrypt(t *testing.T) []byte {
+	body := s.received
+	require.Greater(t, len(body), 21)
+
+	salt := body[:16]
+	rs := binary.BigEndian.Uint32(body[16:20])
+	idLen := int(body[20])
+	asPublicBytes := body[21 : 21+idLen]
+	ciphertext := body[21+idLen:]
+	assert.Equal(t, uint32(recordSize), rs)
+
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicBytes)
+	require.NoError(t, err)
+	shared, err := s.uaPrivate.ECDH(asPublic)
+	require.NoError(t, err)
+
+	cek, nonce, err := deriveKeys(shared, s.auth, salt, s.uaPrivate.PublicKey().Bytes(), asPublicBytes)
+	require.NoError(t, err)
+
+	block, err := aes.NewCipher(cek)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	require.NoError(t, err)
+	require.Equal(t, byte(0x02), plaintext[len(plaintext)-1])
+
+	return plaintext[:len(plaintext)-1]
+}
+
+func TestClient_Send(t *testing.T) {
+	stub := newPushStub(t)
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	keys, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+
+	client := NewClient(keys, "mailto:ops@example.com")
+	client.checkEndpoint = allowAnyEndpoint
+	payload := []byte(`{"title":"Giliran Anda","body":"A001 silakan menuju Loket 1"}`)
+
+	err = client.Send(context.Background(), stub.subscription(server.URL+"/push/abc"), payload, Options{TTL: 5 * time.Minute, Urgency: "high"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "aes128gcm", stub.headers.Get("Content-Encoding"))
+	assert.Equal(t, "300", stub.headers.Get("TTL"))
+	assert.Equal(t, "high", stub.headers.Get("Urgency"))
+	assert.Equal(t, payload, stub.decrypt(t))
+
+	// Verify the VAPID JWT against the advertised public key
+	authHeader := stub.headers.Get("Authorization")
+	require.True(t, strings.HasPrefix(authHeader, "vapid t="))
+	parts := strings.Split(strings.TrimPrefix(authHeader, "vapid "), ", ")
+	require.Len(t, parts, 2)
+	token := strings.TrimPrefix(parts[0], "t=")
+	assert.Equal(t, "k="+keys.PublicKeyString(), parts[1])
+
+	pubBytes, err := base64.RawURLEncoding.DecodeString(keys.PublicKeyString())
+	require.NoError(t, err)
+	pub := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(pubBytes[1:33]),
+		Y:     new(big.Int).SetBytes(pubBytes[33:]),
+	}
+
+	claims := jwt.MapClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return pub, nil })
+	require.NoError(t, err)
+	assert.True(t, parsed.Valid)
+	assert.Equal(t, server.URL, claims["aud"])
+	assert.Equal(t, "mailto:ops@example.com", claims["sub"])
+}
+
+func TestClient_SendGone(t *testing.T) {
+	stub := newPushStub(t)
+	stub.status = http.StatusGone
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	keys, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+
+	client := NewClient(keys, "mailto:ops@example.com")
+	client.checkEndpoint = allowAnyEndpoint
+	err = client.Send(context.Background(), stub.subscription(server.URL), []byte("{}"), Options{})
+
+	assert.ErrorIs(t, err, ErrSubscriptionGone)
+}
+
+// allowAnyEndpoint lets a client send to the plain-HTTP loopback stub
+func allowAnyEndpoint(ctx context.Context, endpoint string) error { return nil }
+
+func TestClient_SendRefusesLocalEndpoint(t *testing.T) {
+	keys, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+
+	client := NewClient(keys, "mailto:ops@example.com")
+	for _, endpoint := range []string{
+		"http://push.example.com/abc",
+		"https://127.0.0.1/abc",
+		"https://localhost:8080/abc",
+		"https://10.0.0.5/abc",
+		"https://192.168.1.1/abc",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::1]/abc",
+		"https://[fd00::1]/abc",
+		"https://0.0.0.0/abc",
+	} {
+		err := client.Send(context.Background(), Subscription{Endpoint: endpoint}, []byte("{}"), Options{})
+		assert.ErrorIs(t, err, ErrEndpointNotAllowed, endpoint)
+	}
+
+	assert.NoError(t, CheckEndpoint(context.Background(), "https://203.0.113.10/push/abc"))
+}
+
+func TestParseVAPIDPrivateKey_RoundTrip(t *testing.T) {
+	keys, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+
+	parsed, err := ParseVAPIDPrivateKey(keys.PrivateKeyString())
+	require.NoError(t, err)
+
+	assert.Equal(t, keys.PublicKeyString(), parsed.PublicKeyString())
+}
@@ -0,0 +1,113 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+// recordSize is the aes128gcm record size advertised in the content coding header
+const recordSize = 4096
+
+// maxPayloadSize keeps the plaintext inside a single record
+const maxPayloadSize = recordSize - 16 - 1
+
+// encrypt encrypts a message for a subscription using the aes128gcm content coding (RFC 8291, RFC 8188)
+func encrypt(payload []byte, p256dh, authSecret string) ([]byte, error) {
+	if len(payload) > maxPayloadSize {
+		return nil, errors.New("push payload too large")
+	}
+
+	uaPublicBytes, err := decodeBase64(p256dh)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := decodeBase64(authSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	curve := ecdh.P256()
+	uaPublic, err := curve.NewPublicKey(uaPublicBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	asPrivate, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublicBytes := asPrivate.PublicKey().Bytes()
+
+	sharedSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	cek, nonce, err := deriveKeys(sharedSecret, auth, salt, uaPublicBytes, asPublicBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// A single record terminated by the last-record delimiter
+	plaintext := make([]byte, 0, len(payload)+1)
+	plaintext = append(plaintext, payload...)
+	plaintext = append(plaintext, 0x02)
+
+	header := make([]byte, 0, 16+4+1+len(asPublicBytes))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(asPublicBytes)))
+	header = append(header, asPublicBytes...)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// deriveKeys derives the content encryption key and nonce shared by sender and receiver
+func deriveKeys(sharedSecret, auth, salt, uaPublic, asPublic []byte) ([]byte, []byte, error) {
+	prkKey, err := hkdf.Extract(sha256.New, sharedSecret, auth)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyInfo := "WebPush: info\x00" + string(uaPublic) + string(asPublic)
+	ikm, err := hkdf.Expand(sha256.New, prkKey, keyInfo, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, nonce, nil
+}
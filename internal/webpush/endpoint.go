@@ -0,0 +1,42 @@
+package webpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+)
+
+// ErrEndpointNotAllowed is returned for an endpoint that is not an https URL on a public address
+var ErrEndpointNotAllowed = errors.New("push endpoint must be a public https URL")
+
+// CheckEndpoint returns ErrEndpointNotAllowed unless endpoint is an https URL whose host
+// resolves to public addresses only. Endpoints come from anonymous browsers, so this keeps
+// a subscription from aiming the server's push requests at the local network.
+func CheckEndpoint(ctx context.Context, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return ErrEndpointNotAllowed
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("resolve push endpoint: %w", err)
+	}
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return ErrEndpointNotAllowed
+		}
+	}
+	return nil
+}
+
+// publicAddr reports whether addr is reachable on the internet rather than on the host or
+// the local network; global unicast already leaves out loopback, link-local, multicast and
+// unspecified addresses
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
@@ -0,0 +1,131 @@
+package webpush
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// VAPIDKeys holds the application server key pair used to sign push requests (RFC 8292)
+type VAPIDKeys struct {
+	privateKey *ecdsa.PrivateKey
+}
+
+// GenerateVAPIDKeys creates a new P-256 key pair
+func GenerateVAPIDKeys() (*VAPIDKeys, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &VAPIDKeys{privateKey: priv}, nil
+}
+
+// ParseVAPIDPrivateKey parses a base64url-encoded raw P-256 private scalar,
+// the format used by most Web Push libraries
+func ParseVAPIDPrivateKey(encoded string) (*VAPIDKeys, error) {
+	raw, err := decodeBase64(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key encoding: %w", err)
+	}
+
+	ecdhKey, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(ecdhKey)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	priv, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("VAPID private key is not an ECDSA key")
+	}
+	return &VAPIDKeys{privateKey: priv}, nil
+}
+
+// LoadOrCreateVAPIDKeys reads the key from path, generating and persisting a new one if the file does not exist
+func LoadOrCreateVAPIDKeys(path string) (*VAPIDKeys, error) {
+	content, err := os.ReadFile(path)
+	if err == nil {
+		return ParseVAPIDPrivateKey(string(content))
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	keys, err := GenerateVAPIDKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(keys.PrivateKeyString()), 0o600); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// PublicKey returns the uncompressed public key point
+func (k *VAPIDKeys) PublicKey() []byte {
+	pub, _ := k.privateKey.PublicKey.ECDH()
+	return pub.Bytes()
+}
+
+// PublicKeyString returns the public key as base64url, the applicationServerKey browsers expect
+func (k *VAPIDKeys) PublicKeyString() string {
+	return base64.RawURLEncoding.EncodeToString(k.PublicKey())
+}
+
+// PrivateKeyString returns the raw private scalar as base64url
+func (k *VAPIDKeys) PrivateKeyString() string {
+	priv, _ := k.privateKey.ECDH()
+	return base64.RawURLEncoding.EncodeToString(priv.Bytes())
+}
+
+// authorization builds the "vapid" Authorization header value for a push service endpoint
+func (k *VAPIDKeys) authorization(endpoint, subject string, expiry time.Duration) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(expiry).Unix(),
+		"sub": subject,
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(k.privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return "vapid t=" + token + ", k=" + k.PublicKeyString(), nil
+}
+
+// decodeBase64 accepts both padded and unpadded, standard and URL-safe base64
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+	return base64.RawURLEncoding.DecodeString(s)
+}
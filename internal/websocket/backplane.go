@@ -0,0 +1,144 @@
+package websocket
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Backplane relays hub broadcasts between app instances
+type Backplane interface {
+	// Publish sends message to every instance, including this one
+	Publish(ctx context.Context, message []byte) error
+	// Listen calls deliver for every message published by any instance until ctx is done
+	Listen(ctx context.Context, deliver func(message []byte)) error
+}
+
+// relayEnvelope wraps a broadcast with its origin so instances can drop their own
+// echoes and any message the backplane delivers twice. ID is the message id
+// clients see, so it is the same on every instance.
+type relayEnvelope struct {
+	Origin        string          `json:"origin"`
+	ID            string          `json:"id"`
+	Topics        []string        `json:"topics"`
+	Type          string          `json:"type"`
+	Timestamp     time.Time       `json:"timestamp"`
+	Branch        string          `json:"branch"`
+	Payload       json.RawMessage `json:"payload"`
+	PublicPayload json.RawMessage `json:"public_payload,omitempty"`
+}
+
+// seenSet remembers the most recent relay ids in a fixed-size ring
+type seenSet struct {
+	mu   sync.Mutex
+	ids  map[string]struct{}
+	ring []string
+	next int
+}
+
+func newSeenSet(size int) *seenSet {
+	return &seenSet{
+		ids:  make(map[string]struct{}, size),
+		ring: make([]string, size),
+	}
+}
+
+// add records id and reports whether it was new
+func (s *seenSet) add(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.ids[id]; ok {
+		return false
+	}
+
+	if old := s.ring[s.next]; old != "" {
+		delete(s.ids, old)
+	}
+	s.ring[s.next] = id
+	s.next = (s.next + 1) % len(s.ring)
+	s.ids[id] = struct{}{}
+	return true
+}
+
+func randomID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// UseBackplane relays every broadcast through bp and delivers messages from other
+// instances to local clients. It blocks until ctx is cancelled.
+func (h *Hub) UseBackplane(ctx context.Context, bp Backplane) {
+	outbound := make(chan message, 256)
+	h.mu.Lock()
+	h.outbound = outbound
+	h.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg := <-outbound:
+				h.relay(ctx, bp, msg)
+			}
+		}
+	}()
+
+	err := bp.Listen(ctx, h.receiveRelay)
+	if err != nil && ctx.Err() == nil {
+		log.Error().Err(err).Str("instance", h.instanceID).Msg("Backplane listener stopped")
+	}
+}
+
+// relay publishes a locally originated message to the other instances
+func (h *Hub) relay(ctx context.Context, bp Backplane, msg message) {
+	envelope, err := json.Marshal(relayEnvelope{
+		Origin:        h.instanceID,
+		ID:            msg.id,
+		Topics:        msg.topics,
+		Type:          msg.messageType,
+		Timestamp:     msg.timestamp,
+		Branch:        msg.branch,
+		Payload:       msg.payload,
+		PublicPayload: msg.publicPayload,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal backplane envelope")
+		return
+	}
+
+	if err := bp.Publish(ctx, envelope); err != nil {
+		log.Error().Err(err).Str("instance", h.instanceID).Msg("Failed to publish to backplane")
+	}
+}
+
+// receiveRelay delivers a message from another instance to local clients
+func (h *Hub) receiveRelay(raw []byte) {
+	var envelope relayEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		log.Warn().Err(err).Msg("Dropping malformed backplane message")
+		return
+	}
+
+	if envelope.Origin == h.instanceID || !h.seen.add(envelope.Origin+":"+envelope.ID) {
+		return
+	}
+
+	// Relayed messages take this instance's next sequence number like local ones
+	h.enqueue(message{
+		topics:        envelope.Topics,
+		id:            envelope.ID,
+		messageType:   envelope.Type,
+		timestamp:     envelope.Timestamp,
+		branch:        envelope.Branch,
+		payload:       envelope.Payload,
+		publicPayload: envelope.PublicPayload,
+	})
+}
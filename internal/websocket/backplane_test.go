@@ -0,0 +1,116 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+)
+
+// memBackplane fans every publish out to all listeners, optionally twice to
+// mimic a redelivery after reconnect
+type memBackplane struct {
+	mu        sync.Mutex
+	listeners []func([]byte)
+	duplicate bool
+}
+
+func (b *memBackplane) Publish(ctx context.Context, message []byte) error {
+	b.mu.Lock()
+	listeners := append([]func([]byte){}, b.listeners...)
+	b.mu.Unlock()
+
+	for _, deliver := range listeners {
+		deliver(message)
+		if b.duplicate {
+			deliver(message)
+		}
+	}
+	return nil
+}
+
+func (b *memBackplane) Listen(ctx context.Context, deliver func([]byte)) error {
+	b.mu.Lock()
+	b.listeners = append(b.listeners, deliver)
+	b.mu.Unlock()
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (b *memBackplane) listenerCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.listeners)
+}
+
+func startHub(t *testing.T, ctx context.Context, bp Backplane) (*Hub, *Client) {
+	hub := NewHub(&config.WebSocketConfig{Branch: "north"})
+	go hub.Run()
+	go hub.UseBackplane(ctx, bp)
+
+	client := &Client{hub: hub, send: make(chan []byte, 16), topics: make(map[string]struct{})}
+	client.subscribe([]string{TopicDisplayAll})
+	hub.register <- client
+	assert.Contains(t, receive(t, client), `"hello"`)
+	return hub, client
+}
+
+func receive(t *testing.T, client *Client) string {
+	select {
+	case msg := <-client.send:
+		return string(msg)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+		return ""
+	}
+}
+
+func assertNoMessage(t *testing.T, client *Client) {
+	select {
+	case msg := <-client.send:
+		t.Fatalf("unexpected message %s", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHub_Backplane_RelaysBetweenInstances(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bp := &memBackplane{duplicate: true}
+	hubA, clientA := startHub(t, ctx, bp)
+	_, clientB := startHub(t, ctx, bp)
+	require.Eventually(t, func() bool { return bp.listenerCount() == 2 }, time.Second, 5*time.Millisecond)
+
+	hubA.Publish([]string{TopicDisplayAll}, "ticket_update", map[string]string{"ticket_number": "A001"})
+
+	// Each instance numbers the messages it delivers, but the message keeps its id
+	fromA := assertEnvelope(t, receive(t, clientA), "ticket_update", `{"ticket_number":"A001"}`)
+	fromB := assertEnvelope(t, receive(t, clientB), "ticket_update", `{"ticket_number":"A001"}`)
+	assert.Equal(t, uint64(1), fromA.Seq)
+	assert.Equal(t, uint64(1), fromB.Seq)
+	assert.NotEmpty(t, fromA.ID)
+	assert.Equal(t, fromA.ID, fromB.ID)
+	assert.Equal(t, "north", fromB.Branch)
+	assert.Equal(t, fromA.Timestamp, fromB.Timestamp)
+
+	// Neither the echo back to A nor the duplicate delivery reaches a client twice
+	assertNoMessage(t, clientA)
+	assertNoMessage(t, clientB)
+}
+
+func TestSeenSet_EvictsOldest(t *testing.T) {
+	seen := newSeenSet(2)
+
+	assert.True(t, seen.add("a"))
+	assert.False(t, seen.add("a"))
+	assert.True(t, seen.add("b"))
+	assert.True(t, seen.add("c"))
+	assert.True(t, seen.add("a"), "a should have been evicted")
+}
@@ -1,145 +1,538 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
-	"sync"
-
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
 )
 
+const (
+	writeWait             = 10 * time.Second
+	defaultPingInterval   = 25 * time.Second
+	defaultBroadcastQueue = 1024
+	defaultClientBuffer   = 256
+)
+
+// Hub fans messages out to connected clients. Run is the only goroutine that
+// touches the client map, so registering, dropping and broadcasting never race;
+// everyone else talks to it through channels that do not block the caller.
 type Hub struct {
 	clients    map[*Client]bool
-	broadcast  chan []byte
+	broadcast  chan message
 	register   chan *Client
 	unregister chan *Client
-	mu         sync.RWMutex
+
+	// quit is closed by Shutdown, done when Run has disconnected every client
+	quit         chan struct{}
+	done         chan struct{}
+	shutdownOnce sync.Once
+	// pumps counts clients whose connection is still being written to
+	pumps sync.WaitGroup
+
+	mu       sync.RWMutex
+	outbound chan message
+
+	instanceID   string
+	branch       string
+	seen         *seenSet
+	upgrader     websocket.Upgrader
+	pingInterval time.Duration
+	clientBuffer int
+	metrics      hubMetrics
+
+	// seq and replay are only touched by Run
+	seq    uint64
+	replay *replayBuffer
+}
+
+// message is a hub message and the topics it is routed to. Staff connections get
+// payload; anonymous ones get publicPayload, or nothing when it is nil. Clients
+// receive it wrapped in a dto.RealtimeEnvelope.
+type message struct {
+	topics        []string
+	id            string
+	messageType   string
+	timestamp     time.Time
+	branch        string
+	payload       json.RawMessage
+	publicPayload json.RawMessage
+}
+
+// resumePoint is where a reconnecting client left off
+type resumePoint struct {
+	instance string
+	seq      uint64
 }
 
 type Client struct {
-	hub  *Hub
-	conn *websocket.Conn
-	send chan []byte
+	hub      *Hub
+	conn     *websocket.Conn
+	send     chan []byte
+	identity Identity
+	resume   *resumePoint
+	// closeCode is set by Run before it closes send and tells writePump which
+	// close frame to send
+	closeCode int
+	// sendMu lets replies from the reader skip a send channel Run has closed
+	sendMu sync.Mutex
+	closed bool
+
+	topicsMu sync.RWMutex
+	topics   map[string]struct{}
 }
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins in development
-	},
+// clientRequest is what clients send to manage their subscriptions
+type clientRequest struct {
+	Action string   `json:"action"`
+	Topics []string `json:"topics"`
 }
 
-func NewHub() *Hub {
+func NewHub(cfg *config.WebSocketConfig) *Hub {
+	pingInterval := cfg.PingInterval
+	if pingInterval <= 0 {
+		pingInterval = defaultPingInterval
+	}
+	broadcastQueue := cfg.BroadcastQueue
+	if broadcastQueue <= 0 {
+		broadcastQueue = defaultBroadcastQueue
+	}
+	clientBuffer := cfg.ClientBuffer
+	if clientBuffer <= 0 {
+		clientBuffer = defaultClientBuffer
+	}
+
 	return &Hub{
 		clients:    make(map[*Client]bool),
-		broadcast:  make(chan []byte),
+		broadcast:  make(chan message, broadcastQueue),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		quit:       make(chan struct{}),
+		done:       make(chan struct{}),
+		instanceID: randomID(),
+		branch:     cfg.Branch,
+		seen:       newSeenSet(1024),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     checkOrigin(cfg.AllowedOrigins),
+		},
+		pingInterval: pingInterval,
+		clientBuffer: clientBuffer,
+		replay:       newReplayBuffer(cfg.ReplayBuffer),
 	}
 }
 
+// InstanceID identifies this hub on the backplane
+func (h *Hub) InstanceID() string {
+	return h.instanceID
+}
+
 func (h *Hub) Run() {
+	defer close(h.done)
+
 	for {
 		select {
 		case client := <-h.register:
-			h.mu.Lock()
 			h.clients[client] = true
-			h.mu.Unlock()
+			h.pumps.Add(1)
+			h.metrics.clients.Store(int64(len(h.clients)))
+			h.greet(client)
 			log.Info().Int("clients", len(h.clients)).Msg("Client registered")
 
 		case client := <-h.unregister:
-			h.mu.Lock()
-			if _, ok := h.clients[client]; ok {
-				delete(h.clients, client)
-				close(client.send)
-			}
-			h.mu.Unlock()
+			h.drop(client, websocket.CloseNormalClosure)
 			log.Info().Int("clients", len(h.clients)).Msg("Client unregistered")
 
-		case message := <-h.broadcast:
-			h.mu.RLock()
+		case msg := <-h.broadcast:
+			entry, err := h.stamp(msg)
+			if err != nil {
+				log.Error().Err(err).Str("type", msg.messageType).Msg("Failed to encode broadcast message")
+				continue
+			}
+			h.replay.add(entry)
+			h.metrics.broadcasts.Add(1)
+
 			for client := range h.clients {
+				data := entry.dataFor(client)
+				if data == nil {
+					continue
+				}
 				select {
-				case client.send <- message:
+				case client.send <- data:
+					h.metrics.delivered.Add(1)
 				default:
-					close(client.send)
-					delete(h.clients, client)
+					// A full buffer means the client stopped reading; waiting for it
+					// would hold up everyone else
+					h.metrics.slowClients.Add(1)
+					log.Warn().Int("user_id", client.identity.UserID).Msg("Disconnecting slow realtime client")
+					h.drop(client, websocket.CloseTryAgainLater)
 				}
 			}
-			h.mu.RUnlock()
+
+		case <-h.quit:
+			for client := range h.clients {
+				h.drop(client, websocket.CloseGoingAway)
+			}
+			log.Info().Msg("Realtime hub stopped")
+			return
+		}
+	}
+}
+
+// drop removes client and closes its send channel so its writer says goodbye with
+// code. Only Run calls it, which makes the close happen exactly once.
+func (h *Hub) drop(client *Client, code int) {
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+	delete(h.clients, client)
+	h.metrics.clients.Store(int64(len(h.clients)))
+	client.sendMu.Lock()
+	client.closeCode = code
+	client.closed = true
+	close(client.send)
+	client.sendMu.Unlock()
+}
+
+// join hands client to Run, or reports false once the hub has stopped
+func (h *Hub) join(client *Client) bool {
+	select {
+	case h.register <- client:
+		return true
+	case <-h.done:
+		return false
+	}
+}
+
+// leave asks Run to drop client; it returns straight away if the hub has stopped
+func (h *Hub) leave(client *Client) {
+	select {
+	case h.unregister <- client:
+	case <-h.done:
+	}
+}
+
+// pumpDone marks the end of a registered client's writer
+func (h *Hub) pumpDone() {
+	h.pumps.Done()
+}
+
+// Shutdown stops the hub, sends every WebSocket client a going-away close frame,
+// ends every event stream and waits until they are flushed or ctx is done
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.shutdownOnce.Do(func() {
+		close(h.quit)
+	})
+
+	select {
+	case <-h.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	flushed := make(chan struct{})
+	go func() {
+		h.pumps.Wait()
+		close(flushed)
+	}()
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stamp gives msg the next sequence number and encodes it for both audiences
+func (h *Hub) stamp(msg message) (replayEntry, error) {
+	h.seq++
+	entry := replayEntry{seq: h.seq, topics: msg.topics}
+
+	envelope := dto.RealtimeEnvelope{
+		ID:        msg.id,
+		Type:      msg.messageType,
+		Version:   dto.RealtimeSchemaVersion,
+		Timestamp: msg.timestamp,
+		Branch:    msg.branch,
+		Seq:       h.seq,
+		Payload:   msg.payload,
+	}
+
+	var err error
+	if entry.data, err = json.Marshal(envelope); err != nil {
+		return entry, err
+	}
+	if msg.publicPayload != nil {
+		envelope.Payload = msg.publicPayload
+		if entry.publicData, err = json.Marshal(envelope); err != nil {
+			return entry, err
 		}
 	}
+	return entry, nil
 }
 
-func (h *Hub) Broadcast(messageType string, payload interface{}) {
-	msg := map[string]interface{}{
-		"type":    messageType,
-		"payload": payload,
+// dataFor returns what client should receive for entry, or nil when nothing
+func (e replayEntry) dataFor(client *Client) []byte {
+	if !client.subscribedToAny(e.topics) {
+		return nil
 	}
+	if client.identity.IsStaff() {
+		return e.data
+	}
+	return e.publicData
+}
+
+// greet replays what a reconnecting client missed, or tells it to resync when that
+// is no longer possible, then tells it where the stream is now
+func (h *Hub) greet(client *Client) {
+	if client.resume != nil {
+		missed, ok := h.replay.since(client.resume.seq, h.seq)
+		if client.resume.instance != h.instanceID || len(missed) > cap(client.send)-2 {
+			ok = false
+		}
 
-	data, err := json.Marshal(msg)
+		if ok {
+			for _, entry := range missed {
+				if data := entry.dataFor(client); data != nil {
+					client.send <- data
+				}
+			}
+		} else {
+			client.reply(dto.RealtimeResync, dto.ResyncEvent{Seq: h.seq})
+		}
+	}
+
+	client.reply(dto.RealtimeHello, dto.HelloEvent{Instance: h.instanceID, Seq: h.seq})
+}
+
+// Publish sends the same payload to every client subscribed to at least one of
+// topics, on this instance and, through the backplane, on every other instance
+func (h *Hub) Publish(topics []string, messageType string, payload interface{}) {
+	data, err := json.Marshal(payload)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to marshal broadcast message")
 		return
 	}
 
-	h.broadcast <- data
+	h.send(message{topics: topics, messageType: messageType, payload: data, publicPayload: data})
 }
 
-func (h *Hub) BroadcastTicketUpdate(ticket interface{}) {
-	h.Broadcast("ticket_update", ticket)
+// PublishRedacted sends payload to staff connections and publicPayload to anonymous
+// ones. A nil publicPayload keeps the message staff-only.
+func (h *Hub) PublishRedacted(topics []string, messageType string, payload, publicPayload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal broadcast message")
+		return
+	}
+
+	msg := message{topics: topics, messageType: messageType, payload: data}
+	if publicPayload != nil {
+		if msg.publicPayload, err = json.Marshal(publicPayload); err != nil {
+			log.Error().Err(err).Msg("Failed to marshal public broadcast message")
+			return
+		}
+	}
+
+	h.send(msg)
 }
 
-func (h *Hub) BroadcastCounterUpdate(counter interface{}) {
-	h.Broadcast("counter_update", counter)
+func (h *Hub) send(msg message) {
+	// Admin connections see everything
+	msg.topics = append(append([]string{}, msg.topics...), TopicAdminAll)
+	msg.id = randomID()
+	msg.timestamp = time.Now().UTC()
+	msg.branch = h.branch
+	h.enqueue(msg)
+
+	h.mu.RLock()
+	outbound := h.outbound
+	h.mu.RUnlock()
+	if outbound != nil {
+		select {
+		case outbound <- msg:
+		default:
+			log.Warn().Str("instance", h.instanceID).Msg("Backplane queue full, message not relayed")
+		}
+	}
 }
 
-func (h *Hub) BroadcastStatsUpdate(stats interface{}) {
-	h.Broadcast("stats_update", stats)
+// enqueue queues msg for Run without ever blocking the caller, which is usually
+// an HTTP request. When the queue is full the message is counted and dropped.
+func (h *Hub) enqueue(msg message) {
+	select {
+	case <-h.done:
+		return
+	default:
+	}
+
+	select {
+	case h.broadcast <- msg:
+	default:
+		h.metrics.droppedBroadcasts.Add(1)
+		log.Warn().Str("type", msg.messageType).Msg("Realtime hub queue full, message dropped")
+	}
 }
 
-func (h *Hub) BroadcastDisplayUpdate(display interface{}) {
-	h.Broadcast("display_update", display)
+// subscribe adds valid topics to the client and returns its current topics
+func (c *Client) subscribe(topics []string) ([]string, bool) {
+	c.topicsMu.Lock()
+	defer c.topicsMu.Unlock()
+
+	ok := true
+	for _, topic := range topics {
+		if !ValidTopic(topic) || !CanSubscribe(c.identity, topic) || (len(c.topics) >= maxClientTopics && !c.hasTopic(topic)) {
+			ok = false
+			continue
+		}
+		c.topics[topic] = struct{}{}
+	}
+	return c.topicList(), ok
+}
+
+// unsubscribe removes topics from the client and returns its current topics
+func (c *Client) unsubscribe(topics []string) []string {
+	c.topicsMu.Lock()
+	defer c.topicsMu.Unlock()
+
+	for _, topic := range topics {
+		delete(c.topics, topic)
+	}
+	return c.topicList()
+}
+
+func (c *Client) subscribedToAny(topics []string) bool {
+	c.topicsMu.RLock()
+	defer c.topicsMu.RUnlock()
+
+	for _, topic := range topics {
+		if c.hasTopic(topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTopic and topicList expect topicsMu to be held
+func (c *Client) hasTopic(topic string) bool {
+	_, ok := c.topics[topic]
+	return ok
+}
+
+func (c *Client) topicList() []string {
+	list := make([]string, 0, len(c.topics))
+	for topic := range c.topics {
+		list = append(list, topic)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// reply queues a message for this client only
+func (c *Client) reply(messageType string, payload interface{}) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(dto.RealtimeEnvelope{
+		Type:      messageType,
+		Version:   dto.RealtimeSchemaVersion,
+		Timestamp: time.Now().UTC(),
+		Branch:    c.hub.branch,
+		Payload:   raw,
+	})
+	if err != nil {
+		return
+	}
+
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	if c.closed {
+		return
+	}
+	select {
+	case c.send <- data:
+	default:
+	}
+}
+
+// handleRequest applies a subscribe or unsubscribe request from the client
+func (c *Client) handleRequest(raw []byte) {
+	var req clientRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		c.reply(dto.RealtimeError, dto.ErrorEvent{Message: "invalid request"})
+		return
+	}
+
+	switch req.Action {
+	case "subscribe":
+		topics, ok := c.subscribe(req.Topics)
+		if !ok {
+			c.reply(dto.RealtimeError, dto.ErrorEvent{Message: "some topics were rejected"})
+		}
+		c.reply(dto.RealtimeSubscribed, dto.SubscribedEvent{Topics: topics})
+	case "unsubscribe":
+		c.reply(dto.RealtimeSubscribed, dto.SubscribedEvent{Topics: c.unsubscribe(req.Topics)})
+	default:
+		c.reply(dto.RealtimeError, dto.ErrorEvent{Message: "unknown action"})
+	}
 }
 
 func (c *Client) readPump() {
 	defer func() {
-		c.hub.unregister <- c
+		c.hub.leave(c)
 		c.conn.Close()
 	}()
 
-	c.conn.SetReadLimit(512)
-	c.conn.SetPongHandler(func(string) error { return nil })
+	// A client that misses two pings in a row is considered gone
+	pongWait := 2 * c.hub.pingInterval
+	c.conn.SetReadLimit(4096)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
 
 	for {
-		_, _, err := c.conn.ReadMessage()
+		_, raw, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Error().Err(err).Msg("WebSocket error")
 			}
 			break
 		}
+		c.handleRequest(raw)
 	}
 }
 
 func (c *Client) writePump() {
+	ticker := time.NewTicker(c.hub.pingInterval)
 	defer func() {
+		ticker.Stop()
 		c.conn.Close()
+		c.hub.pumpDone()
 	}()
 
 	for {
 		select {
 		case message, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if !ok {
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(c.closeCode, closeReason(c.closeCode)))
 				return
 			}
 
-			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 			w, err := c.conn.NextWriter(websocket.TextMessage)
 			if err != nil {
 				return
@@ -155,20 +548,76 @@ func (c *Client) writePump() {
 			if err := w.Close(); err != nil {
 				return
 			}
+
+		case <-ticker.C:
+			// Keeps idle connections open through proxies and lets readPump notice dead peers
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
 		}
 	}
 }
 
-func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+// ServeWs upgrades the request for identity and subscribes the client to the comma
+// separated topics query parameter; more topics can be joined later over the socket.
+// A reconnecting client passes the instance and seq it last saw to catch up.
+func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request, identity Identity) {
+	conn, err := hub.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to upgrade WebSocket")
 		return
 	}
 
-	client := &Client{hub: hub, conn: conn, send: make(chan []byte, 256)}
-	client.hub.register <- client
+	client := newClient(hub, r, identity)
+	client.conn = conn
+	if !hub.join(client) {
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, closeReason(websocket.CloseGoingAway)))
+		conn.Close()
+		return
+	}
 
 	go client.writePump()
 	go client.readPump()
 }
+
+// newClient builds a client for either transport with the topics and resume point
+// from the request. Topics the identity may not join are dropped.
+func newClient(hub *Hub, r *http.Request, identity Identity) *Client {
+	client := &Client{hub: hub, send: make(chan []byte, hub.clientBuffer), identity: identity, topics: make(map[string]struct{})}
+	if topics := r.URL.Query().Get("topics"); topics != "" {
+		client.subscribe(strings.Split(topics, ","))
+	}
+	client.resume = parseResume(r)
+	return client
+}
+
+// parseResume reads the since and instance query parameters, or the Last-Event-ID
+// header an EventSource sends when it reconnects by itself
+func parseResume(r *http.Request) *resumePoint {
+	query := r.URL.Query()
+	instance, since := query.Get("instance"), query.Get("since")
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		instance, since, _ = strings.Cut(lastEventID, ":")
+	}
+	if since == "" {
+		return nil
+	}
+
+	seq, err := strconv.ParseUint(since, 10, 64)
+	if err != nil {
+		return &resumePoint{}
+	}
+	return &resumePoint{instance: instance, seq: seq}
+}
+
+func closeReason(code int) string {
+	switch code {
+	case websocket.CloseGoingAway:
+		return "server shutting down"
+	case websocket.CloseTryAgainLater:
+		return "client too slow"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,368 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+	"tenangantri/internal/dto"
+)
+
+func newTestClient(hub *Hub, identity Identity, topics ...string) *Client {
+	client := &Client{hub: hub, send: make(chan []byte, hub.clientBuffer), identity: identity, topics: make(map[string]struct{})}
+	client.subscribe(topics)
+	hub.register <- client
+	<-client.send // hello
+	return client
+}
+
+// assertEnvelope checks raw is a versioned envelope of messageType carrying payload
+func assertEnvelope(t *testing.T, raw, messageType, payload string) dto.RealtimeEnvelope {
+	t.Helper()
+
+	var envelope dto.RealtimeEnvelope
+	require.NoError(t, json.Unmarshal([]byte(raw), &envelope))
+	assert.Equal(t, messageType, envelope.Type)
+	assert.Equal(t, dto.RealtimeSchemaVersion, envelope.Version)
+	assert.False(t, envelope.Timestamp.IsZero())
+	assert.JSONEq(t, payload, string(envelope.Payload))
+	return envelope
+}
+
+// resumingClient connects as a client that last saw seq on instance
+func resumingClient(t *testing.T, hub *Hub, instance string, seq uint64) (*Client, []string) {
+	client := &Client{hub: hub, send: make(chan []byte, 16), topics: make(map[string]struct{}), resume: &resumePoint{instance: instance, seq: seq}}
+	client.subscribe([]string{TopicDisplayAll})
+	hub.register <- client
+
+	var received []string
+	for {
+		msg := receive(t, client)
+		received = append(received, msg)
+		if strings.Contains(msg, `"hello"`) {
+			return client, received
+		}
+	}
+}
+
+func TestHub_Publish_RoutesByTopic(t *testing.T) {
+	hub := NewHub(&config.WebSocketConfig{})
+	go hub.Run()
+
+	board := newTestClient(hub, Identity{}, TopicDisplayAll)
+	categoryThree := newTestClient(hub, Identity{}, CategoryTopic(3))
+	tracker := newTestClient(hub, Identity{}, TicketTopic("B004"))
+	admin := newTestClient(hub, Identity{UserID: 1, Role: "admin"}, TopicAdminAll)
+	idle := newTestClient(hub, Identity{})
+
+	hub.Publish([]string{TopicDisplayAll, CategoryTopic(3), TicketTopic("A001")}, "ticket_update", map[string]string{"ticket_number": "A001"})
+
+	assert.Contains(t, receive(t, board), "A001")
+	assert.Contains(t, receive(t, categoryThree), "A001")
+	assert.Contains(t, receive(t, admin), "A001")
+	assertNoMessage(t, tracker)
+	assertNoMessage(t, idle)
+}
+
+func TestHub_PublishRedacted(t *testing.T) {
+	hub := NewHub(&config.WebSocketConfig{})
+	go hub.Run()
+
+	board := newTestClient(hub, Identity{}, CounterTopic(2))
+	staff := newTestClient(hub, Identity{UserID: 7, Role: "staff"}, CounterTopic(2))
+
+	hub.PublishRedacted([]string{CounterTopic(2)}, "ticket_update",
+		map[string]string{"ticket_number": "A001", "notes": "wheelchair"},
+		map[string]string{"ticket_number": "A001"})
+
+	assert.NotContains(t, receive(t, board), "wheelchair")
+	assert.Contains(t, receive(t, staff), "wheelchair")
+
+	// Without a public payload the message is staff-only
+	hub.PublishRedacted([]string{CounterTopic(2)}, "ticket_transferred", map[string]string{"ticket_number": "A002"}, nil)
+
+	assert.Contains(t, receive(t, staff), "A002")
+	assertNoMessage(t, board)
+}
+
+func TestClient_HandleRequest(t *testing.T) {
+	client := &Client{hub: NewHub(&config.WebSocketConfig{}), send: make(chan []byte, 16), topics: make(map[string]struct{})}
+
+	client.handleRequest([]byte(`{"action":"subscribe","topics":["counter:2","ticket:A001"]}`))
+	assertEnvelope(t, receive(t, client), "subscribed", `{"topics":["counter:2","ticket:A001"]}`)
+	assert.True(t, client.subscribedToAny([]string{CounterTopic(2)}))
+
+	client.handleRequest([]byte(`{"action":"unsubscribe","topics":["counter:2"]}`))
+	assertEnvelope(t, receive(t, client), "subscribed", `{"topics":["ticket:A001"]}`)
+	assert.False(t, client.subscribedToAny([]string{CounterTopic(2)}))
+
+	client.handleRequest([]byte(`{"action":"subscribe","topics":["everything"]}`))
+	assert.Contains(t, receive(t, client), `"error"`)
+	assert.Contains(t, receive(t, client), `"subscribed"`)
+
+	// Anonymous clients cannot join staff or admin topics
+	client.handleRequest([]byte(`{"action":"subscribe","topics":["admin:all","staff:3"]}`))
+	assert.Contains(t, receive(t, client), `"error"`)
+	assertEnvelope(t, receive(t, client), "subscribed", `{"topics":["ticket:A001"]}`)
+
+	client.handleRequest([]byte(`not json`))
+	assert.Contains(t, receive(t, client), "invalid request")
+}
+
+func TestValidTopic(t *testing.T) {
+	valid := []string{"display:all", "admin:all", "stats", "alerts", "category:3", "counter:12", "staff:7", "ticket:A001", "screen:lobby-1", "device:5", "kiosk:lantai-2"}
+	for _, topic := range valid {
+		assert.True(t, ValidTopic(topic), topic)
+	}
+
+	invalid := []string{"", "display", "category:", "category:abc", "counter:-1", "staff:0", "ticket:a001", "ticket:A 1", "screen:Lobby", "screen:", "device:x", "kiosk:Lantai", "other:1"}
+	for _, topic := range invalid {
+		assert.False(t, ValidTopic(topic), topic)
+	}
+}
+
+func TestCanSubscribe(t *testing.T) {
+	public := Identity{}
+	staff := Identity{UserID: 7, Role: "staff"}
+	supervisor := Identity{UserID: 9, Role: "supervisor"}
+	admin := Identity{UserID: 1, Role: "admin"}
+	device := Identity{DeviceID: 5}
+
+	assert.True(t, CanSubscribe(public, TopicDisplayAll))
+	assert.True(t, CanSubscribe(public, TicketTopic("A001")))
+	assert.False(t, CanSubscribe(public, TopicStats))
+	assert.False(t, CanSubscribe(public, StaffTopic(7)))
+	assert.False(t, CanSubscribe(public, TopicAdminAll))
+
+	assert.True(t, CanSubscribe(staff, TopicStats))
+	assert.True(t, CanSubscribe(staff, StaffTopic(7)))
+	assert.False(t, CanSubscribe(staff, StaffTopic(8)))
+	assert.False(t, CanSubscribe(staff, TopicAdminAll))
+	assert.False(t, CanSubscribe(staff, TopicAlerts))
+
+	assert.True(t, CanSubscribe(supervisor, TopicStats))
+	assert.True(t, CanSubscribe(supervisor, TopicAlerts))
+	assert.True(t, CanSubscribe(supervisor, StaffTopic(9)))
+	assert.False(t, CanSubscribe(supervisor, TopicAdminAll))
+
+	assert.True(t, CanSubscribe(admin, StaffTopic(8)))
+	assert.True(t, CanSubscribe(admin, TopicAdminAll))
+	assert.True(t, CanSubscribe(admin, TopicAlerts))
+
+	// Device commands only reach the device holding its token
+	assert.False(t, CanSubscribe(public, DeviceTopic(5)))
+	assert.False(t, CanSubscribe(staff, DeviceTopic(5)))
+	assert.True(t, CanSubscribe(device, DeviceTopic(5)))
+	assert.False(t, CanSubscribe(device, DeviceTopic(6)))
+	assert.True(t, CanSubscribe(device, TopicDisplayAll))
+	assert.True(t, CanSubscribe(admin, DeviceTopic(5)))
+}
+
+func TestCheckOrigin(t *testing.T) {
+	check := checkOrigin([]string{"https://signage.example.com"})
+
+	request := func(origin string) bool {
+		r := httptest.NewRequest("GET", "http://antri.local/ws", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		return check(r)
+	}
+
+	assert.True(t, request(""))
+	assert.True(t, request("http://antri.local"))
+	assert.True(t, request("https://signage.example.com"))
+	assert.False(t, request("https://evil.example.com"))
+}
+
+func TestHub_Resume(t *testing.T) {
+	hub := NewHub(&config.WebSocketConfig{ReplayBuffer: 4})
+	go hub.Run()
+
+	board := newTestClient(hub, Identity{}, TopicDisplayAll)
+	for i := 1; i <= 6; i++ {
+		hub.Publish([]string{TopicDisplayAll}, "ticket_update", map[string]string{"ticket_number": fmt.Sprintf("A00%d", i)})
+		assert.Contains(t, receive(t, board), fmt.Sprintf(`"seq":%d`, i))
+	}
+	hub.Publish([]string{CounterTopic(9)}, "counter_update", map[string]int{"id": 9})
+
+	// Missed 5 and 6; the counter message is not on a joined topic
+	_, received := resumingClient(t, hub, hub.InstanceID(), 4)
+	assert.Len(t, received, 3)
+	assert.Contains(t, received[0], "A005")
+	assert.Contains(t, received[1], "A006")
+	assertEnvelope(t, received[2], "hello", fmt.Sprintf(`{"instance":%q,"seq":7}`, hub.InstanceID()))
+
+	// Up to date: only the hello
+	_, received = resumingClient(t, hub, hub.InstanceID(), 7)
+	assert.Len(t, received, 1)
+
+	// Seq 2 has been evicted from the buffer
+	_, received = resumingClient(t, hub, hub.InstanceID(), 1)
+	assertEnvelope(t, received[0], "resync", `{"seq":7}`)
+
+	// Sequence numbers from another instance mean nothing here
+	_, received = resumingClient(t, hub, "other", 6)
+	assertEnvelope(t, received[0], "resync", `{"seq":7}`)
+}
+
+func TestReplayBuffer_Since(t *testing.T) {
+	buffer := newReplayBuffer(3)
+	for seq := uint64(1); seq <= 5; seq++ {
+		buffer.add(replayEntry{seq: seq})
+	}
+
+	missed, ok := buffer.since(2, 5)
+	assert.True(t, ok)
+	assert.Equal(t, []uint64{3, 4, 5}, seqs(missed))
+
+	missed, ok = buffer.since(4, 5)
+	assert.True(t, ok)
+	assert.Equal(t, []uint64{5}, seqs(missed))
+
+	_, ok = buffer.since(1, 5)
+	assert.False(t, ok, "seq 2 was evicted")
+
+	_, ok = buffer.since(6, 5)
+	assert.False(t, ok, "ahead of the stream")
+
+	_, ok = newReplayBuffer(0).since(1, 2)
+	assert.False(t, ok, "replay disabled")
+}
+
+func seqs(entries []replayEntry) []uint64 {
+	list := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, entry.seq)
+	}
+	return list
+}
+
+// drainClient reads client's messages like a writer would, until the hub closes it
+func drainClient(hub *Hub, client *Client) {
+	go func() {
+		for range client.send {
+		}
+		hub.pumpDone()
+	}()
+}
+
+func TestHub_Publish_NeverBlocks(t *testing.T) {
+	// Run is not started, so nothing ever empties the queue
+	hub := NewHub(&config.WebSocketConfig{BroadcastQueue: 2})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			hub.Publish([]string{TopicDisplayAll}, "ticket_update", i)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a full queue")
+	}
+	assert.Equal(t, int64(3), hub.Metrics().DroppedBroadcasts)
+}
+
+func TestHub_SlowClientDisconnected(t *testing.T) {
+	hub := NewHub(&config.WebSocketConfig{ClientBuffer: 2})
+	go hub.Run()
+
+	slow := newTestClient(hub, Identity{}, TopicDisplayAll)
+	fast := &Client{hub: hub, send: make(chan []byte, 16), topics: make(map[string]struct{})}
+	fast.subscribe([]string{TopicDisplayAll})
+	hub.register <- fast
+	drainClient(hub, fast)
+
+	for i := 0; i < 3; i++ {
+		hub.Publish([]string{TopicDisplayAll}, "ticket_update", i)
+	}
+
+	require.Eventually(t, func() bool {
+		m := hub.Metrics()
+		return m.SlowClients == 1 && m.Clients == 1 && m.Broadcasts == 3
+	}, time.Second, 5*time.Millisecond)
+
+	// Two messages fit, the third found the buffer full and the hub let go
+	assert.Contains(t, receive(t, slow), `"seq":1`)
+	assert.Contains(t, receive(t, slow), `"seq":2`)
+	_, open := <-slow.send
+	assert.False(t, open)
+	assert.Equal(t, websocket.CloseTryAgainLater, slow.closeCode)
+}
+
+func TestHub_Storm(t *testing.T) {
+	hub := NewHub(&config.WebSocketConfig{ReplayBuffer: 64})
+	go hub.Run()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				client := &Client{hub: hub, send: make(chan []byte, 8), topics: make(map[string]struct{})}
+				client.subscribe([]string{TopicDisplayAll, CounterTopic(j + 1)})
+				require.True(t, hub.join(client))
+				drainClient(hub, client)
+				client.handleRequest([]byte(`{"action":"unsubscribe","topics":["display:all"]}`))
+				hub.leave(client)
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				hub.Publish([]string{TopicDisplayAll, CounterTopic(j%20 + 1)}, "ticket_update", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, hub.Shutdown(ctx))
+	assert.Equal(t, int64(0), hub.Metrics().Clients)
+}
+
+func TestHub_Shutdown(t *testing.T) {
+	hub := NewHub(&config.WebSocketConfig{})
+	go hub.Run()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeWs(hub, w, r, Identity{})
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?topics=display:all", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, hello, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Contains(t, string(hello), `"hello"`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, hub.Shutdown(ctx))
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "got %v", err)
+
+	// Publishing after shutdown is a no-op rather than a hang
+	hub.Publish([]string{TopicDisplayAll}, "ticket_update", 1)
+	require.NoError(t, hub.Shutdown(ctx))
+}
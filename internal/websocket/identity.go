@@ -0,0 +1,77 @@
+package websocket
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Identity is who opened a connection. The zero value is an anonymous client
+// such as a public display board or a customer tracking a ticket.
+type Identity struct {
+	UserID int
+	Role   string
+	// DeviceID is the paired kiosk or display whose device token the connection presented
+	DeviceID int
+}
+
+// IsStaff reports whether the connection belongs to a signed-in staff member, supervisor or admin
+func (i Identity) IsStaff() bool {
+	return i.Role == "staff" || i.Role == "supervisor" || i.Role == "admin"
+}
+
+// IsSupervisor reports whether the connection belongs to a supervisor or admin
+func (i Identity) IsSupervisor() bool {
+	return i.Role == "supervisor" || i.Role == "admin"
+}
+
+func (i Identity) IsAdmin() bool {
+	return i.Role == "admin"
+}
+
+// CanSubscribe reports whether identity may join topic. Public topics are open to
+// everyone but anonymous clients only receive the redacted payloads.
+func CanSubscribe(identity Identity, topic string) bool {
+	switch topic {
+	case TopicAdminAll:
+		return identity.IsAdmin()
+	case TopicStats:
+		return identity.IsStaff()
+	case TopicAlerts:
+		return identity.IsSupervisor()
+	}
+
+	if id, ok := strings.CutPrefix(topic, "staff:"); ok {
+		userID, _ := strconv.Atoi(id)
+		return identity.IsAdmin() || (identity.IsStaff() && identity.UserID == userID)
+	}
+	if id, ok := strings.CutPrefix(topic, "device:"); ok {
+		deviceID, _ := strconv.Atoi(id)
+		return identity.IsAdmin() || (identity.DeviceID != 0 && identity.DeviceID == deviceID)
+	}
+	return true
+}
+
+// checkOrigin accepts same-host requests, non-browser clients without an Origin
+// header and the configured allowlist
+func checkOrigin(allowed []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		u, err := url.Parse(origin)
+		if err == nil && strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+
+		for _, a := range allowed {
+			if strings.EqualFold(strings.TrimSuffix(a, "/"), origin) {
+				return true
+			}
+		}
+		return false
+	}
+}
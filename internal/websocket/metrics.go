@@ -0,0 +1,37 @@
+package websocket
+
+import "sync/atomic"
+
+// hubMetrics counts what the hub did since it started
+type hubMetrics struct {
+	clients           atomic.Int64
+	broadcasts        atomic.Int64
+	delivered         atomic.Int64
+	droppedBroadcasts atomic.Int64
+	slowClients       atomic.Int64
+}
+
+// Metrics is a snapshot of the hub's counters
+type Metrics struct {
+	// Clients is the number of connected WebSocket and SSE clients
+	Clients int64 `json:"clients"`
+	// Broadcasts counts messages the hub fanned out, local and relayed
+	Broadcasts int64 `json:"broadcasts"`
+	// Delivered counts messages queued to individual clients
+	Delivered int64 `json:"delivered"`
+	// DroppedBroadcasts counts messages dropped because the hub queue was full
+	DroppedBroadcasts int64 `json:"dropped_broadcasts"`
+	// SlowClients counts clients disconnected because their buffer was full
+	SlowClients int64 `json:"slow_clients_disconnected"`
+}
+
+// Metrics returns the hub's current counters
+func (h *Hub) Metrics() Metrics {
+	return Metrics{
+		Clients:           h.metrics.clients.Load(),
+		Broadcasts:        h.metrics.broadcasts.Load(),
+		Delivered:         h.metrics.delivered.Load(),
+		DroppedBroadcasts: h.metrics.droppedBroadcasts.Load(),
+		SlowClients:       h.metrics.slowClients.Load(),
+	}
+}
@@ -0,0 +1,59 @@
+package websocket
+
+// replayEntry is a delivered message kept so reconnecting clients can catch up
+type replayEntry struct {
+	seq        uint64
+	topics     []string
+	data       []byte
+	publicData []byte
+}
+
+// replayBuffer keeps the most recent messages in a fixed-size ring. It is owned
+// by the hub's Run loop and is not safe for concurrent use.
+type replayBuffer struct {
+	entries []replayEntry
+	next    int
+	count   int
+}
+
+func newReplayBuffer(size int) *replayBuffer {
+	return &replayBuffer{entries: make([]replayEntry, size)}
+}
+
+func (b *replayBuffer) add(entry replayEntry) {
+	if len(b.entries) == 0 {
+		return
+	}
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % len(b.entries)
+	if b.count < len(b.entries) {
+		b.count++
+	}
+}
+
+// since returns the entries after seq, oldest first. ok is false when some of
+// them have already been evicted or seq is ahead of latest, so the client must resync.
+func (b *replayBuffer) since(seq, latest uint64) (missed []replayEntry, ok bool) {
+	if seq > latest {
+		return nil, false
+	}
+	if seq == latest {
+		return nil, true
+	}
+	if b.count == 0 || b.oldest().seq > seq+1 {
+		return nil, false
+	}
+
+	start := (b.next - b.count + len(b.entries)) % len(b.entries)
+	for i := 0; i < b.count; i++ {
+		entry := b.entries[(start+i)%len(b.entries)]
+		if entry.seq > seq {
+			missed = append(missed, entry)
+		}
+	}
+	return missed, true
+}
+
+func (b *replayBuffer) oldest() replayEntry {
+	return b.entries[(b.next-b.count+len(b.entries))%len(b.entries)]
+}
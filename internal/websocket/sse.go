@@ -0,0 +1,91 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// sseRetry is how long an EventSource waits before reconnecting by itself
+const sseRetry = 3 * time.Second
+
+// ServeSSE streams the same messages as ServeWs as Server-Sent Events, for networks
+// whose proxies break WebSocket upgrades. The stream cannot carry subscribe requests,
+// so its topics are fixed; clients reopen it with a new topics list to change them.
+// Every broadcast gets the event id "<instance>:<seq>" so Last-Event-ID resumes it.
+func ServeSSE(hub *Hub, w http.ResponseWriter, r *http.Request, identity Identity) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// The server's WriteTimeout would otherwise cut every stream short
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn().Err(err).Msg("Failed to clear SSE write deadline")
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+	flusher.Flush()
+
+	client := newClient(hub, r, identity)
+	if !hub.join(client) {
+		return
+	}
+	defer func() {
+		hub.leave(client)
+		hub.pumpDone()
+	}()
+
+	ticker := time.NewTicker(hub.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case data, ok := <-client.send:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, hub.instanceID, data); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case <-ticker.C:
+			// A comment line keeps proxies from timing out an idle stream
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent writes one hub message as an SSE event. Replies without a seq get no
+// id, so the browser keeps the id of the last broadcast.
+func writeEvent(w io.Writer, instance string, data []byte) error {
+	var head struct {
+		Seq uint64 `json:"seq"`
+	}
+	if err := json.Unmarshal(data, &head); err == nil && head.Seq > 0 {
+		if _, err := fmt.Fprintf(w, "id: %s:%d\n", instance, head.Seq); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
@@ -0,0 +1,100 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tenangantri/internal/config"
+)
+
+// openStream connects to the SSE endpoint and returns a function reading the next
+// event as its id and data lines
+func openStream(t *testing.T, url, lastEventID string) func() (string, string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	return func() (id, data string) {
+		for {
+			select {
+			case line := <-lines:
+				switch {
+				case strings.HasPrefix(line, "id: "):
+					id = strings.TrimPrefix(line, "id: ")
+				case strings.HasPrefix(line, "data: "):
+					data = strings.TrimPrefix(line, "data: ")
+				case line == "" && data != "":
+					return id, data
+				}
+			case <-time.After(time.Second):
+				t.Fatal("timed out waiting for event")
+				return "", ""
+			}
+		}
+	}
+}
+
+func TestServeSSE(t *testing.T) {
+	hub := NewHub(&config.WebSocketConfig{ReplayBuffer: 16})
+	go hub.Run()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeSSE(hub, w, r, Identity{})
+	}))
+	t.Cleanup(server.Close)
+
+	next := openStream(t, server.URL+"?topics=display:all,stats", "")
+
+	// stats is staff-only, so the anonymous stream only joined display:all
+	id, data := next()
+	assert.Empty(t, id)
+	assert.Contains(t, data, `"hello"`)
+
+	hub.Publish([]string{TopicDisplayAll}, "ticket_update", map[string]string{"ticket_number": "A001"})
+	hub.Publish([]string{TopicStats}, "stats_update", map[string]int{"waiting": 3})
+	hub.Publish([]string{TopicDisplayAll}, "ticket_update", map[string]string{"ticket_number": "A002"})
+
+	id, data = next()
+	assert.Equal(t, hub.InstanceID()+":1", id)
+	assert.Contains(t, data, "A001")
+
+	id, data = next()
+	assert.Equal(t, hub.InstanceID()+":3", id)
+	assert.Contains(t, data, "A002")
+
+	// A browser reconnecting after seq 1 gets A002 replayed
+	resumed := openStream(t, server.URL+"?topics=display:all", hub.InstanceID()+":1")
+	id, data = resumed()
+	assert.Equal(t, hub.InstanceID()+":3", id)
+	assert.Contains(t, data, "A002")
+
+	_, data = resumed()
+	assert.Contains(t, data, `"hello"`)
+}
@@ -0,0 +1,87 @@
+package websocket
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Topics a client can subscribe to. A message is delivered to a client when it
+// is subscribed to any of the message's topics.
+const (
+	// TopicDisplayAll carries every ticket call and counter status change for public boards
+	TopicDisplayAll = "display:all"
+	// TopicAdminAll carries every message the hub sends
+	TopicAdminAll = "admin:all"
+	// TopicStats carries dashboard stats snapshots
+	TopicStats = "stats"
+	// TopicAlerts carries queue threshold alerts for supervisors
+	TopicAlerts = "alerts"
+)
+
+// maxClientTopics bounds how many topics one connection may hold
+const maxClientTopics = 32
+
+var (
+	ticketTopicPattern = regexp.MustCompile(`^[A-Z0-9-]{1,32}$`)
+	screenTopicPattern = regexp.MustCompile(`^[a-z0-9-]{1,50}$`)
+)
+
+// CategoryTopic carries tickets issued, called and closed in one category
+func CategoryTopic(categoryID int) string {
+	return "category:" + strconv.Itoa(categoryID)
+}
+
+// CounterTopic carries tickets served at one counter and its status changes
+func CounterTopic(counterID int) string {
+	return "counter:" + strconv.Itoa(counterID)
+}
+
+// TicketTopic carries updates for one ticket, addressed by its ticket number
+func TicketTopic(ticketNumber string) string {
+	return "ticket:" + ticketNumber
+}
+
+// StaffTopic carries changes made by one staff user
+func StaffTopic(userID int) string {
+	return "staff:" + strconv.Itoa(userID)
+}
+
+// ScreenTopic carries reload requests for the boards showing one display profile
+func ScreenTopic(slug string) string {
+	return "screen:" + slug
+}
+
+// KioskTopic carries reload requests for the kiosks showing one kiosk profile
+func KioskTopic(slug string) string {
+	return "kiosk:" + slug
+}
+
+// DeviceTopic carries remote actions for one paired kiosk or display
+func DeviceTopic(deviceID int) string {
+	return "device:" + strconv.Itoa(deviceID)
+}
+
+// ValidTopic reports whether topic is one the hub routes
+func ValidTopic(topic string) bool {
+	switch topic {
+	case TopicDisplayAll, TopicAdminAll, TopicStats, TopicAlerts:
+		return true
+	}
+
+	kind, id, ok := strings.Cut(topic, ":")
+	if !ok {
+		return false
+	}
+
+	switch kind {
+	case "category", "counter", "staff", "device":
+		n, err := strconv.Atoi(id)
+		return err == nil && n > 0
+	case "ticket":
+		return ticketTopicPattern.MatchString(id)
+	case "screen", "kiosk":
+		return screenTopicPattern.MatchString(id)
+	}
+	return false
+}
@@ -0,0 +1,336 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Style formats the value of a cell
+type Style int
+
+// The styles are the indexes of cellXfs in styles.xml
+const (
+	StyleDefault Style = iota
+	StyleHeader
+	StyleDateTime
+	StyleDate
+	StyleDuration
+	StylePercent
+	StyleDecimal
+)
+
+// maxCellText is the most characters Excel keeps in a cell
+const maxCellText = 32767
+
+// excelEpoch is day zero of Excel's 1900 date system, counted so that serials from March 1900 on are right
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+type cellKind int
+
+const (
+	cellBlank cellKind = iota
+	cellText
+	cellNumber
+)
+
+// Cell is one typed value of a row
+type Cell struct {
+	kind   cellKind
+	text   string
+	number float64
+	style  Style
+}
+
+// Blank is an empty cell
+func Blank() Cell {
+	return Cell{}
+}
+
+// String is a text cell
+func String(s string) Cell {
+	return Cell{kind: cellText, text: s}
+}
+
+// Int is a whole number cell
+func Int(n int64) Cell {
+	return Cell{kind: cellNumber, number: float64(n)}
+}
+
+// Float is a number cell shown with two decimals
+func Float(f float64) Cell {
+	return number(f, StyleDecimal)
+}
+
+// Percent is a fraction shown as a percentage, 0.25 being 25%
+func Percent(f float64) Cell {
+	return number(f, StylePercent)
+}
+
+// DateTime is a date and time cell, in the wall clock of t's location
+func DateTime(t time.Time) Cell {
+	return Cell{kind: cellNumber, number: serial(t), style: StyleDateTime}
+}
+
+// Date is a date cell, in the wall clock of t's location
+func Date(t time.Time) Cell {
+	return Cell{kind: cellNumber, number: math.Floor(serial(t)), style: StyleDate}
+}
+
+// Duration is a length of time shown as hours, minutes and seconds
+func Duration(seconds float64) Cell {
+	return number(seconds/86400, StyleDuration)
+}
+
+// number is a number cell, or a blank one for values Excel cannot store
+func number(f float64, style Style) Cell {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Blank()
+	}
+	return Cell{kind: cellNumber, number: f, style: style}
+}
+
+// serial is the Excel date serial of t: days since the epoch, with the time of day as a fraction
+func serial(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return float64(wall.Sub(excelEpoch)) / float64(24*time.Hour)
+}
+
+// Column is a column of a sheet: its header and its width in characters
+type Column struct {
+	Header string
+	Width  float64
+}
+
+// Writer streams a workbook to w. Sheets are written one after the other and rows go
+// straight into the zip, so a workbook of any size is written in constant memory.
+type Writer struct {
+	zw      *zip.Writer
+	sheets  []string
+	current *Sheet
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{zw: zip.NewWriter(w)}
+}
+
+// AddSheet ends the previous sheet and starts a new one, whose first row holds the
+// column headers and stays in view when scrolling. Characters Excel does not allow
+// in sheet names are replaced and names are cut to 31 characters.
+func (w *Writer) AddSheet(name string, columns []Column) (*Sheet, error) {
+	if err := w.endSheet(); err != nil {
+		return nil, err
+	}
+	name = sheetName(name)
+	for _, existing := range w.sheets {
+		if strings.EqualFold(existing, name) {
+			return nil, fmt.Errorf("duplicate sheet name %q", name)
+		}
+	}
+
+	part, err := w.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)+1))
+	if err != nil {
+		return nil, err
+	}
+	w.sheets = append(w.sheets, name)
+	sheet := &Sheet{w: bufio.NewWriter(part)}
+	w.current = sheet
+
+	sheet.w.WriteString(xml.Header)
+	sheet.w.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	sheet.w.WriteString(`<sheetViews><sheetView workbookViewId="0">`)
+	sheet.w.WriteString(`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/><selection pane="bottomLeft"/>`)
+	sheet.w.WriteString(`</sheetView></sheetViews>`)
+	if len(columns) > 0 {
+		sheet.w.WriteString(`<cols>`)
+		for i, column := range columns {
+			width := column.Width
+			if width <= 0 {
+				width = 12
+			}
+			fmt.Fprintf(sheet.w, `<col min="%d" max="%d" width="%s" customWidth="1"/>`, i+1, i+1, strconv.FormatFloat(width, 'f', -1, 64))
+		}
+		sheet.w.WriteString(`</cols>`)
+	}
+	sheet.w.WriteString(`<sheetData>`)
+
+	header := make([]Cell, len(columns))
+	for i, column := range columns {
+		header[i] = Cell{kind: cellText, text: column.Header, style: StyleHeader}
+	}
+	if err := sheet.WriteRow(header...); err != nil {
+		return nil, err
+	}
+	return sheet, nil
+}
+
+// Close ends the last sheet and writes the workbook parts that list the sheets
+func (w *Writer) Close() error {
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+	if len(w.sheets) == 0 {
+		return fmt.Errorf("a workbook needs at least one sheet")
+	}
+
+	var types, workbook, rels strings.Builder
+	types.WriteString(xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i, name := range w.sheets {
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		workbook.WriteString(`<sheet name="`)
+		xml.EscapeText(&workbook, []byte(name))
+		fmt.Fprintf(&workbook, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	types.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(w.sheets)+1)
+	rels.WriteString(`</Relationships>`)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", types.String()},
+		{"_rels/.rels", packageRels},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+		{"xl/styles.xml", styles},
+	}
+	for _, part := range parts {
+		pw, err := w.zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(pw, part.content); err != nil {
+			return err
+		}
+	}
+	return w.zw.Close()
+}
+
+func (w *Writer) endSheet() error {
+	if w.current == nil {
+		return nil
+	}
+	sheet := w.current
+	w.current = nil
+	if sheet.err != nil {
+		return sheet.err
+	}
+	sheet.w.WriteString(`</sheetData></worksheet>`)
+	return sheet.w.Flush()
+}
+
+// Sheet receives the rows of one sheet; it is only valid until the next AddSheet or Close
+type Sheet struct {
+	w    *bufio.Writer
+	rows int
+	err  error
+}
+
+// WriteRow appends a row of cells, starting in the first column
+func (s *Sheet) WriteRow(cells ...Cell) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.rows++
+	fmt.Fprintf(s.w, `<row r="%d">`, s.rows)
+	for i, cell := range cells {
+		if cell.kind == cellBlank {
+			continue
+		}
+		fmt.Fprintf(s.w, `<c r="%s%d"`, columnName(i), s.rows)
+		if cell.style != StyleDefault {
+			fmt.Fprintf(s.w, ` s="%d"`, cell.style)
+		}
+		switch cell.kind {
+		case cellText:
+			s.w.WriteString(` t="inlineStr"><is><t xml:space="preserve">`)
+			xml.EscapeText(s.w, []byte(truncate(cell.text, maxCellText)))
+			s.w.WriteString(`</t></is></c>`)
+		case cellNumber:
+			s.w.WriteString(`><v>`)
+			s.w.WriteString(strconv.FormatFloat(cell.number, 'g', -1, 64))
+			s.w.WriteString(`</v></c>`)
+		}
+	}
+	_, s.err = s.w.WriteString(`</row>`)
+	return s.err
+}
+
+// Rows is the number of rows written, the header included
+func (s *Sheet) Rows() int {
+	return s.rows
+}
+
+// columnName is the letters of a zero-based column index: A to Z, then AA and on
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return ' '
+		}
+		return r
+	}, name)
+	name = strings.Trim(truncate(name, 31), "' ")
+	if name == "" {
+		return "Sheet"
+	}
+	return name
+}
+
+// truncate cuts s to at most n characters
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+const packageRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// styles holds one cellXfs entry per Style, in the same order
+const styles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="3">` +
+	`<numFmt numFmtId="164" formatCode="dd/mm/yyyy hh:mm:ss"/>` +
+	`<numFmt numFmtId="165" formatCode="dd/mm/yyyy"/>` +
+	`<numFmt numFmtId="166" formatCode="[h]:mm:ss"/>` +
+	`</numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="3"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>` +
+	`<fill><patternFill patternType="solid"><fgColor rgb="FFE5E7EB"/><bgColor indexed="64"/></patternFill></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="7">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="2" borderId="0" xfId="0" applyFont="1" applyFill="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="166" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="10" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
@@ -0,0 +1,150 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type worksheet struct {
+	Pane struct {
+		YSplit int    `xml:"ySplit,attr"`
+		State  string `xml:"state,attr"`
+	} `xml:"sheetViews>sheetView>pane"`
+	Cols []struct {
+		Width float64 `xml:"width,attr"`
+	} `xml:"cols>col"`
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			S      int    `xml:"s,attr"`
+			T      string `xml:"t,attr"`
+			V      string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readParts unzips a workbook and checks that every part is well-formed XML
+func readParts(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	parts := map[string][]byte{}
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err, "%s is not well-formed", file.Name)
+		}
+		parts[file.Name] = content
+	}
+	return parts
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	tickets, err := w.AddSheet("Tiket", []Column{{Header: "Nomor", Width: 10}, {Header: "Diambil"}, {Header: "Tunggu"}, {Header: "Catatan"}})
+	require.NoError(t, err)
+	created := time.Date(2026, 10, 19, 8, 30, 0, 0, time.FixedZone("WIB", 7*3600))
+	require.NoError(t, tickets.WriteRow(String("A001"), DateTime(created), Duration(330), String(`pindah, "loket" <2> & 3`)))
+	require.NoError(t, tickets.WriteRow(String("A002"), Blank(), Blank(), String("")))
+	assert.Equal(t, 3, tickets.Rows())
+
+	daily, err := w.AddSheet("Harian: Okt/2026", []Column{{Header: "Tanggal"}, {Header: "Tiket"}, {Header: "Selesai"}, {Header: "Rata-rata"}})
+	require.NoError(t, err)
+	require.NoError(t, daily.WriteRow(Date(created), Int(42), Percent(0.75), Float(1.5)))
+	require.NoError(t, w.Close())
+
+	parts := readParts(t, buf.Bytes())
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		assert.Contains(t, parts, name)
+	}
+	assert.Contains(t, string(parts["[Content_Types].xml"]), `PartName="/xl/worksheets/sheet2.xml"`)
+	assert.Contains(t, string(parts["xl/workbook.xml"]), `<sheet name="Harian  Okt 2026" sheetId="2" r:id="rId2"/>`)
+	assert.Contains(t, string(parts["xl/_rels/workbook.xml.rels"]), `Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"`)
+
+	var sheet worksheet
+	require.NoError(t, xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet))
+	assert.Equal(t, 1, sheet.Pane.YSplit)
+	assert.Equal(t, "frozen", sheet.Pane.State)
+	require.Len(t, sheet.Cols, 4)
+	assert.Equal(t, 10.0, sheet.Cols[0].Width)
+	assert.Equal(t, 12.0, sheet.Cols[1].Width)
+
+	require.Len(t, sheet.Rows, 3)
+	header := sheet.Rows[0].Cells
+	assert.Equal(t, "Nomor", header[0].Inline)
+	assert.Equal(t, int(StyleHeader), header[0].S)
+
+	row := sheet.Rows[1].Cells
+	require.Len(t, row, 4)
+	assert.Equal(t, "inlineStr", row[0].T)
+	assert.Equal(t, "B2", row[1].R)
+	assert.Equal(t, int(StyleDateTime), row[1].S)
+	// 19 October 2026 is serial 46314, and 08:30 is 0.354166... of a day in the ticket's own zone
+	assert.True(t, strings.HasPrefix(row[1].V, "46314.354166"), row[1].V)
+	assert.Equal(t, int(StyleDuration), row[2].S)
+	assert.True(t, strings.HasPrefix(row[2].V, "0.0038194"), row[2].V)
+	assert.Equal(t, `pindah, "loket" <2> & 3`, row[3].Inline)
+
+	// Blank cells are left out; empty strings are kept
+	empty := sheet.Rows[2].Cells
+	require.Len(t, empty, 2)
+	assert.Equal(t, "D3", empty[1].R)
+
+	var second worksheet
+	require.NoError(t, xml.Unmarshal(parts["xl/worksheets/sheet2.xml"], &second))
+	row = second.Rows[1].Cells
+	assert.Equal(t, "46314", row[0].V)
+	assert.Equal(t, int(StyleDate), row[0].S)
+	assert.Equal(t, "42", row[1].V)
+	assert.Equal(t, int(StyleDefault), row[1].S)
+	assert.Equal(t, "0.75", row[2].V)
+	assert.Equal(t, int(StylePercent), row[2].S)
+	assert.Equal(t, int(StyleDecimal), row[3].S)
+}
+
+func TestWriter_Errors(t *testing.T) {
+	w := NewWriter(io.Discard)
+	assert.Error(t, w.Close(), "a workbook without sheets is invalid")
+
+	w = NewWriter(io.Discard)
+	_, err := w.AddSheet("Tiket", nil)
+	require.NoError(t, err)
+	_, err = w.AddSheet("tiket", nil)
+	assert.Error(t, err, "sheet names are unique regardless of case")
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+	assert.Equal(t, "BA", columnName(52))
+}
+
+func TestNumber_SkipsValuesExcelCannotStore(t *testing.T) {
+	assert.Equal(t, Blank(), Float(math.NaN()))
+	assert.Equal(t, Blank(), Duration(math.Inf(1)))
+}
@@ -0,0 +1,9 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_push_subscriptions_updated_at ON push_subscriptions;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_push_subscriptions_ticket_id;
+DROP INDEX IF EXISTS idx_push_subscriptions_expires_at;
+
+-- Drop table
+DROP TABLE IF EXISTS push_subscriptions;
@@ -0,0 +1,22 @@
+-- Create push_subscriptions table for browser Web Push on tracked tickets
+CREATE TABLE IF NOT EXISTS push_subscriptions (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    endpoint TEXT NOT NULL,
+    p256dh VARCHAR(255) NOT NULL,
+    auth VARCHAR(255) NOT NULL,
+    nearly_up_notified_at TIMESTAMP,
+    called_notified_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(ticket_id, endpoint)
+);
+
+-- Create indexes for faster lookups
+CREATE INDEX idx_push_subscriptions_ticket_id ON push_subscriptions(ticket_id);
+CREATE INDEX idx_push_subscriptions_expires_at ON push_subscriptions(expires_at);
+
+-- Trigger to update updated_at timestamp
+CREATE TRIGGER update_push_subscriptions_updated_at BEFORE UPDATE ON push_subscriptions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
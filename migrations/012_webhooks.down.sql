@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS tickets_outbox_event ON tickets;
+DROP FUNCTION IF EXISTS enqueue_ticket_outbox_event();
+
+DROP TRIGGER IF EXISTS update_webhook_deliveries_updated_at ON webhook_deliveries;
+DROP TRIGGER IF EXISTS update_webhook_subscriptions_updated_at ON webhook_subscriptions;
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,104 @@
+-- Transactional outbox: ticket changes enqueue an event in the same transaction
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    aggregate_id INTEGER NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_events_unprocessed ON outbox_events(id) WHERE processed_at IS NULL;
+CREATE INDEX idx_outbox_events_processed_at ON outbox_events(processed_at);
+
+-- Admin-managed webhook subscriptions
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per event per subscription; doubles as the delivery log and dead-letter list
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP,
+    last_attempt_at TIMESTAMP,
+    last_status_code INTEGER,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(subscription_id, event_id)
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_subscription_id ON webhook_deliveries(subscription_id);
+CREATE INDEX idx_webhook_deliveries_status ON webhook_deliveries(status);
+
+-- Trigger to update updated_at timestamp
+CREATE TRIGGER update_webhook_subscriptions_updated_at BEFORE UPDATE ON webhook_subscriptions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_webhook_deliveries_updated_at BEFORE UPDATE ON webhook_deliveries
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Enqueue ticket lifecycle events into the outbox
+CREATE OR REPLACE FUNCTION enqueue_ticket_outbox_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    evt VARCHAR(50);
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        evt := 'ticket.issued';
+    ELSIF NEW.status = 'serving' AND (
+        OLD.status IS DISTINCT FROM NEW.status
+        OR OLD.called_at IS DISTINCT FROM NEW.called_at
+        OR OLD.counter_id IS DISTINCT FROM NEW.counter_id
+    ) THEN
+        evt := 'ticket.called';
+    ELSIF OLD.status IS DISTINCT FROM NEW.status THEN
+        evt := CASE NEW.status
+            WHEN 'completed' THEN 'ticket.completed'
+            WHEN 'cancelled' THEN 'ticket.cancelled'
+            WHEN 'no_show' THEN 'ticket.no_show'
+        END;
+    END IF;
+
+    IF evt IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO outbox_events (event_type, aggregate_id, payload)
+    SELECT evt, NEW.id, jsonb_build_object(
+        'ticket_id', NEW.id,
+        'ticket_number', NEW.ticket_number,
+        'status', NEW.status,
+        'queue_date', NEW.queue_date,
+        'category_id', NEW.category_id,
+        'category_name', (SELECT name FROM categories WHERE id = NEW.category_id),
+        'counter_id', NEW.counter_id,
+        'counter_number', (SELECT number FROM counters WHERE id = NEW.counter_id),
+        'created_at', NEW.created_at,
+        'called_at', NEW.called_at,
+        'completed_at', NEW.completed_at
+    );
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER tickets_outbox_event AFTER INSERT OR UPDATE OF status, called_at, counter_id ON tickets
+    FOR EACH ROW EXECUTE FUNCTION enqueue_ticket_outbox_event();
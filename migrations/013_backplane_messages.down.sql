@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_backplane_messages_created_at;
+
+-- Drop table
+DROP TABLE IF EXISTS backplane_messages;
@@ -0,0 +1,8 @@
+-- Holds hub broadcasts too large for a NOTIFY payload (8000 bytes); the notification carries the row id instead
+CREATE TABLE IF NOT EXISTS backplane_messages (
+    id BIGSERIAL PRIMARY KEY,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_backplane_messages_created_at ON backplane_messages(created_at);
@@ -0,0 +1,55 @@
+-- Restore the outbox trigger from 012, which watches called_at
+CREATE OR REPLACE FUNCTION enqueue_ticket_outbox_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    evt VARCHAR(50);
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        evt := 'ticket.issued';
+    ELSIF NEW.status = 'serving' AND (
+        OLD.status IS DISTINCT FROM NEW.status
+        OR OLD.called_at IS DISTINCT FROM NEW.called_at
+        OR OLD.counter_id IS DISTINCT FROM NEW.counter_id
+    ) THEN
+        evt := 'ticket.called';
+    ELSIF OLD.status IS DISTINCT FROM NEW.status THEN
+        evt := CASE NEW.status
+            WHEN 'completed' THEN 'ticket.completed'
+            WHEN 'cancelled' THEN 'ticket.cancelled'
+            WHEN 'no_show' THEN 'ticket.no_show'
+        END;
+    END IF;
+
+    IF evt IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO outbox_events (event_type, aggregate_id, payload)
+    SELECT evt, NEW.id, jsonb_build_object(
+        'ticket_id', NEW.id,
+        'ticket_number', NEW.ticket_number,
+        'status', NEW.status,
+        'queue_date', NEW.queue_date,
+        'category_id', NEW.category_id,
+        'category_name', (SELECT name FROM categories WHERE id = NEW.category_id),
+        'counter_id', NEW.counter_id,
+        'counter_number', (SELECT number FROM counters WHERE id = NEW.counter_id),
+        'created_at', NEW.created_at,
+        'called_at', NEW.called_at,
+        'completed_at', NEW.completed_at
+    );
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS tickets_outbox_event ON tickets;
+CREATE TRIGGER tickets_outbox_event AFTER INSERT OR UPDATE OF status, called_at, counter_id ON tickets
+    FOR EACH ROW EXECUTE FUNCTION enqueue_ticket_outbox_event();
+
+DROP INDEX IF EXISTS idx_tickets_serving_last_called;
+ALTER TABLE tickets DROP COLUMN IF EXISTS answered_at;
+ALTER TABLE tickets DROP COLUMN IF EXISTS last_called_at;
+
+DROP INDEX IF EXISTS idx_ticket_calls_ticket_id;
+DROP TABLE IF EXISTS ticket_calls;
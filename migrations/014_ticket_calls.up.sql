@@ -0,0 +1,73 @@
+-- Log every call, recall and transfer of a ticket instead of overwriting called_at
+CREATE TABLE IF NOT EXISTS ticket_calls (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    counter_id INTEGER REFERENCES counters(id) ON DELETE SET NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('call', 'recall', 'transfer')),
+    called_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ticket_calls_ticket_id ON ticket_calls(ticket_id, called_at);
+
+-- called_at now keeps the first call, so wait_time measures time to the first call.
+-- last_called_at follows recalls and transfers; answered_at is set when the customer arrives.
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS last_called_at TIMESTAMP;
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS answered_at TIMESTAMP;
+
+UPDATE tickets SET last_called_at = called_at WHERE called_at IS NOT NULL;
+
+INSERT INTO ticket_calls (ticket_id, counter_id, kind, called_at)
+SELECT id, counter_id, 'call', called_at FROM tickets WHERE called_at IS NOT NULL;
+
+CREATE INDEX idx_tickets_serving_last_called ON tickets(last_called_at) WHERE status = 'serving';
+
+-- Recalls no longer touch called_at; keep announcing them to webhooks as ticket.called
+CREATE OR REPLACE FUNCTION enqueue_ticket_outbox_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    evt VARCHAR(50);
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        evt := 'ticket.issued';
+    ELSIF NEW.status = 'serving' AND (
+        OLD.status IS DISTINCT FROM NEW.status
+        OR OLD.last_called_at IS DISTINCT FROM NEW.last_called_at
+        OR OLD.counter_id IS DISTINCT FROM NEW.counter_id
+    ) THEN
+        evt := 'ticket.called';
+    ELSIF OLD.status IS DISTINCT FROM NEW.status THEN
+        evt := CASE NEW.status
+            WHEN 'completed' THEN 'ticket.completed'
+            WHEN 'cancelled' THEN 'ticket.cancelled'
+            WHEN 'no_show' THEN 'ticket.no_show'
+        END;
+    END IF;
+
+    IF evt IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO outbox_events (event_type, aggregate_id, payload)
+    SELECT evt, NEW.id, jsonb_build_object(
+        'ticket_id', NEW.id,
+        'ticket_number', NEW.ticket_number,
+        'status', NEW.status,
+        'queue_date', NEW.queue_date,
+        'category_id', NEW.category_id,
+        'category_name', (SELECT name FROM categories WHERE id = NEW.category_id),
+        'counter_id', NEW.counter_id,
+        'counter_number', (SELECT number FROM counters WHERE id = NEW.counter_id),
+        'created_at', NEW.created_at,
+        'called_at', NEW.called_at,
+        'last_called_at', NEW.last_called_at,
+        'completed_at', NEW.completed_at
+    );
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS tickets_outbox_event ON tickets;
+CREATE TRIGGER tickets_outbox_event AFTER INSERT OR UPDATE OF status, called_at, last_called_at, counter_id ON tickets
+    FOR EACH ROW EXECUTE FUNCTION enqueue_ticket_outbox_event();
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_display_profiles_updated_at ON display_profiles;
+DROP TABLE IF EXISTS display_profiles;
@@ -0,0 +1,21 @@
+-- Named display board configurations; each screen opens /display/p/<slug>
+CREATE TABLE IF NOT EXISTS display_profiles (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    layout VARCHAR(20) NOT NULL DEFAULT 'landscape' CHECK (layout IN ('landscape', 'portrait')),
+    -- Empty means every category or counter
+    category_ids INTEGER[] NOT NULL DEFAULT '{}',
+    counter_ids INTEGER[] NOT NULL DEFAULT '{}',
+    recent_count INTEGER NOT NULL DEFAULT 10 CHECK (recent_count BETWEEN 1 AND 50),
+    show_waiting BOOLEAN NOT NULL DEFAULT true,
+    accent_color VARCHAR(7) NOT NULL DEFAULT '#3B82F6',
+    background_color VARCHAR(7) NOT NULL DEFAULT '#111827',
+    ticker_text TEXT NOT NULL DEFAULT '',
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_display_profiles_updated_at BEFORE UPDATE ON display_profiles
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS update_signage_schedules_updated_at ON signage_schedules;
+DROP TRIGGER IF EXISTS update_signage_playlists_updated_at ON signage_playlists;
+DROP TRIGGER IF EXISTS update_signage_media_updated_at ON signage_media;
+
+DROP TABLE IF EXISTS signage_schedules;
+DROP TABLE IF EXISTS signage_playlist_items;
+DROP TABLE IF EXISTS signage_playlists;
+DROP TABLE IF EXISTS signage_media;
@@ -0,0 +1,53 @@
+-- Uploaded signage images and videos. Files are stored under their content hash.
+CREATE TABLE IF NOT EXISTS signage_media (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    filename VARCHAR(100) NOT NULL UNIQUE,
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS signage_playlists (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A duration of 0 plays a video to its end
+CREATE TABLE IF NOT EXISTS signage_playlist_items (
+    id SERIAL PRIMARY KEY,
+    playlist_id INTEGER NOT NULL REFERENCES signage_playlists(id) ON DELETE CASCADE,
+    media_id INTEGER NOT NULL REFERENCES signage_media(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    duration_seconds INTEGER NOT NULL DEFAULT 10 CHECK (duration_seconds >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_signage_playlist_items_playlist ON signage_playlist_items(playlist_id, position);
+
+-- When a display profile plays a playlist. Days are 0 (Sunday) to 6; empty means every day.
+-- An end time before the start time runs past midnight.
+CREATE TABLE IF NOT EXISTS signage_schedules (
+    id SERIAL PRIMARY KEY,
+    display_profile_id INTEGER NOT NULL REFERENCES display_profiles(id) ON DELETE CASCADE,
+    playlist_id INTEGER NOT NULL REFERENCES signage_playlists(id) ON DELETE CASCADE,
+    days_of_week INTEGER[] NOT NULL DEFAULT '{}',
+    start_time TIME NOT NULL DEFAULT '00:00',
+    end_time TIME NOT NULL DEFAULT '00:00',
+    priority INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_signage_schedules_profile ON signage_schedules(display_profile_id);
+
+CREATE TRIGGER update_signage_media_updated_at BEFORE UPDATE ON signage_media
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_signage_playlists_updated_at BEFORE UPDATE ON signage_playlists
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_signage_schedules_updated_at BEFORE UPDATE ON signage_schedules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
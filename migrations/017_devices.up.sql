@@ -0,0 +1,26 @@
+-- Kiosks and display screens. A device is created by an admin, paired once with a
+-- short-lived code and then authenticates its heartbeats with a device token.
+CREATE TABLE IF NOT EXISTS devices (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('kiosk', 'display')),
+    display_profile_id INTEGER REFERENCES display_profiles(id) ON DELETE SET NULL,
+    pairing_code VARCHAR(8) UNIQUE,
+    pairing_expires_at TIMESTAMP,
+    -- SHA-256 of the device token; the token itself is only known to the device
+    token_hash VARCHAR(64) UNIQUE,
+    paired_at TIMESTAMP,
+    last_seen_at TIMESTAMP,
+    app_version VARCHAR(50) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    current_url VARCHAR(255) NOT NULL DEFAULT '',
+    uptime_seconds BIGINT NOT NULL DEFAULT 0,
+    last_event_seq BIGINT NOT NULL DEFAULT 0,
+    -- Set when the silent-device alert fired, cleared by the next heartbeat
+    silent_since TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_devices_updated_at BEFORE UPDATE ON devices
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,6 @@
+ALTER TABLE devices DROP COLUMN IF EXISTS kiosk_profile_id;
+
+DROP TRIGGER IF EXISTS update_kiosk_profiles_updated_at ON kiosk_profiles;
+
+DROP TABLE IF EXISTS kiosk_profile_categories;
+DROP TABLE IF EXISTS kiosk_profiles;
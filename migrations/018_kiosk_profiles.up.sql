@@ -0,0 +1,34 @@
+-- Named kiosk configurations; a kiosk opens /kiosk/p/<slug> or is sent there by its device
+CREATE TABLE IF NOT EXISTS kiosk_profiles (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    language VARCHAR(5) NOT NULL DEFAULT 'id' CHECK (language IN ('id', 'en')),
+    ticket_output VARCHAR(10) NOT NULL DEFAULT 'print' CHECK (ticket_output IN ('print', 'qr', 'both')),
+    -- Seconds without a touch before the attract screen shows; 0 disables it
+    idle_seconds INTEGER NOT NULL DEFAULT 60 CHECK (idle_seconds BETWEEN 0 AND 3600),
+    attract_title VARCHAR(100) NOT NULL DEFAULT '',
+    attract_text TEXT NOT NULL DEFAULT '',
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_kiosk_profiles_updated_at BEFORE UPDATE ON kiosk_profiles
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- The categories a profile offers, in button order. A profile without any offers every
+-- active category. Empty label or icon use the category's own. Duplicates are dropped
+-- when saving rather than by a constraint, so the list can be replaced in one statement.
+CREATE TABLE IF NOT EXISTS kiosk_profile_categories (
+    id SERIAL PRIMARY KEY,
+    kiosk_profile_id INTEGER NOT NULL REFERENCES kiosk_profiles(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    label VARCHAR(100) NOT NULL DEFAULT '',
+    icon VARCHAR(50) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_kiosk_profile_categories_profile ON kiosk_profile_categories(kiosk_profile_id, position);
+
+ALTER TABLE devices ADD COLUMN IF NOT EXISTS kiosk_profile_id INTEGER REFERENCES kiosk_profiles(id) ON DELETE SET NULL;
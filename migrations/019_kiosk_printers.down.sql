@@ -0,0 +1,3 @@
+ALTER TABLE kiosk_profiles
+    DROP COLUMN IF EXISTS printer_target,
+    DROP COLUMN IF EXISTS printer_type;
@@ -0,0 +1,7 @@
+-- Where a profile's kiosks print tickets: through the kiosk browser's print dialog, on a
+-- network receipt printer (raw TCP, port 9100 unless printer_target names one) or into a
+-- spool file under PRINTER_SPOOL_DIR
+ALTER TABLE kiosk_profiles
+    ADD COLUMN IF NOT EXISTS printer_type VARCHAR(10) NOT NULL DEFAULT 'browser'
+        CHECK (printer_type IN ('browser', 'network', 'file')),
+    ADD COLUMN IF NOT EXISTS printer_target VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS update_report_runs_updated_at ON report_runs;
+DROP TRIGGER IF EXISTS update_report_schedules_updated_at ON report_schedules;
+
+DROP TABLE IF EXISTS report_runs;
+DROP TABLE IF EXISTS report_schedules;
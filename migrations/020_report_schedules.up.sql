@@ -0,0 +1,53 @@
+-- Reports that are rendered on a cron schedule and emailed to a list of recipients.
+-- report_type is the period a run covers: the day, the seven days or the calendar month
+-- before it runs. Unset filters cover everything.
+CREATE TABLE IF NOT EXISTS report_schedules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    report_type VARCHAR(10) NOT NULL CHECK (report_type IN ('daily', 'weekly', 'monthly')),
+    format VARCHAR(10) NOT NULL CHECK (format IN ('pdf', 'xlsx', 'csv')),
+    category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL,
+    counter_id INTEGER REFERENCES counters(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT '',
+    recipients TEXT[] NOT NULL DEFAULT '{}',
+    cron VARCHAR(100) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    next_run_at TIMESTAMP,
+    last_run_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_report_schedules_due ON report_schedules(next_run_at) WHERE is_active;
+
+-- One row per run of a schedule; doubles as the delivery queue and the run history.
+-- The rendered file is kept under REPORT_SCHEDULE_DIR as file_name.
+CREATE TABLE IF NOT EXISTS report_runs (
+    id BIGSERIAL PRIMARY KEY,
+    schedule_id INTEGER NOT NULL REFERENCES report_schedules(id) ON DELETE CASCADE,
+    scheduled_for TIMESTAMP NOT NULL,
+    period_from DATE NOT NULL,
+    period_to DATE NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    recipients TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP,
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    file_size BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(schedule_id, scheduled_for)
+);
+
+CREATE INDEX idx_report_runs_due ON report_runs(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_report_runs_schedule_id ON report_runs(schedule_id);
+
+CREATE TRIGGER update_report_schedules_updated_at BEFORE UPDATE ON report_schedules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_report_runs_updated_at BEFORE UPDATE ON report_runs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS counter_pauses;
+DROP TABLE IF EXISTS staff_shifts;
+
+DROP INDEX IF EXISTS idx_tickets_served_by;
+DROP INDEX IF EXISTS idx_tickets_completed_by;
+ALTER TABLE tickets DROP COLUMN IF EXISTS completed_by;
+ALTER TABLE tickets DROP COLUMN IF EXISTS served_at;
+ALTER TABLE tickets DROP COLUMN IF EXISTS served_by;
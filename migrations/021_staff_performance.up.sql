@@ -0,0 +1,45 @@
+-- Stamp tickets with the staff member serving them; counters can change hands, so
+-- counter_id alone cannot attribute work afterwards
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS served_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS served_at TIMESTAMP;
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS completed_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+-- Until now the call log was the only record of who served a ticket
+UPDATE tickets t SET served_by = lc.user_id, served_at = lc.called_at
+FROM (
+    SELECT DISTINCT ON (ticket_id) ticket_id, user_id, called_at
+    FROM ticket_calls WHERE kind <> 'recall'
+    ORDER BY ticket_id, called_at DESC, id DESC
+) lc
+WHERE lc.ticket_id = t.id;
+
+UPDATE tickets SET completed_by = served_by WHERE status IN ('completed', 'no_show');
+
+CREATE INDEX idx_tickets_completed_by ON tickets(completed_by, completed_at) WHERE completed_by IS NOT NULL;
+CREATE INDEX idx_tickets_served_by ON tickets(served_by) WHERE status = 'serving';
+
+-- A shift is a stretch of work at one counter, from the first action until logout
+-- or until the staff member has been inactive for the configured gap
+CREATE TABLE IF NOT EXISTS staff_shifts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    counter_id INTEGER REFERENCES counters(id) ON DELETE SET NULL,
+    started_at TIMESTAMP NOT NULL,
+    last_active_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP
+);
+
+CREATE INDEX idx_staff_shifts_started_at ON staff_shifts(started_at, user_id);
+CREATE UNIQUE INDEX idx_staff_shifts_open ON staff_shifts(user_id) WHERE ended_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS counter_pauses (
+    id SERIAL PRIMARY KEY,
+    shift_id INTEGER NOT NULL REFERENCES staff_shifts(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    counter_id INTEGER REFERENCES counters(id) ON DELETE SET NULL,
+    paused_at TIMESTAMP NOT NULL,
+    resumed_at TIMESTAMP
+);
+
+CREATE INDEX idx_counter_pauses_shift_id ON counter_pauses(shift_id);
+CREATE UNIQUE INDEX idx_counter_pauses_open ON counter_pauses(user_id) WHERE resumed_at IS NULL;
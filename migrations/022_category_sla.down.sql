@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS sla_breaches_outbox_event ON sla_breaches;
+DROP FUNCTION IF EXISTS enqueue_sla_breach_outbox_event();
+DROP TABLE IF EXISTS sla_breaches;
+ALTER TABLE categories DROP COLUMN IF EXISTS sla_service_minutes;
+ALTER TABLE categories DROP COLUMN IF EXISTS sla_wait_minutes;
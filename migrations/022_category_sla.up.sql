@@ -0,0 +1,48 @@
+-- SLA targets per category in minutes; 0 means no target
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS sla_wait_minutes INTEGER NOT NULL DEFAULT 0 CHECK (sla_wait_minutes >= 0);
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS sla_service_minutes INTEGER NOT NULL DEFAULT 0 CHECK (sla_service_minutes >= 0);
+
+-- One row per ticket that went past a target. The unique key makes the monitor report
+-- each breach once, however many instances run it.
+CREATE TABLE IF NOT EXISTS sla_breaches (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('wait', 'service')),
+    target_seconds INTEGER NOT NULL,
+    elapsed_seconds INTEGER NOT NULL,
+    breached_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(ticket_id, kind)
+);
+
+CREATE INDEX idx_sla_breaches_breached_at ON sla_breaches(breached_at);
+
+-- Announce breaches to webhooks through the outbox, in the transaction that records them
+CREATE OR REPLACE FUNCTION enqueue_sla_breach_outbox_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO outbox_events (event_type, aggregate_id, payload)
+    SELECT 'ticket.sla_breached', t.id, jsonb_build_object(
+        'ticket_id', t.id,
+        'ticket_number', t.ticket_number,
+        'status', t.status,
+        'queue_date', t.queue_date,
+        'category_id', t.category_id,
+        'category_name', (SELECT name FROM categories WHERE id = t.category_id),
+        'counter_id', t.counter_id,
+        'counter_number', (SELECT number FROM counters WHERE id = t.counter_id),
+        'created_at', t.created_at,
+        'called_at', t.called_at,
+        'sla_kind', NEW.kind,
+        'target_seconds', NEW.target_seconds,
+        'elapsed_seconds', NEW.elapsed_seconds,
+        'breached_at', NEW.breached_at
+    )
+    FROM tickets t WHERE t.id = NEW.ticket_id;
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER sla_breaches_outbox_event AFTER INSERT ON sla_breaches
+    FOR EACH ROW EXECUTE FUNCTION enqueue_sla_breach_outbox_event();
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS queue_alerts_outbox_event ON queue_alerts;
+DROP FUNCTION IF EXISTS enqueue_queue_alert_outbox_event();
+DROP TABLE IF EXISTS queue_alerts;
+DROP TABLE IF EXISTS alert_rule_states;
+DROP TABLE IF EXISTS alert_rules;
+UPDATE users SET role = 'staff' WHERE role = 'supervisor';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'staff'));
@@ -0,0 +1,80 @@
+-- Supervisors work counters like staff and also receive queue alerts
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'staff', 'supervisor'));
+
+-- Thresholds checked against the live queue of each category, or of every category when
+-- category_id is NULL. Threshold is a ticket count for waiting_count, minutes for oldest_wait
+-- and unused for no_open_counter.
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('waiting_count', 'oldest_wait', 'no_open_counter')),
+    category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE,
+    threshold INTEGER NOT NULL DEFAULT 0 CHECK (threshold >= 0),
+    cooldown_minutes INTEGER NOT NULL DEFAULT 15 CHECK (cooldown_minutes >= 0),
+    email_recipients TEXT[] NOT NULL DEFAULT '{}',
+    notify_webhook BOOLEAN NOT NULL DEFAULT false,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_alert_rules_updated_at BEFORE UPDATE ON alert_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- When each rule last fired for each category. Claiming a row is what starts a cooldown,
+-- so an alert is raised once however many instances check the rules.
+CREATE TABLE IF NOT EXISTS alert_rule_states (
+    rule_id INTEGER NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    last_triggered_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (rule_id, category_id)
+);
+
+-- Alert history. Rule and category names are copied so entries outlive them.
+CREATE TABLE IF NOT EXISTS queue_alerts (
+    id SERIAL PRIMARY KEY,
+    rule_id INTEGER REFERENCES alert_rules(id) ON DELETE SET NULL,
+    rule_name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL,
+    category_name VARCHAR(100) NOT NULL,
+    value INTEGER NOT NULL,
+    threshold INTEGER NOT NULL,
+    message TEXT NOT NULL,
+    notify_webhook BOOLEAN NOT NULL DEFAULT false,
+    triggered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    emailed_at TIMESTAMP,
+    email_error TEXT
+);
+
+CREATE INDEX idx_queue_alerts_triggered_at ON queue_alerts(triggered_at);
+CREATE INDEX idx_queue_alerts_rule_id ON queue_alerts(rule_id);
+
+-- Announce alerts of rules that notify webhooks through the outbox, in the transaction
+-- that records them
+CREATE OR REPLACE FUNCTION enqueue_queue_alert_outbox_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.notify_webhook THEN
+        INSERT INTO outbox_events (event_type, aggregate_id, payload)
+        VALUES ('queue.alert', NEW.id, jsonb_build_object(
+            'alert_id', NEW.id,
+            'rule_id', NEW.rule_id,
+            'rule_name', NEW.rule_name,
+            'kind', NEW.kind,
+            'category_id', NEW.category_id,
+            'category_name', NEW.category_name,
+            'value', NEW.value,
+            'threshold', NEW.threshold,
+            'message', NEW.message,
+            'triggered_at', NEW.triggered_at
+        ));
+    END IF;
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER queue_alerts_outbox_event AFTER INSERT ON queue_alerts
+    FOR EACH ROW EXECUTE FUNCTION enqueue_queue_alert_outbox_event();
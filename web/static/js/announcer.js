@@ -0,0 +1,61 @@
+// Plays call announcements one after another. Calls that arrive while one is
+// playing wait their turn, so two counters calling at once never talk over each
+// other. Messages replayed after a reconnect are skipped once they are stale.
+//
+// Browsers block audio until the page has been interacted with. Unattended
+// display screens should run the browser with autoplay allowed, e.g. Chrome's
+// --autoplay-policy=no-user-gesture-required; otherwise a tap on the page
+// unlocks it.
+(function () {
+  const maxAge = 60 * 1000;
+  const queue = [];
+  const idleCallbacks = [];
+  let playing = false;
+
+  function next() {
+    const url = queue.shift();
+    if (!url) {
+      playing = false;
+      idleCallbacks.splice(0).forEach(function (callback) {
+        callback();
+      });
+      return;
+    }
+
+    playing = true;
+    const audio = new Audio(url);
+    audio.onended = next;
+    audio.onerror = next;
+    audio.play().catch(function (error) {
+      console.warn("Announcement not played:", error.message);
+      next();
+    });
+  }
+
+  // enqueue plays a ticket_update message's announcement after any already queued
+  function enqueue(message) {
+    const payload = message.payload || {};
+    if (!payload.announcement) {
+      return false;
+    }
+    if (message.timestamp && Date.now() - Date.parse(message.timestamp) > maxAge) {
+      return false;
+    }
+    queue.push(payload.announcement);
+    if (!playing) {
+      next();
+    }
+    return true;
+  }
+
+  // whenIdle runs callback once nothing is playing or queued
+  function whenIdle(callback) {
+    if (!playing) {
+      callback();
+      return;
+    }
+    idleCallbacks.push(callback);
+  }
+
+  window.TenangAnnouncer = { enqueue: enqueue, whenIdle: whenIdle };
+})();
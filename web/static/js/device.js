@@ -0,0 +1,122 @@
+// Makes a paired kiosk or display report in. A browser that was never paired at
+// /device/pair keeps working as before and sends nothing.
+//
+// Heartbeats carry how long the browser tab has been running (display boards reload
+// after every call, so page age means little) and the last realtime sequence it saw,
+// so admins can spot frozen screens. Remote actions (reload, identify,
+// navigate) arrive on the device's own topic. A display or kiosk assigned to a
+// profile is sent back to it when it shows something else.
+(function () {
+  const tokenKey = "tenang-device-token";
+  const startedKey = "tenang-device-started";
+  const clientVersion = "1";
+  const defaultInterval = 30;
+
+  function start(options) {
+    const token = localStorage.getItem(tokenKey);
+    if (!token) {
+      return;
+    }
+    options = options || {};
+    let device = null;
+    let commands = null;
+    if (!sessionStorage.getItem(startedKey)) {
+      sessionStorage.setItem(startedKey, String(Date.now()));
+    }
+    const started = parseInt(sessionStorage.getItem(startedKey), 10);
+
+    // Waits for a running announcement on display boards before leaving the page
+    function whenIdle(fn) {
+      if (window.TenangAnnouncer) {
+        window.TenangAnnouncer.whenIdle(fn);
+      } else {
+        fn();
+      }
+    }
+
+    function lastSeq() {
+      const source = options.realtime || commands;
+      return source ? source.lastSeq() : 0;
+    }
+
+    async function heartbeat() {
+      let interval = defaultInterval;
+      try {
+        const response = await fetch("/device/api/heartbeat", {
+          method: "POST",
+          headers: { "Content-Type": "application/json", Authorization: "Bearer " + token },
+          body: JSON.stringify({
+            version: clientVersion,
+            uptime_seconds: Math.max(0, Math.floor((Date.now() - started) / 1000)),
+            last_event_seq: lastSeq(),
+            url: (window.location.pathname + window.location.search).slice(0, 255),
+          }),
+        });
+        if (response.status === 401) {
+          // The device was deleted or given a new pairing code
+          localStorage.removeItem(tokenKey);
+          window.location.href = "/device/pair";
+          return;
+        }
+        if (response.ok) {
+          device = await response.json();
+          interval = device.heartbeat_interval || defaultInterval;
+          if (!commands) {
+            commands = TenangRealtime.connect(["device:" + device.id], handle, { deviceToken: token });
+          }
+          const profiled = device.home_url.startsWith("/display/p/") || device.home_url.startsWith("/kiosk/p/");
+          if (profiled && window.location.pathname !== device.home_url) {
+            whenIdle(() => (window.location.href = device.home_url));
+            return;
+          }
+        }
+      } catch (error) {
+        console.warn("Device heartbeat failed:", error.message);
+      }
+      setTimeout(heartbeat, interval * 1000);
+    }
+
+    function handle(message) {
+      if (message.type !== "device_command") {
+        return;
+      }
+      const command = message.payload;
+      if (command.action === "reload") {
+        whenIdle(() => window.location.reload());
+      } else if (command.action === "navigate" && command.url) {
+        whenIdle(() => (window.location.href = command.url));
+      } else if (command.action === "identify") {
+        identify();
+      }
+    }
+
+    // identify flashes the device's name over the whole screen for ten seconds
+    function identify() {
+      const overlay = document.createElement("div");
+      overlay.textContent = device ? device.name : "";
+      overlay.style.cssText =
+        "position:fixed;inset:0;z-index:9999;display:flex;align-items:center;justify-content:center;" +
+        "font:bold 8vw sans-serif;color:#fff;background:#dc2626;text-align:center;padding:2rem";
+      document.body.appendChild(overlay);
+
+      let visible = true;
+      const flash = setInterval(() => {
+        visible = !visible;
+        overlay.style.background = visible ? "#dc2626" : "#1d4ed8";
+      }, 400);
+      setTimeout(() => {
+        clearInterval(flash);
+        overlay.remove();
+      }, 10000);
+    }
+
+    heartbeat();
+  }
+
+  // pair stores the token handed out for a pairing code
+  function pair(token) {
+    localStorage.setItem(tokenKey, token);
+  }
+
+  window.TenangDevice = { start: start, pair: pair };
+})();
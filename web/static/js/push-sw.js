@@ -0,0 +1,40 @@
+// Service worker for ticket Web Push notifications
+self.addEventListener("push", function (event) {
+  let data = {};
+  if (event.data) {
+    try {
+      data = event.data.json();
+    } catch (e) {
+      data = { title: "Tenang Antri", body: event.data.text() };
+    }
+  }
+
+  const title = data.title || "Tenang Antri";
+  event.waitUntil(
+    self.registration.showNotification(title, {
+      body: data.body || "",
+      tag: data.tag || undefined,
+      renotify: true,
+      requireInteraction: true,
+      vibrate: [200, 100, 200],
+      data: { url: data.url || "/track/" },
+    }),
+  );
+});
+
+self.addEventListener("notificationclick", function (event) {
+  event.notification.close();
+  const url = (event.notification.data && event.notification.data.url) || "/track/";
+
+  event.waitUntil(
+    clients.matchAll({ type: "window", includeUncontrolled: true }).then(function (list) {
+      for (const client of list) {
+        if (client.url.includes("/track") && "focus" in client) {
+          client.navigate(url);
+          return client.focus();
+        }
+      }
+      return clients.openWindow(url);
+    }),
+  );
+});
@@ -0,0 +1,151 @@
+// Realtime connection to the hub. Pages pass the topics they show and get
+// only the messages routed to those topics. Reconnects with backoff, re-joins
+// every topic after a drop and replays what was missed meanwhile. When the
+// server can no longer replay the gap it sends "resync" and options.onResync
+// should reload whatever the page shows. A paired device passes its token as
+// options.deviceToken to join its own device topic.
+//
+// When WebSocket upgrades keep failing (some proxies block them) it switches
+// to the /events Server-Sent Events stream, which carries the same messages.
+(function () {
+  function connect(topics, onMessage, options) {
+    options = options || {};
+    const joined = new Set(topics);
+    const scheme = window.location.protocol === "https:" ? "wss://" : "ws://";
+    let socket = null;
+    let retryDelay = 1000;
+    let connectedBefore = false;
+    // Where the stream was when the connection dropped
+    let instance = null;
+    let lastSeq = 0;
+    // WebSocket attempts that closed before opening; two in a row means SSE
+    let failedOpens = 0;
+    let stream = null;
+
+    function send(action, list) {
+      if (stream) {
+        // An event stream cannot take requests; reopen it with the new topics
+        stream.close();
+        openStream();
+        return;
+      }
+      if (socket && socket.readyState === WebSocket.OPEN) {
+        socket.send(JSON.stringify({ action: action, topics: list }));
+      }
+    }
+
+    function handle(raw) {
+      // The server may batch several messages into one frame, one per line
+      raw.split("\n").forEach(function (line) {
+        if (!line) {
+          return;
+        }
+        const message = JSON.parse(line);
+        if (message.type === "subscribed") {
+          return;
+        }
+        if (message.type === "hello") {
+          instance = message.payload.instance;
+          lastSeq = message.payload.seq;
+          return;
+        }
+        if (message.type === "resync") {
+          if (options.onResync) {
+            options.onResync();
+          }
+          return;
+        }
+        if (message.seq) {
+          lastSeq = message.seq;
+        }
+        if (message.type === "error") {
+          console.warn("realtime:", message.payload.message);
+          return;
+        }
+        onMessage(message);
+      });
+    }
+
+    function query() {
+      let query = "topics=" + encodeURIComponent(Array.from(joined).join(","));
+      if (instance) {
+        query += "&instance=" + encodeURIComponent(instance) + "&since=" + lastSeq;
+      }
+      if (options.deviceToken) {
+        query += "&device_token=" + encodeURIComponent(options.deviceToken);
+      }
+      return query;
+    }
+
+    function open() {
+      if (!("WebSocket" in window) || failedOpens >= 2) {
+        openStream();
+        return;
+      }
+
+      let opened = false;
+      socket = new WebSocket(scheme + window.location.host + "/ws?" + query());
+
+      socket.onopen = function () {
+        opened = true;
+        failedOpens = 0;
+        retryDelay = 1000;
+        if (connectedBefore && options.onReconnect) {
+          options.onReconnect();
+        }
+        connectedBefore = true;
+      };
+      socket.onmessage = function (event) {
+        handle(event.data);
+      };
+      socket.onclose = function () {
+        if (!opened) {
+          failedOpens++;
+        }
+        setTimeout(open, retryDelay);
+        retryDelay = Math.min(retryDelay * 2, 30000);
+      };
+    }
+
+    // The browser reconnects an event stream by itself and resumes it with
+    // Last-Event-ID; only a stream it gave up on is reopened here
+    function openStream() {
+      stream = new EventSource("/events?" + query());
+      stream.onopen = function () {
+        retryDelay = 1000;
+      };
+      stream.onmessage = function (event) {
+        handle(event.data);
+      };
+      stream.onerror = function () {
+        if (stream.readyState === EventSource.CLOSED) {
+          setTimeout(openStream, retryDelay);
+          retryDelay = Math.min(retryDelay * 2, 30000);
+        }
+      };
+    }
+
+    open();
+
+    return {
+      // The sequence of the last message seen, reported in device heartbeats
+      lastSeq: function () {
+        return lastSeq;
+      },
+      subscribe: function (list) {
+        list.forEach(function (topic) {
+          joined.add(topic);
+        });
+        send("subscribe", list);
+      },
+      unsubscribe: function (list) {
+        list.forEach(function (topic) {
+          joined.delete(topic);
+        });
+        send("unsubscribe", list);
+      },
+    };
+  }
+
+  window.TenangRealtime = { connect: connect };
+})();
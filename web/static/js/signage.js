@@ -0,0 +1,102 @@
+// Loops the signage playlist scheduled for a display profile in the page's media
+// zone. The playlist is fetched again after every loop, so schedule and playlist
+// changes show up without a reload. The position survives page reloads, so a board
+// that reloads after each call carries on where it was instead of starting over.
+//
+// Videos play muted so they never talk over call announcements; interrupt() pauses
+// them and covers the zone while a call is announced.
+(function () {
+  const retryDelay = 60 * 1000;
+
+  function start(slug, zone, options) {
+    options = options || {};
+    const stage = zone.querySelector("[data-signage-stage]");
+    const storageKey = "signage:" + slug;
+    let items = [];
+    let index = parseInt(sessionStorage.getItem(storageKey) || "0", 10);
+    let timer = null;
+    let video = null;
+
+    function setActive(active) {
+      zone.classList.toggle("hidden", !active);
+      if (options.onActive) {
+        options.onActive(active);
+      }
+    }
+
+    async function load() {
+      try {
+        const response = await fetch("/display/p/" + encodeURIComponent(slug) + "/playlist", { cache: "no-store" });
+        const playback = await response.json();
+        items = response.ok ? playback.items || [] : [];
+      } catch (error) {
+        console.warn("Signage playlist not loaded:", error.message);
+      }
+
+      if (items.length === 0) {
+        setActive(false);
+        timer = setTimeout(load, retryDelay);
+        return;
+      }
+      setActive(true);
+      show();
+    }
+
+    function next() {
+      clearTimeout(timer);
+      index++;
+      if (index >= items.length) {
+        index = 0;
+        sessionStorage.setItem(storageKey, "0");
+        load();
+        return;
+      }
+      show();
+    }
+
+    function show() {
+      if (index >= items.length) {
+        index = 0;
+      }
+      sessionStorage.setItem(storageKey, String(index));
+      const item = items[index];
+
+      let element;
+      video = null;
+      if (item.type === "video") {
+        element = document.createElement("video");
+        element.muted = true;
+        element.autoplay = true;
+        element.playsInline = true;
+        element.onended = next;
+        element.onerror = next;
+        video = element;
+      } else {
+        element = document.createElement("img");
+        element.onerror = next;
+      }
+      element.src = item.url;
+      element.className = "w-full h-full object-contain";
+      stage.replaceChildren(element);
+
+      if (item.duration > 0) {
+        timer = setTimeout(next, item.duration * 1000);
+      }
+    }
+
+    // interrupt pauses the playlist and shows the call in the media zone
+    function interrupt(cover) {
+      if (video) {
+        video.pause();
+      }
+      if (cover) {
+        cover.classList.remove("hidden");
+      }
+    }
+
+    load();
+    return { interrupt: interrupt };
+  }
+
+  window.TenangSignage = { start: start };
+})();
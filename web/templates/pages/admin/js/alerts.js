@@ -0,0 +1,102 @@
+function openModal(id) {
+    document.getElementById(id).classList.remove('hidden');
+    document.getElementById(id).classList.add('flex');
+}
+
+function closeModal(id) {
+    document.getElementById(id).classList.add('hidden');
+    document.getElementById(id).classList.remove('flex');
+}
+
+// The threshold is a ticket count or minutes, and unused when no counter is open
+function updateThresholdField() {
+    const kind = document.getElementById('ruleKind').value;
+    document.getElementById('ruleThresholdField').classList.toggle('hidden', kind === 'no_open_counter');
+    document.getElementById('ruleThresholdLabel').textContent = kind === 'oldest_wait' ? 'Batas (menit)' : 'Batas (tiket)';
+}
+
+function openCreateRule() {
+    const form = document.getElementById('ruleForm');
+    form.reset();
+    document.getElementById('ruleId').value = '';
+    document.getElementById('ruleModalTitle').textContent = 'Tambah Aturan';
+    updateThresholdField();
+    openModal('ruleModal');
+}
+
+async function editRule(id) {
+    try {
+        const response = await fetch(`/admin/api/alert-rules/${id}`);
+        if (!response.ok) {
+            alert('Gagal memuat data aturan');
+            return;
+        }
+        const rule = await response.json();
+
+        document.getElementById('ruleId').value = rule.id;
+        document.getElementById('ruleName').value = rule.name || '';
+        document.getElementById('ruleKind').value = rule.kind;
+        document.getElementById('ruleCategory').value = rule.category_id.Valid ? rule.category_id.Int64 : 0;
+        document.getElementById('ruleThreshold').value = rule.threshold || 1;
+        document.getElementById('ruleCooldown').value = rule.cooldown_minutes;
+        document.getElementById('ruleRecipients').value = (rule.email_recipients || []).join('\n');
+        document.getElementById('ruleWebhook').checked = rule.notify_webhook;
+        document.getElementById('ruleActive').checked = rule.is_active;
+        document.getElementById('ruleModalTitle').textContent = 'Edit Aturan';
+        updateThresholdField();
+
+        openModal('ruleModal');
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+async function saveRule(event) {
+    event.preventDefault();
+    const form = event.target;
+    const id = form.id.value;
+
+    const data = {
+        name: form.name.value,
+        kind: form.kind.value,
+        category_id: parseInt(form.category_id.value) || 0,
+        threshold: parseInt(form.threshold.value) || 0,
+        cooldown_minutes: parseInt(form.cooldown_minutes.value) || 0,
+        email_recipients: form.email_recipients.value.split(/[\n,;]/).map(r => r.trim()).filter(r => r),
+        notify_webhook: form.notify_webhook.checked,
+        is_active: form.is_active.checked
+    };
+
+    try {
+        const response = await fetch(id ? `/admin/api/alert-rules/${id}` : '/admin/api/alert-rules', {
+            method: id ? 'PUT' : 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(data)
+        });
+
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            const error = await response.json();
+            alert(error.error || 'Gagal menyimpan aturan');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+    return false;
+}
+
+async function deleteRule(id) {
+    if (!confirm('Apakah Anda yakin ingin menghapus aturan ini? Riwayat peringatannya tetap disimpan.')) return;
+
+    try {
+        const response = await fetch(`/admin/api/alert-rules/${id}`, { method: 'DELETE' });
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            alert('Gagal menghapus aturan');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
@@ -157,7 +157,9 @@ async function addCategory(event) {
   
   const data = {
     ...formData,
-    priority: parseInt(formData.priority) || 0
+    priority: parseInt(formData.priority) || 0,
+    sla_wait_minutes: parseInt(formData.sla_wait_minutes) || 0,
+    sla_service_minutes: parseInt(formData.sla_service_minutes) || 0
   };
 
   console.log('Category data being sent:', data);
@@ -220,6 +222,8 @@ async function loadCategoryForEdit(id) {
       document.getElementById("editName").value = category.name || "";
       document.getElementById("editPrefix").value = category.prefix || "";
       document.getElementById("editPriority").value = category.priority || 0;
+      document.getElementById("editSLAWait").value = category.sla_wait_minutes || 0;
+      document.getElementById("editSLAService").value = category.sla_service_minutes || 0;
       document.getElementById("editColorCode").value =
         category.color_code || "#3B82F6";
       document.getElementById("editDescription").value =
@@ -250,7 +254,9 @@ async function updateCategory(event) {
 
   const data = {
     ...formData,
-    priority: parseInt(formData.priority) || 0
+    priority: parseInt(formData.priority) || 0,
+    sla_wait_minutes: parseInt(formData.sla_wait_minutes) || 0,
+    sla_service_minutes: parseInt(formData.sla_service_minutes) || 0
   };
 
   console.log('Category update data being sent:', data);
@@ -1,20 +1,92 @@
-const ws = new WebSocket("ws://" + window.location.host + "/ws");
-
-ws.onmessage = function (event) {
-  const data = JSON.parse(event.data);
-
+TenangRealtime.connect(["admin:all"], function (data) {
   if (data.type === "stats_update") {
     updateStatsDisplay(data.payload);
   } else if (data.type === "ticket_update") {
+    refreshSLA();
     showNotification(
       "Ticket " + data.payload.ticket_number + " - " + data.payload.status,
     );
   } else if (data.type === "counter_update") {
     showNotification(
-      "Counter " + data.payload.name + " - " + data.payload.status,
+      "Counter " + (data.payload.name || data.payload.number) + " - " + data.payload.status,
+    );
+  } else if (data.type === "device_alert") {
+    // A silent device stays on screen until someone dismisses it
+    const offline = data.payload.status === "offline";
+    showNotification(
+      "Perangkat " + data.payload.name + " - " + data.payload.status,
+      offline,
+    );
+  } else if (data.type === "sla_breach") {
+    // A breach stays on screen until someone dismisses it
+    showNotification(
+      "SLA terlampaui: tiket " + data.payload.ticket_number + " " +
+        slaKindLabel(data.payload.kind) + " " + formatSeconds(data.payload.elapsed_seconds) +
+        " (target " + formatSeconds(data.payload.target_seconds) + ")",
+      true,
     );
+    refreshSLA();
+  } else if (data.type === "queue_alert") {
+    // An alert stays on screen until someone dismisses it
+    showNotification("Peringatan antrian: " + data.payload.message, true);
+  }
+});
+
+// SLA tickets are re-read while they age, not only when the queue changes
+const SLA_REFRESH_MS = 15000;
+
+function slaKindLabel(kind) {
+  return kind === "wait" ? "menunggu" : "dilayani";
+}
+
+// formatSeconds formats a duration in seconds as hours and minutes, or minutes and seconds
+function formatSeconds(seconds) {
+  const total = Math.round(seconds || 0);
+  if (total >= 3600) {
+    return Math.floor(total / 3600) + "j " + Math.floor((total % 3600) / 60) + "m";
   }
-};
+  return Math.floor(total / 60) + "m " + (total % 60) + "s";
+}
+
+function escapeHtml(text) {
+  const div = document.createElement("div");
+  div.textContent = text || "";
+  return div.innerHTML;
+}
+
+function refreshSLA() {
+  fetch("/admin/api/sla/live")
+    .then((response) => response.json())
+    .then((data) => renderSLA(data.tickets || []))
+    .catch((error) => console.error("Failed to refresh SLA:", error));
+}
+
+function renderSLA(tickets) {
+  const body = document.getElementById("slaTableBody");
+  if (!body) return;
+
+  if (tickets.length === 0) {
+    body.innerHTML =
+      '<tr><td colspan="6" class="px-4 py-6 text-center text-gray-500">Semua tiket dalam target</td></tr>';
+    return;
+  }
+  body.innerHTML = tickets
+    .map((ticket) => {
+      const breached = ticket.level === "breached";
+      const badge = breached
+        ? '<span class="px-2 py-0.5 rounded-full text-xs bg-red-100 text-red-800">Terlampaui</span>'
+        : '<span class="px-2 py-0.5 rounded-full text-xs bg-yellow-100 text-yellow-800">Mendekati</span>';
+      return `<tr class="${breached ? "bg-red-50" : ""}">
+        <td class="px-4 py-2 font-medium">${escapeHtml(ticket.ticket_number)}</td>
+        <td class="px-4 py-2">${escapeHtml(ticket.category_name)}</td>
+        <td class="px-4 py-2">${escapeHtml(ticket.counter_number) || "-"}</td>
+        <td class="px-4 py-2">${slaKindLabel(ticket.kind)} ${formatSeconds(ticket.target_seconds)}</td>
+        <td class="px-4 py-2">${formatSeconds(ticket.elapsed_seconds)}</td>
+        <td class="px-4 py-2">${badge}</td>
+      </tr>`;
+    })
+    .join("");
+}
 
 function updateStatsDisplay(stats) {
   updateTodayStats(stats);
@@ -31,15 +103,15 @@ function updateTodayStats(stats) {
     avgServiceTime: document.querySelector("[data-avg-service-time]"),
   };
 
-  animateValue(elements.totalTickets, stats.TotalTicketsToday);
-  animateValue(elements.currentlyServing, stats.CurrentlyServing);
-  animateValue(elements.waitingTickets, stats.WaitingTickets);
-  animateValue(elements.activeCounters, stats.ActiveCounters);
+  animateValue(elements.totalTickets, stats.total_tickets_today);
+  animateValue(elements.currentlyServing, stats.currently_serving);
+  animateValue(elements.waitingTickets, stats.waiting_tickets);
+  animateValue(elements.activeCounters, stats.active_counters);
 }
 
 function updateOverallStats(stats) {
-  updateQueueByCategory(stats.QueueLengthByCategory);
-  updateHourlyDistribution(stats.HourlyDistribution);
+  updateQueueByCategory(stats.queue_length_by_category);
+  updateHourlyDistribution(stats.hourly_distribution);
 }
 
 function updateQueueByCategory(queueData) {
@@ -71,13 +143,19 @@ function animateValue(element, newValue) {
   requestAnimationFrame(update);
 }
 
-function showNotification(message) {
+function showNotification(message, sticky) {
   const toast = document.createElement("div");
   toast.className =
-    "fixed bottom-4 right-4 bg-blue-600 text-white px-6 py-3 rounded-lg shadow-lg z-50 animate-pulse";
+    "fixed bottom-4 right-4 text-white px-6 py-3 rounded-lg shadow-lg z-50 " +
+    (sticky ? "bg-red-600 cursor-pointer" : "bg-blue-600 animate-pulse");
   toast.textContent = message;
   document.body.appendChild(toast);
 
+  if (sticky) {
+    toast.title = "Klik untuk menutup";
+    toast.addEventListener("click", () => toast.remove());
+    return;
+  }
   setTimeout(() => {
     toast.remove();
   }, 3000);
@@ -91,6 +169,9 @@ function refreshStats() {
 }
 
 document.addEventListener("DOMContentLoaded", function () {
+  refreshSLA();
+  setInterval(refreshSLA, SLA_REFRESH_MS);
+
   const elements = document.querySelectorAll('[class*="text-3xl"]');
   elements.forEach((el) => {
     if (
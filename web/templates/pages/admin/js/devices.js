@@ -0,0 +1,150 @@
+document.querySelectorAll('[data-uptime]').forEach(el => {
+    el.textContent = formatUptime(parseInt(el.dataset.uptime));
+});
+
+// Statuses change with every alert; refresh regularly so last-seen times stay current,
+// but not while a form is open
+function refresh() {
+    if (document.getElementById('deviceModal').classList.contains('hidden')) {
+        window.location.reload();
+    }
+}
+TenangRealtime.connect(['admin:all'], function (data) {
+    if (data.type === 'device_alert') {
+        refresh();
+    }
+});
+setInterval(refresh, 60000);
+
+function formatUptime(seconds) {
+    if (seconds < 60) return seconds + ' dtk';
+    const minutes = Math.floor(seconds / 60);
+    if (minutes < 60) return minutes + ' mnt';
+    const hours = Math.floor(minutes / 60);
+    if (hours < 24) return hours + ' j ' + (minutes % 60) + ' mnt';
+    return Math.floor(hours / 24) + ' h ' + (hours % 24) + ' j';
+}
+
+function openModal(id) {
+    document.getElementById(id).classList.remove('hidden');
+    document.getElementById(id).classList.add('flex');
+}
+
+function closeModal(id) {
+    document.getElementById(id).classList.add('hidden');
+    document.getElementById(id).classList.remove('flex');
+}
+
+function toggleProfile() {
+    const isDisplay = document.getElementById('deviceKind').value === 'display';
+    document.getElementById('deviceProfileField').classList.toggle('hidden', !isDisplay);
+    document.getElementById('deviceKioskProfileField').classList.toggle('hidden', isDisplay);
+}
+
+function openCreateDevice() {
+    document.getElementById('deviceForm').reset();
+    document.getElementById('deviceId').value = '';
+    document.getElementById('deviceModalTitle').textContent = 'Tambah Perangkat';
+    toggleProfile();
+    openModal('deviceModal');
+}
+
+async function editDevice(id) {
+    try {
+        const response = await fetch(`/admin/api/devices/${id}`);
+        if (!response.ok) {
+            alert('Gagal memuat data perangkat');
+            return;
+        }
+        const device = await response.json();
+
+        document.getElementById('deviceId').value = device.id;
+        document.getElementById('deviceName').value = device.name;
+        document.getElementById('deviceKind').value = device.kind;
+        document.getElementById('deviceProfile').value = device.display_profile_id.Valid ? device.display_profile_id.Int64 : '';
+        document.getElementById('deviceKioskProfile').value = device.kiosk_profile_id.Valid ? device.kiosk_profile_id.Int64 : '';
+        document.getElementById('deviceModalTitle').textContent = 'Edit Perangkat';
+        toggleProfile();
+
+        openModal('deviceModal');
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+async function saveDevice(event) {
+    event.preventDefault();
+    const form = event.target;
+    const id = form.id.value;
+
+    const data = {
+        name: form.name.value,
+        kind: form.kind.value,
+        display_profile_id: form.display_profile_id.value ? parseInt(form.display_profile_id.value) : null,
+        kiosk_profile_id: form.kiosk_profile_id.value ? parseInt(form.kiosk_profile_id.value) : null
+    };
+
+    try {
+        const response = await fetch(id ? `/admin/api/devices/${id}` : '/admin/api/devices', {
+            method: id ? 'PUT' : 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(data)
+        });
+
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            const error = await response.json();
+            alert(error.error || 'Gagal menyimpan perangkat');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+    return false;
+}
+
+async function newPairingCode(id) {
+    if (!confirm('Perangkat akan terputus dan harus dipasangkan ulang dengan kode baru. Lanjutkan?')) return;
+
+    try {
+        const response = await fetch(`/admin/api/devices/${id}/pairing-code`, { method: 'POST' });
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            alert('Gagal membuat kode pemasangan');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+async function sendCommand(id, action) {
+    try {
+        const response = await fetch(`/admin/api/devices/${id}/commands`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ action: action })
+        });
+        if (!response.ok) {
+            const error = await response.json();
+            alert(error.error || 'Gagal mengirim perintah');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+async function deleteDevice(id) {
+    if (!confirm('Apakah Anda yakin ingin menghapus perangkat ini?')) return;
+
+    try {
+        const response = await fetch(`/admin/api/devices/${id}`, { method: 'DELETE' });
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            alert('Gagal menghapus perangkat');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
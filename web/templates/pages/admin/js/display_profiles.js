@@ -0,0 +1,111 @@
+function openModal(id) {
+    document.getElementById(id).classList.remove('hidden');
+    document.getElementById(id).classList.add('flex');
+}
+
+function closeModal(id) {
+    document.getElementById(id).classList.add('hidden');
+    document.getElementById(id).classList.remove('flex');
+}
+
+function setChecked(name, ids) {
+    document.querySelectorAll(`#profileForm input[name="${name}"]`).forEach(cb => {
+        cb.checked = ids.includes(parseInt(cb.value));
+    });
+}
+
+function checkedIds(form, name) {
+    return Array.from(form.querySelectorAll(`input[name="${name}"]:checked`)).map(cb => parseInt(cb.value));
+}
+
+function openCreateProfile() {
+    const form = document.getElementById('profileForm');
+    form.reset();
+    document.getElementById('profileId').value = '';
+    document.getElementById('profileModalTitle').textContent = 'Tambah Profil';
+    setChecked('category_ids', []);
+    setChecked('counter_ids', []);
+    openModal('profileModal');
+}
+
+async function editProfile(id) {
+    try {
+        const response = await fetch(`/admin/api/display-profiles/${id}`);
+        if (!response.ok) {
+            alert('Gagal memuat data profil');
+            return;
+        }
+        const profile = await response.json();
+
+        document.getElementById('profileId').value = profile.id;
+        document.getElementById('profileName').value = profile.name || '';
+        document.getElementById('profileSlug').value = profile.slug || '';
+        document.getElementById('profileLayout').value = profile.layout;
+        document.getElementById('profileRecentCount').value = profile.recent_count;
+        document.getElementById('profileAccentColor').value = profile.accent_color.toLowerCase();
+        document.getElementById('profileBackgroundColor').value = profile.background_color.toLowerCase();
+        document.getElementById('profileTickerText').value = profile.ticker_text || '';
+        document.getElementById('profileShowWaiting').checked = profile.show_waiting;
+        document.getElementById('profileActive').checked = profile.is_active;
+        document.getElementById('profileModalTitle').textContent = 'Edit Profil';
+        setChecked('category_ids', profile.category_ids || []);
+        setChecked('counter_ids', profile.counter_ids || []);
+
+        openModal('profileModal');
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+async function saveProfile(event) {
+    event.preventDefault();
+    const form = event.target;
+    const id = form.id.value;
+
+    const data = {
+        name: form.name.value,
+        slug: form.slug.value,
+        layout: form.layout.value,
+        recent_count: parseInt(form.recent_count.value),
+        accent_color: form.accent_color.value,
+        background_color: form.background_color.value,
+        ticker_text: form.ticker_text.value,
+        category_ids: checkedIds(form, 'category_ids'),
+        counter_ids: checkedIds(form, 'counter_ids'),
+        show_waiting: form.show_waiting.checked,
+        is_active: form.is_active.checked
+    };
+
+    try {
+        const response = await fetch(id ? `/admin/api/display-profiles/${id}` : '/admin/api/display-profiles', {
+            method: id ? 'PUT' : 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(data)
+        });
+
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            const error = await response.json();
+            alert(error.error || 'Gagal menyimpan profil');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+    return false;
+}
+
+async function deleteProfile(id) {
+    if (!confirm('Apakah Anda yakin ingin menghapus profil display ini?')) return;
+
+    try {
+        const response = await fetch(`/admin/api/display-profiles/${id}`, { method: 'DELETE' });
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            alert('Gagal menghapus profil');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
@@ -0,0 +1,148 @@
+function openModal(id) {
+    document.getElementById(id).classList.remove('hidden');
+    document.getElementById(id).classList.add('flex');
+}
+
+function closeModal(id) {
+    document.getElementById(id).classList.add('hidden');
+    document.getElementById(id).classList.remove('flex');
+}
+
+function addProfileCategory(category) {
+    const template = document.getElementById('profileCategoryTemplate');
+    const row = template.content.firstElementChild.cloneNode(true);
+    document.getElementById('profileCategories').appendChild(row);
+
+    if (category) {
+        row.querySelector('select').value = category.category_id;
+        row.querySelector('input[name="label"]').value = category.label || '';
+        row.querySelector('input[name="icon"]').value = category.icon || '';
+    }
+}
+
+function moveCategory(button, step) {
+    const row = button.closest('[data-category]');
+    const sibling = step < 0 ? row.previousElementSibling : row.nextElementSibling;
+    if (!sibling) return;
+    if (step < 0) {
+        row.parentNode.insertBefore(row, sibling);
+    } else {
+        row.parentNode.insertBefore(sibling, row);
+    }
+}
+
+// Printer settings only apply to profiles that print
+function togglePrinterTarget() {
+    const output = document.getElementById('profileTicketOutput').value;
+    const type = document.getElementById('profilePrinterType').value;
+    document.getElementById('printerFields').classList.toggle('hidden', output === 'qr');
+    document.getElementById('printerTargetField').classList.toggle('hidden', type === 'browser');
+
+    const target = document.getElementById('profilePrinterTarget');
+    if (type === 'network') {
+        document.getElementById('printerTargetLabel').textContent = 'Alamat Printer';
+        document.getElementById('printerTargetHint').textContent = 'Host atau host:port, port bawaan 9100.';
+        target.placeholder = '192.168.1.50:9100';
+    } else {
+        document.getElementById('printerTargetLabel').textContent = 'Nama File Spool';
+        document.getElementById('printerTargetHint').textContent = 'Dibuat di direktori spool server.';
+        target.placeholder = 'kiosk-lantai-1.prn';
+    }
+}
+
+function openCreateProfile() {
+    document.getElementById('profileForm').reset();
+    document.getElementById('profileId').value = '';
+    document.getElementById('profileCategories').innerHTML = '';
+    document.getElementById('profileModalTitle').textContent = 'Tambah Profil';
+    togglePrinterTarget();
+    openModal('profileModal');
+}
+
+async function editProfile(id) {
+    try {
+        const response = await fetch(`/admin/api/kiosk-profiles/${id}`);
+        if (!response.ok) {
+            alert('Gagal memuat data profil');
+            return;
+        }
+        const profile = await response.json();
+
+        document.getElementById('profileId').value = profile.id;
+        document.getElementById('profileName').value = profile.name || '';
+        document.getElementById('profileSlug').value = profile.slug || '';
+        document.getElementById('profileLanguage').value = profile.language;
+        document.getElementById('profileTicketOutput').value = profile.ticket_output;
+        document.getElementById('profilePrinterType').value = profile.printer_type || 'browser';
+        document.getElementById('profilePrinterTarget').value = profile.printer_target || '';
+        togglePrinterTarget();
+        document.getElementById('profileIdleSeconds').value = profile.idle_seconds;
+        document.getElementById('profileAttractTitle').value = profile.attract_title || '';
+        document.getElementById('profileAttractText').value = profile.attract_text || '';
+        document.getElementById('profileActive').checked = profile.is_active;
+        document.getElementById('profileModalTitle').textContent = 'Edit Profil';
+        document.getElementById('profileCategories').innerHTML = '';
+        (profile.categories || []).forEach(addProfileCategory);
+
+        openModal('profileModal');
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+async function saveProfile(event) {
+    event.preventDefault();
+    const form = event.target;
+    const id = form.id.value;
+
+    const data = {
+        name: form.name.value,
+        slug: form.slug.value,
+        language: form.language.value,
+        ticket_output: form.ticket_output.value,
+        printer_type: form.printer_type.value,
+        printer_target: form.printer_target.value,
+        idle_seconds: parseInt(form.idle_seconds.value) || 0,
+        attract_title: form.attract_title.value,
+        attract_text: form.attract_text.value,
+        categories: Array.from(document.querySelectorAll('#profileCategories [data-category]')).map(row => ({
+            category_id: parseInt(row.querySelector('select').value),
+            label: row.querySelector('input[name="label"]').value,
+            icon: row.querySelector('input[name="icon"]').value
+        })),
+        is_active: form.is_active.checked
+    };
+
+    try {
+        const response = await fetch(id ? `/admin/api/kiosk-profiles/${id}` : '/admin/api/kiosk-profiles', {
+            method: id ? 'PUT' : 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(data)
+        });
+
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            const error = await response.json();
+            alert(error.error || 'Gagal menyimpan profil');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+    return false;
+}
+
+async function deleteProfile(id) {
+    if (!confirm('Apakah Anda yakin ingin menghapus profil kiosk ini?')) return;
+
+    try {
+        const response = await fetch(`/admin/api/kiosk-profiles/${id}`, { method: 'DELETE' });
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            alert('Gagal menghapus profil');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
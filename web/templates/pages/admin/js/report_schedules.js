@@ -0,0 +1,128 @@
+function openModal(id) {
+    document.getElementById(id).classList.remove('hidden');
+    document.getElementById(id).classList.add('flex');
+}
+
+function closeModal(id) {
+    document.getElementById(id).classList.add('hidden');
+    document.getElementById(id).classList.remove('flex');
+}
+
+function openCreateSchedule() {
+    const form = document.getElementById('scheduleForm');
+    form.reset();
+    document.getElementById('scheduleId').value = '';
+    document.getElementById('scheduleModalTitle').textContent = 'Tambah Jadwal';
+    openModal('scheduleModal');
+}
+
+async function editSchedule(id) {
+    try {
+        const response = await fetch(`/admin/api/report-schedules/${id}`);
+        if (!response.ok) {
+            alert('Gagal memuat data jadwal');
+            return;
+        }
+        const schedule = await response.json();
+
+        document.getElementById('scheduleId').value = schedule.id;
+        document.getElementById('scheduleName').value = schedule.name || '';
+        document.getElementById('scheduleReportType').value = schedule.report_type;
+        document.getElementById('scheduleFormat').value = schedule.format;
+        document.getElementById('scheduleCategory').value = schedule.category_id.Valid ? schedule.category_id.Int64 : 0;
+        document.getElementById('scheduleCounter').value = schedule.counter_id.Valid ? schedule.counter_id.Int64 : 0;
+        document.getElementById('scheduleStatus').value = schedule.status || '';
+        document.getElementById('scheduleCron').value = schedule.cron || '';
+        document.getElementById('scheduleRecipients').value = (schedule.recipients || []).join('\n');
+        document.getElementById('scheduleActive').checked = schedule.is_active;
+        document.getElementById('scheduleModalTitle').textContent = 'Edit Jadwal';
+
+        openModal('scheduleModal');
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+async function saveSchedule(event) {
+    event.preventDefault();
+    const form = event.target;
+    const id = form.id.value;
+
+    const data = {
+        name: form.name.value,
+        report_type: form.report_type.value,
+        format: form.format.value,
+        category_id: parseInt(form.category_id.value) || 0,
+        counter_id: parseInt(form.counter_id.value) || 0,
+        status: form.status.value,
+        cron: form.cron.value,
+        recipients: form.recipients.value.split(/[\n,;]/).map(r => r.trim()).filter(r => r),
+        is_active: form.is_active.checked
+    };
+
+    if (data.recipients.length === 0) {
+        alert('Isi minimal satu penerima');
+        return false;
+    }
+
+    try {
+        const response = await fetch(id ? `/admin/api/report-schedules/${id}` : '/admin/api/report-schedules', {
+            method: id ? 'PUT' : 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(data)
+        });
+
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            const error = await response.json();
+            alert(error.error || 'Gagal menyimpan jadwal');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+    return false;
+}
+
+async function deleteSchedule(id) {
+    if (!confirm('Apakah Anda yakin ingin menghapus jadwal ini beserta riwayat pengirimannya?')) return;
+
+    try {
+        const response = await fetch(`/admin/api/report-schedules/${id}`, { method: 'DELETE' });
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            alert('Gagal menghapus jadwal');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+async function runSchedule(id) {
+    try {
+        const response = await fetch(`/admin/api/report-schedules/${id}/run`, { method: 'POST' });
+        if (response.ok) {
+            window.location.href = `/admin/report-schedules?schedule_id=${id}`;
+        } else {
+            const error = await response.json();
+            alert(error.error || 'Gagal mengirim laporan');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+async function retryRun(id) {
+    try {
+        const response = await fetch(`/admin/api/report-runs/${id}/retry`, { method: 'POST' });
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            const error = await response.json();
+            alert(error.error || 'Gagal mengirim ulang');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
@@ -60,24 +60,28 @@ function showTab(tabName) {
 function exportCSV() {
     const dateFrom = document.getElementById('dateFrom').value;
     const dateTo = document.getElementById('dateTo').value;
-    const reportType = document.getElementById('reportType').value;
     
-    window.open(`/admin/api/export/tickets?date_from=${dateFrom}&date_to=${dateTo}&type=${reportType}`, '_blank');
+    window.open(`/admin/api/export/tickets?date_from=${dateFrom}&date_to=${dateTo}`, '_blank');
+}
+
+function exportXLSX() {
+    const dateFrom = document.getElementById('dateFrom').value;
+    const dateTo = document.getElementById('dateTo').value;
+    
+    window.open(`/admin/api/export/xlsx?date_from=${dateFrom}&date_to=${dateTo}`, '_blank');
 }
 
 function exportPDF() {
     const dateFrom = document.getElementById('dateFrom').value;
     const dateTo = document.getElementById('dateTo').value;
-    const reportType = document.getElementById('reportType').value;
     
-    window.open(`/admin/api/export/tickets/pdf?date_from=${dateFrom}&date_to=${dateTo}&type=${reportType}`, '_blank');
+    window.open(`/admin/api/export/pdf?date_from=${dateFrom}&date_to=${dateTo}`, '_blank');
 }
 
 // Load report data
 function loadReportData() {
     const dateFrom = document.getElementById('dateFrom').value;
     const dateTo = document.getElementById('dateTo').value;
-    const reportType = document.getElementById('reportType').value;
     
     if (!dateFrom || !dateTo) {
         document.getElementById('noDataState').classList.remove('hidden');
@@ -89,7 +93,7 @@ function loadReportData() {
     document.getElementById('reportContent').classList.remove('hidden');
     document.getElementById('loadingState').classList.remove('hidden');
     
-    fetch(`/admin/api/reports/data?date_from=${dateFrom}&date_to=${dateTo}&type=${reportType}`)
+    fetch(`/admin/api/reports/data?date_from=${dateFrom}&date_to=${dateTo}`)
         .then(response => response.json())
         .then(data => {
             document.getElementById('loadingState').classList.add('hidden');
@@ -100,9 +104,10 @@ function loadReportData() {
                 return;
             }
             
-            updateReportStats(data.summary);
-            updateCharts(data.summary);
-            updateTables(data.details);
+            updateReportStats(data.summary, data.hourly);
+            updateHourlyChart(data.hourly);
+            updateCategoryChart(data.categories);
+            updateTables(data);
         })
         .catch(error => {
             console.error('Gagal memuat data laporan:', error);
@@ -112,93 +117,128 @@ function loadReportData() {
         });
 }
 
-function updateReportStats(summary) {
-    document.getElementById('totalTickets').textContent = summary.total_tickets || 0;
-    document.getElementById('completedTickets').textContent = summary.completed_tickets || 0;
-    document.getElementById('noShowTickets').textContent = summary.no_show_tickets || 0;
-    document.getElementById('cancelledTickets').textContent = summary.cancelled_tickets || 0;
-    document.getElementById('avgWaitTime').textContent = summary.avg_wait_time || 0;
-    document.getElementById('avgServiceTime').textContent = summary.avg_service_time || 0;
-    document.getElementById('peakHour').textContent = summary.peak_hour || '--';
-    document.getElementById('serviceRate').textContent = summary.service_rate || 0;
+// formatSeconds formats a duration in seconds as minutes and seconds
+function formatSeconds(seconds) {
+    const total = Math.round(seconds || 0);
+    return Math.floor(total / 60) + 'm ' + (total % 60) + 's';
+}
+
+function formatRate(rate) {
+    return (rate || 0).toFixed(1) + '%';
+}
+
+// formatSLA shows the share of measured tickets that met their target, with the counts
+function formatSLA(met, measured) {
+    if (!measured) return '-';
+    return `${formatRate(met * 100 / measured)} <span class="text-gray-500 text-xs">(${met}/${measured})</span>`;
 }
 
-function updateCharts(summary) {
-    updateHourlyChart(summary.hourly_distribution);
-    updateCategoryChart(summary.category_distribution);
+function updateReportStats(summary, hourly) {
+    document.getElementById('totalTickets').textContent = summary.total_tickets;
+    document.getElementById('completedTickets').textContent = summary.completed;
+    document.getElementById('noShowTickets').textContent = summary.no_show;
+    document.getElementById('cancelledTickets').textContent = summary.cancelled;
+    document.getElementById('avgWaitTime').textContent = formatSeconds(summary.avg_wait_seconds);
+    document.getElementById('waitPercentiles').textContent =
+        `median ${formatSeconds(summary.median_wait_seconds)} · p90 ${formatSeconds(summary.p90_wait_seconds)}`;
+    document.getElementById('avgServiceTime').textContent = formatSeconds(summary.avg_service_seconds);
+    document.getElementById('servicePercentiles').textContent =
+        `median ${formatSeconds(summary.median_service_seconds)} · p90 ${formatSeconds(summary.p90_service_seconds)}`;
+    document.getElementById('completionRate').textContent = formatRate(summary.completion_rate);
+    document.getElementById('noShowRate').textContent = 'tidak hadir ' + formatRate(summary.no_show_rate);
+    document.getElementById('slaWaitCompliance').innerHTML = formatSLA(summary.sla_wait_met, summary.sla_wait_tickets);
+    document.getElementById('slaServiceCompliance').innerHTML = formatSLA(summary.sla_service_met, summary.sla_service_tickets);
+
+    const peak = hourly.reduce((best, hour) => hour.tickets > best.tickets ? hour : best, hourly[0]);
+    document.getElementById('peakHour').textContent =
+        peak && peak.tickets > 0 ? peak.hour.toString().padStart(2, '0') + ':00' : '--';
 }
 
-function updateHourlyChart(hourlyData) {
+function updateHourlyChart(hourly) {
     const chartContainer = document.getElementById('hourlyChart');
-    if (!hourlyData || !chartContainer) return;
+    if (!hourly || !chartContainer) return;
     
-    const maxTickets = Math.max(...Object.values(hourlyData));
-    const hours = Array.from({length: 17}, (_, i) => i + 8);
+    const maxTickets = Math.max(...hourly.map(hour => hour.tickets));
+    const hours = hourly.slice(8, 21);
     
     chartContainer.innerHTML = hours.map(hour => {
-        const ticketCount = hourlyData[hour] || 0;
-        const height = maxTickets > 0 ? (ticketCount / maxTickets) * 100 : 0;
+        const height = maxTickets > 0 ? (hour.tickets / maxTickets) * 100 : 0;
         
         return `
             <div class="flex-1 flex items-end">
                 <div class="w-full bg-gray-200 rounded-t-lg relative" style="height: ${height}%">
                     <div class="absolute bottom-0 left-0 right-0 bg-blue-500 text-center text-xs text-white py-1">
-                        ${ticketCount}
+                        ${hour.tickets}
                     </div>
                 </div>
-                <span class="w-12 text-xs text-gray-600 text-right">${hour.toString().padStart(2, '0')}:00</span>
+                <span class="w-12 text-xs text-gray-600 text-right">${hour.hour.toString().padStart(2, '0')}:00</span>
             </div>
         `;
     }).join('');
 }
 
-function updateCategoryChart(categoryData) {
+function updateCategoryChart(categories) {
     const chartContainer = document.getElementById('categoryChart');
-    if (!categoryData || !chartContainer) return;
+    if (!categories || !chartContainer) return;
     
-    chartContainer.innerHTML = Object.entries(categoryData).map(([category, data]) => `
+    chartContainer.innerHTML = categories.map(category => `
         <div class="flex items-center justify-between p-4 bg-gray-50 rounded-lg">
             <div class="flex items-center">
-                <div class="w-4 h-4 rounded-full mr-3" style="background-color: ${data.color_code}"></div>
+                <div class="w-4 h-4 rounded-full mr-3" style="background-color: ${category.color_code}"></div>
                 <div>
-                    <p class="font-medium">${category}</p>
-                    <p class="text-sm text-gray-500">${data.prefix}</p>
+                    <p class="font-medium">${category.category_id ? category.name : '(kategori dihapus)'}</p>
+                    <p class="text-sm text-gray-500">${category.prefix}</p>
                 </div>
             </div>
             <div class="text-right">
-                <div class="text-2xl font-bold" style="color: ${data.color_code}">${data.count}</div>
-                <p class="text-sm text-gray-500">tickets</p>
+                <div class="text-2xl font-bold" style="color: ${category.color_code}">${category.total}</div>
+                <p class="text-sm text-gray-500">${formatRate(category.completion_rate)} selesai</p>
             </div>
         </div>
     `).join('');
 }
 
-function updateTables(details) {
-    const tableBody = document.getElementById('ticketsTableBody');
-    if (!details || !tableBody) return;
-    
-    tableBody.innerHTML = details.map(ticket => `
-        <tr class="hover:bg-gray-50">
-            <td class="px-4 py-2">${new Date(ticket.created_at).toLocaleDateString()}</td>
-            <td class="px-4 py-2 font-medium">${ticket.ticket_number}</td>
-            <td class="px-4 py-2">
-                <span class="px-2 py-1 rounded text-xs text-white" style="background-color: ${ticket.category_color_code}">
-                    ${ticket.category_prefix}
-                </span>
-            </td>
-            <td class="px-4 py-2">
-                <span class="px-2 py-1 rounded-full text-xs font-medium
-                      ${ticket.status === 'completed' ? 'bg-green-100 text-green-800' :
-                        ticket.status === 'cancelled' ? 'bg-red-100 text-red-800' :
-                        ticket.status === 'no_show' ? 'bg-orange-100 text-orange-800' :
-                        'bg-blue-100 text-blue-800'}">
-                    ${ticket.status}
-                </span>
-            </td>
-            <td class="px-4 py-2">${ticket.wait_time ? Math.floor(ticket.wait_time / 60) + 'm ' + (ticket.wait_time % 60) + 's' : '-'}</td>
-            <td class="px-4 py-2">${ticket.service_time ? Math.floor(ticket.service_time / 60) + 'm ' + (ticket.service_time % 60) + 's' : '-'}</td>
-        </tr>
-    `).join('');
+const weekdayNames = ['Senin', 'Selasa', 'Rabu', 'Kamis', 'Jumat', 'Sabtu', 'Minggu'];
+
+function tableRow(cells) {
+    return `<tr class="hover:bg-gray-50">${cells.map(cell => `<td class="px-4 py-2">${cell}</td>`).join('')}</tr>`;
+}
+
+function updateTables(report) {
+    document.getElementById('countersTableBody').innerHTML = (report.counters || []).map(counter => tableRow([
+        `<span class="font-medium">${counter.number}</span> ${counter.name}`,
+        counter.total,
+        `${counter.completed} (${formatRate(counter.completion_rate)})`,
+        `${counter.no_show} (${formatRate(counter.no_show_rate)})`,
+        formatSeconds(counter.median_wait_seconds),
+        formatSeconds(counter.median_service_seconds),
+        formatSeconds(counter.p90_service_seconds),
+    ])).join('');
+
+    document.getElementById('categoriesTableBody').innerHTML = (report.categories || []).map(category => tableRow([
+        category.category_id ? category.name : '(kategori dihapus)',
+        category.total,
+        `${category.completed} (${formatRate(category.completion_rate)})`,
+        `${category.no_show} (${formatRate(category.no_show_rate)})`,
+        formatSeconds(category.median_wait_seconds),
+        formatSeconds(category.p90_wait_seconds),
+        formatSeconds(category.median_service_seconds),
+        formatSLA(category.sla_wait_met, category.sla_wait_tickets),
+        formatSLA(category.sla_service_met, category.sla_service_tickets),
+    ])).join('');
+
+    document.getElementById('weekdaysTableBody').innerHTML = (report.weekdays || []).map(day => tableRow([
+        weekdayNames[day.weekday - 1],
+        day.tickets,
+        formatSeconds(day.avg_wait_seconds),
+    ])).join('');
+
+    document.getElementById('slaTableBody').innerHTML = (report.days || []).map(day => tableRow([
+        new Date(day.day).toLocaleDateString('id-ID'),
+        day.total,
+        formatSLA(day.sla_wait_met, day.sla_wait_tickets),
+        formatSLA(day.sla_service_met, day.sla_service_tickets),
+    ])).join('');
 }
 
 // Auto-load data when page loads
@@ -0,0 +1,254 @@
+const dayNames = ['Min', 'Sen', 'Sel', 'Rab', 'Kam', 'Jum', 'Sab'];
+
+document.querySelectorAll('[data-days]').forEach(cell => {
+    const days = cell.dataset.days ? cell.dataset.days.split(',').map(d => dayNames[parseInt(d)]) : [];
+    cell.textContent = days.length ? days.join(', ') : 'Setiap hari';
+});
+
+function openModal(id) {
+    document.getElementById(id).classList.remove('hidden');
+    document.getElementById(id).classList.add('flex');
+}
+
+function closeModal(id) {
+    document.getElementById(id).classList.add('hidden');
+    document.getElementById(id).classList.remove('flex');
+}
+
+async function uploadMedia(event) {
+    event.preventDefault();
+    const form = event.target;
+    const button = document.getElementById('mediaUploadButton');
+    button.disabled = true;
+
+    try {
+        const response = await fetch('/admin/api/signage/media', {
+            method: 'POST',
+            body: new FormData(form)
+        });
+
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            const error = await response.json().catch(() => ({}));
+            alert(error.error || 'Gagal mengunggah media');
+        }
+    } catch (error) {
+        alert('Network error');
+    } finally {
+        button.disabled = false;
+    }
+    return false;
+}
+
+async function deleteMedia(id) {
+    if (!confirm('Media ini juga akan dihapus dari semua playlist. Lanjutkan?')) return;
+
+    try {
+        const response = await fetch(`/admin/api/signage/media/${id}`, { method: 'DELETE' });
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            alert('Gagal menghapus media');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+// Videos default to playing until they end
+function defaultDuration(select) {
+    const option = select.options[select.selectedIndex];
+    const duration = select.closest('[data-item]').querySelector('input[name="duration_seconds"]');
+    duration.value = option && option.dataset.video === 'true' ? 0 : 10;
+}
+
+function addPlaylistItem(item) {
+    const template = document.getElementById('playlistItemTemplate');
+    const row = template.content.firstElementChild.cloneNode(true);
+    const select = row.querySelector('select');
+    document.getElementById('playlistItems').appendChild(row);
+
+    if (item) {
+        select.value = item.media_id;
+        row.querySelector('input[name="duration_seconds"]').value = item.duration_seconds;
+    } else {
+        defaultDuration(select);
+    }
+    select.addEventListener('change', () => defaultDuration(select));
+}
+
+function moveItem(button, step) {
+    const row = button.closest('[data-item]');
+    const sibling = step < 0 ? row.previousElementSibling : row.nextElementSibling;
+    if (!sibling) return;
+    if (step < 0) {
+        row.parentNode.insertBefore(row, sibling);
+    } else {
+        row.parentNode.insertBefore(sibling, row);
+    }
+}
+
+function openCreatePlaylist() {
+    document.getElementById('playlistForm').reset();
+    document.getElementById('playlistId').value = '';
+    document.getElementById('playlistItems').replaceChildren();
+    document.getElementById('playlistModalTitle').textContent = 'Tambah Playlist';
+    openModal('playlistModal');
+}
+
+async function editPlaylist(id) {
+    try {
+        const response = await fetch(`/admin/api/signage/playlists/${id}`);
+        if (!response.ok) {
+            alert('Gagal memuat data playlist');
+            return;
+        }
+        const playlist = await response.json();
+
+        document.getElementById('playlistId').value = playlist.id;
+        document.getElementById('playlistName').value = playlist.name || '';
+        document.getElementById('playlistItems').replaceChildren();
+        (playlist.items || []).forEach(item => addPlaylistItem(item));
+        document.getElementById('playlistModalTitle').textContent = 'Edit Playlist';
+
+        openModal('playlistModal');
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+async function savePlaylist(event) {
+    event.preventDefault();
+    const form = event.target;
+    const id = form.id.value;
+
+    const data = {
+        name: form.name.value,
+        items: Array.from(document.querySelectorAll('#playlistItems [data-item]')).map(row => ({
+            media_id: parseInt(row.querySelector('select').value),
+            duration_seconds: parseInt(row.querySelector('input[name="duration_seconds"]').value) || 0
+        }))
+    };
+
+    try {
+        const response = await fetch(id ? `/admin/api/signage/playlists/${id}` : '/admin/api/signage/playlists', {
+            method: id ? 'PUT' : 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(data)
+        });
+
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            const error = await response.json();
+            alert(error.error || 'Gagal menyimpan playlist');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+    return false;
+}
+
+async function deletePlaylist(id) {
+    if (!confirm('Apakah Anda yakin ingin menghapus playlist ini beserta jadwalnya?')) return;
+
+    try {
+        const response = await fetch(`/admin/api/signage/playlists/${id}`, { method: 'DELETE' });
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            alert('Gagal menghapus playlist');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+function setDays(days) {
+    document.querySelectorAll('#scheduleForm input[name="days_of_week"]').forEach(cb => {
+        cb.checked = days.includes(parseInt(cb.value));
+    });
+}
+
+function openCreateSchedule() {
+    document.getElementById('scheduleForm').reset();
+    document.getElementById('scheduleId').value = '';
+    document.getElementById('scheduleModalTitle').textContent = 'Tambah Jadwal';
+    setDays([]);
+    openModal('scheduleModal');
+}
+
+async function editSchedule(id) {
+    try {
+        const response = await fetch(`/admin/api/signage/schedules/${id}`);
+        if (!response.ok) {
+            alert('Gagal memuat data jadwal');
+            return;
+        }
+        const schedule = await response.json();
+
+        document.getElementById('scheduleId').value = schedule.id;
+        document.getElementById('scheduleProfile').value = schedule.display_profile_id;
+        document.getElementById('schedulePlaylist').value = schedule.playlist_id;
+        document.getElementById('scheduleStart').value = schedule.start_time;
+        document.getElementById('scheduleEnd').value = schedule.end_time;
+        document.getElementById('schedulePriority').value = schedule.priority;
+        document.getElementById('scheduleActive').checked = schedule.is_active;
+        document.getElementById('scheduleModalTitle').textContent = 'Edit Jadwal';
+        setDays(schedule.days_of_week || []);
+
+        openModal('scheduleModal');
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+async function saveSchedule(event) {
+    event.preventDefault();
+    const form = event.target;
+    const id = form.id.value;
+
+    const data = {
+        display_profile_id: parseInt(form.display_profile_id.value),
+        playlist_id: parseInt(form.playlist_id.value),
+        days_of_week: Array.from(form.querySelectorAll('input[name="days_of_week"]:checked')).map(cb => parseInt(cb.value)),
+        start_time: form.start_time.value,
+        end_time: form.end_time.value,
+        priority: parseInt(form.priority.value) || 0,
+        is_active: form.is_active.checked
+    };
+
+    try {
+        const response = await fetch(id ? `/admin/api/signage/schedules/${id}` : '/admin/api/signage/schedules', {
+            method: id ? 'PUT' : 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(data)
+        });
+
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            const error = await response.json();
+            alert(error.error || 'Gagal menyimpan jadwal');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+    return false;
+}
+
+async function deleteSchedule(id) {
+    if (!confirm('Apakah Anda yakin ingin menghapus jadwal ini?')) return;
+
+    try {
+        const response = await fetch(`/admin/api/signage/schedules/${id}`, { method: 'DELETE' });
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            alert('Gagal menghapus jadwal');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
@@ -0,0 +1,94 @@
+// formatSeconds formats a duration in seconds as hours and minutes, or minutes and seconds
+function formatSeconds(seconds) {
+    const total = Math.round(seconds || 0);
+    if (total >= 3600) {
+        return Math.floor(total / 3600) + 'j ' + Math.floor((total % 3600) / 60) + 'm';
+    }
+    return Math.floor(total / 60) + 'm ' + (total % 60) + 's';
+}
+
+function formatRate(rate) {
+    return (rate || 0).toFixed(1) + '%';
+}
+
+function formatTime(value) {
+    return new Date(value).toLocaleString('id-ID', { dateStyle: 'short', timeStyle: 'short' });
+}
+
+function escapeHtml(text) {
+    const div = document.createElement('div');
+    div.textContent = text || '';
+    return div.innerHTML;
+}
+
+function tableRow(cells) {
+    return `<tr class="hover:bg-gray-50">${cells.map(cell => `<td class="px-4 py-2">${cell}</td>`).join('')}</tr>`;
+}
+
+function emptyRow(colspan) {
+    return `<tr><td colspan="${colspan}" class="px-4 py-8 text-center text-gray-500">Tidak ada shift pada periode ini</td></tr>`;
+}
+
+function statsCells(stats) {
+    return [
+        stats.tickets_served,
+        `${stats.no_show} (${formatRate(stats.no_show_rate)})`,
+        formatSeconds(stats.avg_service_seconds),
+    ];
+}
+
+function loadPerformance(event) {
+    if (event) {
+        event.preventDefault();
+    }
+    const params = new URLSearchParams({
+        date_from: document.getElementById('dateFrom').value,
+        date_to: document.getElementById('dateTo').value,
+        user_id: document.getElementById('userId').value,
+    });
+
+    fetch(`/admin/api/reports/staff?${params}`)
+        .then(response => response.json())
+        .then(data => {
+            if (data.error) {
+                alert(data.error);
+                return;
+            }
+            renderPerformance(data.staff || []);
+        })
+        .catch(error => {
+            console.error('Gagal memuat kinerja staf:', error);
+        });
+}
+
+function renderPerformance(staff) {
+    document.getElementById('staffTableBody').innerHTML = staff.length === 0 ? emptyRow(9) : staff.map(member => tableRow([
+        `<span class="font-medium">${escapeHtml(member.full_name)}</span> <span class="text-gray-500">${escapeHtml(member.username)}</span>`,
+        member.shifts.length,
+        ...statsCells(member),
+        formatSeconds(member.shift_seconds),
+        formatSeconds(member.pause_seconds),
+        formatSeconds(member.idle_seconds),
+        formatRate(member.utilisation),
+    ])).join('');
+
+    const shifts = staff.flatMap(member => member.shifts.map(shift => ({ member, shift })));
+    document.getElementById('shiftsTableBody').innerHTML = shifts.length === 0 ? emptyRow(10) : shifts.map(({ member, shift }) => tableRow([
+        escapeHtml(member.full_name),
+        escapeHtml(shift.counter_number) || '-',
+        formatTime(shift.started_at),
+        shift.open ? '<span class="px-2 py-0.5 rounded-full text-xs bg-green-100 text-green-800">Berlangsung</span>' : formatTime(shift.ended_at),
+        ...statsCells(shift),
+        formatSeconds(shift.pause_seconds),
+        formatSeconds(shift.idle_seconds),
+        formatRate(shift.utilisation),
+    ])).join('');
+}
+
+document.addEventListener('DOMContentLoaded', function() {
+    const today = new Date();
+    const weekAgo = new Date(today.getTime() - 7 * 24 * 60 * 60 * 1000);
+    document.getElementById('dateFrom').value = weekAgo.toISOString().split('T')[0];
+    document.getElementById('dateTo').value = today.toISOString().split('T')[0];
+    loadPerformance();
+});
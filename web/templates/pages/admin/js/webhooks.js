@@ -0,0 +1,119 @@
+function openModal(id) {
+    document.getElementById(id).classList.remove('hidden');
+    document.getElementById(id).classList.add('flex');
+}
+
+function closeModal(id) {
+    document.getElementById(id).classList.add('hidden');
+    document.getElementById(id).classList.remove('flex');
+}
+
+function setSelectedEvents(eventTypes) {
+    document.querySelectorAll('#webhookForm input[name="event_types"]').forEach(cb => {
+        cb.checked = eventTypes.includes(cb.value);
+    });
+}
+
+function openCreateWebhook() {
+    const form = document.getElementById('webhookForm');
+    form.reset();
+    document.getElementById('webhookId').value = '';
+    document.getElementById('webhookModalTitle').textContent = 'Tambah Webhook';
+    document.getElementById('webhookSecret').placeholder = 'Kosongkan untuk dibuat otomatis';
+    setSelectedEvents([]);
+    openModal('webhookModal');
+}
+
+async function editWebhook(id) {
+    try {
+        const response = await fetch(`/admin/api/webhooks/${id}`);
+        if (!response.ok) {
+            alert('Gagal memuat data webhook');
+            return;
+        }
+        const webhook = await response.json();
+
+        document.getElementById('webhookId').value = webhook.id;
+        document.getElementById('webhookName').value = webhook.name || '';
+        document.getElementById('webhookUrl').value = webhook.url || '';
+        document.getElementById('webhookSecret').value = '';
+        document.getElementById('webhookSecret').placeholder = 'Kosongkan untuk mempertahankan secret saat ini';
+        document.getElementById('webhookActive').checked = webhook.is_active;
+        document.getElementById('webhookModalTitle').textContent = 'Edit Webhook';
+        setSelectedEvents(webhook.event_types || []);
+
+        openModal('webhookModal');
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+async function saveWebhook(event) {
+    event.preventDefault();
+    const form = event.target;
+    const id = form.id.value;
+
+    const data = {
+        name: form.name.value,
+        url: form.url.value,
+        secret: form.secret.value,
+        event_types: Array.from(form.querySelectorAll('input[name="event_types"]:checked')).map(cb => cb.value),
+        is_active: form.is_active.checked
+    };
+
+    if (data.event_types.length === 0) {
+        alert('Pilih minimal satu event');
+        return false;
+    }
+
+    try {
+        const response = await fetch(id ? `/admin/api/webhooks/${id}` : '/admin/api/webhooks', {
+            method: id ? 'PUT' : 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(data)
+        });
+
+        if (response.ok) {
+            const webhook = await response.json();
+            if (!id) {
+                alert(`Webhook dibuat. Simpan secret ini:\n${webhook.secret}`);
+            }
+            window.location.reload();
+        } else {
+            const error = await response.json();
+            alert(error.error || 'Gagal menyimpan webhook');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+    return false;
+}
+
+async function deleteWebhook(id) {
+    if (!confirm('Apakah Anda yakin ingin menghapus webhook ini beserta log pengirimannya?')) return;
+
+    try {
+        const response = await fetch(`/admin/api/webhooks/${id}`, { method: 'DELETE' });
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            alert('Gagal menghapus webhook');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
+
+async function redeliver(id) {
+    try {
+        const response = await fetch(`/admin/api/webhook-deliveries/${id}/redeliver`, { method: 'POST' });
+        if (response.ok) {
+            window.location.reload();
+        } else {
+            const error = await response.json();
+            alert(error.error || 'Gagal mengirim ulang');
+        }
+    } catch (error) {
+        alert('Network error');
+    }
+}
@@ -155,6 +155,31 @@
           });
       },
 
+      markArrived: function () {
+        var self = this;
+        self.loading = true;
+        fetch("/staff/arrived", { method: "POST" })
+          .then(function (response) {
+            return response.json();
+          })
+          .then(function (data) {
+            if (data.error) {
+              self.showToast(data.error, "error");
+            } else {
+              self.showToast("Pelanggan hadir");
+              setTimeout(function () {
+                window.location.reload();
+              }, 500);
+            }
+          })
+          .catch(function (error) {
+            self.showToast("Network error", "error");
+          })
+          .finally(function () {
+            self.loading = false;
+          });
+      },
+
       toggleCounterStatus: function () {
         var self = this;
         if (self.counterStatus === "disabled") {
@@ -186,4 +211,56 @@
     return {
       counterStatus: dataEl ? dataEl.dataset.counterStatus : 'idle'
     };
-  }
\ No newline at end of file
+  }
+
+  // SLA flags are re-read while tickets age; the page reloads on every queue change
+  var SLA_REFRESH_MS = 15000;
+
+  function formatSLASeconds(seconds) {
+    var total = Math.round(seconds || 0);
+    if (total >= 3600) {
+      return Math.floor(total / 3600) + "j " + Math.floor((total % 3600) / 60) + "m";
+    }
+    return Math.floor(total / 60) + "m " + (total % 60) + "s";
+  }
+
+  function slaBreachMessage(payload) {
+    return "SLA terlampaui: tiket " + payload.ticket_number +
+      (payload.kind === "wait" ? " menunggu " : " dilayani ") +
+      formatSLASeconds(payload.elapsed_seconds) +
+      " (target " + formatSLASeconds(payload.target_seconds) + ")";
+  }
+
+  // refreshSLAFlags marks the tickets on the page that are near or past their target
+  function refreshSLAFlags() {
+    fetch("/staff/api/sla")
+      .then(function (response) {
+        return response.json();
+      })
+      .then(function (data) {
+        var flagged = {};
+        (data.tickets || []).forEach(function (ticket) {
+          flagged[ticket.ticket_id] = ticket;
+        });
+        document.querySelectorAll("[data-sla-ticket]").forEach(function (el) {
+          var ticket = flagged[el.dataset.slaTicket];
+          if (!ticket) {
+            el.className = "hidden";
+            return;
+          }
+          var breached = ticket.level === "breached";
+          el.className = "px-2 py-1 rounded-full text-xs font-medium " +
+            (breached ? "bg-red-100 text-red-800" : "bg-yellow-100 text-yellow-800");
+          el.title = "Target " + formatSLASeconds(ticket.target_seconds);
+          el.textContent = (breached ? "SLA terlampaui " : "Mendekati SLA ") + formatSLASeconds(ticket.elapsed_seconds);
+        });
+      })
+      .catch(function (error) {
+        console.log("error", error);
+      });
+  }
+
+  document.addEventListener("DOMContentLoaded", function () {
+    refreshSLAFlags();
+    setInterval(refreshSLAFlags, SLA_REFRESH_MS);
+  });
@@ -68,12 +68,40 @@ function viewTicketDetail(ticketId) {
     .then(ticket => {
         displayTicketDetail(ticket);
         openTicketDetailModal();
+        loadTicketCalls(ticketId);
     })
     .catch(error => {
         alert('Terjadi kesalahan: ' + error.message);
     });
 }
 
+const callKindLabels = { call: 'Dipanggil', recall: 'Dipanggil ulang', transfer: 'Dipindahkan' };
+
+function loadTicketCalls(ticketId) {
+    const list = document.getElementById('detailCalls');
+    list.innerHTML = '';
+    fetch('/staff/api/tickets/' + ticketId + '/calls')
+        .then(response => response.ok ? response.json() : [])
+        .then(calls => {
+            if (!calls || calls.length === 0) {
+                list.innerHTML = '<li class="text-gray-400">Belum dipanggil</li>';
+                return;
+            }
+            calls.forEach(call => {
+                const item = document.createElement('li');
+                let text = formatDateTime(call.called_at) + ' - ' + (callKindLabels[call.kind] || call.kind);
+                if (call.counter_number && call.counter_number.Valid) {
+                    text += ' ke loket ' + call.counter_number.String;
+                }
+                if (call.staff_name && call.staff_name.Valid) {
+                    text += ' oleh ' + call.staff_name.String;
+                }
+                item.textContent = text;
+                list.appendChild(item);
+            });
+        });
+}
+
 function openTicketDetailModal() {
     document.getElementById('ticketDetailModal').classList.remove('hidden');
     document.getElementById('ticketDetailModal').classList.add('flex');